@@ -16,8 +16,10 @@
 package api
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
@@ -33,28 +35,112 @@ import (
 	httpMux "github.com/google/cadvisor/http/mux"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/google/cadvisor/manager"
+	"github.com/google/cadvisor/utils/msgpack"
+	"github.com/google/cadvisor/utils/websocket"
 )
 
 const (
 	apiResource = "/api/"
+
+	// Interval between pings sent to keep WebSocket connections alive.
+	webSocketPingPeriod = 30 * time.Second
+
+	// Interval between comments sent to keep Server-Sent Events streams alive.
+	sseKeepalivePeriod = 15 * time.Second
 )
 
+var maxBatchSize = flag.Int("api_max_batch_size", 100, "Maximum number of containers whose stats can be requested in a single batch request")
+var gzipMinSize = flag.Int("api_gzip_min_size", 1024, "Minimum size in bytes of an API response before it is gzip compressed for clients that accept it")
+var floatPrecision = flag.Int("api_float_precision", -1, "Number of decimals the derived floating point stats of the v2 API (headroom of summaries, rates and shares) are rounded to. They are not rounded if negative")
+
 func RegisterHandlers(mux httpMux.Mux, m manager.Manager) error {
+	if err := validateJsonFieldNames(); err != nil {
+		return err
+	}
 	apiVersions := getApiVersions()
 	supportedApiVersions := make(map[string]ApiVersion, len(apiVersions))
 	for _, v := range apiVersions {
 		supportedApiVersions[v.Version()] = v
 	}
 
+	limiter := newRateLimiter(map[string]float64{
+		statsRequests:   *statsRateLimit,
+		eventsRequests:  *eventsRateLimit,
+		machineRequests: *machineRateLimit,
+	}, *rateLimitBurst)
+
 	mux.HandleFunc(apiResource, func(w http.ResponseWriter, r *http.Request) {
-		err := handleRequest(supportedApiVersions, m, w, r)
+		err := limiter.check(w, r)
+		if err == nil {
+			err = handleRequest(supportedApiVersions, m, w, r)
+		}
 		if err != nil {
-			http.Error(w, err.Error(), 500)
+			writeError(err, w)
 		}
 	})
 	return nil
 }
 
+// Body of the responses to failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+	// HTTP status code of the response.
+	Code int `json:"code"`
+	// Name of the container that was not found, if any.
+	Name string `json:"name,omitempty"`
+}
+
+// Reports err to the client as JSON with the status code matching its type.
+func writeError(err error, w http.ResponseWriter) {
+	resp := errorResponse{
+		Error: err.Error(),
+		Code:  http.StatusInternalServerError,
+	}
+	switch e := err.(type) {
+	case *manager.ContainerNotFoundError:
+		// Clients treat a 404 as terminal, unlike 5xx errors which they retry.
+		resp.Error = "container not found"
+		resp.Code = http.StatusNotFound
+		resp.Name = e.Name
+	case *httpError:
+		resp.Code = e.status
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.Code)
+	w.Write(out)
+}
+
+// An error that is reported to the client with a specific HTTP status code.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (self *httpError) Error() string {
+	return self.err.Error()
+}
+
+// Returns an error reported to the client as a 400 Bad Request.
+func badRequestError(format string, args ...interface{}) error {
+	return &httpError{
+		status: http.StatusBadRequest,
+		err:    fmt.Errorf(format, args...),
+	}
+}
+
+// Returns an error reported to the client as a 404 Not Found.
+func notFoundError(format string, args ...interface{}) error {
+	return &httpError{
+		status: http.StatusNotFound,
+		err:    fmt.Errorf(format, args...),
+	}
+}
+
 // Captures the API version, requestType [optional], and remaining request [optional].
 var apiRegexp = regexp.MustCompile("/api/([^/]+)/?([^/]+)?(.*)")
 
@@ -74,7 +160,7 @@ func handleRequest(supportedApiVersions map[string]ApiVersion, m manager.Manager
 
 	const apiPrefix = "/api"
 	if !strings.HasPrefix(request, apiPrefix) {
-		return fmt.Errorf("incomplete API request %q", request)
+		return notFoundError("incomplete API request %q", request)
 	}
 
 	// If the request doesn't have an API version, list those.
@@ -92,7 +178,7 @@ func handleRequest(supportedApiVersions map[string]ApiVersion, m manager.Manager
 	// /<version>/<request type>[/<args...>]
 	requestElements := apiRegexp.FindStringSubmatch(request)
 	if len(requestElements) == 0 {
-		return fmt.Errorf("malformed request %q", request)
+		return badRequestError("malformed request %q", request)
 	}
 	version := requestElements[apiVersion]
 	requestType := requestElements[apiRequestType]
@@ -101,7 +187,7 @@ func handleRequest(supportedApiVersions map[string]ApiVersion, m manager.Manager
 	// Check supported versions.
 	versionHandler, ok := supportedApiVersions[version]
 	if !ok {
-		return fmt.Errorf("unsupported API version %q", version)
+		return notFoundError("unsupported API version %q", version)
 	}
 
 	// If no request type, list possible request types.
@@ -121,16 +207,80 @@ func handleRequest(supportedApiVersions map[string]ApiVersion, m manager.Manager
 
 }
 
-func writeResult(res interface{}, w http.ResponseWriter) error {
-	out, err := json.Marshal(res)
+func writeResult(res interface{}, w http.ResponseWriter, r *http.Request) error {
+	contentType := "application/json"
+	marshal := json.Marshal
+	if acceptsMsgpack(r) {
+		contentType = msgpackContentType
+		marshal = msgpack.Marshal
+	} else {
+		res = jsonValue(res, r)
+	}
+	out, err := marshal(res)
 	if err != nil {
 		return fmt.Errorf("failed to marshall response %+v with error: %s", res, err)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(out)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.Header().Add("Vary", "Accept-Encoding")
+	if len(out) < *gzipMinSize || !acceptsGzip(r) {
+		w.Write(out)
+		return nil
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzip.NewWriter(w)
+	_, err = gz.Write(out)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		// Headers are already sent, all we can do is log.
+		glog.Errorf("failed to write gzip compressed response: %v", err)
+	}
 	return nil
+}
+
+const msgpackContentType = "application/x-msgpack"
+
+// Returns whether the request asks for a MessagePack encoded response, either
+// with the "format=msgpack" query parameter or with its Accept header.
+func acceptsMsgpack(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "msgpack" {
+		return true
+	}
+	for _, value := range r.Header["Accept"] {
+		for _, mediaType := range strings.Split(value, ",") {
+			mediaType = strings.TrimSpace(strings.Split(mediaType, ";")[0])
+			if mediaType == msgpackContentType || mediaType == "application/msgpack" {
+				return true
+			}
+		}
+	}
+	return false
+}
 
+// Returns whether the request advertises support for gzip encoded responses.
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header["Accept-Encoding"] {
+		for _, encoding := range strings.Split(value, ",") {
+			// Each encoding may carry a quality value, e.g.: "gzip;q=0.5".
+			parts := strings.Split(encoding, ";")
+			if strings.TrimSpace(parts[0]) != "gzip" {
+				continue
+			}
+			for _, param := range parts[1:] {
+				if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+					if quality, err := strconv.ParseFloat(q[2:], 64); err == nil && quality == 0 {
+						return false
+					}
+				}
+			}
+			return true
+		}
+	}
+	return false
 }
 
 func streamResults(eventChannel *events.EventChannel, w http.ResponseWriter, r *http.Request, m manager.Manager) error {
@@ -156,7 +306,7 @@ func streamResults(eventChannel *events.EventChannel, w http.ResponseWriter, r *
 			return nil
 		case ev := <-eventChannel.GetChannel():
 			glog.V(3).Infof("Received event from watch channel in api: %v", ev)
-			err := enc.Encode(ev)
+			err := enc.Encode(jsonValue(ev, r))
 			if err != nil {
 				glog.Errorf("error encoding message %+v for result stream: %v", ev, err)
 			}
@@ -165,23 +315,274 @@ func streamResults(eventChannel *events.EventChannel, w http.ResponseWriter, r *
 	}
 }
 
+// Streams the stats of a container as they are collected, one JSON encoded
+// v2.ContainerStats per line. Stats collected less than interval after the
+// last streamed ones are skipped.
+func streamStats(statsChannel *manager.StatsChannel, interval time.Duration, w http.ResponseWriter, r *http.Request, m manager.Manager) error {
+	defer m.CloseStatsChannel(statsChannel)
+	cn, ok := w.(http.CloseNotifier)
+	if !ok {
+		return errors.New("could not access http.CloseNotifier")
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("could not access http.Flusher")
+	}
+
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	enc := json.NewEncoder(w)
+	var lastTimestamp time.Time
+	for {
+		select {
+		case <-cn.CloseNotify():
+			glog.V(3).Infof("Received CloseNotify event")
+			return nil
+		case cinfo, ok := <-statsChannel.GetChannel():
+			if !ok {
+				// The container went away.
+				return nil
+			}
+			stats := cinfo.Stats[0]
+			if !lastTimestamp.IsZero() && stats.Timestamp.Sub(lastTimestamp) < interval {
+				continue
+			}
+			lastTimestamp = stats.Timestamp
+			err := enc.Encode(jsonValue(convertStat(&cinfo.Spec, stats), r))
+			if err != nil {
+				// The client went away.
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Streams the containers added and removed, one JSON encoded
+// v2.TopologyChange per line, after the containers tracked when the stream
+// starts. The stream ends if the client does not keep up.
+func streamTopology(topologyChannel *manager.TopologyChannel, w http.ResponseWriter, r *http.Request, m manager.Manager) error {
+	defer m.CloseTopologyChannel(topologyChannel)
+	cn, ok := w.(http.CloseNotifier)
+	if !ok {
+		return errors.New("could not access http.CloseNotifier")
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("could not access http.Flusher")
+	}
+
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for _, change := range topologyChannel.GetContainers() {
+		if err := enc.Encode(jsonValue(change, r)); err != nil {
+			// The client went away.
+			return nil
+		}
+	}
+	flusher.Flush()
+	for {
+		select {
+		case <-cn.CloseNotify():
+			glog.V(3).Infof("Received CloseNotify event")
+			return nil
+		case change, ok := <-topologyChannel.GetChannel():
+			if !ok {
+				// The client fell behind.
+				return nil
+			}
+			if err := enc.Encode(jsonValue(change, r)); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Streams events to the client as Server-Sent Events, one "data:" message
+// per JSON encoded event, as consumed by EventSource in browsers.
+func streamResultsAsSse(eventChannel *events.EventChannel, w http.ResponseWriter, r *http.Request, m manager.Manager) error {
+	cn, ok := w.(http.CloseNotifier)
+	if !ok {
+		return errors.New("could not access http.CloseNotifier")
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("could not access http.Flusher")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepalivePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cn.CloseNotify():
+			glog.V(3).Infof("Received CloseNotify event")
+			m.CloseEventChannel(eventChannel.GetWatchId())
+			return nil
+		case <-ticker.C:
+			// Lines starting with a colon are comments ignored by clients.
+			_, err := io.WriteString(w, ":keepalive\n\n")
+			if err != nil {
+				m.CloseEventChannel(eventChannel.GetWatchId())
+				return nil
+			}
+			flusher.Flush()
+		case ev, ok := <-eventChannel.GetChannel():
+			if !ok {
+				return nil
+			}
+			glog.V(3).Infof("Received event from watch channel in api: %v", ev)
+			out, err := json.Marshal(jsonValue(ev, r))
+			if err != nil {
+				glog.Errorf("error encoding message %+v for event stream: %v", ev, err)
+				continue
+			}
+			// The JSON encoding has no newlines, the event fits in a single data line.
+			_, err = fmt.Fprintf(w, "data: %s\n\n", out)
+			if err != nil {
+				m.CloseEventChannel(eventChannel.GetWatchId())
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Streams events to the client as JSON text frames over a WebSocket. If
+// maxEvents is positive the connection is closed after that many events.
+func streamResultsOverWebSocket(eventChannel *events.EventChannel, maxEvents int, w http.ResponseWriter, r *http.Request, m manager.Manager) error {
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		m.CloseEventChannel(eventChannel.GetWatchId())
+		return err
+	}
+	defer conn.Close()
+	defer m.CloseEventChannel(eventChannel.GetWatchId())
+
+	// The client only sends control frames, read them until the socket is closed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			opcode, _, err := conn.ReadMessage()
+			if err != nil || opcode == websocket.CloseMessage {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(webSocketPingPeriod)
+	defer ticker.Stop()
+	sent := 0
+	for {
+		select {
+		case <-closed:
+			glog.V(3).Infof("WebSocket closed by the client")
+			return nil
+		case <-ticker.C:
+			if err := conn.WritePing(); err != nil {
+				glog.V(3).Infof("Failed to ping WebSocket client: %v", err)
+				return nil
+			}
+		case ev, ok := <-eventChannel.GetChannel():
+			if !ok {
+				return nil
+			}
+			glog.V(3).Infof("Received event from watch channel in api: %v", ev)
+			out, err := json.Marshal(jsonValue(ev, r))
+			if err != nil {
+				glog.Errorf("error encoding message %+v for WebSocket stream: %v", ev, err)
+				continue
+			}
+			if err := conn.WriteText(out); err != nil {
+				glog.V(3).Infof("Failed to write event to WebSocket client: %v", err)
+				return nil
+			}
+			sent++
+			if maxEvents > 0 && sent >= maxEvents {
+				return nil
+			}
+		}
+	}
+}
+
 func getContainerInfoRequest(body io.ReadCloser) (*info.ContainerInfoRequest, error) {
-	query := info.DefaultContainerInfoRequest()
+	var raw json.RawMessage
 	decoder := json.NewDecoder(body)
-	err := decoder.Decode(&query)
+	err := decoder.Decode(&raw)
 	if err != nil && err != io.EOF {
-		return nil, fmt.Errorf("unable to decode the json value: %s", err)
+		return nil, badRequestError("unable to decode the json value: %s", err)
+	}
+	var query info.ContainerInfoRequest
+	// An explicit num_stats of 0 requests no stats, the default only applies
+	// when it is absent.
+	var numStats struct {
+		NumStats *int `json:"num_stats"`
+	}
+	if err == nil {
+		if err := json.Unmarshal(raw, &query); err != nil {
+			return nil, badRequestError("unable to decode the json value: %s", err)
+		}
+		if err := json.Unmarshal(raw, &numStats); err != nil {
+			return nil, badRequestError("unable to decode the json value: %s", err)
+		}
+	}
+	if !query.Start.IsZero() && !query.End.IsZero() && query.End.Before(query.Start) {
+		return nil, badRequestError("end time %v is before start time %v", query.End, query.Start)
 	}
 
+	if numStats.NumStats == nil {
+		if !query.Start.IsZero() && !query.End.IsZero() {
+			// Return the whole time range.
+			query.NumStats = -1
+		} else {
+			query.NumStats = info.DefaultContainerInfoRequest().NumStats
+		}
+	}
 	return &query, nil
 }
 
+// Returns the X-Events-Cursor header of the events following the given
+// sequence number, in the given epoch of the sequence numbers.
+func formatEventsCursor(epoch int64, sequence uint64) string {
+	return fmt.Sprintf("%d-%d", epoch, sequence)
+}
+
+// Parses an X-Events-Cursor header into its epoch and sequence number.
+func parseEventsCursor(cursor string) (int64, uint64, error) {
+	parts := strings.Split(cursor, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed events cursor %q", cursor)
+	}
+	epoch, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed epoch in events cursor %q: %v", cursor, err)
+	}
+	sequence, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed sequence number in events cursor %q: %v", cursor, err)
+	}
+	return epoch, sequence, nil
+}
+
 // The user can set any or none of the following arguments in any order
 // with any twice defined arguments being assigned the first value.
 // If the value type for the argument is wrong the field will be assumed to be
 // unassigned
-// bools: historical, subcontainers, oom_events, creation_events, deletion_events
-// ints: max_events, start_time (unix timestamp), end_time (unix timestamp)
+// bools: historical, subcontainers, oom_events, creation_events, deletion_events,
+// restart_loop_events
+// ints: max_events, offset, limit, start_time (unix timestamp), end_time (unix timestamp)
+// regexps: container_regexp (an invalid regexp is reported as a bad request)
+// since: X-Events-Cursor header or RFC3339 time (invalid ones are reported as a bad request)
 // example r.URL: http://localhost:8080/api/v1.3/events?oom_events=true&historical=true&max_events=10
 func getEventRequest(r *http.Request) (*events.Request, bool, error) {
 	query := events.NewRequest()
@@ -219,12 +620,30 @@ func getEventRequest(r *http.Request) (*events.Request, bool, error) {
 			query.EventType[events.TypeContainerDeletion] = newBool
 		}
 	}
+	if val, ok := urlMap["restart_loop_events"]; ok {
+		newBool, err := strconv.ParseBool(val[0])
+		if err == nil {
+			query.EventType[events.TypeRestartLoop] = newBool
+		}
+	}
 	if val, ok := urlMap["max_events"]; ok {
 		newInt, err := strconv.Atoi(val[0])
 		if err == nil {
 			query.MaxEventsReturned = int(newInt)
 		}
 	}
+	if val, ok := urlMap["offset"]; ok {
+		newInt, err := strconv.Atoi(val[0])
+		if err == nil && newInt >= 0 {
+			query.Offset = newInt
+		}
+	}
+	if val, ok := urlMap["limit"]; ok {
+		newInt, err := strconv.Atoi(val[0])
+		if err == nil && newInt >= 0 {
+			query.Limit = newInt
+		}
+	}
 	if val, ok := urlMap["start_time"]; ok {
 		newTime, err := time.Parse(time.RFC3339, val[0])
 		if err == nil {
@@ -237,6 +656,25 @@ func getEventRequest(r *http.Request) (*events.Request, bool, error) {
 			query.EndTime = newTime
 		}
 	}
+	if val, ok := urlMap["since"]; ok {
+		// Either the cursor of the last events received or a time.
+		if epoch, sequence, err := parseEventsCursor(val[0]); err == nil {
+			query.SinceEpoch = epoch
+			query.Since = sequence
+		} else if since, err := time.Parse(time.RFC3339Nano, val[0]); err == nil {
+			// Only the events which occurred after since, StartTime is inclusive.
+			query.StartTime = since.Add(time.Nanosecond)
+		} else {
+			return nil, false, badRequestError("invalid since %q, expected an X-Events-Cursor header or an RFC3339 time", val[0])
+		}
+	}
+	if val, ok := urlMap["container_regexp"]; ok {
+		containerRegexp, err := regexp.Compile(val[0])
+		if err != nil {
+			return nil, false, badRequestError("invalid container_regexp %q: %v", val[0], err)
+		}
+		query.ContainerRegexp = containerRegexp
+	}
 
 	glog.V(2).Infof(
 		"%v was returned in api/handler.go:getEventRequest from the url rawQuery %v",
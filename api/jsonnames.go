@@ -0,0 +1,240 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var jsonFieldNames = flag.String("api_json_field_names", "default", "Naming of the JSON fields of the v2 API responses: default, the documented names, or snake_case, which also converts the fields without a documented name (e.g. of events) to snake_case")
+
+// Returns an error if -api_json_field_names is not a known naming.
+func validateJsonFieldNames() error {
+	switch *jsonFieldNames {
+	case "default", "snake_case":
+		return nil
+	}
+	return fmt.Errorf("unknown -api_json_field_names %q, expected default or snake_case", *jsonFieldNames)
+}
+
+// Returns the value to encode as JSON in the response to r: res itself, or
+// with -api_json_field_names=snake_case and a v2 request, a copy of res whose
+// struct fields are named in snake_case. The keys of maps, e.g. container
+// names and labels, are left as is.
+func jsonValue(res interface{}, r *http.Request) interface{} {
+	if *jsonFieldNames != "snake_case" || !strings.HasPrefix(r.URL.Path, apiResource+"v2.") {
+		return res
+	}
+	return snakeCaseValue(reflect.ValueOf(res))
+}
+
+// A JSON object whose fields are encoded in order, as those of a struct are.
+type jsonObject []jsonField
+
+type jsonField struct {
+	name  string
+	value interface{}
+}
+
+func (self jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range self {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Returns a value encoding to the JSON of v, with the struct fields named in
+// snake_case. Values with their own JSON encoding, e.g. times, are kept.
+func snakeCaseValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	t := v.Type()
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	if v.CanAddr() && (reflect.PtrTo(t).Implements(marshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)) {
+		return v.Addr().Interface()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return snakeCaseValue(v.Elem())
+	case reflect.Struct:
+		object := jsonObject{}
+		appendSnakeCaseFields(v, &object)
+		return object
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			m[mapKeyString(key)] = snakeCaseValue(v.MapIndex(key))
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Encoded as base64.
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = snakeCaseValue(v.Index(i))
+		}
+		return s
+	}
+	return v.Interface()
+}
+
+// Appends the fields of the struct v as encoding/json would, fields of
+// embedded structs included, named in snake_case.
+func appendSnakeCaseFields(v reflect.Value, object *jsonObject) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, options = tag[:i], tag[i:]
+		}
+		value := v.Field(i)
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				appendSnakeCaseFields(embedded, object)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// Unexported.
+			continue
+		}
+		if strings.Contains(options, ",omitempty") && isEmptyValue(value) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		*object = append(*object, jsonField{name: toSnakeCase(name), value: snakeCaseValue(value)})
+	}
+}
+
+// Returns the JSON object key of a map key, as encoding/json does.
+func mapKeyString(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	}
+	return fmt.Sprint(key.Interface())
+}
+
+// Whether omitempty leaves out the value, as in encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Converts a field name to snake_case, e.g. "ContainerName" to
+// "container_name" and "IPAddress" to "ip_address". Names already in
+// snake_case are kept.
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var out []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' {
+				prev := runes[i-1]
+				acronymEnd := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || acronymEnd {
+					out = append(out, '_')
+				}
+			}
+			r = unicode.ToLower(r)
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var statsRateLimit = flag.Float64("api_stats_rate_limit", 0, "Maximum number of API requests for container stats, specs and processes served per second, 0 for no limit")
+var eventsRateLimit = flag.Float64("api_events_rate_limit", 0, "Maximum number of API requests for events served per second, 0 for no limit")
+var machineRateLimit = flag.Float64("api_machine_rate_limit", 0, "Maximum number of API requests for machine information, attributes, version, storage and health served per second, 0 for no limit")
+var rateLimitBurst = flag.Int("api_rate_limit_burst", 20, "Number of API requests of each class served at once before the rate limits apply")
+
+// Classes of requests limited separately.
+const (
+	statsRequests   = "stats"
+	eventsRequests  = "events"
+	machineRequests = "machine"
+)
+
+// Returns the class of a request type, all the request types that are not
+// about events or the machine are about containers.
+func requestClass(requestType string) string {
+	switch requestType {
+	case eventsApi, eventsWsApi:
+		return eventsRequests
+	case "", machineApi, attributesApi, versionApi, storageApi, healthApi, schemaApi:
+		return machineRequests
+	}
+	return statsRequests
+}
+
+// Token bucket refilled with rate tokens per second, up to burst tokens.
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// Takes a token if one is available, otherwise returns how long until one is.
+func (self *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if elapsed := now.Sub(self.last); elapsed > 0 {
+		self.tokens = math.Min(self.burst, self.tokens+elapsed.Seconds()*self.rate)
+		self.last = now
+	}
+	if self.tokens >= 1 {
+		self.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - self.tokens) / self.rate * float64(time.Second))
+}
+
+// Limits the rate of the requests of each class, classes without a limit
+// have no bucket.
+type rateLimiter struct {
+	buckets map[string]*tokenBucket
+}
+
+func newRateLimiter(limits map[string]float64, burst int) *rateLimiter {
+	now := time.Now()
+	buckets := make(map[string]*tokenBucket, len(limits))
+	for class, rate := range limits {
+		if rate > 0 {
+			buckets[class] = newTokenBucket(rate, burst, now)
+		}
+	}
+	return &rateLimiter{buckets}
+}
+
+// Returns an error reported as a 429 Too Many Requests if the request cannot
+// be served now, along with a Retry-After header telling the client when to
+// retry.
+func (self *rateLimiter) check(w http.ResponseWriter, r *http.Request) error {
+	requestType := ""
+	if requestElements := apiRegexp.FindStringSubmatch(r.URL.Path); len(requestElements) != 0 {
+		requestType = requestElements[apiRequestType]
+	}
+	class := requestClass(requestType)
+	bucket, ok := self.buckets[class]
+	if !ok {
+		return nil
+	}
+	allowed, wait := bucket.take(time.Now())
+	if allowed {
+		return nil
+	}
+	// Retry-After is in whole seconds.
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	return &httpError{
+		status: http.StatusTooManyRequests,
+		err:    fmt.Errorf("too many %s requests, retry in %v", class, wait),
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/cadvisor/events"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+	"github.com/google/cadvisor/version"
+)
+
+// A query parameter of an API operation.
+type apiParam struct {
+	name        string
+	typ         string
+	description string
+}
+
+// An operation of the API as described in its OpenAPI document.
+type apiOperation struct {
+	requestType string
+	method      string
+	summary     string
+	// Whether the name of a container follows the request type in the path.
+	container bool
+	params    []apiParam
+	// Zero value of the type of the response, nil if it is not a single
+	// JSON document.
+	response interface{}
+	// Describes the response when it is not a single JSON document.
+	stream string
+}
+
+var requestOptionParams = []apiParam{
+	{"type", "string", "Type of the container identifier, name (default) or docker."},
+	{"count", "integer", "Number of stats samples to return. Default is 64."},
+	{"recursive", "boolean", "Whether to include the subcontainers of the container."},
+	{"depth", "integer", "Number of levels of subcontainers included by recursive requests. Default is all of them."},
+}
+
+var unitsParam = apiParam{"units", "string", "Unit of the memory and network byte fields: bytes (default), KiB or MiB."}
+
+var eventParams = []apiParam{
+	{"historical", "boolean", "Return past events rather than streaming new ones."},
+	{"subcontainers", "boolean", "Include the events of the subcontainers of the container."},
+	{"oom_events", "boolean", "Select OOM events."},
+	{"creation_events", "boolean", "Select container creation events."},
+	{"deletion_events", "boolean", "Select container deletion events."},
+	{"restart_loop_events", "boolean", "Select the events reporting containers started too often."},
+	{"max_events", "integer", "Maximum number of the most recent events to consider, or of the first ones after since if it is an events cursor."},
+	{"offset", "integer", "Number of events to skip."},
+	{"limit", "integer", "Maximum number of events to return after skipping offset events."},
+	{"start_time", "string", "RFC3339 time before which events are ignored."},
+	{"end_time", "string", "RFC3339 time after which events are ignored."},
+	{"since", "string", "X-Events-Cursor header of a previous request, to only return the events added since, ordered by sequence number. Or RFC3339 time up to which events are ignored."},
+	{"container_regexp", "string", "Regular expression the names of the containers must match."},
+	{"format", "string", "Set to sse to stream events as Server-Sent Events."},
+}
+
+// Operations of the v2.1 API. Keep in sync with version2_1.HandleRequest.
+var v2_1Operations = []apiOperation{
+	{requestType: versionApi, method: "GET", summary: "Version of cAdvisor.", response: ""},
+	{requestType: attributesApi, method: "GET", summary: "Attributes of the machine and of cAdvisor.", response: v2.Attributes{}},
+	{requestType: machineApi, method: "GET", summary: "Hardware of the machine.", response: info.MachineInfo{}},
+	{requestType: summaryApi, method: "GET", summary: "Derived stats of containers.", container: true, params: requestOptionParams, response: map[string]v2.DerivedStats{}},
+	{requestType: statsApi, method: "GET", summary: "Stats of containers.", container: true, params: append([]apiParam{
+		{"fields", "string", "Comma separated list of the stats sections to return."},
+		{"aggregate", "string", "Set to sum to sum the stats of the container and its subcontainers."},
+		unitsParam,
+	}, requestOptionParams...), response: map[string][]v2.ContainerStats{}},
+	{requestType: specApi, method: "GET", summary: "Specs of containers.", container: true, params: requestOptionParams, response: map[string]v2.ContainerSpec{}},
+	{requestType: storageApi, method: "GET", summary: "Filesystems of the machine.", params: []apiParam{
+		{"label", "string", "Label of the filesystem, e.g. docker-images."},
+	}, response: []v2.FsInfo{}},
+	{requestType: eventsApi, method: "GET", summary: "Past or new events of a container.", container: true, params: eventParams, response: events.EventSlice{}},
+	{requestType: eventsApi, method: "DELETE", summary: "Deletes past events of a container.", container: true, params: eventParams, response: v2.DeletedEvents{}},
+	{requestType: eventsWsApi, method: "GET", summary: "New events of a container.", container: true, params: eventParams, stream: "WebSocket of JSON encoded events."},
+	{requestType: byLabelApi, method: "GET", summary: "Stats of the containers with the given labels.", params: append([]apiParam{
+		{"label", "string", "Label selector <key>=<value>, can be repeated."},
+		{"label_match", "string", "Set to prefix to prefix match the label values."},
+		unitsParam,
+	}, requestOptionParams...), response: map[string][]v2.ContainerStats{}},
+	{requestType: housekeepingApi, method: "PUT", summary: "Sets the housekeeping interval of a container.", container: true, response: v2.HousekeepingInterval{}},
+	{requestType: annotationsApi, method: "PUT", summary: "Sets the annotations of a container.", container: true, response: map[string]string{}},
+	{requestType: batchApi, method: "POST", summary: "Stats of a list of containers.", params: append([]apiParam{unitsParam}, requestOptionParams...), response: map[string]v2.BatchStats{}},
+	{requestType: ratesApi, method: "GET", summary: "Per-second rates of the counters of containers.", container: true, params: requestOptionParams, response: map[string]v2.ContainerRates{}},
+	{requestType: latestApi, method: "GET", summary: "Latest stats of containers.", container: true, params: append([]apiParam{unitsParam}, requestOptionParams...), response: map[string]v2.ContainerStats{}},
+	{requestType: processesApi, method: "GET", summary: "Processes of a container.", container: true, params: append([]apiParam{
+		{"sort", "string", "Sort the processes by cpu (default) or memory."},
+		{"limit", "integer", "Maximum number of processes to return."},
+	}, requestOptionParams...), response: []v2.ProcessInfo{}},
+	{requestType: healthApi, method: "GET", summary: "Status of the stats collection of each subsystem.", response: map[string]v2.SubsystemHealth{}},
+	{requestType: statsStreamApi, method: "GET", summary: "Stream of the stats of a container.", container: true, params: []apiParam{
+		{"interval", "string", "Minimum interval between two samples, e.g. 10s."},
+	}, stream: "Chunked stream of JSON encoded v2.ContainerStats."},
+	{requestType: topologyApi, method: "GET", summary: "Stream of the containers added and removed.", stream: "Chunked stream of JSON encoded v2.TopologyChange, starting with the current containers."},
+	{requestType: resolveApi, method: "GET", summary: "Resolves a cgroup path or a container name.", params: []apiParam{
+		{"cgroup", "string", "Cgroup path of the container."},
+		{"name", "string", "Name or alias of the container."},
+	}, response: v2.ResolvedContainer{}},
+	{requestType: topApi, method: "GET", summary: "Containers using the most of a resource.", params: []apiParam{
+		{"by", "string", "Metric to rank containers by: cpu, memory, network_rx or diskio."},
+		{"limit", "integer", "Number of containers to return. Default is 10."},
+	}, response: []v2.TopContainer{}},
+	{requestType: imagesApi, method: "GET", summary: "Resources used by the running containers of each image.", response: []v2.ImageUsage{}},
+	{requestType: shareApi, method: "GET", summary: "Share of the CPU and memory of the machine used by containers.", container: true, params: requestOptionParams, response: map[string]v2.ContainerShare{}},
+	{requestType: specsApi, method: "GET", summary: "Specs of containers, without their stats.", container: true, params: requestOptionParams, response: map[string]v2.ContainerSpec{}},
+	{requestType: cgroupApi, method: "GET", summary: "Settings of a container read from the control files of its cgroups.", container: true, response: map[string]string{}},
+	{requestType: prometheusApi, method: "GET", summary: "Prometheus metrics of a container.", container: true, stream: "Metrics of the container in the Prometheus text format."},
+	{requestType: schemaApi, method: "GET", summary: "OpenAPI description of the API.", response: map[string]interface{}{}},
+}
+
+// Builds the OpenAPI 3 document describing the operations of an API version.
+// The schemas of the responses are generated from their Go types.
+func buildSchema(apiVersion string, operations []apiOperation) map[string]interface{} {
+	generator := &schemaGenerator{schemas: map[string]interface{}{}}
+	paths := map[string]interface{}{}
+	errorSchema := generator.schemaOf(reflect.TypeOf(errorResponse{}))
+	for _, op := range operations {
+		p := path.Join(apiResource, apiVersion, op.requestType)
+		var params []interface{}
+		if op.container {
+			p += "/{container}"
+			params = append(params, map[string]interface{}{
+				"name":        "container",
+				"in":          "path",
+				"required":    true,
+				"description": "Name of the container without its leading slash, e.g. docker/abc. Slashes may be escaped.",
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, param := range op.params {
+			params = append(params, map[string]interface{}{
+				"name":        param.name,
+				"in":          "query",
+				"description": param.description,
+				"schema":      map[string]interface{}{"type": param.typ},
+			})
+		}
+		response := map[string]interface{}{"description": "OK"}
+		switch {
+		case op.stream != "":
+			response["description"] = op.stream
+		case op.response != nil:
+			schema := generator.schemaOf(reflect.TypeOf(op.response))
+			response["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schema},
+				msgpackContentType: map[string]interface{}{"schema": schema},
+			}
+		}
+		operation := map[string]interface{}{
+			"operationId": strings.ToLower(op.method) + "_" + op.requestType,
+			"summary":     op.summary,
+			"responses": map[string]interface{}{
+				"200": response,
+				"default": map[string]interface{}{
+					"description": "Error, with its message and HTTP status code.",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": errorSchema},
+					},
+				},
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		item, ok := paths[p].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[p] = item
+		}
+		item[strings.ToLower(op.method)] = operation
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "cAdvisor API",
+			"version": apiVersion + " (cAdvisor " + version.VERSION + ")",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": generator.schemas},
+	}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Generates the JSON schemas of Go types as encoded by encoding/json. Named
+// structs are added to schemas, keyed by package and type name, and
+// referenced.
+type schemaGenerator struct {
+	schemas map[string]interface{}
+}
+
+func (self *schemaGenerator) schemaOf(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Duration in nanoseconds."}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return self.schemaOf(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": self.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": self.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return self.structSchema(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := self.schemas[name]; !ok {
+			// Registered before its fields for recursive types.
+			self.schemas[name] = nil
+			self.schemas[name] = self.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	// Interfaces hold any value.
+	return map[string]interface{}{}
+}
+
+func (self *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	self.addFields(t, properties, &required)
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// Adds the properties of the exported fields of a struct, including the ones
+// of its embedded structs. Fields without omitempty are always encoded.
+func (self *schemaGenerator) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			self.addFields(fieldType, properties, required)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema := self.schemaOf(field.Type)
+		omitEmpty := false
+		for _, option := range parts[1:] {
+			if option == "omitempty" {
+				omitEmpty = true
+			}
+		}
+		if !omitEmpty {
+			*required = append(*required, name)
+			switch field.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+				if _, ok := schema["$ref"]; ok {
+					// Siblings of $ref are ignored.
+					schema = map[string]interface{}{"allOf": []interface{}{schema}}
+				}
+				schema["nullable"] = true
+			}
+		}
+		properties[name] = schema
+	}
+}
+
+// Serves the OpenAPI document of the v2.1 API.
+func handleSchemaRequest(w http.ResponseWriter, r *http.Request) error {
+	return writeResult(buildSchema("v2.1", v2_1Operations), w, r)
+}
@@ -15,14 +15,24 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/google/cadvisor/events"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/google/cadvisor/info/v2"
 	"github.com/google/cadvisor/manager"
+	"github.com/google/cadvisor/metrics"
+	"github.com/google/cadvisor/utils/websocket"
 )
 
 const (
@@ -37,6 +47,25 @@ const (
 	storageApi       = "storage"
 	attributesApi    = "attributes"
 	versionApi       = "version"
+	eventsWsApi      = "eventsws"
+	byLabelApi       = "bylabel"
+	housekeepingApi  = "housekeeping"
+	batchApi         = "batch"
+	ratesApi         = "rates"
+	latestApi        = "latest"
+	processesApi     = "processes"
+	healthApi        = "health"
+	statsStreamApi   = "statsstream"
+	resolveApi       = "resolve"
+	topApi           = "top"
+	schemaApi        = "schema"
+	prometheusApi    = "prometheus"
+	specsApi         = "specs"
+	shareApi         = "share"
+	cgroupApi        = "cgroup"
+	annotationsApi   = "annotations"
+	imagesApi        = "images"
+	topologyApi      = "topologystream"
 )
 
 // Interface for a cAdvisor API version
@@ -58,8 +87,9 @@ func getApiVersions() []ApiVersion {
 	v1_2 := newVersion1_2(v1_1)
 	v1_3 := newVersion1_3(v1_2)
 	v2_0 := newVersion2_0()
+	v2_1 := newVersion2_1(v2_0)
 
-	return []ApiVersion{v1_0, v1_1, v1_2, v1_3, v2_0}
+	return []ApiVersion{v1_0, v1_1, v1_2, v1_3, v2_0, v2_1}
 
 }
 
@@ -87,7 +117,7 @@ func (self *version1_0) HandleRequest(requestType string, request []string, m ma
 			return err
 		}
 
-		err = writeResult(machineInfo, w)
+		err = writeResult(machineInfo, w, r)
 		if err != nil {
 			return err
 		}
@@ -108,12 +138,12 @@ func (self *version1_0) HandleRequest(requestType string, request []string, m ma
 		}
 
 		// Only output the container as JSON.
-		err = writeResult(cont, w)
+		err = writeResult(cont, w, r)
 		if err != nil {
 			return err
 		}
 	default:
-		return fmt.Errorf("unknown request type %q", requestType)
+		return notFoundError("unknown request type %q", requestType)
 	}
 	return nil
 }
@@ -158,7 +188,7 @@ func (self *version1_1) HandleRequest(requestType string, request []string, m ma
 		}
 
 		// Only output the containers as JSON.
-		err = writeResult(containers, w)
+		err = writeResult(containers, w, r)
 		if err != nil {
 			return err
 		}
@@ -216,6 +246,9 @@ func (self *version1_2) HandleRequest(requestType string, request []string, m ma
 			// Get one Docker container.
 			var cont info.ContainerInfo
 			cont, err = m.DockerContainer(request[0], query)
+			if _, ok := err.(*manager.ContainerNotFoundError); ok {
+				err = fmt.Errorf("unable to find Docker container %q", request[0])
+			}
 			if err != nil {
 				return fmt.Errorf("failed to get Docker container %q with error: %v", request[0], err)
 			}
@@ -223,11 +256,11 @@ func (self *version1_2) HandleRequest(requestType string, request []string, m ma
 				cont.Name: cont,
 			}
 		default:
-			return fmt.Errorf("unknown request for Docker container %v", request)
+			return badRequestError("unknown request for Docker container %v", request)
 		}
 
 		// Only output the containers as JSON.
-		err = writeResult(containers, w)
+		err = writeResult(containers, w, r)
 		if err != nil {
 			return err
 		}
@@ -274,20 +307,62 @@ func handleEventRequest(m manager.Manager, w http.ResponseWriter, r *http.Reques
 	}
 	glog.V(2).Infof("Api - Events(%v)", query)
 	if eventsFromAllTime {
-		pastEvents, err := m.GetPastEvents(query)
+		// The total is the number of events matched before paging.
+		unpaged := *query
+		unpaged.Offset = 0
+		unpaged.Limit = 0
+		allEvents, err := m.GetPastEvents(&unpaged)
 		if err != nil {
 			return err
 		}
-		return writeResult(pastEvents, w)
+		pastEvents := query.Page(allEvents)
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(allEvents)))
+		// Cursor to poll for the events following the returned ones with
+		// "since". Sequence numbers from another epoch are not carried over.
+		epoch := m.GetEventsEpoch()
+		var cursor uint64
+		if query.SinceEpoch == epoch {
+			cursor = query.Since
+		}
+		for _, e := range pastEvents {
+			if e.Sequence > cursor {
+				cursor = e.Sequence
+			}
+		}
+		w.Header().Set("X-Events-Cursor", formatEventsCursor(epoch, cursor))
+		return writeResult(pastEvents, w, r)
 	}
 	eventChannel, err := m.WatchForEvents(query)
 	if err != nil {
 		return err
 	}
+	if r.URL.Query().Get("format") == "sse" {
+		return streamResultsAsSse(eventChannel, w, r, m)
+	}
 	return streamResults(eventChannel, w, r, m)
 
 }
 
+// Deletes the past events matching the request. Events of all types are
+// deleted unless some are selected.
+func handleDeleteEventsRequest(m manager.Manager, w http.ResponseWriter, r *http.Request) error {
+	query, _, err := getEventRequest(r)
+	if err != nil {
+		return err
+	}
+	if len(query.EventType) == 0 {
+		for _, eventType := range []events.EventType{events.TypeOom, events.TypeContainerCreation, events.TypeContainerDeletion, events.TypeRestartLoop} {
+			query.EventType[eventType] = true
+		}
+	}
+	glog.V(2).Infof("Api - Events: Deleting events %v", query)
+	count, err := m.DeleteEvents(query)
+	if err != nil {
+		return err
+	}
+	return writeResult(v2.DeletedEvents{Count: count}, w, r)
+}
+
 // API v2.0
 
 type version2_0 struct {
@@ -317,7 +392,7 @@ func (self *version2_0) HandleRequest(requestType string, request []string, m ma
 		if err != nil {
 			return err
 		}
-		return writeResult(versionInfo.CadvisorVersion, w)
+		return writeResult(versionInfo.CadvisorVersion, w, r)
 	case attributesApi:
 		glog.V(2).Info("Api - Attributes")
 
@@ -330,7 +405,7 @@ func (self *version2_0) HandleRequest(requestType string, request []string, m ma
 			return err
 		}
 		info := v2.GetAttributes(machineInfo, versionInfo)
-		return writeResult(info, w)
+		return writeResult(info, w, r)
 	case machineApi:
 		glog.V(2).Info("Api - Machine")
 
@@ -339,7 +414,7 @@ func (self *version2_0) HandleRequest(requestType string, request []string, m ma
 		if err != nil {
 			return err
 		}
-		return writeResult(machineInfo, w)
+		return writeResult(machineInfo, w, r)
 	case summaryApi:
 		containerName := getContainerName(request)
 		glog.V(2).Infof("Api - Summary for container %q, options %+v", containerName, opt)
@@ -348,19 +423,40 @@ func (self *version2_0) HandleRequest(requestType string, request []string, m ma
 		if err != nil {
 			return err
 		}
-		return writeResult(stats, w)
+		for name, derived := range stats {
+			for _, headroom := range []*float64{derived.Headroom.Cpu, derived.Headroom.Memory} {
+				if headroom != nil {
+					roundFloats(headroom)
+				}
+			}
+			stats[name] = derived
+		}
+		return writeResult(stats, w, r)
 	case statsApi:
 		name := getContainerName(request)
 		glog.V(2).Infof("Api - Stats: Looking for stats for container %q, options %+v", name, opt)
-		conts, err := m.GetRequestedContainersInfo(name, opt)
+		unit, err := getStatsUnit(r)
 		if err != nil {
 			return err
 		}
-		contStats := make(map[string][]v2.ContainerStats, 0)
-		for name, cont := range conts {
-			contStats[name] = convertStats(cont)
+		contStats, err := getStats(m, name, opt, r)
+		if err != nil {
+			return err
 		}
-		return writeResult(contStats, w)
+		for _, stats := range contStats {
+			for i := range stats {
+				convertStatsUnit(&stats[i], unit)
+			}
+		}
+		fields := getStatsFields(r)
+		if len(fields) == 0 {
+			return writeResult(contStats, w, r)
+		}
+		pruned, err := pruneStatsFields(contStats, fields, r)
+		if err != nil {
+			return err
+		}
+		return writeResult(pruned, w, r)
 	case specApi:
 		containerName := getContainerName(request)
 		glog.V(2).Infof("Api - Spec for container %q, options %+v", containerName, opt)
@@ -368,7 +464,7 @@ func (self *version2_0) HandleRequest(requestType string, request []string, m ma
 		if err != nil {
 			return err
 		}
-		return writeResult(specs, w)
+		return writeResult(specs, w, r)
 	case storageApi:
 		var err error
 		fi := []v2.FsInfo{}
@@ -386,47 +482,837 @@ func (self *version2_0) HandleRequest(requestType string, request []string, m ma
 				return err
 			}
 		}
-		return writeResult(fi, w)
+		return writeResult(fi, w, r)
 	case eventsApi:
 		return handleEventRequest(m, w, r)
 	default:
-		return fmt.Errorf("unknown request type %q", requestType)
+		return notFoundError("unknown request type %q", requestType)
 	}
 }
 
-func convertStats(cont *info.ContainerInfo) []v2.ContainerStats {
-	stats := []v2.ContainerStats{}
-	for _, val := range cont.Stats {
-		stat := v2.ContainerStats{
-			Timestamp:     val.Timestamp,
-			HasCpu:        cont.Spec.HasCpu,
-			HasMemory:     cont.Spec.HasMemory,
-			HasNetwork:    cont.Spec.HasNetwork,
-			HasFilesystem: cont.Spec.HasFilesystem,
-			HasDiskIo:     cont.Spec.HasDiskIo,
+// Returns the stats of the requested containers, or the ones of the requested
+// container summed with its subcontainers when asked with "aggregate=sum".
+func getStats(m manager.Manager, name string, opt v2.RequestOptions, r *http.Request) (map[string][]v2.ContainerStats, error) {
+	contStats := make(map[string][]v2.ContainerStats, 0)
+	switch aggregate := r.URL.Query().Get("aggregate"); aggregate {
+	case "":
+		conts, err := m.GetRequestedContainersInfo(name, opt)
+		if err != nil {
+			return nil, err
 		}
-		if stat.HasCpu {
-			stat.Cpu = val.Cpu
+		for name, cont := range conts {
+			contStats[name] = convertStats(cont)
 		}
-		if stat.HasMemory {
-			stat.Memory = val.Memory
+	case "sum":
+		if opt.Recursive {
+			return nil, badRequestError("'aggregate' already includes subcontainers, it cannot be combined with 'recursive'")
 		}
-		if stat.HasNetwork {
-			// TODO(rjnagal): Return stats about all network interfaces.
-			stat.Network = append(stat.Network, val.Network)
+		cont, err := m.GetAggregatedContainerInfo(name, opt)
+		if err != nil {
+			return nil, err
 		}
-		if stat.HasFilesystem {
-			stat.Filesystem = val.Filesystem
+		contStats[cont.Name] = convertStats(cont)
+	default:
+		return nil, badRequestError("unknown 'aggregate' option %q, only \"sum\" is supported", aggregate)
+	}
+	return contStats, nil
+}
+
+// API v2.1
+
+type version2_1 struct {
+	baseVersion *version2_0
+}
+
+// v2.1 builds on v2.0.
+func newVersion2_1(v *version2_0) *version2_1 {
+	return &version2_1{
+		baseVersion: v,
+	}
+}
+
+func (self *version2_1) Version() string {
+	return "v2.1"
+}
+
+func (self *version2_1) SupportedRequestTypes() []string {
+	// attributes is already supported by v2.0.
+	return append(self.baseVersion.SupportedRequestTypes(), eventsWsApi, byLabelApi, housekeepingApi, batchApi, ratesApi, latestApi, processesApi, healthApi, statsStreamApi, resolveApi, topApi, schemaApi, prometheusApi, specsApi, shareApi, cgroupApi, annotationsApi, imagesApi, topologyApi)
+}
+
+func (self *version2_1) HandleRequest(requestType string, request []string, m manager.Manager, w http.ResponseWriter, r *http.Request) error {
+	switch requestType {
+	case attributesApi:
+		glog.V(2).Info("Api - Attributes")
+
+		attributes, err := m.GetAttributes()
+		if err != nil {
+			return err
+		}
+		return writeResult(attributes, w, r)
+	case eventsWsApi:
+		return handleEventWebSocketRequest(m, w, r)
+	case byLabelApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
 		}
-		if stat.HasDiskIo {
-			stat.DiskIo = val.DiskIo
+		selector, prefixMatch, err := getLabelSelector(r)
+		if err != nil {
+			return err
+		}
+		unit, err := getStatsUnit(r)
+		if err != nil {
+			return err
+		}
+		glog.V(2).Infof("Api - Stats: Looking for stats for containers with labels %v (prefix match: %v), options %+v", selector, prefixMatch, opt)
+		conts, err := m.GetContainersInfoByLabels(selector, prefixMatch, opt)
+		if err != nil {
+			return err
+		}
+		contStats := make(map[string][]v2.ContainerStats, len(conts))
+		for name, cont := range conts {
+			stats := convertStats(cont)
+			for i := range stats {
+				convertStatsUnit(&stats[i], unit)
+			}
+			contStats[name] = stats
+		}
+		return writeResult(contStats, w, r)
+	case batchApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
+		}
+		unit, err := getStatsUnit(r)
+		if err != nil {
+			return err
+		}
+		names, err := getBatchContainerNames(r.Body)
+		if err != nil {
+			return err
+		}
+		glog.V(2).Infof("Api - Batch: Looking for stats for containers %v, options %+v", names, opt)
+		batchStats := getBatchStats(names, opt, m)
+		for _, batch := range batchStats {
+			for i := range batch.Stats {
+				convertStatsUnit(&batch.Stats[i], unit)
+			}
+		}
+		return writeResult(batchStats, w, r)
+	case latestApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
+		}
+		unit, err := getStatsUnit(r)
+		if err != nil {
+			return err
+		}
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Latest: Looking for the latest stats of container %q, options %+v", name, opt)
+		conts, err := m.GetLatestContainersInfo(name, opt)
+		if err != nil {
+			return err
+		}
+		latest := make(map[string]v2.ContainerStats, len(conts))
+		for name, cont := range conts {
+			stat := convertStat(&cont.Spec, cont.Stats[0])
+			convertStatsUnit(&stat, unit)
+			latest[name] = stat
+		}
+		return writeResult(latest, w, r)
+	case topApi:
+		by, limit, err := getTopRequest(r)
+		if err != nil {
+			return err
+		}
+		glog.V(4).Infof("Api - Top: Looking for the %d containers with the highest %s", limit, by)
+		top, err := m.GetTopContainers(by, limit)
+		if err != nil {
+			return err
+		}
+		return writeResult(top, w, r)
+	case imagesApi:
+		glog.V(4).Infof("Api - Images")
+		images, err := m.GetImageUsage()
+		if err != nil {
+			return err
+		}
+		return writeResult(images, w, r)
+	case schemaApi:
+		glog.V(4).Infof("Api - Schema")
+		return handleSchemaRequest(w, r)
+	case specsApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
+		}
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Specs: Looking for the specs of container %q, options %+v", name, opt)
+		specs, err := m.GetContainerSpecs(name, opt)
+		if err != nil {
+			return err
+		}
+		return writeResult(specs, w, r)
+	case prometheusApi:
+		name := getContainerName(request)
+		glog.V(4).Infof("Api - Prometheus: Exporting the metrics of container %q", name)
+		return handlePrometheusRequest(m, name, w)
+	case cgroupApi:
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Cgroup: Dumping the cgroup settings of container %q", name)
+		config, err := m.GetCgroupConfig(name)
+		if err != nil {
+			return err
+		}
+		return writeResult(config, w, r)
+	case healthApi:
+		glog.V(4).Infof("Api - Health")
+		return writeResult(m.GetCollectionHealth(), w, r)
+	case resolveApi:
+		query := r.URL.Query()
+		cgroupPath, name := query.Get("cgroup"), query.Get("name")
+		var resolved v2.ResolvedContainer
+		var err error
+		switch {
+		case cgroupPath != "" && name == "":
+			glog.V(4).Infof("Api - Resolve: Looking for the container of cgroup %q", cgroupPath)
+			resolved, err = m.ResolveCgroup(cgroupPath)
+		case name != "" && cgroupPath == "":
+			glog.V(4).Infof("Api - Resolve: Looking for the container named %q", name)
+			resolved, err = m.ResolveName(name)
+		default:
+			return badRequestError("exactly one of 'cgroup' and 'name' must be specified")
+		}
+		if err != nil {
+			return err
+		}
+		return writeResult(resolved, w, r)
+	case statsStreamApi:
+		interval, err := getStreamInterval(r)
+		if err != nil {
+			return err
+		}
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Stats stream: Streaming stats of container %q every %v", name, interval)
+		statsChannel, err := m.WatchStats(name)
+		if err != nil {
+			return err
+		}
+		return streamStats(statsChannel, interval, w, r, m)
+	case topologyApi:
+		glog.V(2).Infof("Api - Topology stream")
+		topologyChannel, err := m.WatchTopology()
+		if err != nil {
+			return err
+		}
+		return streamTopology(topologyChannel, w, r, m)
+	case processesApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
+		}
+		sortBy, limit, err := getProcessListOptions(r)
+		if err != nil {
+			return err
+		}
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Processes: Listing processes of container %q sorted by %s, options %+v", name, sortBy, opt)
+		processes, err := m.GetProcessList(name, opt)
+		if err != nil {
+			return err
+		}
+		return writeResult(topProcesses(processes, sortBy, limit), w, r)
+	case ratesApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
+		}
+		// Rates are computed from the two most recent samples.
+		opt.Count = 2
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Rates: Computing rates for container %q, options %+v", name, opt)
+		conts, err := m.GetRequestedContainersInfo(name, opt)
+		if err != nil {
+			return err
+		}
+		rates := make(map[string]v2.ContainerRates, len(conts))
+		for name, cont := range conts {
+			if len(cont.Stats) < 2 {
+				// Not enough samples yet.
+				continue
+			}
+			rate := computeRates(cont.Stats[len(cont.Stats)-2], cont.Stats[len(cont.Stats)-1])
+			roundRates(&rate)
+			rates[name] = rate
+		}
+		return writeResult(rates, w, r)
+	case shareApi:
+		opt, err := getRequestOptions(r)
+		if err != nil {
+			return err
+		}
+		// The CPU usage is computed from the two most recent samples.
+		opt.Count = 2
+		name := getContainerName(request)
+		glog.V(2).Infof("Api - Share: Computing the share of machine resources of container %q, options %+v", name, opt)
+		machineInfo, err := m.GetMachineInfo()
+		if err != nil {
+			return err
+		}
+		conts, err := m.GetRequestedContainersInfo(name, opt)
+		if err != nil {
+			return err
+		}
+		shares := make(map[string]v2.ContainerShare, len(conts))
+		for name, cont := range conts {
+			if len(cont.Stats) < 2 {
+				// Not enough samples yet.
+				continue
+			}
+			share := computeShare(cont.Stats[len(cont.Stats)-2], cont.Stats[len(cont.Stats)-1], machineInfo)
+			roundFloats(&share.Cpu, &share.Memory, &share.MemoryWorkingSet)
+			shares[name] = share
+		}
+		return writeResult(shares, w, r)
+	case housekeepingApi:
+		if r.Method != "PUT" {
+			return &httpError{
+				status: http.StatusMethodNotAllowed,
+				err:    fmt.Errorf("housekeeping intervals can only be set with PUT, not %s", r.Method),
+			}
+		}
+		containerName := getContainerName(request)
+		interval, err := getHousekeepingInterval(r.Body)
+		if err != nil {
+			return err
+		}
+		glog.V(2).Infof("Api - Housekeeping: Setting interval of container %q to %v", containerName, interval)
+		interval, err = m.SetHousekeepingInterval(containerName, interval)
+		if err != nil {
+			return err
+		}
+		return writeResult(v2.HousekeepingInterval{IntervalMs: int64(interval / time.Millisecond)}, w, r)
+	case annotationsApi:
+		if r.Method != "PUT" {
+			return &httpError{
+				status: http.StatusMethodNotAllowed,
+				err:    fmt.Errorf("annotations can only be set with PUT, not %s", r.Method),
+			}
+		}
+		containerName := getContainerName(request)
+		annotations, err := getAnnotations(r.Body)
+		if err != nil {
+			return err
+		}
+		glog.V(2).Infof("Api - Annotations: Setting annotations of container %q to %v", containerName, annotations)
+		if err := m.SetAnnotations(containerName, annotations); err != nil {
+			return err
+		}
+		return writeResult(annotations, w, r)
+	case eventsApi:
+		if r.Method != "DELETE" {
+			return self.baseVersion.HandleRequest(requestType, request, m, w, r)
+		}
+		return handleDeleteEventsRequest(m, w, r)
+	default:
+		return self.baseVersion.HandleRequest(requestType, request, m, w, r)
+	}
+}
+
+// Writes the Prometheus metrics of a single container, the ones it adds to
+// those of the Prometheus endpoint.
+func handlePrometheusRequest(m manager.Manager, name string, w http.ResponseWriter) error {
+	// Containers which do not exist would have no metrics rather than fail.
+	resolved, err := m.ResolveName(name)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	collector := metrics.NewPrometheusCollector(m)
+	if err := collector.WriteText(&buf, metrics.ContainerNameFilter(resolved.Name)); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", metrics.TextContentType)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Parses the "sort" ("cpu" or "memory") and "limit" parameters of a process list request.
+func getProcessListOptions(r *http.Request) (string, int, error) {
+	query := r.URL.Query()
+	sortBy := query.Get("sort")
+	switch sortBy {
+	case "":
+		sortBy = "cpu"
+	case "cpu", "memory":
+	default:
+		return "", 0, badRequestError("unknown 'sort' %q, expected cpu or memory", sortBy)
+	}
+	limit := 0
+	if l := query.Get("limit"); l != "" {
+		n, err := strconv.ParseUint(l, 10, 32)
+		if err != nil {
+			return "", 0, badRequestError("failed to parse 'limit' option: %v", l)
+		}
+		limit = int(n)
+	}
+	return sortBy, limit, nil
+}
+
+type processesByCpu []v2.ProcessInfo
+
+func (s processesByCpu) Len() int           { return len(s) }
+func (s processesByCpu) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s processesByCpu) Less(i, j int) bool { return s[i].CpuPercent > s[j].CpuPercent }
+
+type processesByMemory []v2.ProcessInfo
+
+func (s processesByMemory) Len() int           { return len(s) }
+func (s processesByMemory) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s processesByMemory) Less(i, j int) bool { return s[i].Rss > s[j].Rss }
+
+// Returns the top limit consumers of the processes, all of them if limit is 0.
+func topProcesses(processes []v2.ProcessInfo, sortBy string, limit int) []v2.ProcessInfo {
+	if sortBy == "memory" {
+		sort.Stable(processesByMemory(processes))
+	} else {
+		sort.Stable(processesByCpu(processes))
+	}
+	if limit > 0 && limit < len(processes) {
+		processes = processes[:limit]
+	}
+	return processes
+}
+
+// Number of containers returned by top requests without a limit.
+const defaultTopLimit = 10
+
+// Returns the metric to rank containers by and the number of containers to
+// return of a top request.
+func getTopRequest(r *http.Request) (string, int, error) {
+	query := r.URL.Query()
+	by := query.Get("by")
+	switch by {
+	case v2.TopByCpu, v2.TopByMemory, v2.TopByNetworkRx, v2.TopByDiskIo:
+	default:
+		return "", 0, badRequestError("unknown 'by' option %q, expected one of %q", by, []string{v2.TopByCpu, v2.TopByMemory, v2.TopByNetworkRx, v2.TopByDiskIo})
+	}
+	limit := defaultTopLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.ParseUint(value, 10, 31)
+		if err != nil {
+			return "", 0, badRequestError("failed to parse 'limit' option %q: %v", value, err)
+		}
+		limit = int(n)
+	}
+	return by, limit, nil
+}
+
+// Computes the per-second rates between two samples. Counters that went
+// backwards, e.g. after a restart, are reported as a zero rate.
+func computeRates(prev, cur *info.ContainerStats) v2.ContainerRates {
+	interval := cur.Timestamp.Sub(prev.Timestamp)
+	rates := v2.ContainerRates{
+		Timestamp: cur.Timestamp,
+		Interval:  interval,
+	}
+	if interval <= 0 {
+		return rates
+	}
+	rate := func(prev, cur uint64) float64 {
+		if cur < prev {
+			return 0
+		}
+		return float64(cur-prev) / interval.Seconds()
+	}
+	// CPU usage is in nanoseconds.
+	rates.CpuUsage = rate(prev.Cpu.Usage.Total, cur.Cpu.Usage.Total) / float64(time.Second)
+	rates.CpuUser = rate(prev.Cpu.Usage.User, cur.Cpu.Usage.User) / float64(time.Second)
+	rates.CpuSystem = rate(prev.Cpu.Usage.System, cur.Cpu.Usage.System) / float64(time.Second)
+	if cur.Cpu.NrPeriods > prev.Cpu.NrPeriods && cur.Cpu.NrThrottled >= prev.Cpu.NrThrottled {
+		rates.CpuThrottledFraction = float64(cur.Cpu.NrThrottled-prev.Cpu.NrThrottled) / float64(cur.Cpu.NrPeriods-prev.Cpu.NrPeriods)
+	}
+
+	rates.RxBytes = rate(prev.Network.RxBytes, cur.Network.RxBytes)
+	rates.RxPackets = rate(prev.Network.RxPackets, cur.Network.RxPackets)
+	rates.TxBytes = rate(prev.Network.TxBytes, cur.Network.TxBytes)
+	rates.TxPackets = rate(prev.Network.TxPackets, cur.Network.TxPackets)
+
+	rates.DiskReadBytes = rate(sumDiskStat(prev.DiskIo.IoServiceBytes, "Read"), sumDiskStat(cur.DiskIo.IoServiceBytes, "Read"))
+	rates.DiskWriteBytes = rate(sumDiskStat(prev.DiskIo.IoServiceBytes, "Write"), sumDiskStat(cur.DiskIo.IoServiceBytes, "Write"))
+	rates.DiskReads = rate(sumDiskStat(prev.DiskIo.IoServiced, "Read"), sumDiskStat(cur.DiskIo.IoServiced, "Read"))
+	rates.DiskWrites = rate(sumDiskStat(prev.DiskIo.IoServiced, "Write"), sumDiskStat(cur.DiskIo.IoServiced, "Write"))
+	return rates
+}
+
+// Returns the share of the resources of the machine used by a container, from
+// its two most recent stats samples.
+func computeShare(prev, cur *info.ContainerStats, machineInfo *info.MachineInfo) v2.ContainerShare {
+	share := v2.ContainerShare{Timestamp: cur.Timestamp}
+	if machineInfo.NumCores > 0 {
+		share.Cpu = computeRates(prev, cur).CpuUsage / float64(machineInfo.NumCores)
+	}
+	if machineInfo.MemoryCapacity > 0 {
+		share.Memory = float64(cur.Memory.Usage) / float64(machineInfo.MemoryCapacity)
+		share.MemoryWorkingSet = float64(cur.Memory.WorkingSet) / float64(machineInfo.MemoryCapacity)
+	}
+	return share
+}
+
+// Sums a blkio stat (e.g. "Read") over all the disks.
+func sumDiskStat(disks []info.PerDiskStats, stat string) uint64 {
+	sum := uint64(0)
+	for _, disk := range disks {
+		sum += disk.Stats[stat]
+	}
+	return sum
+}
+
+// Decodes the container names of a batch request, e.g. {"container_names":["/a","/b"]}.
+func getBatchContainerNames(body io.Reader) ([]string, error) {
+	var req v2.BatchRequest
+	err := json.NewDecoder(body).Decode(&req)
+	if err != nil {
+		return nil, badRequestError("unable to decode the batch request: %v", err)
+	}
+	if len(req.ContainerNames) == 0 {
+		return nil, badRequestError("at least one container name must be specified")
+	}
+	if len(req.ContainerNames) > *maxBatchSize {
+		return nil, badRequestError("too many containers requested: %d, the maximum is %d", len(req.ContainerNames), *maxBatchSize)
+	}
+	return req.ContainerNames, nil
+}
+
+// Gets the stats of each of the containers. Failures are reported for the
+// container they happened for, they do not fail the other containers.
+func getBatchStats(names []string, opt v2.RequestOptions, m manager.Manager) map[string]v2.BatchStats {
+	result := make(map[string]v2.BatchStats, len(names))
+	for _, name := range names {
+		conts, err := m.GetRequestedContainersInfo(name, opt)
+		if err != nil {
+			result[name] = v2.BatchStats{Error: err.Error()}
+			continue
+		}
+		for contName, cont := range conts {
+			result[contName] = v2.BatchStats{Stats: convertStats(cont)}
+		}
+	}
+	return result
+}
+
+// Parses the minimum interval between the stats of a stats stream, e.g.
+// "interval=10s". Defaults to streaming all the stats.
+func getStreamInterval(r *http.Request) (time.Duration, error) {
+	param := r.URL.Query().Get("interval")
+	if param == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(param)
+	if err != nil {
+		return 0, badRequestError("failed to parse 'interval' option: %v", param)
+	}
+	if interval < 0 {
+		return 0, badRequestError("invalid negative 'interval' %v", interval)
+	}
+	return interval, nil
+}
+
+// Decodes the interval of a housekeeping request, e.g. {"interval_ms":500}.
+func getHousekeepingInterval(body io.Reader) (time.Duration, error) {
+	var req v2.HousekeepingInterval
+	err := json.NewDecoder(body).Decode(&req)
+	if err != nil {
+		return 0, badRequestError("unable to decode the housekeeping interval: %v", err)
+	}
+	if req.IntervalMs < 0 {
+		return 0, badRequestError("invalid negative housekeeping interval %dms", req.IntervalMs)
+	}
+	return time.Duration(req.IntervalMs) * time.Millisecond, nil
+}
+
+// Parses the annotations of a container from a JSON object of strings.
+func getAnnotations(body io.Reader) (map[string]string, error) {
+	annotations := map[string]string{}
+	if err := json.NewDecoder(body).Decode(&annotations); err != nil {
+		return nil, badRequestError("unable to decode the annotations: %v", err)
+	}
+	for key := range annotations {
+		if key == "" {
+			return nil, badRequestError("invalid empty annotation key")
+		}
+	}
+	return annotations, nil
+}
+
+// Parses the repeated "label=<key>=<value>" parameters of the request into a
+// selector, and whether values are prefix matched ("label_match=prefix").
+func getLabelSelector(r *http.Request) (map[string]string, bool, error) {
+	query := r.URL.Query()
+	labels := query["label"]
+	if len(labels) == 0 {
+		return nil, false, badRequestError("at least one 'label' must be specified")
+	}
+	selector := make(map[string]string, len(labels))
+	for _, label := range labels {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, false, badRequestError("invalid 'label' %q, expected <key>=<value>", label)
+		}
+		if value, ok := selector[parts[0]]; ok && value != parts[1] {
+			return nil, false, badRequestError("conflicting values %q and %q for label %q", value, parts[1], parts[0])
+		}
+		selector[parts[0]] = parts[1]
+	}
+
+	switch match := query.Get("label_match"); match {
+	case "", "exact":
+		return selector, false, nil
+	case "prefix":
+		return selector, true, nil
+	default:
+		return nil, false, badRequestError("unknown 'label_match' %q", match)
+	}
+}
+
+func handleEventWebSocketRequest(m manager.Manager, w http.ResponseWriter, r *http.Request) error {
+	query, _, err := getEventRequest(r)
+	if err != nil {
+		return err
+	}
+	glog.V(2).Infof("Api - Events over WebSocket(%v)", query)
+	if !websocket.IsWebSocketRequest(r) {
+		return badRequestError("request for %q is not a WebSocket upgrade", r.URL.Path)
+	}
+	eventChannel, err := m.WatchForEvents(query)
+	if err != nil {
+		return err
+	}
+	return streamResultsOverWebSocket(eventChannel, query.MaxEventsReturned, w, r, m)
+}
+
+// JSON keys of each section of v2.ContainerStats that can be selected with the
+// "fields" parameter.
+var statsFieldKeys = map[string][]string{
+	"cpu":            {"has_cpu", "cpu"},
+	"diskio":         {"has_diskio", "diskio"},
+	"memory":         {"has_memory", "memory"},
+	"network":        {"has_network", "network"},
+	"filesystem":     {"has_filesystem", "filesystem"},
+	"load":           {"has_load", "load_stats"},
+	"psi":            {"psi"},
+	"accelerators":   {"accelerators"},
+	"custom_metrics": {"custom_metrics"},
+	"processes":      {"processes"},
+	"pids":           {"pids"},
+}
+
+// Returns the stats sections selected with the "fields" parameter, e.g.
+// "fields=cpu,memory". Sections can also be named after their key in the
+// responses, in snake_case or not, e.g. "load_stats" or "CustomMetrics".
+// Unknown sections are ignored.
+func getStatsFields(r *http.Request) []string {
+	fields := []string{}
+	for _, field := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		field = toSnakeCase(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		section, ok := statsSection(field)
+		if !ok {
+			glog.Warningf("Ignoring unknown stats field %q", field)
+			continue
+		}
+		fields = append(fields, section)
+	}
+	return fields
+}
+
+// Returns the stats section of the given name or JSON key.
+func statsSection(field string) (string, bool) {
+	if _, ok := statsFieldKeys[field]; ok {
+		return field, true
+	}
+	for section, keys := range statsFieldKeys {
+		if keys[len(keys)-1] == field {
+			return section, true
+		}
+	}
+	return "", false
+}
+
+// Removes all but the timestamp and the selected sections from the stats.
+// The pruning is done on the JSON objects since the cpu, memory, diskio and
+// load sections are always serialized, even when empty. The stats are named
+// as in the response to r, e.g. in snake_case, before being pruned.
+func pruneStatsFields(contStats map[string][]v2.ContainerStats, fields []string, r *http.Request) (map[string][]map[string]json.RawMessage, error) {
+	keep := map[string]bool{"timestamp": true}
+	for _, field := range fields {
+		for _, key := range statsFieldKeys[field] {
+			keep[key] = true
+		}
+	}
+
+	pruned := make(map[string][]map[string]json.RawMessage, len(contStats))
+	for name, stats := range contStats {
+		prunedStats := make([]map[string]json.RawMessage, 0, len(stats))
+		for _, stat := range stats {
+			out, err := json.Marshal(jsonValue(stat, r))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshall stats %+v with error: %s", stat, err)
+			}
+			var sections map[string]json.RawMessage
+			err = json.Unmarshal(out, &sections)
+			if err != nil {
+				return nil, err
+			}
+			for key := range sections {
+				if !keep[toSnakeCase(key)] {
+					delete(sections, key)
+				}
+			}
+			prunedStats = append(prunedStats, sections)
+		}
+		pruned[name] = prunedStats
+	}
+	return pruned, nil
+}
+
+func convertStats(cont *info.ContainerInfo) []v2.ContainerStats {
+	stats := []v2.ContainerStats{}
+	for i, val := range cont.Stats {
+		stat := convertStat(&cont.Spec, val)
+		if stat.HasCpu && i > 0 {
+			// CPU usage per second, in cores.
+			stat.Cpu.CpuCores = computeRates(cont.Stats[i-1], val).CpuUsage
 		}
-		// TODO(rjnagal): Handle load stats.
 		stats = append(stats, stat)
 	}
 	return stats
 }
 
+func convertStat(spec *info.ContainerSpec, val *info.ContainerStats) v2.ContainerStats {
+	stat := v2.ContainerStats{
+		Timestamp:     val.Timestamp,
+		HasCpu:        spec.HasCpu,
+		HasMemory:     spec.HasMemory,
+		HasNetwork:    spec.HasNetwork,
+		HasFilesystem: spec.HasFilesystem,
+		HasDiskIo:     spec.HasDiskIo,
+	}
+	if stat.HasCpu {
+		stat.Cpu.CpuStats = val.Cpu
+	}
+	if stat.HasMemory {
+		stat.Memory = val.Memory
+	}
+	if stat.HasNetwork {
+		// TODO(rjnagal): Return stats about all network interfaces.
+		stat.Network = append(stat.Network, val.Network)
+	}
+	if stat.HasFilesystem {
+		stat.Filesystem = val.Filesystem
+	}
+	if stat.HasDiskIo {
+		stat.DiskIo = val.DiskIo
+	}
+	stat.Resolution = val.Resolution
+	stat.PSI = val.PSI
+	stat.Accelerators = val.Accelerators
+	stat.CustomMetrics = val.CustomMetrics
+	stat.Processes = val.Processes
+	stat.Pids = val.Pids
+	// TODO(rjnagal): Handle load stats.
+	return stat
+}
+
+// Units the memory and network byte fields of the stats can be converted to
+// with the "units" parameter, in bytes.
+var statsUnits = map[string]uint64{
+	"bytes": 1,
+	"KiB":   1 << 10,
+	"MiB":   1 << 20,
+}
+
+// Returns the unit selected with the "units" parameter, e.g. "units=MiB", in
+// bytes. Defaults to bytes.
+func getStatsUnit(r *http.Request) (uint64, error) {
+	param := r.URL.Query().Get("units")
+	if param == "" {
+		return 1, nil
+	}
+	unit, ok := statsUnits[param]
+	if !ok {
+		return 0, badRequestError("unknown 'units' %q, expected bytes, KiB or MiB", param)
+	}
+	return unit, nil
+}
+
+// Rounds the fields to -api_float_precision decimals, half away from zero.
+// Values too large to have decimals are left unchanged.
+func roundFloats(fields ...*float64) {
+	if *floatPrecision < 0 {
+		return
+	}
+	scale := math.Pow(10, float64(*floatPrecision))
+	for _, field := range fields {
+		scaled := math.Abs(*field) * scale
+		if scaled >= 1<<53 || math.IsNaN(scaled) {
+			continue
+		}
+		rounded := math.Floor(scaled+0.5) / scale
+		if *field < 0 {
+			rounded = -rounded
+		}
+		*field = rounded
+	}
+}
+
+func roundRates(rates *v2.ContainerRates) {
+	roundFloats(&rates.CpuUsage, &rates.CpuUser, &rates.CpuSystem, &rates.CpuThrottledFraction,
+		&rates.RxBytes, &rates.RxPackets, &rates.TxBytes, &rates.TxPackets,
+		&rates.DiskReadBytes, &rates.DiskWriteBytes, &rates.DiskReads, &rates.DiskWrites)
+}
+
+// Converts the memory and network byte fields of the stats to the unit, in
+// bytes, rounding down. The maps and slices shared with the stored stats are
+// copied before they are converted.
+func convertStatsUnit(stat *v2.ContainerStats, unit uint64) {
+	if unit == 1 {
+		return
+	}
+	memory := &stat.Memory
+	for _, field := range []*uint64{&memory.Usage, &memory.WorkingSet, &memory.Cache, &memory.RSS, &memory.Swap} {
+		*field /= unit
+	}
+	if memory.HugeTlb != nil {
+		hugeTlb := make(map[string]info.HugeTlbStats, len(memory.HugeTlb))
+		for size, stats := range memory.HugeTlb {
+			stats.Usage /= unit
+			stats.MaxUsage /= unit
+			hugeTlb[size] = stats
+		}
+		memory.HugeTlb = hugeTlb
+	}
+	for i := range stat.Network {
+		network := &stat.Network[i]
+		network.RxBytes /= unit
+		network.TxBytes /= unit
+		if network.Interfaces == nil {
+			continue
+		}
+		interfaces := make([]info.InterfaceStats, len(network.Interfaces))
+		for j, iface := range network.Interfaces {
+			iface.RxBytes /= unit
+			iface.TxBytes /= unit
+			interfaces[j] = iface
+		}
+		network.Interfaces = interfaces
+	}
+}
+
 func getRequestOptions(r *http.Request) (v2.RequestOptions, error) {
 	supportedTypes := map[string]bool{
 		v2.TypeName:   true,
@@ -441,7 +1327,7 @@ func getRequestOptions(r *http.Request) (v2.RequestOptions, error) {
 	idType := r.URL.Query().Get("type")
 	if len(idType) != 0 {
 		if !supportedTypes[idType] {
-			return opt, fmt.Errorf("unknown 'type' %q", idType)
+			return opt, badRequestError("unknown 'type' %q", idType)
 		}
 		opt.IdType = idType
 	}
@@ -449,7 +1335,7 @@ func getRequestOptions(r *http.Request) (v2.RequestOptions, error) {
 	if len(count) != 0 {
 		n, err := strconv.ParseUint(count, 10, 32)
 		if err != nil {
-			return opt, fmt.Errorf("failed to parse 'count' option: %v", count)
+			return opt, badRequestError("failed to parse 'count' option: %v", count)
 		}
 		opt.Count = int(n)
 	}
@@ -457,5 +1343,13 @@ func getRequestOptions(r *http.Request) (v2.RequestOptions, error) {
 	if recursive == "true" {
 		opt.Recursive = true
 	}
+	depth := r.URL.Query().Get("depth")
+	if len(depth) != 0 {
+		n, err := strconv.ParseUint(depth, 10, 32)
+		if err != nil {
+			return opt, badRequestError("failed to parse 'depth' option: %v", depth)
+		}
+		opt.Depth = int(n)
+	}
 	return opt, nil
 }
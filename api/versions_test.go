@@ -15,12 +15,24 @@
 package api
 
 import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/cadvisor/events"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+	"github.com/google/cadvisor/manager"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,3 +91,1184 @@ func TestGetEventRequestDoubleArgument(t *testing.T) {
 	assert.True(t, getHistoricalEvents)
 	assert.Nil(t, err)
 }
+
+func TestGetEventRequestContainerRegexp(t *testing.T) {
+	r := makeHTTPRequest("http://localhost:8080/api/v1.3/events?oom_events=true&container_regexp=^/docker/", t)
+
+	receivedQuery, _, err := getEventRequest(r)
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, receivedQuery.ContainerRegexp) {
+		assert.True(t, receivedQuery.ContainerRegexp.MatchString("/docker/abc"))
+		assert.False(t, receivedQuery.ContainerRegexp.MatchString("/system/docker"))
+	}
+}
+
+func TestGetEventRequestInvalidContainerRegexp(t *testing.T) {
+	r := makeHTTPRequest("http://localhost:8080/api/v1.3/events?container_regexp=(", t)
+
+	_, _, err := getEventRequest(r)
+
+	httpErr, ok := err.(*httpError)
+	if !ok {
+		t.Fatalf("expected an httpError but received %v", err)
+	}
+	assert.Equal(t, http.StatusBadRequest, httpErr.status)
+}
+
+func TestGetEventRequestSince(t *testing.T) {
+	r := makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&since=1433116800000000000-42", t)
+	query, _, err := getEventRequest(r)
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(1433116800000000000), query.SinceEpoch)
+		assert.Equal(t, uint64(42), query.Since)
+		assert.True(t, query.StartTime.IsZero())
+	}
+
+	r = makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&since=2015-06-01T00:00:00Z", t)
+	query, _, err = getEventRequest(r)
+	if assert.Nil(t, err) {
+		assert.Equal(t, uint64(0), query.Since)
+		assert.True(t, query.StartTime.Equal(time.Date(2015, 6, 1, 0, 0, 0, 1, time.UTC)))
+	}
+
+	for _, since := range []string{"yesterday", "42", "1-2-3"} {
+		r = makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&since="+since, t)
+		_, _, err = getEventRequest(r)
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for since %q, got %v", since, err)
+		}
+	}
+}
+
+// Manager returning the events after the requested sequence number among
+// three, in epoch 7, ignoring paging.
+type pastEventsManager struct {
+	manager.Manager
+	calls int
+}
+
+func (self *pastEventsManager) GetEventsEpoch() int64 {
+	return 7
+}
+
+func (self *pastEventsManager) GetPastEvents(request *events.Request) (events.EventSlice, error) {
+	self.calls++
+	returned := events.EventSlice{}
+	for sequence := uint64(1); sequence <= 3; sequence++ {
+		if request.SinceEpoch != 7 || sequence > request.Since {
+			returned = append(returned, &events.Event{ContainerName: "/a", Sequence: sequence})
+		}
+	}
+	return returned, nil
+}
+
+func TestHandleEventRequestCursor(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&since=7-1", t)
+	assert.Nil(t, handleEventRequest(&pastEventsManager{}, w, r))
+	assert.Equal(t, "7-3", w.Header().Get("X-Events-Cursor"))
+	assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+
+	// The cursor is kept when there are no new events.
+	w = httptest.NewRecorder()
+	r = makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&since=7-3", t)
+	assert.Nil(t, handleEventRequest(&pastEventsManager{}, w, r))
+	assert.Equal(t, "7-3", w.Header().Get("X-Events-Cursor"))
+
+	// A cursor from another epoch, e.g. before cAdvisor restarted, is not.
+	w = httptest.NewRecorder()
+	r = makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&since=6-5", t)
+	assert.Nil(t, handleEventRequest(&pastEventsManager{}, w, r))
+	assert.Equal(t, "7-3", w.Header().Get("X-Events-Cursor"))
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+
+	// The total counts the events of all the pages, the cursor follows the
+	// returned ones. The events are only read once.
+	w = httptest.NewRecorder()
+	r = makeHTTPRequest("http://localhost:8080/api/v1.3/events?historical=true&offset=1&limit=1", t)
+	m := &pastEventsManager{}
+	assert.Nil(t, handleEventRequest(m, w, r))
+	assert.Equal(t, "7-2", w.Header().Get("X-Events-Cursor"))
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, 1, m.calls)
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	testCases := map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"deflate, gzip;q=1.0": true,
+		"gzip;q=0":            false,
+		"identity":            false,
+	}
+	for header, expected := range testCases {
+		r := makeHTTPRequest("http://localhost:8080/api/v2.0/machine", t)
+		r.Header.Set("Accept-Encoding", header)
+		assert.Equal(t, expected, acceptsGzip(r), "Accept-Encoding: %q", header)
+	}
+}
+
+func TestWriteResultGzip(t *testing.T) {
+	res := strings.Repeat("a", *gzipMinSize)
+
+	r := makeHTTPRequest("http://localhost:8080/api/v2.0/machine", t)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	assert.Nil(t, writeResult(res, w, r))
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(w.Body)
+	assert.Nil(t, err)
+	out, err := ioutil.ReadAll(gz)
+	assert.Nil(t, err)
+	assert.Equal(t, "\""+res+"\"", string(out))
+
+	// Small responses are not compressed.
+	w = httptest.NewRecorder()
+	assert.Nil(t, writeResult("a", w, r))
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "\"a\"", w.Body.String())
+}
+
+func TestWriteResultMsgpack(t *testing.T) {
+	for _, url := range []string{"http://localhost:8080/api/v2.0/stats?format=msgpack", "http://localhost:8080/api/v2.0/stats"} {
+		r := makeHTTPRequest(url, t)
+		r.Header.Set("Accept", "application/x-msgpack, application/json;q=0.5")
+		w := httptest.NewRecorder()
+		assert.Nil(t, writeResult(map[string]uint64{"a": 1}, w, r))
+		assert.Equal(t, "application/x-msgpack", w.Header().Get("Content-Type"))
+		assert.Equal(t, []byte{0x81, 0xa1, 'a', 0x01}, w.Body.Bytes())
+	}
+
+	r := makeHTTPRequest("http://localhost:8080/api/v2.0/stats", t)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	assert.Nil(t, writeResult(map[string]uint64{"a": 1}, w, r))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"a":1}`, w.Body.String())
+}
+
+func TestWriteErrorContainerNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeError(&manager.ContainerNotFoundError{Name: "/docker/abc"}, w)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"error":"container not found","code":404,"name":"/docker/abc"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	writeError(errors.New("failed"), w)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"error":"failed","code":500}`, w.Body.String())
+}
+
+func TestWriteErrorStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeError(badRequestError("bad %q", "count"), w)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `{"error":"bad \"count\"","code":400}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	writeError(notFoundError("unsupported API version %q", "v9"), w)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"error":"unsupported API version \"v9\"","code":404}`, w.Body.String())
+}
+
+func TestHandleRequestErrors(t *testing.T) {
+	versions := map[string]ApiVersion{}
+	for _, v := range getApiVersions() {
+		versions[v.Version()] = v
+	}
+	for _, test := range []struct {
+		url    string
+		status int
+	}{
+		{"http://localhost:8080/api/v9/containers", http.StatusNotFound},
+		{"http://localhost:8080/api/v2.0/unknown", http.StatusNotFound},
+		{"http://localhost:8080/api/v2.0/stats?type=unknown", http.StatusBadRequest},
+		{"http://localhost:8080/api/v2.0/stats?count=many", http.StatusBadRequest},
+	} {
+		err := handleRequest(versions, nil, httptest.NewRecorder(), makeHTTPRequest(test.url, t))
+		e, ok := err.(*httpError)
+		if assert.True(t, ok, "%s: unexpected error %v", test.url, err) {
+			assert.Equal(t, test.status, e.status, test.url)
+		}
+	}
+}
+
+func TestGetLabelSelector(t *testing.T) {
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/bylabel?label=app=nginx&label=version=1.0=beta&label_match=prefix", t)
+	selector, prefixMatch, err := getLabelSelector(r)
+	assert.Nil(t, err)
+	assert.True(t, prefixMatch)
+	assert.Equal(t, map[string]string{"app": "nginx", "version": "1.0=beta"}, selector)
+
+	for _, query := range []string{"", "label=app", "label==nginx", "label=app=a&label=app=b", "label=app=nginx&label_match=regexp"} {
+		r = makeHTTPRequest("http://localhost:8080/api/v2.1/bylabel?"+query, t)
+		_, _, err = getLabelSelector(r)
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", query, err)
+		}
+	}
+}
+
+// Manager knowing no containers.
+type emptyManager struct {
+	manager.Manager
+}
+
+func (self *emptyManager) GetContainerInfo(containerName string, query *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
+	return nil, &manager.ContainerNotFoundError{Name: containerName}
+}
+
+func (self *emptyManager) DockerContainer(name string, query *info.ContainerInfoRequest) (info.ContainerInfo, error) {
+	return info.ContainerInfo{}, &manager.ContainerNotFoundError{Name: name}
+}
+
+func TestV1UnknownContainer(t *testing.T) {
+	// The v1 resources fail as before unknown containers got a 404.
+	api := newVersion1_2(newVersion1_1(&version1_0{}))
+	for requestType, request := range map[string][]string{containersApi: {"missing"}, dockerApi: {"missing"}} {
+		r, err := http.NewRequest("GET", "http://localhost:8080/api/v1.2/"+requestType+"/missing", strings.NewReader(""))
+		assert.Nil(t, err)
+		err = api.HandleRequest(requestType, request, &emptyManager{}, httptest.NewRecorder(), r)
+		if assert.Error(t, err) {
+			_, ok := err.(*manager.ContainerNotFoundError)
+			assert.False(t, ok, "%s: %v", requestType, err)
+		}
+	}
+}
+
+// Manager only implementing SetHousekeepingInterval.
+type housekeepingManager struct {
+	manager.Manager
+	intervals map[string]time.Duration
+}
+
+func (self *housekeepingManager) SetHousekeepingInterval(containerName string, interval time.Duration) (time.Duration, error) {
+	if containerName != "/docker/abc" {
+		return 0, &manager.ContainerNotFoundError{Name: containerName}
+	}
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	self.intervals[containerName] = interval
+	return interval, nil
+}
+
+func TestHousekeepingRequest(t *testing.T) {
+	m := &housekeepingManager{intervals: make(map[string]time.Duration)}
+	api := newVersion2_1(newVersion2_0())
+
+	r, err := http.NewRequest("PUT", "http://localhost:8080/api/v2.1/housekeeping/docker/abc", strings.NewReader(`{"interval_ms":50}`))
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	assert.Nil(t, api.HandleRequest(housekeepingApi, []string{"docker", "abc"}, m, w, r))
+	assert.Equal(t, `{"interval_ms":100}`, w.Body.String())
+	assert.Equal(t, 100*time.Millisecond, m.intervals["/docker/abc"])
+
+	r, err = http.NewRequest("PUT", "http://localhost:8080/api/v2.1/housekeeping/docker/missing", strings.NewReader(`{"interval_ms":500}`))
+	assert.Nil(t, err)
+	err = api.HandleRequest(housekeepingApi, []string{"docker", "missing"}, m, httptest.NewRecorder(), r)
+	_, ok := err.(*manager.ContainerNotFoundError)
+	assert.True(t, ok)
+
+	r, err = http.NewRequest("GET", "http://localhost:8080/api/v2.1/housekeeping/docker/abc", nil)
+	assert.Nil(t, err)
+	err = api.HandleRequest(housekeepingApi, []string{"docker", "abc"}, m, httptest.NewRecorder(), r)
+	httpErr, ok := err.(*httpError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusMethodNotAllowed, httpErr.status)
+	}
+}
+
+// Manager only implementing SetAnnotations.
+type annotationsManager struct {
+	manager.Manager
+	annotations map[string]map[string]string
+}
+
+func (self *annotationsManager) SetAnnotations(containerName string, annotations map[string]string) error {
+	if containerName != "/docker/abc" {
+		return &manager.ContainerNotFoundError{Name: containerName}
+	}
+	self.annotations[containerName] = annotations
+	return nil
+}
+
+func TestAnnotationsRequest(t *testing.T) {
+	m := &annotationsManager{annotations: make(map[string]map[string]string)}
+	api := newVersion2_1(newVersion2_0())
+
+	r, err := http.NewRequest("PUT", "http://localhost:8080/api/v2.1/annotations/docker/abc", strings.NewReader(`{"version":"1.2"}`))
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	assert.Nil(t, api.HandleRequest(annotationsApi, []string{"docker", "abc"}, m, w, r))
+	assert.Equal(t, `{"version":"1.2"}`, w.Body.String())
+	assert.Equal(t, map[string]string{"version": "1.2"}, m.annotations["/docker/abc"])
+
+	r, err = http.NewRequest("PUT", "http://localhost:8080/api/v2.1/annotations/docker/abc", strings.NewReader(`{"version":1}`))
+	assert.Nil(t, err)
+	err = api.HandleRequest(annotationsApi, []string{"docker", "abc"}, m, httptest.NewRecorder(), r)
+	httpErr, ok := err.(*httpError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusBadRequest, httpErr.status)
+	}
+
+	r, err = http.NewRequest("PUT", "http://localhost:8080/api/v2.1/annotations/docker/missing", strings.NewReader(`{}`))
+	assert.Nil(t, err)
+	err = api.HandleRequest(annotationsApi, []string{"docker", "missing"}, m, httptest.NewRecorder(), r)
+	_, ok = err.(*manager.ContainerNotFoundError)
+	assert.True(t, ok)
+
+	r, err = http.NewRequest("GET", "http://localhost:8080/api/v2.1/annotations/docker/abc", nil)
+	assert.Nil(t, err)
+	err = api.HandleRequest(annotationsApi, []string{"docker", "abc"}, m, httptest.NewRecorder(), r)
+	httpErr, ok = err.(*httpError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusMethodNotAllowed, httpErr.status)
+	}
+}
+
+// Manager holding /docker and /docker/abc.
+type prometheusManager struct {
+	manager.Manager
+}
+
+func (self *prometheusManager) ResolveName(name string) (v2.ResolvedContainer, error) {
+	switch name {
+	case "/docker", "/docker/abc":
+		return v2.ResolvedContainer{Name: name}, nil
+	}
+	return v2.ResolvedContainer{}, &manager.ContainerNotFoundError{Name: name}
+}
+
+func (self *prometheusManager) SubcontainersInfo(containerName string, query *info.ContainerInfoRequest) ([]*info.ContainerInfo, error) {
+	var containers []*info.ContainerInfo
+	for i, name := range []string{"/docker", "/docker/abc"} {
+		containers = append(containers, &info.ContainerInfo{
+			ContainerReference: info.ContainerReference{Name: name},
+			Stats:              []*info.ContainerStats{{Memory: info.MemoryStats{Usage: uint64(i + 1)}}},
+		})
+	}
+	return containers, nil
+}
+
+func TestPrometheusRequest(t *testing.T) {
+	api := newVersion2_1(newVersion2_0())
+	w := httptest.NewRecorder()
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/prometheus/docker/abc", t)
+	assert.Nil(t, api.HandleRequest(prometheusApi, []string{"docker", "abc"}, &prometheusManager{}, w, r))
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, "container_memory_usage_bytes{id=\"/docker/abc\",name=\"/docker/abc\"} 2\n"), body)
+	assert.False(t, strings.Contains(body, `id="/docker"`), body)
+
+	err := api.HandleRequest(prometheusApi, []string{"missing"}, &prometheusManager{}, httptest.NewRecorder(), makeHTTPRequest("http://localhost:8080/api/v2.1/prometheus/missing", t))
+	_, ok := err.(*manager.ContainerNotFoundError)
+	assert.True(t, ok)
+}
+
+// Manager only implementing GetContainerSpecs, recording the options it is called with.
+type specsManager struct {
+	manager.Manager
+	options v2.RequestOptions
+}
+
+func (self *specsManager) GetContainerSpecs(containerName string, options v2.RequestOptions) (map[string]v2.ContainerSpec, error) {
+	self.options = options
+	return map[string]v2.ContainerSpec{
+		"/":       {HasCpu: true},
+		"/docker": {HasMemory: true, Image: "nginx:1.9"},
+	}, nil
+}
+
+func TestSpecsRequest(t *testing.T) {
+	api := newVersion2_1(newVersion2_0())
+	m := &specsManager{}
+	w := httptest.NewRecorder()
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/specs?recursive=true", t)
+	assert.Nil(t, api.HandleRequest(specsApi, []string{}, m, w, r))
+	assert.True(t, m.options.Recursive)
+	assert.Equal(t, v2.TypeName, m.options.IdType)
+
+	var specs map[string]v2.ContainerSpec
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &specs))
+	assert.Equal(t, 2, len(specs))
+	assert.Equal(t, "nginx:1.9", specs["/docker"].Image)
+	assert.False(t, strings.Contains(w.Body.String(), "stats"), w.Body.String())
+}
+
+func TestGetHousekeepingInterval(t *testing.T) {
+	interval, err := getHousekeepingInterval(strings.NewReader(`{"interval_ms":1500}`))
+	assert.Nil(t, err)
+	assert.Equal(t, 1500*time.Millisecond, interval)
+
+	for _, body := range []string{"", `{"interval_ms":-1}`, `{"interval_ms":"1s"}`} {
+		_, err = getHousekeepingInterval(strings.NewReader(body))
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", body, err)
+		}
+	}
+}
+
+// Manager only implementing GetRequestedContainersInfo, for the containers in infos.
+type batchManager struct {
+	manager.Manager
+	infos map[string]*info.ContainerInfo
+}
+
+func (self *batchManager) GetRequestedContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	cont, ok := self.infos[containerName]
+	if !ok {
+		return nil, &manager.ContainerNotFoundError{Name: containerName}
+	}
+	return map[string]*info.ContainerInfo{containerName: cont}, nil
+}
+
+func TestGetBatchStats(t *testing.T) {
+	now := time.Now()
+	m := &batchManager{
+		infos: map[string]*info.ContainerInfo{
+			"/a": {
+				Spec:  info.ContainerSpec{HasCpu: true},
+				Stats: []*info.ContainerStats{{Timestamp: now}},
+			},
+		},
+	}
+	result := getBatchStats([]string{"/a", "/missing"}, v2.RequestOptions{Count: 1}, m)
+	assert.Equal(t, 2, len(result))
+	if assert.Equal(t, 1, len(result["/a"].Stats)) {
+		assert.True(t, result["/a"].Stats[0].HasCpu)
+	}
+	assert.Equal(t, "", result["/a"].Error)
+	assert.Equal(t, 0, len(result["/missing"].Stats))
+	assert.Equal(t, `unknown container "/missing"`, result["/missing"].Error)
+}
+
+func TestGetBatchContainerNames(t *testing.T) {
+	names, err := getBatchContainerNames(strings.NewReader(`{"container_names":["/a","/b"]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/a", "/b"}, names)
+
+	tooMany := `{"container_names":["/a"` + strings.Repeat(`,"/a"`, *maxBatchSize) + `]}`
+	for _, body := range []string{"", `{"container_names":[]}`, `{"container_names":"/a"}`, tooMany} {
+		_, err = getBatchContainerNames(strings.NewReader(body))
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", body, err)
+		}
+	}
+}
+
+func TestPruneStatsFields(t *testing.T) {
+	r := makeHTTPRequest("http://localhost:8080/api/v2.0/stats?fields=cpu,unknown,+memory", t)
+	fields := getStatsFields(r)
+	assert.Equal(t, []string{"cpu", "memory"}, fields)
+	assert.Equal(t, 0, len(getStatsFields(makeHTTPRequest("http://localhost:8080/api/v2.0/stats", t))))
+
+	stats := map[string][]v2.ContainerStats{
+		"/a": {{
+			Timestamp:  time.Unix(0, 0).UTC(),
+			HasCpu:     true,
+			HasNetwork: true,
+			Network:    []info.NetworkStats{{RxBytes: 1}},
+		}},
+	}
+	pruned, err := pruneStatsFields(stats, fields, r)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(pruned["/a"])) {
+		keys := []string{}
+		for key := range pruned["/a"][0] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		assert.Equal(t, []string{"cpu", "has_cpu", "has_memory", "memory", "timestamp"}, keys)
+		assert.Equal(t, "true", string(pruned["/a"][0]["has_cpu"]))
+	}
+
+	// Sections can be named as in snake_case responses.
+	defer func(names string) { *jsonFieldNames = names }(*jsonFieldNames)
+	*jsonFieldNames = "snake_case"
+	r = makeHTTPRequest("http://localhost:8080/api/v2.0/stats?fields=load_stats,CustomMetrics,network", t)
+	fields = getStatsFields(r)
+	assert.Equal(t, []string{"load", "custom_metrics", "network"}, fields)
+	pruned, err = pruneStatsFields(stats, fields, r)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(pruned["/a"])) {
+		assert.True(t, strings.Contains(string(pruned["/a"][0]["network"]), `"rx_bytes":1`), string(pruned["/a"][0]["network"]))
+		assert.Equal(t, "true", string(pruned["/a"][0]["has_network"]))
+	}
+}
+
+func TestRoundFloats(t *testing.T) {
+	defer func(precision int) { *floatPrecision = precision }(*floatPrecision)
+	values := []float64{0.123456789, -2.345, 1.5, 1e300, 98.7654321}
+	round := func(precision int) []float64 {
+		*floatPrecision = precision
+		rounded := append([]float64{}, values...)
+		roundFloats(&rounded[0], &rounded[1], &rounded[2], &rounded[3], &rounded[4])
+		return rounded
+	}
+	assert.Equal(t, values, round(-1))
+	assert.Equal(t, []float64{0.12, -2.35, 1.5, 1e300, 98.77}, round(2))
+	assert.Equal(t, []float64{0, -2, 2, 1e300, 99}, round(0))
+
+	*floatPrecision = 3
+	rates := v2.ContainerRates{CpuUsage: 1.23456, DiskWrites: 0.0004, RxBytes: 1024}
+	roundRates(&rates)
+	assert.Equal(t, v2.ContainerRates{CpuUsage: 1.235, DiskWrites: 0, RxBytes: 1024}, rates)
+}
+
+func TestConvertStatsUnit(t *testing.T) {
+	unit, err := getStatsUnit(makeHTTPRequest("http://localhost:8080/api/v2.0/stats?units=MiB", t))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1<<20), unit)
+	unit, err = getStatsUnit(makeHTTPRequest("http://localhost:8080/api/v2.0/stats", t))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), unit)
+	_, err = getStatsUnit(makeHTTPRequest("http://localhost:8080/api/v2.0/stats?units=GB", t))
+	httpErr, ok := err.(*httpError)
+	if !ok || httpErr.status != http.StatusBadRequest {
+		t.Errorf("expected a bad request error for unknown units but received %v", err)
+	}
+
+	val := &info.ContainerStats{}
+	val.Memory.Usage = 3 << 20
+	val.Memory.WorkingSet = 2<<20 + 1
+	val.Memory.Failcnt = 5
+	val.Memory.HugeTlb = map[string]info.HugeTlbStats{"2MB": {Usage: 4 << 20, Failcnt: 1}}
+	val.Network.RxBytes = 1 << 20
+	val.Network.RxPackets = 1000
+	val.Network.Interfaces = []info.InterfaceStats{{Name: "eth0", TxBytes: 5 << 20}}
+	stat := convertStat(&info.ContainerSpec{HasMemory: true, HasNetwork: true}, val)
+	convertStatsUnit(&stat, 1<<20)
+
+	assert.Equal(t, uint64(3), stat.Memory.Usage)
+	assert.Equal(t, uint64(2), stat.Memory.WorkingSet)
+	// Counts are left as is.
+	assert.Equal(t, uint64(5), stat.Memory.Failcnt)
+	assert.Equal(t, info.HugeTlbStats{Usage: 4, Failcnt: 1}, stat.Memory.HugeTlb["2MB"])
+	assert.Equal(t, uint64(1), stat.Network[0].RxBytes)
+	assert.Equal(t, uint64(1000), stat.Network[0].RxPackets)
+	assert.Equal(t, uint64(5), stat.Network[0].Interfaces[0].TxBytes)
+
+	// The stored stats are not converted.
+	assert.Equal(t, uint64(4<<20), val.Memory.HugeTlb["2MB"].Usage)
+	assert.Equal(t, uint64(5<<20), val.Network.Interfaces[0].TxBytes)
+}
+
+func TestComputeRates(t *testing.T) {
+	now := time.Now()
+	prev := &info.ContainerStats{Timestamp: now}
+	prev.Cpu.Usage.Total = uint64(time.Second)
+	prev.Cpu.NrPeriods = 100
+	prev.Cpu.NrThrottled = 10
+	prev.Network.RxBytes = 1000
+	prev.Network.TxBytes = 5000
+	prev.DiskIo.IoServiceBytes = []info.PerDiskStats{
+		{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 100, "Write": 0}},
+		{Major: 8, Minor: 16, Stats: map[string]uint64{"Read": 100, "Write": 0}},
+	}
+
+	cur := &info.ContainerStats{Timestamp: now.Add(2 * time.Second)}
+	cur.Cpu.Usage.Total = uint64(4 * time.Second)
+	cur.Cpu.NrPeriods = 120
+	cur.Cpu.NrThrottled = 15
+	cur.Network.RxBytes = 3000
+	cur.Network.TxBytes = 0
+	cur.DiskIo.IoServiceBytes = []info.PerDiskStats{
+		{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 300, "Write": 4096}},
+		{Major: 8, Minor: 16, Stats: map[string]uint64{"Read": 300, "Write": 0}},
+	}
+
+	rates := computeRates(prev, cur)
+	assert.Equal(t, cur.Timestamp, rates.Timestamp)
+	assert.Equal(t, 2*time.Second, rates.Interval)
+	assert.InDelta(t, 1.5, rates.CpuUsage, 1e-9)
+	assert.InDelta(t, 0.25, rates.CpuThrottledFraction, 1e-9)
+	assert.InDelta(t, 1000, rates.RxBytes, 1e-9)
+	// The counter went backwards.
+	assert.Equal(t, 0.0, rates.TxBytes)
+	assert.InDelta(t, 200, rates.DiskReadBytes, 1e-9)
+	assert.InDelta(t, 2048, rates.DiskWriteBytes, 1e-9)
+
+	// Samples with the same timestamp have no rates.
+	assert.Equal(t, 0.0, computeRates(cur, cur).CpuUsage)
+}
+
+// Manager serving the containers of batchManager on a 4 core machine with 8GiB
+// of memory.
+type shareManager struct {
+	batchManager
+}
+
+func (self *shareManager) GetMachineInfo() (*info.MachineInfo, error) {
+	return &info.MachineInfo{NumCores: 4, MemoryCapacity: 8 << 30}, nil
+}
+
+func TestShareRequest(t *testing.T) {
+	now := time.Now()
+	prev := &info.ContainerStats{Timestamp: now}
+	prev.Cpu.Usage.Total = uint64(time.Second)
+	cur := &info.ContainerStats{Timestamp: now.Add(time.Second)}
+	cur.Cpu.Usage.Total = uint64(3 * time.Second)
+	cur.Memory.Usage = 2 << 30
+	cur.Memory.WorkingSet = 1 << 30
+	m := &shareManager{batchManager{infos: map[string]*info.ContainerInfo{
+		"/a":   {Stats: []*info.ContainerStats{prev, cur}},
+		"/new": {Stats: []*info.ContainerStats{cur}},
+	}}}
+	api := newVersion2_1(newVersion2_0())
+
+	w := httptest.NewRecorder()
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/share/a", t)
+	assert.Nil(t, api.HandleRequest(shareApi, []string{"a"}, m, w, r))
+	var shares map[string]v2.ContainerShare
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &shares))
+	assert.Equal(t, 1, len(shares))
+	assert.InDelta(t, 0.5, shares["/a"].Cpu, 1e-9)
+	assert.InDelta(t, 0.25, shares["/a"].Memory, 1e-9)
+	assert.InDelta(t, 0.125, shares["/a"].MemoryWorkingSet, 1e-9)
+
+	// Containers with a single sample are left out.
+	w = httptest.NewRecorder()
+	r = makeHTTPRequest("http://localhost:8080/api/v2.1/share/new", t)
+	assert.Nil(t, api.HandleRequest(shareApi, []string{"new"}, m, w, r))
+	assert.Equal(t, "{}", strings.TrimSpace(w.Body.String()))
+}
+
+func TestConvertStatsCpuCores(t *testing.T) {
+	now := time.Now()
+	cont := &info.ContainerInfo{Spec: info.ContainerSpec{HasCpu: true}}
+	for i, usage := range []time.Duration{time.Second, 2500 * time.Millisecond, 4 * time.Second} {
+		stats := &info.ContainerStats{Timestamp: now.Add(time.Duration(i) * time.Second)}
+		stats.Cpu.Usage.Total = uint64(usage)
+		cont.Stats = append(cont.Stats, stats)
+	}
+
+	stats := convertStats(cont)
+	assert.Equal(t, 3, len(stats))
+	// The first sample has no previous one.
+	assert.Equal(t, 0.0, stats[0].Cpu.CpuCores)
+	assert.InDelta(t, 1.5, stats[1].Cpu.CpuCores, 1e-9)
+	assert.InDelta(t, 1.5, stats[2].Cpu.CpuCores, 1e-9)
+}
+
+func TestTopProcesses(t *testing.T) {
+	processes := []v2.ProcessInfo{
+		{Pid: 1, CpuPercent: 10, Rss: 300},
+		{Pid: 2, CpuPercent: 50, Rss: 100},
+		{Pid: 3, CpuPercent: 20, Rss: 200},
+	}
+	pids := func(processes []v2.ProcessInfo) []int {
+		ret := []int{}
+		for _, p := range processes {
+			ret = append(ret, p.Pid)
+		}
+		return ret
+	}
+	assert.Equal(t, []int{2, 3}, pids(topProcesses(processes, "cpu", 2)))
+	assert.Equal(t, []int{1, 3, 2}, pids(topProcesses(processes, "memory", 0)))
+
+	sortBy, limit, err := getProcessListOptions(makeHTTPRequest("http://localhost:8080/api/v2.1/processes/docker/abc?limit=5", t))
+	assert.Nil(t, err)
+	assert.Equal(t, "cpu", sortBy)
+	assert.Equal(t, 5, limit)
+	for _, query := range []string{"sort=pid", "limit=-1"} {
+		_, _, err = getProcessListOptions(makeHTTPRequest("http://localhost:8080/api/v2.1/processes/?"+query, t))
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", query, err)
+		}
+	}
+}
+
+type eventsManager struct {
+	manager.Manager
+	closed []int
+}
+
+func (self *eventsManager) CloseEventChannel(watchId int) {
+	self.closed = append(self.closed, watchId)
+}
+
+// ResponseRecorder which reports the connection as closed once closed is.
+type closeNotifyRecorder struct {
+	*httptest.ResponseRecorder
+	closed chan bool
+}
+
+func (self *closeNotifyRecorder) CloseNotify() <-chan bool {
+	return self.closed
+}
+
+func TestStreamResultsAsSse(t *testing.T) {
+	m := &eventsManager{}
+	w := &closeNotifyRecorder{httptest.NewRecorder(), make(chan bool)}
+	eventChannel := events.NewEventChannel(1)
+	eventChannel.GetChannel() <- &events.Event{
+		ContainerName: "/a",
+		Timestamp:     time.Unix(0, 0).UTC(),
+		EventType:     events.TypeOom,
+	}
+	close(eventChannel.GetChannel())
+
+	err := streamResultsAsSse(eventChannel, w, makeHTTPRequest("http://localhost:8080/api/v1.3/events?format=sse", t), m)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, `data: {"ContainerName":"/a",`), body)
+	assert.True(t, strings.HasSuffix(body, "}\n\n"), body)
+	assert.Equal(t, 1, strings.Count(body, "data: "))
+
+	// The watch is closed when the client goes away.
+	w = &closeNotifyRecorder{httptest.NewRecorder(), make(chan bool)}
+	close(w.closed)
+	err = streamResultsAsSse(events.NewEventChannel(2), w, makeHTTPRequest("http://localhost:8080/api/v1.3/events?format=sse", t), m)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, m.closed)
+}
+
+func TestGetContainerInfoRequest(t *testing.T) {
+	start := time.Date(2015, 10, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2015, 10, 1, 10, 5, 0, 0, time.UTC)
+	for body, expected := range map[string]info.ContainerInfoRequest{
+		``:                                 {NumStats: 60},
+		`{"num_stats":10}`:                 {NumStats: 10},
+		`{"start":"2015-10-01T10:00:00Z"}`: {NumStats: 60, Start: start},
+		`{"start":"2015-10-01T10:00:00Z","end":"2015-10-01T10:05:00Z"}`:               {NumStats: -1, Start: start, End: end},
+		`{"num_stats":5,"start":"2015-10-01T10:00:00Z","end":"2015-10-01T10:05:00Z"}`: {NumStats: 5, Start: start, End: end},
+		`{"num_stats":0,"start":"2015-10-01T10:00:00Z","end":"2015-10-01T10:05:00Z"}`: {NumStats: 0, Start: start, End: end},
+		`{"num_stats":0}`: {NumStats: 0},
+	} {
+		query, err := getContainerInfoRequest(ioutil.NopCloser(strings.NewReader(body)))
+		if assert.NoError(t, err, body) {
+			assert.True(t, query.Equals(expected), "%s: expected %+v, got %+v", body, expected, *query)
+		}
+	}
+
+	for _, body := range []string{
+		`{"num_stats":"ten"}`,
+		`{"start":"10:00"}`,
+		`{"start":"2015-10-01T10:05:00Z","end":"2015-10-01T10:00:00Z"}`,
+	} {
+		_, err := getContainerInfoRequest(ioutil.NopCloser(strings.NewReader(body)))
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", body, err)
+		}
+	}
+}
+
+type statsStreamManager struct {
+	manager.Manager
+	closed []int
+}
+
+func (self *statsStreamManager) CloseStatsChannel(statsChannel *manager.StatsChannel) {
+	self.closed = append(self.closed, statsChannel.GetWatchId())
+}
+
+func TestStreamStats(t *testing.T) {
+	m := &statsStreamManager{}
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/stats/a?stream=true", t)
+	w := &closeNotifyRecorder{httptest.NewRecorder(), make(chan bool)}
+	statsChannel := manager.NewStatsChannel(1, "/a")
+	start := time.Unix(100, 0).UTC()
+	for _, offset := range []time.Duration{0, time.Second, 2 * time.Second, 10 * time.Second} {
+		statsChannel.GetChannel() <- &info.ContainerInfo{
+			Spec:  info.ContainerSpec{HasMemory: true},
+			Stats: []*info.ContainerStats{{Timestamp: start.Add(offset)}},
+		}
+	}
+	// The container went away.
+	close(statsChannel.GetChannel())
+
+	err := streamStats(statsChannel, 2*time.Second, w, r, m)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Equal(t, 3, len(lines)) {
+		assert.True(t, strings.HasPrefix(lines[0], `{"timestamp":"1970-01-01T00:01:40Z",`), lines[0])
+		assert.True(t, strings.HasPrefix(lines[1], `{"timestamp":"1970-01-01T00:01:42Z",`), lines[1])
+		assert.True(t, strings.HasPrefix(lines[2], `{"timestamp":"1970-01-01T00:01:50Z",`), lines[2])
+	}
+	assert.Equal(t, []int{1}, m.closed)
+
+	// Streaming stops when the client goes away.
+	w = &closeNotifyRecorder{httptest.NewRecorder(), make(chan bool)}
+	close(w.closed)
+	err = streamStats(manager.NewStatsChannel(2, "/a"), 0, w, r, m)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, m.closed)
+}
+
+type topologyStreamManager struct {
+	manager.Manager
+	closed []int
+}
+
+func (self *topologyStreamManager) CloseTopologyChannel(topologyChannel *manager.TopologyChannel) {
+	self.closed = append(self.closed, topologyChannel.GetWatchId())
+}
+
+func TestStreamTopology(t *testing.T) {
+	m := &topologyStreamManager{}
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/topologystream", t)
+	w := &closeNotifyRecorder{httptest.NewRecorder(), make(chan bool)}
+	now := time.Unix(100, 0).UTC()
+	topologyChannel := manager.NewTopologyChannel(1, []v2.TopologyChange{
+		{Type: v2.TopologyAdded, Name: "/", Timestamp: now},
+		{Type: v2.TopologyAdded, Name: "/a", Parent: "/", Timestamp: now},
+	})
+	topologyChannel.GetChannel() <- v2.TopologyChange{Type: v2.TopologyRemoved, Name: "/a", Parent: "/", Timestamp: now.Add(time.Second)}
+	// The watcher fell behind.
+	close(topologyChannel.GetChannel())
+
+	err := streamTopology(topologyChannel, w, r, m)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"added","name":"/","timestamp":"1970-01-01T00:01:40Z"}
+{"type":"added","name":"/a","parent":"/","timestamp":"1970-01-01T00:01:40Z"}
+{"type":"removed","name":"/a","parent":"/","timestamp":"1970-01-01T00:01:41Z"}
+`, w.Body.String())
+	assert.Equal(t, []int{1}, m.closed)
+
+	// Streaming stops when the client goes away.
+	w = &closeNotifyRecorder{httptest.NewRecorder(), make(chan bool)}
+	close(w.closed)
+	err = streamTopology(manager.NewTopologyChannel(2, nil), w, r, m)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, m.closed)
+}
+
+func TestGetStreamInterval(t *testing.T) {
+	interval, err := getStreamInterval(makeHTTPRequest("http://localhost:8080/api/v2.1/statsstream/a", t))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), interval)
+	interval, err = getStreamInterval(makeHTTPRequest("http://localhost:8080/api/v2.1/statsstream/a?interval=5s", t))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, interval)
+	for _, param := range []string{"5", "-1s"} {
+		_, err = getStreamInterval(makeHTTPRequest("http://localhost:8080/api/v2.1/statsstream/a?interval="+param, t))
+		assert.Error(t, err, param)
+	}
+}
+
+type deleteEventsManager struct {
+	manager.Manager
+	requests []*events.Request
+}
+
+func (self *deleteEventsManager) DeleteEvents(request *events.Request) (int, error) {
+	self.requests = append(self.requests, request)
+	return 3, nil
+}
+
+func TestDeleteEventsRequest(t *testing.T) {
+	m := &deleteEventsManager{}
+	api := newVersion2_1(newVersion2_0())
+
+	r, err := http.NewRequest("DELETE", "http://localhost:8080/api/v2.1/events?oom_events=true&end_time=2015-06-01T00:00:00Z", nil)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	assert.Nil(t, api.HandleRequest(eventsApi, []string{}, m, w, r))
+	assert.Equal(t, `{"count":3}`, w.Body.String())
+	if assert.Equal(t, 1, len(m.requests)) {
+		assert.Equal(t, map[events.EventType]bool{events.TypeOom: true}, m.requests[0].EventType)
+		assert.True(t, m.requests[0].EndTime.Equal(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)))
+	}
+
+	// Events of all types are deleted by default.
+	r, err = http.NewRequest("DELETE", "http://localhost:8080/api/v2.1/events", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, api.HandleRequest(eventsApi, []string{}, m, httptest.NewRecorder(), r))
+	if assert.Equal(t, 2, len(m.requests)) {
+		assert.Equal(t, 4, len(m.requests[1].EventType))
+	}
+}
+
+// Manager only implementing GetAggregatedContainerInfo.
+type aggregateManager struct {
+	manager.Manager
+}
+
+func (self *aggregateManager) GetAggregatedContainerInfo(containerName string, options v2.RequestOptions) (*info.ContainerInfo, error) {
+	cont := &info.ContainerInfo{
+		ContainerReference: info.ContainerReference{Name: containerName},
+		Spec:               info.ContainerSpec{HasCpu: true},
+	}
+	cont.Stats = []*info.ContainerStats{{Timestamp: time.Unix(10, 0)}}
+	cont.Stats[0].Cpu.Usage.Total = 42
+	return cont, nil
+}
+
+func TestGetAggregatedStats(t *testing.T) {
+	m := &aggregateManager{}
+	stats, err := getStats(m, "/kubepods", v2.RequestOptions{IdType: v2.TypeName}, makeHTTPRequest("http://localhost:8080/api/v2.0/stats/kubepods?aggregate=sum", t))
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(stats["/kubepods"])) {
+		assert.Equal(t, uint64(42), stats["/kubepods"][0].Cpu.Usage.Total)
+	}
+
+	for _, url := range []string{
+		"http://localhost:8080/api/v2.0/stats/kubepods?aggregate=avg",
+		"http://localhost:8080/api/v2.0/stats/kubepods?aggregate=sum&recursive=true",
+	} {
+		r := makeHTTPRequest(url, t)
+		opt, err := getRequestOptions(r)
+		assert.NoError(t, err)
+		_, err = getStats(m, "/kubepods", opt, r)
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", url, err)
+		}
+	}
+}
+
+func TestGetRequestOptionsDepth(t *testing.T) {
+	opt, err := getRequestOptions(makeHTTPRequest("http://localhost:8080/api/v2.0/stats/?recursive=true&depth=2", t))
+	assert.NoError(t, err)
+	assert.True(t, opt.Recursive)
+	assert.Equal(t, 2, opt.Depth)
+
+	_, err = getRequestOptions(makeHTTPRequest("http://localhost:8080/api/v2.0/stats/?recursive=true&depth=-1", t))
+	httpErr, ok := err.(*httpError)
+	assert.True(t, ok && httpErr.status == http.StatusBadRequest, "expected a bad request error, got %v", err)
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(100, 0)
+	bucket := newTokenBucket(2, 3, now)
+	for i := 0; i < 3; i++ {
+		allowed, _ := bucket.take(now)
+		assert.True(t, allowed)
+	}
+	allowed, wait := bucket.take(now)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Tokens are refilled at the rate, up to the burst.
+	allowed, _ = bucket.take(now.Add(500 * time.Millisecond))
+	assert.True(t, allowed)
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		allowed, _ := bucket.take(now)
+		assert.True(t, allowed)
+	}
+	allowed, _ = bucket.take(now)
+	assert.False(t, allowed)
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(map[string]float64{statsRequests: 0.1, eventsRequests: 0}, 1)
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, limiter.check(w, makeHTTPRequest("http://localhost:8080/api/v2.0/stats/docker", t)))
+	// Other classes of requests are limited separately.
+	assert.NoError(t, limiter.check(w, makeHTTPRequest("http://localhost:8080/api/v2.0/machine", t)))
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.check(w, makeHTTPRequest("http://localhost:8080/api/v2.1/events", t)))
+	}
+
+	err := limiter.check(w, makeHTTPRequest("http://localhost:8080/api/v1.3/containers/docker", t))
+	httpErr, ok := err.(*httpError)
+	if assert.True(t, ok, "expected an httpError but received %v", err) {
+		assert.Equal(t, http.StatusTooManyRequests, httpErr.status)
+	}
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+}
+
+func TestGetTopRequest(t *testing.T) {
+	by, limit, err := getTopRequest(makeHTTPRequest("http://localhost:8080/api/v2.1/top?by=memory", t))
+	assert.NoError(t, err)
+	assert.Equal(t, v2.TopByMemory, by)
+	assert.Equal(t, defaultTopLimit, limit)
+
+	by, limit, err = getTopRequest(makeHTTPRequest("http://localhost:8080/api/v2.1/top?by=network_rx&limit=3", t))
+	assert.NoError(t, err)
+	assert.Equal(t, v2.TopByNetworkRx, by)
+	assert.Equal(t, 3, limit)
+
+	for _, url := range []string{
+		"http://localhost:8080/api/v2.1/top",
+		"http://localhost:8080/api/v2.1/top?by=disk",
+		"http://localhost:8080/api/v2.1/top?by=cpu&limit=-1",
+	} {
+		_, _, err := getTopRequest(makeHTTPRequest(url, t))
+		httpErr, ok := err.(*httpError)
+		if !ok || httpErr.status != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %q but received %v", url, err)
+		}
+	}
+}
+
+func TestSchemaCoversRequestTypes(t *testing.T) {
+	version := newVersion2_1(newVersion2_0())
+	documented := map[string]bool{}
+	for _, op := range v2_1Operations {
+		documented[op.requestType] = true
+	}
+	for _, requestType := range version.SupportedRequestTypes() {
+		assert.True(t, documented[requestType], "request type %q is not described by the schema", requestType)
+	}
+}
+
+func TestBuildSchema(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.NoError(t, handleSchemaRequest(w, makeHTTPRequest("http://localhost:8080/api/v2.1/schema", t)))
+	var schema struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+				Required   []string                          `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &schema); err != nil {
+		t.Fatalf("failed to decode the schema: %v", err)
+	}
+	assert.Equal(t, "3.0.3", schema.OpenAPI)
+	_, ok := schema.Paths["/api/v2.1/stats/{container}"]["get"]
+	assert.True(t, ok, "missing stats operation")
+	_, ok = schema.Paths["/api/v2.1/events/{container}"]["delete"]
+	assert.True(t, ok, "missing events deletion operation")
+	_, ok = schema.Paths["/api/v2.1/housekeeping/{container}"]["put"]
+	assert.True(t, ok, "missing housekeeping operation")
+
+	stats, ok := schema.Components.Schemas["v2.ContainerStats"]
+	if assert.True(t, ok, "missing v2.ContainerStats") {
+		assert.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, stats.Properties["timestamp"])
+		assert.Contains(t, stats.Required, "timestamp")
+		assert.NotContains(t, stats.Required, "cpu")
+	}
+
+	// Errors are described as JSON.
+	errorSchema, ok := schema.Components.Schemas["api.errorResponse"]
+	if assert.True(t, ok, "missing api.errorResponse") {
+		assert.Contains(t, errorSchema.Required, "error")
+		assert.Contains(t, errorSchema.Required, "code")
+	}
+
+	// All the referenced schemas are defined.
+	refs := regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1)
+	assert.NotEmpty(t, refs)
+	for _, ref := range refs {
+		_, ok := schema.Components.Schemas[ref[1]]
+		assert.True(t, ok, "missing schema %q", ref[1])
+	}
+}
+
+// Manager only implementing GetCgroupConfig, for the containers in configs.
+type cgroupManager struct {
+	manager.Manager
+	configs map[string]map[string]string
+}
+
+func (self *cgroupManager) GetCgroupConfig(containerName string) (map[string]string, error) {
+	config, ok := self.configs[containerName]
+	if !ok {
+		return nil, &manager.ContainerNotFoundError{Name: containerName}
+	}
+	return config, nil
+}
+
+func TestCgroupRequest(t *testing.T) {
+	m := &cgroupManager{configs: map[string]map[string]string{
+		"/docker/abc": {"cpu.cfs_quota_us": "50000", "memory.limit_in_bytes": "1073741824"},
+	}}
+	api := newVersion2_1(newVersion2_0())
+
+	w := httptest.NewRecorder()
+	r := makeHTTPRequest("http://localhost:8080/api/v2.1/cgroup/docker/abc", t)
+	assert.Nil(t, api.HandleRequest(cgroupApi, []string{"docker", "abc"}, m, w, r))
+	var config map[string]string
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &config))
+	assert.Equal(t, m.configs["/docker/abc"], config)
+
+	r = makeHTTPRequest("http://localhost:8080/api/v2.1/cgroup/docker/unknown", t)
+	err := api.HandleRequest(cgroupApi, []string{"docker", "unknown"}, m, httptest.NewRecorder(), r)
+	if _, ok := err.(*manager.ContainerNotFoundError); !ok {
+		t.Errorf("expected a container not found error, got %v", err)
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ContainerName":       "container_name",
+		"Timestamp":           "timestamp",
+		"IPAddress":           "ip_address",
+		"ContainerID":         "container_id",
+		"Ipv4Addr":            "ipv4_addr",
+		"memory_limit":        "memory_limit",
+		"MemoryWorkingSet":    "memory_working_set",
+		"VictimContainerName": "victim_container_name",
+	} {
+		assert.Equal(t, expected, toSnakeCase(name))
+	}
+}
+
+func TestWriteResultSnakeCase(t *testing.T) {
+	type embedded struct {
+		EmbeddedField int
+	}
+	type result struct {
+		embedded
+		ContainerName string
+		Labels        map[string]string `json:"labels,omitempty"`
+		Omitted       string            `json:"omitted,omitempty"`
+		Ignored       int               `json:"-"`
+		Timestamp     time.Time
+		Events        []*events.Event
+		Unset         *int
+		unexported    int
+	}
+	res := result{
+		embedded:      embedded{EmbeddedField: 1},
+		ContainerName: "/a",
+		Labels:        map[string]string{"SomeLabel": "b"},
+		Ignored:       2,
+		Timestamp:     time.Unix(0, 0).UTC(),
+		Events:        []*events.Event{{ContainerName: "/a", EventType: events.TypeOom, Sequence: 1}},
+		unexported:    3,
+	}
+	defer func(names string) { *jsonFieldNames = names }(*jsonFieldNames)
+	*jsonFieldNames = "snake_case"
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, writeResult(res, w, makeHTTPRequest("http://localhost:8080/api/v2.1/stats", t)))
+	var out map[string]interface{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &out))
+	expected := map[string]interface{}{
+		"embedded_field": float64(1),
+		"container_name": "/a",
+		"labels":         map[string]interface{}{"SomeLabel": "b"},
+		"timestamp":      "1970-01-01T00:00:00Z",
+		"events":         []interface{}{map[string]interface{}{"container_name": "/a", "timestamp": "0001-01-01T00:00:00Z", "event_type": float64(0), "event_data": nil, "sequence": float64(1)}},
+		"unset":          nil,
+	}
+	if !reflect.DeepEqual(expected, out) {
+		t.Errorf("expected %+v, got %+v", expected, out)
+	}
+	// The fields are in the order of the struct.
+	assert.True(t, strings.HasPrefix(w.Body.String(), `{"embedded_field":1,"container_name":"/a","labels"`), w.Body.String())
+
+	// v1 responses keep their names.
+	w = httptest.NewRecorder()
+	assert.Nil(t, writeResult(res, w, makeHTTPRequest("http://localhost:8080/api/v1.3/containers", t)))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `{"EmbeddedField":1,"ContainerName":"/a"`), w.Body.String())
+
+	*jsonFieldNames = "default"
+	w = httptest.NewRecorder()
+	assert.Nil(t, writeResult(res, w, makeHTTPRequest("http://localhost:8080/api/v2.1/stats", t)))
+	expectedOut, err := json.Marshal(res)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expectedOut), w.Body.String())
+
+	*jsonFieldNames = "camelCase"
+	assert.NotNil(t, validateJsonFieldNames())
+}
@@ -15,27 +15,35 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
 	"runtime"
 	"syscall"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/google/cadvisor/container"
 	cadvisorHttp "github.com/google/cadvisor/http"
 	"github.com/google/cadvisor/manager"
+	"github.com/google/cadvisor/metrics"
 	"github.com/google/cadvisor/utils/sysfs"
 	"github.com/google/cadvisor/version"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var argIp = flag.String("listen_ip", "", "IP to listen on, defaults to all IPs")
-var argPort = flag.Int("port", 8080, "port to listen")
+var argPort = flag.Int("port", 8080, "port to listen, 0 to only listen on -listen_socket")
+var argListenSocket = flag.String("listen_socket", "", "path of a Unix domain socket to also serve the API and UI on, e.g. /var/run/cadvisor.sock. Empty (default) for none")
 var maxProcs = flag.Int("max_procs", 0, "max number of CPUs that can be used simultaneously. Less than 1 for default (number of cores).")
 
-var argDbDriver = flag.String("storage_driver", "", "storage driver to use. Data is always cached shortly in memory, this controls where data is pushed besides the local cache. Empty means none. Options are: <empty> (default), bigquery, and influxdb")
+var argDbDriver = flag.String("storage_driver", "", "storage driver to use. Data is always cached shortly in memory, this controls where data is pushed besides the local cache. Empty means none. Options are: <empty> (default), bigquery, cassandra, elasticsearch, graphite, influxdb, redis and stdout. Several drivers can be given as a comma separated list, the stats are written to all of them")
 var versionFlag = flag.Bool("version", false, "print cAdvisor version and exit")
 
 var httpAuthFile = flag.String("http_auth_file", "", "HTTP auth file for the web UI")
@@ -43,7 +51,25 @@ var httpAuthRealm = flag.String("http_auth_realm", "localhost", "HTTP auth realm
 var httpDigestFile = flag.String("http_digest_file", "", "HTTP digest file for the web UI")
 var httpDigestRealm = flag.String("http_digest_realm", "localhost", "HTTP digest file for the web UI")
 
+var httpBasicAuthFile = flag.String("http_basic_auth_file", "", "htpasswd file of the users allowed to make any HTTP request, including to the API and metrics. Empty (default) for no authentication")
+var tlsCertFile = flag.String("tls_cert_file", "", "TLS certificate to serve HTTPS on -port with. Empty (default) to serve plain HTTP")
+var tlsKeyFile = flag.String("tls_key_file", "", "Private key of -tls_cert_file")
+var tlsClientCa = flag.String("tls_client_ca", "", "CA certificates verifying the client certificates required to make any request over HTTPS. Empty (default) for no client certificates")
+
+var httpReadTimeout = flag.Duration("http_read_timeout", 0, "Maximum duration for reading a request, including its body. 0 (default) for no timeout")
+var httpWriteTimeout = flag.Duration("http_write_timeout", 0, "Maximum duration for writing a response. Streamed stats and events are cut after it. 0 (default) for no timeout")
+var httpIdleTimeout = flag.Duration("http_idle_timeout", 2*time.Minute, "How long idle keep-alive connections are kept open. 0 uses -http_read_timeout")
+
 var prometheusEndpoint = flag.String("prometheus_endpoint", "/metrics", "Endpoint to expose Prometheus metrics on")
+var prometheusRemoteWriteUrl = flag.String("prometheus_remote_write_url", "", "URL of a Prometheus remote-write endpoint to periodically push metrics to. Empty disables pushing")
+var prometheusRemoteWriteInterval = flag.Duration("prometheus_remote_write_interval", 15*time.Second, "Interval between pushes of metrics to the Prometheus remote-write endpoint")
+
+// Metrics not collected for any container.
+var ignoreMetrics = container.MetricSet{}
+
+func init() {
+	flag.Var(ignoreMetrics, "disable_metrics", "comma-separated list of metrics not to collect. Options are 'disk', 'diskIO', 'network', 'tcp', 'udp' and 'process'. Empty (default) collects all metrics")
+}
 
 func main() {
 	defer glog.Flush()
@@ -66,7 +92,7 @@ func main() {
 		glog.Fatalf("Failed to create a system interface: %s", err)
 	}
 
-	containerManager, err := manager.New(memoryStorage, sysFs)
+	containerManager, err := manager.New(memoryStorage, sysFs, ignoreMetrics)
 	if err != nil {
 		glog.Fatalf("Failed to create a Container Manager: %s", err)
 	}
@@ -79,18 +105,87 @@ func main() {
 		glog.Fatalf("Failed to register HTTP handlers: %v", err)
 	}
 
+	if *prometheusRemoteWriteUrl != "" {
+		// Push the metrics collected by the registry exposed on the Prometheus endpoint.
+		metrics.NewRemoteWriter(*prometheusRemoteWriteUrl, *prometheusRemoteWriteInterval, prometheus.UninstrumentedHandler()).Start()
+		glog.Infof("Pushing Prometheus metrics to %q every %v", *prometheusRemoteWriteUrl, *prometheusRemoteWriteInterval)
+	}
+
 	// Start the manager.
 	if err := containerManager.Start(); err != nil {
 		glog.Fatalf("Failed to start container manager: %v", err)
 	}
 
-	// Install signal handler.
-	installSignalHandler(containerManager)
+	// Authenticate all requests, not only those of the UI.
+	var handler http.Handler = mux
+	if *httpBasicAuthFile != "" {
+		glog.Infof("Using basic auth file %s for all requests", *httpBasicAuthFile)
+		handler = cadvisorHttp.RequireBasicAuth(*httpBasicAuthFile, *httpAuthRealm, handler)
+	}
+	// Client certificates are only required on the TCP port, the socket is
+	// protected by its permissions.
+	tcpHandler := handler
+	if *tlsClientCa != "" {
+		if *tlsCertFile == "" {
+			glog.Fatalf("-tls_client_ca requires -tls_cert_file and -tls_key_file")
+		}
+		tcpHandler = cadvisorHttp.RequireClientCert(handler)
+	}
+
+	listeners := make(map[net.Listener]http.Handler)
+	if *argListenSocket != "" {
+		l, err := cadvisorHttp.ListenUnix(*argListenSocket)
+		if err != nil {
+			glog.Fatalf("Failed to listen on socket %q: %v", *argListenSocket, err)
+		}
+		glog.Infof("Starting cAdvisor version: %q on socket %q", version.VERSION, *argListenSocket)
+		listeners[l] = handler
+	}
+	if *argPort != 0 {
+		addr := fmt.Sprintf("%s:%d", *argIp, *argPort)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			glog.Fatalf("Failed to listen on %q: %v", addr, err)
+		}
+		if *tlsCertFile != "" {
+			tlsConfig, err := cadvisorHttp.NewTLSConfig(*tlsCertFile, *tlsKeyFile, *tlsClientCa)
+			if err != nil {
+				glog.Fatalf("Failed to set up TLS: %v", err)
+			}
+			l = tls.NewListener(l, tlsConfig)
+		}
+		glog.Infof("Starting cAdvisor version: %q on port %d", version.VERSION, *argPort)
+		listeners[l] = tcpHandler
+	}
+	if len(listeners) == 0 {
+		glog.Fatalf("Nothing to listen on, -port is 0 and -listen_socket is empty")
+	}
 
-	glog.Infof("Starting cAdvisor version: %q on port %d", version.VERSION, *argPort)
+	// Serve on all the listeners until one of them fails.
+	errs := make(chan error, len(listeners))
+	servers := make([]*http.Server, 0, len(listeners))
+	for l, h := range listeners {
+		server := &http.Server{
+			Handler:      h,
+			ReadTimeout:  *httpReadTimeout,
+			WriteTimeout: *httpWriteTimeout,
+			IdleTimeout:  *httpIdleTimeout,
+		}
+		servers = append(servers, server)
+		go func(l net.Listener) {
+			errs <- server.Serve(l)
+		}(l)
+	}
+
+	// Install signal handler.
+	installSignalHandler(containerManager, servers)
 
-	addr := fmt.Sprintf("%s:%d", *argIp, *argPort)
-	glog.Fatal(http.ListenAndServe(addr, nil))
+	err = <-errs
+	if err == http.ErrServerClosed {
+		// Shutting down, the signal handler exits.
+		select {}
+	}
+	glog.Fatal(err)
 }
 
 func setMaxProcs() {
@@ -111,17 +206,28 @@ func setMaxProcs() {
 	}
 }
 
-func installSignalHandler(containerManager manager.Manager) {
+func installSignalHandler(containerManager manager.Manager, servers []*http.Server) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, os.Kill, syscall.SIGTERM)
 
 	// Block until a signal is received.
 	go func() {
 		sig := <-c
-		if err := containerManager.Stop(); err != nil {
-			glog.Errorf("Failed to stop container manager: %v", err)
+		glog.Infof("Shutting down given signal: %v", sig)
+		// Stop accepting requests and give the pending ones some time to
+		// complete. Streams are cut at the deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), *manager.ShutdownTimeout)
+		for _, server := range servers {
+			if err := server.Shutdown(ctx); err != nil {
+				server.Close()
+			}
+		}
+		cancel()
+		if err := containerManager.Shutdown(); err != nil {
+			glog.Errorf("Failed to shut down container manager: %v", err)
 		}
 		glog.Infof("Exiting given signal: %v", sig)
+		glog.Flush()
 		os.Exit(0)
 	}()
 }
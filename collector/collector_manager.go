@@ -0,0 +1,84 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+type collectorData struct {
+	collector          Collector
+	nextCollectionTime time.Time
+}
+
+type GenericCollectorManager struct {
+	Collectors         []*collectorData
+	NextCollectionTime time.Time
+}
+
+// Returns a new CollectorManager with no collectors.
+func NewCollectorManager() (CollectorManager, error) {
+	return &GenericCollectorManager{
+		Collectors:         []*collectorData{},
+		NextCollectionTime: time.Now(),
+	}, nil
+}
+
+func (self *GenericCollectorManager) RegisterCollector(collector Collector) error {
+	self.Collectors = append(self.Collectors, &collectorData{
+		collector:          collector,
+		nextCollectionTime: time.Now(),
+	})
+	return nil
+}
+
+func (self *GenericCollectorManager) GetSpec() []info.MetricSpec {
+	var specs []info.MetricSpec
+	for _, c := range self.Collectors {
+		specs = append(specs, c.collector.GetSpec()...)
+	}
+	return specs
+}
+
+func (self *GenericCollectorManager) Collect() (time.Time, map[string][]info.MetricVal, error) {
+	var errors []string
+
+	// Collect from all collectors that are ready.
+	var next time.Time
+	metrics := map[string][]info.MetricVal{}
+	for _, c := range self.Collectors {
+		if c.nextCollectionTime.Before(time.Now()) {
+			var err error
+			c.nextCollectionTime, metrics, err = c.collector.Collect(metrics)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("collector %q failed with error %q", c.collector.Name(), err))
+			}
+		}
+
+		// Keep track of the next collector that will be ready.
+		if next.IsZero() || next.After(c.nextCollectionTime) {
+			next = c.nextCollectionTime
+		}
+	}
+	self.NextCollectionTime = next
+	if len(errors) > 0 {
+		return next, metrics, fmt.Errorf("%s", strings.Join(errors, ", "))
+	}
+	return next, metrics, nil
+}
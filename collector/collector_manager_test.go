@@ -0,0 +1,68 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCollector struct {
+	name     string
+	interval time.Duration
+	err      error
+	calls    int
+}
+
+func (fc *fakeCollector) Collect(metrics map[string][]info.MetricVal) (time.Time, map[string][]info.MetricVal, error) {
+	fc.calls++
+	metrics[fc.name] = []info.MetricVal{{IntValue: int64(fc.calls)}}
+	return time.Now().Add(fc.interval), metrics, fc.err
+}
+
+func (fc *fakeCollector) Name() string {
+	return fc.name
+}
+
+func (fc *fakeCollector) GetSpec() []info.MetricSpec {
+	return []info.MetricSpec{{Name: fc.name}}
+}
+
+func TestCollect(t *testing.T) {
+	cm, err := NewCollectorManager()
+	assert.NoError(t, err)
+
+	frequent := &fakeCollector{name: "frequent"}
+	rare := &fakeCollector{name: "rare", interval: time.Hour, err: errors.New("partial failure")}
+	assert.NoError(t, cm.RegisterCollector(frequent))
+	assert.NoError(t, cm.RegisterCollector(rare))
+	assert.Equal(t, []info.MetricSpec{{Name: "frequent"}, {Name: "rare"}}, cm.GetSpec())
+
+	// Both collectors are called at first, a failing one still reports its metrics.
+	_, metrics, err := cm.Collect()
+	assert.Error(t, err)
+	assert.Equal(t, 2, len(metrics))
+
+	// Only the collector that is due is called afterwards.
+	_, metrics, err = cm.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(metrics))
+	assert.Equal(t, int64(2), metrics["frequent"][0].IntValue)
+	assert.Equal(t, 1, rare.calls)
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Configuration of a generic collector, read from the JSON file a container
+// label points to.
+type Config struct {
+	// URL of the endpoint exporting the metrics. It is a template where
+	// {{.Ip}} is replaced by the IP address of the container, e.g.
+	// "http://{{.Ip}}:8080/metrics".
+	Endpoint string `json:"endpoint"`
+
+	// HTTP headers sent with each request to the endpoint, e.g.
+	// {"Authorization": "Bearer <token>"}.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Credentials sent with each request to the endpoint using basic auth.
+	BasicAuth *BasicAuth `json:"basic_auth,omitempty"`
+
+	// File the bearer token sent with each request is read from, e.g. a
+	// Kubernetes service account token, relative to
+	// -collector_credentials_dir. It is re-read on each request so rotated
+	// tokens are picked up.
+	BearerTokenFile string `json:"bearer_token_file,omitempty"`
+
+	// PEM file of the certificate authorities the certificate of an HTTPS
+	// endpoint is verified with, relative to -collector_credentials_dir. The
+	// host's ones are used if empty.
+	TlsCaFile string `json:"tls_ca_file,omitempty"`
+
+	// Whether to skip verifying the certificate of an HTTPS endpoint.
+	TlsInsecureSkipVerify bool `json:"tls_insecure_skip_verify,omitempty"`
+
+	// The metrics to collect from the endpoint.
+	MetricsConfig []MetricConfig `json:"metrics_config"`
+}
+
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Configuration of a metric collected by a generic collector.
+type MetricConfig struct {
+	// The name of the metric.
+	Name string `json:"name"`
+
+	// Type of the metric (gauge or cumulative).
+	MetricType info.MetricType `json:"metric_type"`
+
+	// Data type of the metric (int or float).
+	DataType info.DataType `json:"data_type"`
+
+	// Units of the metric.
+	Units string `json:"units"`
+
+	// How often to collect the metric, in nanoseconds.
+	PollingFrequency time.Duration `json:"polling_frequency"`
+
+	// Regular expression matching the value of the metric in the response
+	// of the endpoint as its first submatch.
+	Regex string `json:"regex"`
+}
@@ -0,0 +1,286 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Timeout of the requests to the endpoint of a generic collector.
+const requestTimeout = 10 * time.Second
+
+var configDir = flag.String("collector_config_dir", "", "Directory the configurations of the collectors named by container labels are read from, as paths relative to it. Container labels cannot configure collectors if empty")
+var credentialsDir = flag.String("collector_credentials_dir", "", "Directory the bearer_token_file and tls_ca_file of the collectors configured by container labels are read from, as paths relative to it. Collectors cannot read credential files if empty")
+
+// Collects metrics from an HTTP endpoint, matching each metric in the
+// response with a regular expression.
+type GenericCollector struct {
+	// Name of the collector.
+	name string
+
+	// Configuration of the collector.
+	config Config
+
+	// URL of the endpoint, with the template expanded.
+	endpoint string
+
+	// Client used to query the endpoint.
+	client *http.Client
+
+	// Compiled regexps of the metrics, in the order of config.MetricsConfig.
+	regexps []*regexp.Regexp
+
+	// Smallest polling frequency of the metrics.
+	minPollingFrequency time.Duration
+}
+
+// Values the endpoint template of a collector is expanded with.
+type endpointArgs struct {
+	Ip string
+}
+
+// Returns a new collector from the JSON configuration in configFile.
+// containerIp is the IP address of the container the application runs in.
+func NewGenericCollector(collectorName string, configFile []byte, containerIp string) (*GenericCollector, error) {
+	var config Config
+	err := json.Unmarshal(configFile, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the configuration of collector %q: %v", collectorName, err)
+	}
+	if len(config.MetricsConfig) == 0 {
+		return nil, fmt.Errorf("no metrics configured for collector %q", collectorName)
+	}
+
+	regexps := make([]*regexp.Regexp, len(config.MetricsConfig))
+	var minPollingFrequency time.Duration
+	for i, metricConfig := range config.MetricsConfig {
+		regexps[i], err = regexp.Compile(metricConfig.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp %q for metric %q of collector %q: %v", metricConfig.Regex, metricConfig.Name, collectorName, err)
+		}
+		if metricConfig.PollingFrequency <= 0 {
+			return nil, fmt.Errorf("invalid polling frequency %v for metric %q of collector %q", metricConfig.PollingFrequency, metricConfig.Name, collectorName)
+		}
+		if minPollingFrequency == 0 || metricConfig.PollingFrequency < minPollingFrequency {
+			minPollingFrequency = metricConfig.PollingFrequency
+		}
+	}
+
+	endpoint, err := expandEndpoint(config.Endpoint, containerIp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint of collector %q: %v", collectorName, err)
+	}
+	client, err := newHttpClient(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the HTTP client of collector %q: %v", collectorName, err)
+	}
+
+	return &GenericCollector{
+		name:                collectorName,
+		config:              config,
+		endpoint:            endpoint,
+		client:              client,
+		regexps:             regexps,
+		minPollingFrequency: minPollingFrequency,
+	}, nil
+}
+
+// Expands the endpoint template with the IP address of the container.
+func expandEndpoint(endpoint string, containerIp string) (string, error) {
+	tmpl, err := template.New("endpoint").Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, endpointArgs{Ip: containerIp})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Returns an HTTP client verifying the certificates of HTTPS endpoints as
+// configured.
+func newHttpClient(config *Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.TlsInsecureSkipVerify,
+	}
+	if config.TlsCaFile != "" {
+		pem, err := readCredentialsFile(config.TlsCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %q", config.TlsCaFile)
+		}
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+		Timeout: requestTimeout,
+	}, nil
+}
+
+// Reads the configuration of a collector named by a container label. The
+// labels come from containers, so the file must be within -collector_config_dir
+// for containers not to make cAdvisor read any file of the host.
+func ReadConfigFile(name string) ([]byte, error) {
+	return readFileWithin(*configDir, "-collector_config_dir", "configuration", name)
+}
+
+// Reads a credentials file of a collector. The configuration of collectors
+// comes from containers, so the file must be within -collector_credentials_dir
+// for containers not to make cAdvisor read, and send to an endpoint of their
+// choosing, any file of the host.
+func readCredentialsFile(name string) ([]byte, error) {
+	return readFileWithin(*credentialsDir, "-collector_credentials_dir", "credential", name)
+}
+
+// Reads the file at the relative path name within dir, set by the flag
+// flagName. Absolute paths, paths with ".." elements and symlinks leading out
+// of dir are rejected.
+func readFileWithin(dir, flagName, kind, name string) ([]byte, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%s files are not allowed without %s", kind, flagName)
+	}
+	if filepath.IsAbs(name) {
+		return nil, fmt.Errorf("%s file %q must be relative to %s", kind, name, flagName)
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(name), "/") {
+		if elem == ".." {
+			return nil, fmt.Errorf("%s file %q must not contain \"..\"", kind, name)
+		}
+	}
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	file, err := filepath.EvalSymlinks(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file %q within %s", kind, name, flagName)
+	}
+	// Checked after the symlinks are resolved, which catches links pointing
+	// out of the directory.
+	if rel, err := filepath.Rel(dir, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s file %q is not within %s", kind, name, flagName)
+	}
+	return ioutil.ReadFile(file)
+}
+
+func (self *GenericCollector) Name() string {
+	return self.name
+}
+
+func (self *GenericCollector) GetSpec() []info.MetricSpec {
+	specs := make([]info.MetricSpec, 0, len(self.config.MetricsConfig))
+	for _, metricConfig := range self.config.MetricsConfig {
+		specs = append(specs, info.MetricSpec{
+			Name:   metricConfig.Name,
+			Type:   metricConfig.MetricType,
+			Format: metricConfig.DataType,
+			Units:  metricConfig.Units,
+		})
+	}
+	return specs
+}
+
+// Returns the request to the endpoint, with the configured headers and credentials.
+func (self *GenericCollector) newRequest() (*http.Request, error) {
+	req, err := http.NewRequest("GET", self.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range self.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if self.config.BasicAuth != nil {
+		req.SetBasicAuth(self.config.BasicAuth.Username, self.config.BasicAuth.Password)
+	}
+	if self.config.BearerTokenFile != "" {
+		token, err := readCredentialsFile(self.config.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bearer token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return req, nil
+}
+
+// Collects the metrics from the endpoint. Metrics not found in its response
+// are reported in the returned error, the others are still added to metrics.
+func (self *GenericCollector) Collect(metrics map[string][]info.MetricVal) (time.Time, map[string][]info.MetricVal, error) {
+	currentTime := time.Now()
+	nextCollectionTime := currentTime.Add(self.minPollingFrequency)
+
+	req, err := self.newRequest()
+	if err != nil {
+		return nextCollectionTime, metrics, err
+	}
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return nextCollectionTime, metrics, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nextCollectionTime, metrics, fmt.Errorf("request to %q failed with status %q", self.endpoint, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nextCollectionTime, metrics, err
+	}
+
+	var errs []string
+	for i, metricConfig := range self.config.MetricsConfig {
+		matches := self.regexps[i].FindSubmatch(body)
+		if len(matches) < 2 {
+			errs = append(errs, fmt.Sprintf("no match for metric %q", metricConfig.Name))
+			continue
+		}
+		value := strings.TrimSpace(string(matches[1]))
+		metric := info.MetricVal{Timestamp: currentTime}
+		if metricConfig.DataType == info.FloatType {
+			metric.FloatValue, err = strconv.ParseFloat(value, 64)
+		} else {
+			metric.IntValue, err = strconv.ParseInt(value, 10, 64)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value %q for metric %q: %v", value, metricConfig.Name, err))
+			continue
+		}
+		metrics[metricConfig.Name] = append(metrics[metricConfig.Name], metric)
+	}
+	if len(errs) > 0 {
+		return nextCollectionTime, metrics, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nextCollectionTime, metrics, nil
+}
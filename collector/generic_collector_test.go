@@ -0,0 +1,232 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+const metricsConfig = `"metrics_config": [
+	{"name": "activeConnections", "metric_type": "gauge", "data_type": "int", "units": "connections", "polling_frequency": 10000000000, "regex": "Active connections: ([0-9]+)"},
+	{"name": "load", "metric_type": "gauge", "data_type": "float", "units": "", "polling_frequency": 5000000000, "regex": "Load: ([0-9.]+)"}
+]`
+
+func TestNewGenericCollector(t *testing.T) {
+	c, err := NewGenericCollector("nginx", []byte(`{"endpoint": "http://{{.Ip}}:8000/status", `+metricsConfig+`}`), "10.0.0.2")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "nginx", c.Name())
+	assert.Equal(t, "http://10.0.0.2:8000/status", c.endpoint)
+	assert.Equal(t, 5*time.Second, c.minPollingFrequency)
+	assert.Equal(t, []info.MetricSpec{
+		{Name: "activeConnections", Type: info.MetricGauge, Format: info.IntType, Units: "connections"},
+		{Name: "load", Type: info.MetricGauge, Format: info.FloatType},
+	}, c.GetSpec())
+
+	invalid := []string{
+		`{"endpoint": "http://localhost/status"}`,
+		`{"endpoint": "http://{{.Ip/status", ` + metricsConfig + `}`,
+		`{"endpoint": "http://localhost/status", "metrics_config": [{"name": "a", "polling_frequency": 1000000000, "regex": "("}]}`,
+		`{"endpoint": "http://localhost/status", "metrics_config": [{"name": "a", "regex": "a: (.*)"}]}`,
+		`{"endpoint": "http://localhost/status", "tls_ca_file": "does-not-exist", ` + metricsConfig + `}`,
+	}
+	for _, config := range invalid {
+		_, err := NewGenericCollector("nginx", []byte(config), "10.0.0.2")
+		assert.Error(t, err, config)
+	}
+}
+
+func TestCollectWithAuth(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "cadvisor" || password != "secret" || r.Header.Get("X-Scope") != "metrics" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, "Active connections: 3")
+		fmt.Fprintln(w, "Load: 0.25")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "collector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(dir string) { *credentialsDir = dir }(*credentialsDir)
+	*credentialsDir = dir
+	caFile := "ca.pem"
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.TLS.Certificates[0].Certificate[0]})
+	err = ioutil.WriteFile(path.Join(dir, caFile), ca, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := strings.Replace(server.URL, "127.0.0.1", "{{.Ip}}", 1)
+	config := fmt.Sprintf(`{"endpoint": %q, "tls_ca_file": %q, "headers": {"X-Scope": "metrics"}, "basic_auth": {"username": "cadvisor", "password": "secret"}, %s}`, endpoint, caFile, metricsConfig)
+	c, err := NewGenericCollector("nginx", []byte(config), "127.0.0.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, metrics, err := c.Collect(map[string][]info.MetricVal{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(3), metrics["activeConnections"][0].IntValue)
+		assert.Equal(t, 0.25, metrics["load"][0].FloatValue)
+	}
+
+	// Without the credentials the request is rejected.
+	config = fmt.Sprintf(`{"endpoint": %q, "tls_ca_file": %q, %s}`, server.URL, caFile, metricsConfig)
+	c, err = NewGenericCollector("nginx", []byte(config), "127.0.0.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, metrics, err = c.Collect(map[string][]info.MetricVal{})
+	assert.Error(t, err)
+	assert.Empty(t, metrics)
+
+	// Without the CA the certificate of the server is not trusted.
+	config = fmt.Sprintf(`{"endpoint": %q, %s}`, server.URL, metricsConfig)
+	c, err = NewGenericCollector("nginx", []byte(config), "127.0.0.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, _, err = c.Collect(map[string][]info.MetricVal{})
+	assert.Error(t, err)
+}
+
+func TestCollectBearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, "Active connections: 7")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "collector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(dir string) { *credentialsDir = dir }(*credentialsDir)
+	*credentialsDir = dir
+	tokenFile := "token"
+	err = ioutil.WriteFile(path.Join(dir, tokenFile), []byte("abc\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := fmt.Sprintf(`{"endpoint": %q, "bearer_token_file": %q, %s}`, server.URL, tokenFile, metricsConfig)
+	c, err := NewGenericCollector("nginx", []byte(config), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, metrics, err := c.Collect(map[string][]info.MetricVal{})
+	// The load is not in the response but the other metric is still collected.
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "load")
+	}
+	assert.Equal(t, int64(7), metrics["activeConnections"][0].IntValue)
+	assert.Empty(t, metrics["load"])
+}
+
+func TestReadCredentialsFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "collector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := path.Join(root, "credentials")
+	for _, d := range []string{dir, path.Join(dir, "app")} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for file, content := range map[string]string{path.Join(dir, "app", "token"): "abc", path.Join(root, "secret"): "host"} {
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(path.Join(root, "secret"), path.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(dir string) { *credentialsDir = dir }(*credentialsDir)
+	*credentialsDir = ""
+	_, err = readCredentialsFile("app/token")
+	assert.Error(t, err)
+
+	*credentialsDir = dir
+	token, err := readCredentialsFile("app/token")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", string(token))
+
+	for _, name := range []string{path.Join(root, "secret"), "../secret", "app/../../secret", "app/../app/token", "link", "missing"} {
+		_, err := readCredentialsFile(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "collector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := path.Join(root, "config")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for file, content := range map[string]string{path.Join(dir, "nginx.json"): "{}", path.Join(root, "secret"): "host"} {
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(path.Join(root, "secret"), path.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(dir string) { *configDir = dir }(*configDir)
+	*configDir = ""
+	_, err = ReadConfigFile("nginx.json")
+	assert.Error(t, err)
+
+	*configDir = dir
+	config, err := ReadConfigFile("nginx.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(config))
+
+	// Paths outside of the directory are rejected without echoing the file.
+	for _, name := range []string{path.Join(root, "secret"), "../secret", "./../secret", "link"} {
+		_, err := ReadConfigFile(name)
+		if assert.Error(t, err, name) {
+			assert.NotContains(t, err.Error(), "host")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Collectors of the custom metrics exported by applications running in containers.
+package collector
+
+import (
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Collects the custom metrics of one application.
+type Collector interface {
+	// Collects the metrics of the application and adds them to metrics, which
+	// is returned even when collection fails.
+	// Returns the next time the collector should be called.
+	Collect(metrics map[string][]info.MetricVal) (time.Time, map[string][]info.MetricVal, error)
+
+	// Returns the specs of the metrics collected.
+	GetSpec() []info.MetricSpec
+
+	// Name of the collector.
+	Name() string
+}
+
+// Manages the collectors of a container and calls each of them when it is due.
+type CollectorManager interface {
+	// Registers a collector of the container.
+	RegisterCollector(collector Collector) error
+
+	// Calls the collectors that are due and returns the metrics they collected.
+	// Returns the next time collection should be attempted.
+	Collect() (time.Time, map[string][]info.MetricVal, error)
+
+	// Returns the specs of the metrics of all the collectors.
+	GetSpec() []info.MetricSpec
+}
@@ -16,7 +16,13 @@
 // defines an interface for container operation handlers.
 package container
 
-import info "github.com/google/cadvisor/info/v1"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	info "github.com/google/cadvisor/info/v1"
+)
 
 // ListType describes whether listing should be just for a
 // specific container or performed recursively.
@@ -75,4 +81,161 @@ type ContainerHandler interface {
 
 	// Returns whether the container still exists.
 	Exists() bool
+
+	// Returns the IP address of the container, applications in it listen on.
+	GetContainerIPAddress() string
+}
+
+// Implemented by handlers which can leave metrics out of a single stats
+// collection, e.g. to collect the costly ones less often than the others.
+type PartialStatsHandler interface {
+	// Returns the current stats values of the container, without the metrics in skip.
+	GetPartialStats(skip MetricSet) (*info.ContainerStats, error)
+}
+
+// Implemented by handlers whose runtime reports how containers exited.
+type ExitStatusHandler interface {
+	// Returns how the container exited, once it stopped.
+	GetExitStatus() (*info.ContainerExitStatus, error)
+}
+
+// Subsystems the stats of containers are collected for.
+const (
+	SubsystemCpu        = "cpu"
+	SubsystemMemory     = "memory"
+	SubsystemNetwork    = "network"
+	SubsystemDiskIo     = "diskio"
+	SubsystemFilesystem = "filesystem"
+	SubsystemPsi        = "psi"
+	SubsystemProcesses  = "processes"
+)
+
+// Failure to collect the stats of some subsystems of a container, as returned
+// by GetStats(). The stats of the other subsystems are valid.
+type StatsError struct {
+	// Error of each subsystem that failed.
+	Errors map[string]error
+}
+
+func (self *StatsError) Error() string {
+	subsystems := make([]string, 0, len(self.Errors))
+	for subsystem := range self.Errors {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	msgs := make([]string, 0, len(subsystems))
+	for _, subsystem := range subsystems {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", subsystem, self.Errors[subsystem]))
+	}
+	return fmt.Sprintf("failed to get stats of %s", strings.Join(msgs, "; "))
+}
+
+// Records err, if any, as the failure of subsystem. The failures of err are
+// merged in if it is itself a *StatsError.
+func (self *StatsError) Add(subsystem string, err error) {
+	if err == nil {
+		return
+	}
+	if self.Errors == nil {
+		self.Errors = make(map[string]error)
+	}
+	if statsErr, ok := err.(*StatsError); ok {
+		for s, e := range statsErr.Errors {
+			self.Errors[s] = e
+		}
+		return
+	}
+	self.Errors[subsystem] = err
+}
+
+// Returns the error to report, nil if no subsystem failed.
+func (self *StatsError) OrNil() error {
+	if len(self.Errors) == 0 {
+		return nil
+	}
+	return self
+}
+
+// Kinds of metrics whose collection can be disabled.
+type MetricKind string
+
+const (
+	DiskUsageMetrics       MetricKind = "disk"
+	DiskIoMetrics          MetricKind = "diskIO"
+	NetworkUsageMetrics    MetricKind = "network"
+	NetworkTcpUsageMetrics MetricKind = "tcp"
+	NetworkUdpUsageMetrics MetricKind = "udp"
+	ProcessMetrics         MetricKind = "process"
+)
+
+// Kinds of the metrics of the network of containers.
+var NetworkMetrics = MetricSet{NetworkUsageMetrics: {}, NetworkTcpUsageMetrics: {}, NetworkUdpUsageMetrics: {}}
+
+var allMetricKinds = []MetricKind{DiskUsageMetrics, DiskIoMetrics, NetworkUsageMetrics, NetworkTcpUsageMetrics, NetworkUdpUsageMetrics, ProcessMetrics}
+
+// Set of metric kinds, usable as a flag holding a comma separated list.
+type MetricSet map[MetricKind]struct{}
+
+func (self MetricSet) Has(kind MetricKind) bool {
+	_, ok := self[kind]
+	return ok
+}
+
+// Whether the set holds all the metric kinds of other.
+func (self MetricSet) HasAll(other MetricSet) bool {
+	for kind := range other {
+		if !self.Has(kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the metric kinds in either set.
+func (self MetricSet) Union(other MetricSet) MetricSet {
+	union := make(MetricSet, len(self)+len(other))
+	for kind := range self {
+		union[kind] = struct{}{}
+	}
+	for kind := range other {
+		union[kind] = struct{}{}
+	}
+	return union
+}
+
+func (self MetricSet) String() string {
+	kinds := make([]string, 0, len(self))
+	for kind := range self {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ",")
+}
+
+// Replaces the content of the set by the comma separated metric kinds of value.
+func (self MetricSet) Set(value string) error {
+	kinds := MetricSet{}
+	for _, kind := range strings.Split(value, ",") {
+		if kind == "" {
+			continue
+		}
+		known := false
+		for _, k := range allMetricKinds {
+			if MetricKind(kind) == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unsupported metric kind %q, supported kinds are %v", kind, allMetricKinds)
+		}
+		kinds[MetricKind(kind)] = struct{}{}
+	}
+	for kind := range self {
+		delete(self, kind)
+	}
+	for kind := range kinds {
+		self[kind] = struct{}{}
+	}
+	return nil
 }
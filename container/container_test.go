@@ -0,0 +1,53 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricSet(t *testing.T) {
+	metrics := MetricSet{}
+	assert.NoError(t, metrics.Set("tcp,disk"))
+	assert.True(t, metrics.Has(DiskUsageMetrics))
+	assert.True(t, metrics.Has(NetworkTcpUsageMetrics))
+	assert.False(t, metrics.Has(NetworkUsageMetrics))
+	assert.Equal(t, "disk,tcp", metrics.String())
+	assert.False(t, metrics.HasAll(NetworkMetrics))
+	assert.True(t, metrics.HasAll(MetricSet{DiskUsageMetrics: {}}))
+
+	// Setting replaces the previous content.
+	assert.NoError(t, metrics.Set("network"))
+	assert.Equal(t, "network", metrics.String())
+	assert.NoError(t, metrics.Set(""))
+	assert.Equal(t, 0, len(metrics))
+
+	// Unknown kinds are rejected and leave the set unchanged.
+	assert.Error(t, metrics.Set("disk,cpu"))
+	assert.Equal(t, 0, len(metrics))
+}
+
+func TestFilterEnv(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "DB_PASSWORD=hunter2", "api_key=abc", "LOG_LEVEL=debug", "EMPTY=", "FLAG", "GREETING=a=b"}
+	denied := splitPatterns(" *PASSWORD* ,*KEY*,")
+
+	assert.Equal(t, map[string]string{"PATH": "/usr/bin", "LOG_LEVEL": "debug", "EMPTY": "", "FLAG": "", "GREETING": "a=b"}, filterEnv(env, nil, denied))
+	// The denylist wins over the allowlist.
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug"}, filterEnv(env, splitPatterns("LOG_*,DB_*"), denied))
+	// Not exposed by default.
+	assert.Nil(t, FilterEnv(env))
+}
@@ -0,0 +1,187 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	containersapi "github.com/containerd/containerd/api/services/containers/v1"
+	imagesapi "github.com/containerd/containerd/api/services/images/v1"
+	namespacesapi "github.com/containerd/containerd/api/services/namespaces/v1"
+	tasksapi "github.com/containerd/containerd/api/services/tasks/v1"
+	tasktypes "github.com/containerd/containerd/api/types/task"
+	"github.com/golang/glog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Timeout of the calls to the containerd API.
+var apiTimeout = 5 * time.Second
+
+// gRPC metadata key selecting the containerd namespace of a call.
+const namespaceKey = "containerd-namespace"
+
+// The calls to the containerd API cAdvisor needs.
+type containerdClient interface {
+	// Returns the names of the namespaces of containerd.
+	Namespaces() ([]string, error)
+	// Returns the containers of a namespace, with the pid of their task.
+	Tasks(namespace string) ([]*task, error)
+	// Returns the container with the given ID in a namespace, with the pid of
+	// its task, nil if there is no such container.
+	Task(namespace, id string) (*task, error)
+	// Returns the digest of the image with the given name in a namespace,
+	// empty if there is no such image.
+	ImageDigest(namespace, name string) (string, error)
+}
+
+// Client of the containerd API over its gRPC socket.
+type grpcClient struct {
+	namespaces namespacesapi.NamespacesClient
+	containers containersapi.ContainersClient
+	tasks      tasksapi.TasksClient
+	images     imagesapi.ImagesClient
+}
+
+// Returns a client of the containerd socket at the given path, checking that
+// containerd answers on it.
+func newGrpcClient(socket string) (*grpcClient, error) {
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	client := &grpcClient{
+		namespaces: namespacesapi.NewNamespacesClient(conn),
+		containers: containersapi.NewContainersClient(conn),
+		tasks:      tasksapi.NewTasksClient(conn),
+		images:     imagesapi.NewImagesClient(conn),
+	}
+	if _, err := client.Namespaces(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// Returns the context of a call in the given namespace, empty for calls
+// which do not apply to a namespace.
+func callContext(namespace string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	if namespace != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, namespaceKey, namespace)
+	}
+	return ctx, cancel
+}
+
+func (self *grpcClient) Namespaces() ([]string, error) {
+	ctx, cancel := callContext("")
+	defer cancel()
+	resp, err := self.namespaces.List(ctx, &namespacesapi.ListNamespacesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the containerd namespaces: %v", err)
+	}
+	names := make([]string, 0, len(resp.Namespaces))
+	for _, namespace := range resp.Namespaces {
+		names = append(names, namespace.Name)
+	}
+	return names, nil
+}
+
+func (self *grpcClient) Tasks(namespace string) ([]*task, error) {
+	ctx, cancel := callContext(namespace)
+	defer cancel()
+	containers, err := self.containers.List(ctx, &containersapi.ListContainersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the containers of containerd namespace %q: %v", namespace, err)
+	}
+	running, err := self.tasks.List(ctx, &tasksapi.ListTasksRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tasks of containerd namespace %q: %v", namespace, err)
+	}
+	pids := make(map[string]int, len(running.Tasks))
+	for _, process := range running.Tasks {
+		pids[process.ID] = processPid(process)
+	}
+	tasks := make([]*task, 0, len(containers.Containers))
+	for _, c := range containers.Containers {
+		t, err := containerTask(namespace, c, pids[c.ID])
+		if err != nil {
+			glog.V(2).Infof("skipping containerd container: %v", err)
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func (self *grpcClient) Task(namespace, id string) (*task, error) {
+	ctx, cancel := callContext(namespace)
+	defer cancel()
+	resp, err := self.containers.Get(ctx, &containersapi.GetContainerRequest{ID: id})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get containerd container %q: %v", id, err)
+	}
+	pid := 0
+	process, err := self.tasks.Get(ctx, &tasksapi.GetRequest{ContainerID: id})
+	switch {
+	case err == nil:
+		pid = processPid(process.Process)
+	case status.Code(err) != codes.NotFound:
+		// Containers without task have not been started or have exited.
+		return nil, fmt.Errorf("failed to get the task of containerd container %q: %v", id, err)
+	}
+	return containerTask(namespace, resp.Container, pid)
+}
+
+func (self *grpcClient) ImageDigest(namespace, name string) (string, error) {
+	ctx, cancel := callContext(namespace)
+	defer cancel()
+	resp, err := self.images.Get(ctx, &imagesapi.GetImageRequest{Name: name})
+	if status.Code(err) == codes.NotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get containerd image %q: %v", name, err)
+	}
+	return resp.Image.GetTarget().GetDigest(), nil
+}
+
+// Returns the pid of the init process of a task, 0 if it is not running.
+func processPid(process *tasktypes.Process) int {
+	if process == nil || process.Status == tasktypes.Status_STOPPED {
+		return 0
+	}
+	return int(process.Pid)
+}
+
+// Returns the task of a container given by the containerd API.
+func containerTask(namespace string, c *containersapi.Container, pid int) (*task, error) {
+	var spec []byte
+	if c.Spec != nil {
+		spec = c.Spec.Value
+	}
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("containerd container %q has no spec", c.ID)
+	}
+	return newTask(namespace, c.ID, c.Image, c.Labels, spec, pid)
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/container"
+	"github.com/google/cadvisor/container/libcontainer"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/utils"
+)
+
+var ArgContainerdEndpoint = flag.String("containerd", "/run/containerd/containerd.sock", "Path to the gRPC socket of containerd")
+
+// The namespace under which containerd aliases are unique.
+const ContainerdNamespace = "containerd"
+
+// Label holding the containerd namespace of a container, e.g. "k8s.io".
+const NamespaceLabel = "io.containerd.namespace"
+
+type containerdFactory struct {
+	machineInfoFactory info.MachineInfoFactory
+
+	client containerdClient
+	tasks  *taskIndex
+
+	// Information about the mounted cgroup subsystems.
+	cgroupSubsystems libcontainer.CgroupSubsystems
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
+}
+
+func (self *containerdFactory) String() string {
+	return ContainerdNamespace
+}
+
+func (self *containerdFactory) NewContainerHandler(name string) (container.ContainerHandler, error) {
+	return newContainerdContainerHandler(self.client, self.tasks, name, self.machineInfoFactory, &self.cgroupSubsystems, self.ignoreMetrics)
+}
+
+// containerd handles the containers of its running tasks. Other containers,
+// or all of them if containerd cannot be reached, are left to the raw handler.
+func (self *containerdFactory) CanHandle(name string) (bool, error) {
+	t, err := self.tasks.get(name)
+	if err != nil {
+		return false, fmt.Errorf("error getting the containerd tasks: %v", err)
+	}
+	return t != nil && t.Pid > 0, nil
+}
+
+// Register root container before running this function!
+func Register(factory info.MachineInfoFactory, ignoreMetrics container.MetricSet) error {
+	if !utils.FileExists(*ArgContainerdEndpoint) {
+		return fmt.Errorf("containerd socket not found at %q", *ArgContainerdEndpoint)
+	}
+	client, err := newGrpcClient(*ArgContainerdEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to connect to containerd: %v", err)
+	}
+
+	cgroupSubsystems, err := libcontainer.GetCgroupSubsystems()
+	if err != nil {
+		return fmt.Errorf("failed to get cgroup subsystems: %v", err)
+	}
+
+	glog.Infof("Registering containerd factory (socket %q)", *ArgContainerdEndpoint)
+	f := &containerdFactory{
+		machineInfoFactory: factory,
+		client:             client,
+		tasks:              newTaskIndex(client),
+		cgroupSubsystems:   cgroupSubsystems,
+		ignoreMetrics:      ignoreMetrics,
+	}
+	container.RegisterContainerHandlerFactory(f)
+	return nil
+}
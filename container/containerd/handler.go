@@ -0,0 +1,264 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Handler for containerd containers.
+package containerd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcontainer"
+	"github.com/docker/libcontainer/cgroups"
+	cgroup_fs "github.com/docker/libcontainer/cgroups/fs"
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/container"
+	containerLibcontainer "github.com/google/cadvisor/container/libcontainer"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/utils"
+)
+
+type containerdContainerHandler struct {
+	name               string
+	aliases            []string
+	machineInfoFactory info.MachineInfoFactory
+
+	// Absolute path to the cgroup hierarchies of this container.
+	// (e.g.: "cpu" -> "/sys/fs/cgroup/cpu/kubepods/pod<uid>/<id>")
+	cgroupPaths map[string]string
+
+	cgroup cgroups.Cgroup
+
+	// Pid of the init process of the container, 0 if unknown.
+	pid int
+
+	// Annotations of the container, along with its containerd namespace.
+	labels map[string]string
+
+	// Image the container was created from, if known, and the tag or
+	// digest it names.
+	image       string
+	imageDigest string
+	repoTags    []string
+
+	// Environment variables of the container that can be reported.
+	env map[string]string
+
+	// Security profiles of the container, if known.
+	seccompProfile  string
+	appArmorProfile string
+
+	// Version of the cgroup hierarchies in cgroupPaths.
+	cgroupVersion int
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
+}
+
+func newContainerdContainerHandler(
+	client containerdClient,
+	tasks *taskIndex,
+	name string,
+	machineInfoFactory info.MachineInfoFactory,
+	cgroupSubsystems *containerLibcontainer.CgroupSubsystems,
+	ignoreMetrics container.MetricSet,
+) (container.ContainerHandler, error) {
+	// Create the cgroup paths.
+	cgroupPaths := make(map[string]string, len(cgroupSubsystems.MountPoints))
+	for key, val := range cgroupSubsystems.MountPoints {
+		cgroupPaths[key] = path.Join(val, name)
+	}
+
+	handler := &containerdContainerHandler{
+		name:               name,
+		machineInfoFactory: machineInfoFactory,
+		cgroupPaths:        cgroupPaths,
+		cgroup: cgroups.Cgroup{
+			Parent: "/",
+			Name:   name,
+		},
+		cgroupVersion: cgroupSubsystems.Version,
+		ignoreMetrics: ignoreMetrics,
+	}
+
+	// The stats only need the cgroups, a container whose task can no longer
+	// be read is still reported, without metadata.
+	t, err := tasks.get(name)
+	if err != nil || t == nil {
+		glog.V(2).Infof("failed to get the containerd task of container %q: %v", name, err)
+		return handler, nil
+	}
+	handler.pid = t.Pid
+	handler.image = t.Image
+	if handler.image == "" {
+		handler.image = t.Annotations[imageNameAnnotation]
+	}
+	if tag, digest := container.ParseImageReference(handler.image); tag != "" {
+		handler.repoTags = []string{tag}
+	} else {
+		handler.imageDigest = digest
+	}
+	if handler.imageDigest == "" && t.Image != "" {
+		handler.imageDigest, err = client.ImageDigest(t.Namespace, t.Image)
+		if err != nil {
+			glog.V(2).Infof("failed to get the image digest of container %q: %v", name, err)
+		}
+	}
+	handler.env = container.FilterEnv(t.Env)
+	handler.seccompProfile = t.SeccompProfile
+	handler.appArmorProfile = t.AppArmorProfile
+	handler.labels = make(map[string]string, len(t.Annotations)+len(t.Labels)+1)
+	for k, v := range t.Annotations {
+		handler.labels[k] = v
+	}
+	for k, v := range t.Labels {
+		handler.labels[k] = v
+	}
+	handler.labels[NamespaceLabel] = t.Namespace
+
+	// Add the name and bare ID as aliases of the container.
+	if containerName := t.Annotations[containerNameAnnotation]; containerName != "" {
+		handler.aliases = append(handler.aliases, containerName)
+	}
+	handler.aliases = append(handler.aliases, t.Id)
+
+	return handler, nil
+}
+
+func (self *containerdContainerHandler) ContainerReference() (info.ContainerReference, error) {
+	return info.ContainerReference{
+		Name:      self.name,
+		Aliases:   self.aliases,
+		Namespace: ContainerdNamespace,
+	}, nil
+}
+
+// Reads an unsigned integer from a cgroup file, returns 0 if it is missing.
+func readUint64(dirpath string, file string) uint64 {
+	out, err := ioutil.ReadFile(path.Join(dirpath, file))
+	if err != nil {
+		return 0
+	}
+	val, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
+func (self *containerdContainerHandler) GetSpec() (info.ContainerSpec, error) {
+	var spec info.ContainerSpec
+	mi, err := self.machineInfoFactory.GetMachineInfo()
+	if err != nil {
+		return spec, err
+	}
+	// containerd does not record when its tasks started, use the creation
+	// time of the cgroups as for raw containers.
+	spec.CreationTime = containerLibcontainer.GetCgroupCreationTime(self.cgroupPaths)
+	spec.Labels = self.labels
+	spec.Image = self.image
+	spec.ImageDigest = self.imageDigest
+	spec.RepoTags = self.repoTags
+	spec.SeccompProfile = self.seccompProfile
+	spec.AppArmorProfile = self.appArmorProfile
+	spec.Env = self.env
+	spec.CgroupVersion = self.cgroupVersion
+	spec.Controllers = containerLibcontainer.GetCgroupControllers(self.cgroupPaths)
+
+	spec.HasCpu = true
+	spec.Cpu.Limit = 1024
+	if shares := readUint64(self.cgroupPaths["cpu"], "cpu.shares"); shares != 0 {
+		spec.Cpu.Limit = shares
+	}
+	spec.Cpu.MaxLimit = containerLibcontainer.GetCpuMaxLimit(self.cgroupPaths["cpu"])
+	mask, err := ioutil.ReadFile(path.Join(self.cgroupPaths["cpuset"], "cpuset.cpus"))
+	if err == nil {
+		spec.Cpu.Mask = utils.FixCpuMask(strings.TrimSpace(string(mask)), mi.NumCores)
+	} else {
+		spec.Cpu.Mask = utils.FixCpuMask("", mi.NumCores)
+	}
+	containerLibcontainer.GetCpusetSpec(self.cgroupPaths["cpuset"], &spec.Cpu)
+
+	spec.HasMemory = true
+	spec.Memory.Limit = math.MaxUint64
+	spec.Memory.SwapLimit = math.MaxUint64
+	containerLibcontainer.GetMemorySpec(self.cgroupPaths["memory"], &spec.Memory)
+
+	spec.HasNetwork = self.pid > 0 && !self.ignoreMetrics.HasAll(container.NetworkMetrics)
+	spec.HasDiskIo = !self.ignoreMetrics.Has(container.DiskIoMetrics)
+
+	return spec, nil
+}
+
+func (self *containerdContainerHandler) GetStats() (*info.ContainerStats, error) {
+	return self.GetPartialStats(nil)
+}
+
+func (self *containerdContainerHandler) GetPartialStats(skip container.MetricSet) (*info.ContainerStats, error) {
+	// The host side of the network of containerd containers is unknown, only
+	// the sockets are read through the init process.
+	state := &libcontainer.State{
+		InitPid: self.pid,
+	}
+	return containerLibcontainer.GetStats(self.cgroupPaths, state, self.ignoreMetrics.Union(skip))
+}
+
+func (self *containerdContainerHandler) ListContainers(listType container.ListType) ([]info.ContainerReference, error) {
+	// containerd containers have no subcontainers.
+	return []info.ContainerReference{}, nil
+}
+
+func (self *containerdContainerHandler) GetCgroupPath(resource string) (string, error) {
+	path, ok := self.cgroupPaths[resource]
+	if !ok {
+		return "", fmt.Errorf("could not find path for resource %q for container %q\n", resource, self.name)
+	}
+	return path, nil
+}
+
+func (self *containerdContainerHandler) GetContainerIPAddress() string {
+	// containerd leaves the network setup to its clients.
+	return ""
+}
+
+func (self *containerdContainerHandler) ListThreads(listType container.ListType) ([]int, error) {
+	return nil, nil
+}
+
+func (self *containerdContainerHandler) ListProcesses(listType container.ListType) ([]int, error) {
+	return cgroup_fs.GetPids(&self.cgroup)
+}
+
+func (self *containerdContainerHandler) WatchSubcontainers(events chan container.SubcontainerEvent) error {
+	return fmt.Errorf("watch is unimplemented in the containerd container driver")
+}
+
+func (self *containerdContainerHandler) StopWatchingSubcontainers() error {
+	// No-op for containerd driver.
+	return nil
+}
+
+func (self *containerdContainerHandler) Exists() bool {
+	// The container is gone once its cgroup is removed.
+	for _, cgroupPath := range self.cgroupPaths {
+		if utils.FileExists(cgroupPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Annotations the CRI plugin of containerd sets on the containers of
+// Kubernetes pods.
+const (
+	imageNameAnnotation     = "io.kubernetes.cri.image-name"
+	containerNameAnnotation = "io.kubernetes.cri.container-name"
+)
+
+// A containerd container and its task, as described by the containerd API.
+type task struct {
+	Namespace string
+	Id        string
+	// Name of the cgroup of the container, e.g. "/kubepods/pod<uid>/<id>".
+	CgroupName string
+	// Image the container was created from, e.g. "docker.io/library/nginx:1.9".
+	Image       string
+	Labels      map[string]string
+	Annotations map[string]string
+	// Environment of the init process, as "name=value" strings.
+	Env []string
+	// Pid of the init process, 0 if it is not running.
+	Pid int
+	// Seccomp profile of the task: "custom" if it has one, the spec only
+	// holds its content, or "unconfined".
+	SeccompProfile  string
+	AppArmorProfile string
+}
+
+// The parts of the OCI runtime spec of a container we need.
+type ociSpec struct {
+	Annotations map[string]string `json:"annotations"`
+	Process     struct {
+		Env             []string `json:"env"`
+		ApparmorProfile string   `json:"apparmorProfile"`
+	} `json:"process"`
+	Linux struct {
+		CgroupsPath string           `json:"cgroupsPath"`
+		Seccomp     *json.RawMessage `json:"seccomp"`
+	} `json:"linux"`
+}
+
+// Returns the task of a container from its OCI runtime spec, as JSON.
+func newTask(namespace, id, image string, labels map[string]string, specJson []byte, pid int) (*task, error) {
+	var spec ociSpec
+	if err := json.Unmarshal(specJson, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse the spec of container %q: %v", id, err)
+	}
+	t := &task{
+		Namespace:   namespace,
+		Id:          id,
+		CgroupName:  cgroupsPathToName(spec.Linux.CgroupsPath),
+		Image:       image,
+		Labels:      labels,
+		Annotations: spec.Annotations,
+		Env:         spec.Process.Env,
+		Pid:         pid,
+
+		SeccompProfile:  "unconfined",
+		AppArmorProfile: spec.Process.ApparmorProfile,
+	}
+	if spec.Linux.Seccomp != nil {
+		t.SeccompProfile = "custom"
+	}
+	return t, nil
+}
+
+// Returns the container name of a cgroups path of an OCI spec. The systemd
+// cgroup driver uses "<slice>:<prefix>:<name>" paths, placing the container in
+// the <prefix>-<name>.scope unit of the slice, which is nested in the slices
+// named after the dash separated prefixes of its name.
+func cgroupsPathToName(cgroupsPath string) string {
+	parts := strings.Split(cgroupsPath, ":")
+	if len(parts) != 3 {
+		return path.Clean("/" + cgroupsPath)
+	}
+	slice, prefix, name := parts[0], parts[1], parts[2]
+	dir := "/"
+	if slice != "" && slice != "-.slice" {
+		sliceName := strings.TrimSuffix(slice, ".slice")
+		components := strings.Split(sliceName, "-")
+		for i := range components {
+			dir = path.Join(dir, strings.Join(components[:i+1], "-")+".slice")
+		}
+	}
+	scope := name + ".scope"
+	if prefix != "" {
+		scope = prefix + "-" + scope
+	}
+	return path.Join(dir, scope)
+}
+
+// Minimum time between two listings of all the containers of containerd.
+var minRescanInterval = 10 * time.Second
+
+// Index of the tasks of containerd by container name. A container it does not
+// know is looked up in the containers named after its cgroup, which is how
+// containerd and its CRI plugin name cgroups, then in all the containers if
+// they were not listed for minRescanInterval. The containers found in neither
+// are not looked up again until the containers can be listed again. Indexed
+// tasks are checked to still run when they are looked up, so that the tasks
+// which exited are evicted.
+type taskIndex struct {
+	client containerdClient
+	lock   sync.Mutex
+	tasks  map[string]*task
+	// Containers which are not tasks of containerd.
+	misses   map[string]bool
+	lastScan time.Time
+}
+
+func newTaskIndex(client containerdClient) *taskIndex {
+	return &taskIndex{
+		client: client,
+		tasks:  make(map[string]*task),
+		misses: make(map[string]bool),
+	}
+}
+
+// Returns the task of the container with the given name, nil if there is none.
+func (self *taskIndex) get(name string) (*task, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if t, ok := self.tasks[name]; ok {
+		current, err := self.client.Task(t.Namespace, t.Id)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil && current.CgroupName == name && current.Pid > 0 {
+			self.tasks[name] = current
+			return current, nil
+		}
+		// The task exited, or the container was replaced.
+		delete(self.tasks, name)
+	}
+	rescan := time.Since(self.lastScan) >= minRescanInterval
+	if self.misses[name] && !rescan {
+		return nil, nil
+	}
+	t, err := self.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		self.tasks[name] = t
+		return t, nil
+	}
+	if rescan {
+		if err := self.refresh(); err != nil {
+			return nil, err
+		}
+		if t, ok := self.tasks[name]; ok {
+			return t, nil
+		}
+	}
+	self.misses[name] = true
+	return nil, nil
+}
+
+// Returns the ids a container running in the cgroup with the given name may
+// have: its base name, without the ".scope" suffix and the dash separated
+// prefixes of systemd scopes, e.g. "cri-containerd-<id>.scope".
+func candidateIds(name string) []string {
+	base := path.Base(name)
+	if !strings.HasSuffix(base, ".scope") {
+		return []string{base}
+	}
+	base = strings.TrimSuffix(base, ".scope")
+	ids := []string{base}
+	for i, c := range base {
+		if c == '-' {
+			ids = append(ids, base[i+1:])
+		}
+	}
+	return ids
+}
+
+// Gets the containers named after the cgroup of the container, nil if none of
+// them runs in it. Called with lock held.
+func (self *taskIndex) lookup(name string) (*task, error) {
+	namespaces, err := self.client.Namespaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, namespace := range namespaces {
+		for _, id := range candidateIds(name) {
+			t, err := self.client.Task(namespace, id)
+			if err != nil {
+				return nil, err
+			}
+			if t != nil && t.CgroupName == name {
+				return t, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Lists all the containers again. Called with lock held.
+func (self *taskIndex) refresh() error {
+	namespaces, err := self.client.Namespaces()
+	if err != nil {
+		return err
+	}
+	tasks := make(map[string]*task, len(self.tasks))
+	for _, namespace := range namespaces {
+		list, err := self.client.Tasks(namespace)
+		if err != nil {
+			return err
+		}
+		for _, t := range list {
+			tasks[t.CgroupName] = t
+		}
+	}
+	self.tasks = tasks
+	self.misses = make(map[string]bool)
+	self.lastScan = time.Now()
+	return nil
+}
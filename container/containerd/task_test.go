@@ -0,0 +1,166 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupsPathToName(t *testing.T) {
+	for cgroupsPath, expected := range map[string]string{
+		"/kubepods/besteffort/pod123/abc":                     "/kubepods/besteffort/pod123/abc",
+		"default/abc":                                         "/default/abc",
+		"kubepods-besteffort-pod123.slice:cri-containerd:abc": "/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod123.slice/cri-containerd-abc.scope",
+		"system.slice:containerd:abc":                         "/system.slice/containerd-abc.scope",
+		"-.slice::abc":                                        "/abc.scope",
+	} {
+		assert.Equal(t, expected, cgroupsPathToName(cgroupsPath), cgroupsPath)
+	}
+}
+
+// A containerd holding the given containers, by namespace and ID.
+type fakeClient struct {
+	tasks map[string]map[string]*task
+	err   error
+}
+
+func (self *fakeClient) Namespaces() ([]string, error) {
+	var namespaces []string
+	for namespace := range self.tasks {
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, self.err
+}
+
+func (self *fakeClient) Tasks(namespace string) ([]*task, error) {
+	var tasks []*task
+	for _, t := range self.tasks[namespace] {
+		tasks = append(tasks, t)
+	}
+	return tasks, self.err
+}
+
+func (self *fakeClient) Task(namespace, id string) (*task, error) {
+	return self.tasks[namespace][id], self.err
+}
+
+func (self *fakeClient) ImageDigest(namespace, name string) (string, error) {
+	return "", self.err
+}
+
+func (self *fakeClient) add(t *testing.T, namespace, id, spec string, pid int) {
+	added, err := newTask(namespace, id, "", nil, []byte(spec), pid)
+	require.NoError(t, err)
+	if self.tasks[namespace] == nil {
+		self.tasks[namespace] = make(map[string]*task)
+	}
+	self.tasks[namespace][id] = added
+}
+
+func TestNewTask(t *testing.T) {
+	task, err := newTask("k8s.io", "abc", "docker.io/library/nginx:1.9", map[string]string{"app": "web"}, []byte(`{
+		"annotations": {"io.kubernetes.cri.image-name": "nginx:1.9", "io.kubernetes.cri.container-name": "web"},
+		"process": {"env": ["PATH=/usr/bin"], "apparmorProfile": "cri-containerd.apparmor.d"},
+		"linux": {"cgroupsPath": "/kubepods/pod123/abc", "seccomp": {"defaultAction": "SCMP_ACT_ERRNO"}}
+	}`), 42)
+	require.NoError(t, err)
+	assert.Equal(t, "k8s.io", task.Namespace)
+	assert.Equal(t, "abc", task.Id)
+	assert.Equal(t, "/kubepods/pod123/abc", task.CgroupName)
+	assert.Equal(t, "docker.io/library/nginx:1.9", task.Image)
+	assert.Equal(t, map[string]string{"app": "web"}, task.Labels)
+	assert.Equal(t, 42, task.Pid)
+	assert.Equal(t, "nginx:1.9", task.Annotations[imageNameAnnotation])
+	assert.Equal(t, []string{"PATH=/usr/bin"}, task.Env)
+	assert.Equal(t, "custom", task.SeccompProfile)
+	assert.Equal(t, "cri-containerd.apparmor.d", task.AppArmorProfile)
+
+	task, err = newTask("default", "def", "", nil, []byte(`{"linux": {"cgroupsPath": "/default/def"}}`), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "unconfined", task.SeccompProfile)
+	assert.Equal(t, "", task.AppArmorProfile)
+
+	_, err = newTask("default", "ghi", "", nil, []byte(`{`), 0)
+	assert.Error(t, err)
+}
+
+func TestTaskIndex(t *testing.T) {
+	client := &fakeClient{tasks: make(map[string]map[string]*task)}
+	index := newTaskIndex(client)
+
+	task, err := index.get("/k8s/abc")
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+
+	// Containers created later are found.
+	client.add(t, "k8s.io", "abc", `{"linux": {"cgroupsPath": "/kubepods/pod123/abc"}}`, 42)
+	client.add(t, "default", "def", `{"linux": {"cgroupsPath": "/default/def"}}`, 0)
+	task, err = index.get("/kubepods/pod123/abc")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "k8s.io", task.Namespace)
+	assert.Equal(t, 42, task.Pid)
+	task, err = index.get("/default/def")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, 0, task.Pid)
+
+	// Containers whose id is not the name of their cgroup are found by
+	// listing all the containers, at most every minRescanInterval.
+	defer func(interval time.Duration) { minRescanInterval = interval }(minRescanInterval)
+	minRescanInterval = time.Hour
+	client.add(t, "default", "ghi", `{"linux": {"cgroupsPath": "system.slice:cri-containerd:jkl"}}`, 43)
+	task, err = index.get("/system.slice/cri-containerd-jkl.scope")
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+	minRescanInterval = 0
+	task, err = index.get("/system.slice/cri-containerd-jkl.scope")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "ghi", task.Id)
+
+	// Containers named after their systemd scope are found right away.
+	minRescanInterval = time.Hour
+	client.add(t, "k8s.io", "mno", `{"linux": {"cgroupsPath": "kubepods.slice:cri-containerd:mno"}}`, 44)
+	task, err = index.get("/kubepods.slice/cri-containerd-mno.scope")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "mno", task.Id)
+
+	// Tasks which exited are evicted without waiting for a rescan, and
+	// restarted tasks are updated.
+	delete(client.tasks["default"], "ghi")
+	task, err = index.get("/system.slice/cri-containerd-jkl.scope")
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+	_, ok := index.tasks["/system.slice/cri-containerd-jkl.scope"]
+	assert.False(t, ok)
+	client.add(t, "k8s.io", "abc", `{"linux": {"cgroupsPath": "/kubepods/pod123/abc"}}`, 45)
+	task, err = index.get("/kubepods/pod123/abc")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, 45, task.Pid)
+
+	// When containerd cannot be reached, the containers are left to other
+	// handlers.
+	client.err = fmt.Errorf("connection refused")
+	_, err = newTaskIndex(client).get("/default/def")
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crio
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+const crioClientTimeout = 10 * time.Second
+
+// Information about the CRI-O daemon, as returned by its /info endpoint.
+type crioInfo struct {
+	StorageDriver string `json:"storage_driver"`
+	StorageRoot   string `json:"storage_root"`
+	CgroupDriver  string `json:"cgroup_driver"`
+}
+
+// Information about a container, as returned by the /containers/<id> endpoint.
+type crioContainerInfo struct {
+	Name string `json:"name"`
+	Pid  int    `json:"pid"`
+	// Image the container was created from.
+	Image string `json:"image"`
+	// Creation time, in nanoseconds since the epoch.
+	CreatedTime int64             `json:"created_time"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	// ID of the pod sandbox the container belongs to.
+	Sandbox   string `json:"sandbox"`
+	IPAddress string `json:"ip_address"`
+}
+
+// Client of the inspect HTTP API CRI-O serves on its unix socket.
+type crioClient struct {
+	client *http.Client
+}
+
+func newCrioClient(socket string) *crioClient {
+	return &crioClient{
+		client: &http.Client{
+			Timeout: crioClientTimeout,
+			Transport: &http.Transport{
+				// The host of the request URLs is ignored, all requests go to the socket.
+				Dial: func(network, addr string) (net.Conn, error) {
+					return net.Dial("unix", socket)
+				},
+			},
+		},
+	}
+}
+
+func (self *crioClient) get(path string, out interface{}) error {
+	resp, err := self.client.Get("http://crio" + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request for %q failed with status %q", path, resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("failed to decode response to %q: %v", path, err)
+	}
+	return nil
+}
+
+func (self *crioClient) Info() (crioInfo, error) {
+	var info crioInfo
+	err := self.get("/info", &info)
+	return info, err
+}
+
+func (self *crioClient) InspectContainer(id string) (*crioContainerInfo, error) {
+	var info crioContainerInfo
+	err := self.get("/containers/"+id, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
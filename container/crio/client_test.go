@@ -0,0 +1,86 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crio
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testId = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestContainerNameToCrioId(t *testing.T) {
+	for name, id := range map[string]string{
+		"/kubepods/besteffort/pod1/crio-" + testId:                      testId,
+		"/kubepods.slice/kubepods-pod1.slice/crio-" + testId + ".scope": testId,
+		"/kubepods/besteffort/pod1/crio-conmon-" + testId:               "",
+		"/docker/" + testId: "",
+		"/":                 "",
+	} {
+		assert.Equal(t, id, ContainerNameToCrioId(name), name)
+	}
+}
+
+func TestInspectContainer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "crio.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"storage_driver":"overlay","storage_root":"/var/lib/containers/storage","cgroup_driver":"systemd"}`))
+	})
+	mux.HandleFunc("/containers/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/containers/") != testId {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"k8s_nginx_web","pid":42,"image":"docker.io/library/nginx:latest","created_time":1444000000000000000,"labels":{"io.kubernetes.pod.name":"web"},"sandbox":"fedcba"}`))
+	})
+	go http.Serve(listener, mux)
+
+	client := newCrioClient(socket)
+	crioInfo, err := client.Info()
+	assert.NoError(t, err)
+	assert.Equal(t, "systemd", crioInfo.CgroupDriver)
+
+	ctnr, err := client.InspectContainer(testId)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "k8s_nginx_web", ctnr.Name)
+		assert.Equal(t, 42, ctnr.Pid)
+		assert.Equal(t, "docker.io/library/nginx:latest", ctnr.Image)
+		assert.Equal(t, int64(1444000000000000000), ctnr.CreatedTime)
+		assert.Equal(t, "web", ctnr.Labels["io.kubernetes.pod.name"])
+		assert.Equal(t, "fedcba", ctnr.Sandbox)
+	}
+
+	_, err = client.InspectContainer("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crio
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/container"
+	"github.com/google/cadvisor/container/libcontainer"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/utils"
+)
+
+var ArgCrioEndpoint = flag.String("crio", "/var/run/crio/crio.sock", "CRI-O endpoint")
+
+// The namespace under which CRI-O aliases are unique.
+const CrioNamespace = "crio"
+
+// Label holding the ID of the pod sandbox of a container.
+const SandboxIdLabel = "io.kubernetes.cri-o.SandboxID"
+
+// CRI-O places containers in cgroups named crio-<id> with the cgroupfs
+// driver and crio-<id>.scope with the systemd one. The conmon monitors of the
+// containers (crio-conmon-<id>) are not containers themselves.
+var crioCgroupRegexp = regexp.MustCompile(`^crio-([0-9a-f]{64})(\.scope)?$`)
+
+// Returns the CRI-O ID of the container with the given name, or an empty
+// string if the name is not the one of a CRI-O container.
+func ContainerNameToCrioId(name string) string {
+	matches := crioCgroupRegexp.FindStringSubmatch(path.Base(name))
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+type crioFactory struct {
+	machineInfoFactory info.MachineInfoFactory
+
+	client *crioClient
+
+	// Information about the mounted cgroup subsystems.
+	cgroupSubsystems libcontainer.CgroupSubsystems
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
+}
+
+func (self *crioFactory) String() string {
+	return CrioNamespace
+}
+
+func (self *crioFactory) NewContainerHandler(name string) (container.ContainerHandler, error) {
+	return newCrioContainerHandler(self.client, name, self.machineInfoFactory, &self.cgroupSubsystems, self.ignoreMetrics)
+}
+
+// CRI-O handles the containers it knows about that have a process running.
+func (self *crioFactory) CanHandle(name string) (bool, error) {
+	id := ContainerNameToCrioId(name)
+	if id == "" {
+		return false, nil
+	}
+	// We assume that if the inspection fails then the container is not known to CRI-O.
+	ctnr, err := self.client.InspectContainer(id)
+	if err != nil {
+		return false, fmt.Errorf("error inspecting container: %v", err)
+	}
+	if ctnr.Pid <= 0 {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Register root container before running this function!
+func Register(factory info.MachineInfoFactory, ignoreMetrics container.MetricSet) error {
+	if !utils.FileExists(*ArgCrioEndpoint) {
+		return fmt.Errorf("CRI-O socket %q not found", *ArgCrioEndpoint)
+	}
+	client := newCrioClient(*ArgCrioEndpoint)
+	crioInfo, err := client.Info()
+	if err != nil {
+		return fmt.Errorf("unable to communicate with CRI-O: %v", err)
+	}
+
+	cgroupSubsystems, err := libcontainer.GetCgroupSubsystems()
+	if err != nil {
+		return fmt.Errorf("failed to get cgroup subsystems: %v", err)
+	}
+
+	glog.Infof("Registering CRI-O factory (storage driver %q, cgroup driver %q)", crioInfo.StorageDriver, crioInfo.CgroupDriver)
+	f := &crioFactory{
+		machineInfoFactory: factory,
+		client:             client,
+		cgroupSubsystems:   cgroupSubsystems,
+		ignoreMetrics:      ignoreMetrics,
+	}
+	container.RegisterContainerHandlerFactory(f)
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Handler for CRI-O containers.
+package crio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/libcontainer"
+	"github.com/docker/libcontainer/cgroups"
+	cgroup_fs "github.com/docker/libcontainer/cgroups/fs"
+	"github.com/google/cadvisor/container"
+	containerLibcontainer "github.com/google/cadvisor/container/libcontainer"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/utils"
+)
+
+type crioContainerHandler struct {
+	client             *crioClient
+	name               string
+	id                 string
+	aliases            []string
+	machineInfoFactory info.MachineInfoFactory
+
+	// Absolute path to the cgroup hierarchies of this container.
+	// (e.g.: "cpu" -> "/sys/fs/cgroup/cpu/kubepods/crio-<id>")
+	cgroupPaths map[string]string
+
+	cgroup cgroups.Cgroup
+
+	// Pid of the init process of the container.
+	pid int
+
+	// Time at which this container was created.
+	creationTime time.Time
+
+	// Labels of the container, along with the ID of its pod sandbox.
+	labels map[string]string
+
+	// Image the container was created from.
+	image string
+
+	// IP address of the pod sandbox of the container.
+	ipAddress string
+
+	// Version of the cgroup hierarchies in cgroupPaths.
+	cgroupVersion int
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
+}
+
+func newCrioContainerHandler(
+	client *crioClient,
+	name string,
+	machineInfoFactory info.MachineInfoFactory,
+	cgroupSubsystems *containerLibcontainer.CgroupSubsystems,
+	ignoreMetrics container.MetricSet,
+) (container.ContainerHandler, error) {
+	// Create the cgroup paths.
+	cgroupPaths := make(map[string]string, len(cgroupSubsystems.MountPoints))
+	for key, val := range cgroupSubsystems.MountPoints {
+		cgroupPaths[key] = path.Join(val, name)
+	}
+
+	id := ContainerNameToCrioId(name)
+	ctnr, err := client.InspectContainer(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %q: %v", id, err)
+	}
+
+	labels := make(map[string]string, len(ctnr.Labels)+1)
+	for k, v := range ctnr.Labels {
+		labels[k] = v
+	}
+	if ctnr.Sandbox != "" {
+		labels[SandboxIdLabel] = ctnr.Sandbox
+	}
+
+	handler := &crioContainerHandler{
+		client:             client,
+		name:               name,
+		id:                 id,
+		machineInfoFactory: machineInfoFactory,
+		cgroupPaths:        cgroupPaths,
+		cgroup: cgroups.Cgroup{
+			Parent: "/",
+			Name:   name,
+		},
+		pid:           ctnr.Pid,
+		labels:        labels,
+		image:         ctnr.Image,
+		ipAddress:     ctnr.IPAddress,
+		cgroupVersion: cgroupSubsystems.Version,
+		ignoreMetrics: ignoreMetrics,
+	}
+
+	if ctnr.CreatedTime != 0 {
+		handler.creationTime = time.Unix(0, ctnr.CreatedTime)
+	} else {
+		// Fall back to the creation time of the cgroups, as for raw containers.
+		handler.creationTime = containerLibcontainer.GetCgroupCreationTime(cgroupPaths)
+	}
+
+	// Add the name and bare ID as aliases of the container.
+	if ctnr.Name != "" {
+		handler.aliases = append(handler.aliases, ctnr.Name)
+	}
+	handler.aliases = append(handler.aliases, id)
+
+	return handler, nil
+}
+
+func (self *crioContainerHandler) ContainerReference() (info.ContainerReference, error) {
+	return info.ContainerReference{
+		Name:      self.name,
+		Aliases:   self.aliases,
+		Namespace: CrioNamespace,
+	}, nil
+}
+
+// Reads an unsigned integer from a cgroup file, returns 0 if it is missing.
+func readUint64(dirpath string, file string) uint64 {
+	out, err := ioutil.ReadFile(path.Join(dirpath, file))
+	if err != nil {
+		return 0
+	}
+	val, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
+func (self *crioContainerHandler) GetSpec() (info.ContainerSpec, error) {
+	var spec info.ContainerSpec
+	mi, err := self.machineInfoFactory.GetMachineInfo()
+	if err != nil {
+		return spec, err
+	}
+	spec.CreationTime = self.creationTime
+	spec.Labels = self.labels
+	spec.Image = self.image
+	spec.CgroupVersion = self.cgroupVersion
+	spec.Controllers = containerLibcontainer.GetCgroupControllers(self.cgroupPaths)
+
+	// CRI-O sets the limits of its containers in their cgroups.
+	spec.HasCpu = true
+	spec.Cpu.Limit = 1024
+	if shares := readUint64(self.cgroupPaths["cpu"], "cpu.shares"); shares != 0 {
+		spec.Cpu.Limit = shares
+	}
+	spec.Cpu.MaxLimit = containerLibcontainer.GetCpuMaxLimit(self.cgroupPaths["cpu"])
+	mask, err := ioutil.ReadFile(path.Join(self.cgroupPaths["cpuset"], "cpuset.cpus"))
+	if err == nil {
+		spec.Cpu.Mask = utils.FixCpuMask(strings.TrimSpace(string(mask)), mi.NumCores)
+	} else {
+		spec.Cpu.Mask = utils.FixCpuMask("", mi.NumCores)
+	}
+	containerLibcontainer.GetCpusetSpec(self.cgroupPaths["cpuset"], &spec.Cpu)
+
+	spec.HasMemory = true
+	spec.Memory.Limit = math.MaxUint64
+	spec.Memory.SwapLimit = math.MaxUint64
+	containerLibcontainer.GetMemorySpec(self.cgroupPaths["memory"], &spec.Memory)
+
+	spec.HasNetwork = !self.ignoreMetrics.HasAll(container.NetworkMetrics)
+	spec.HasDiskIo = !self.ignoreMetrics.Has(container.DiskIoMetrics)
+
+	return spec, nil
+}
+
+func (self *crioContainerHandler) GetStats() (*info.ContainerStats, error) {
+	return self.GetPartialStats(nil)
+}
+
+func (self *crioContainerHandler) GetPartialStats(skip container.MetricSet) (*info.ContainerStats, error) {
+	// CRI-O does not expose the host side of the network of its containers,
+	// only the sockets are read through the init process.
+	state := &libcontainer.State{
+		InitPid: self.pid,
+	}
+	return containerLibcontainer.GetStats(self.cgroupPaths, state, self.ignoreMetrics.Union(skip))
+}
+
+func (self *crioContainerHandler) ListContainers(listType container.ListType) ([]info.ContainerReference, error) {
+	// CRI-O containers have no subcontainers.
+	return []info.ContainerReference{}, nil
+}
+
+func (self *crioContainerHandler) GetCgroupPath(resource string) (string, error) {
+	path, ok := self.cgroupPaths[resource]
+	if !ok {
+		return "", fmt.Errorf("could not find path for resource %q for container %q\n", resource, self.name)
+	}
+	return path, nil
+}
+
+func (self *crioContainerHandler) GetContainerIPAddress() string {
+	return self.ipAddress
+}
+
+func (self *crioContainerHandler) ListThreads(listType container.ListType) ([]int, error) {
+	return nil, nil
+}
+
+func (self *crioContainerHandler) ListProcesses(listType container.ListType) ([]int, error) {
+	return cgroup_fs.GetPids(&self.cgroup)
+}
+
+func (self *crioContainerHandler) WatchSubcontainers(events chan container.SubcontainerEvent) error {
+	return fmt.Errorf("watch is unimplemented in the CRI-O container driver")
+}
+
+func (self *crioContainerHandler) StopWatchingSubcontainers() error {
+	// No-op for CRI-O driver.
+	return nil
+}
+
+func (self *crioContainerHandler) Exists() bool {
+	// The container is gone once its cgroup is removed.
+	for _, cgroupPath := range self.cgroupPaths {
+		if utils.FileExists(cgroupPath) {
+			return true
+		}
+	}
+	return false
+}
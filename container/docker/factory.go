@@ -81,6 +81,9 @@ type dockerFactory struct {
 
 	// Information about mounted filesystems.
 	fsInfo fs.FsInfo
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
 }
 
 func (self *dockerFactory) String() string {
@@ -100,6 +103,7 @@ func (self *dockerFactory) NewContainerHandler(name string) (handler container.C
 		*dockerRootDir,
 		self.usesAufsDriver,
 		&self.cgroupSubsystems,
+		self.ignoreMetrics,
 	)
 	return
 }
@@ -161,7 +165,7 @@ func parseDockerVersion(full_version_string string) ([]int, error) {
 }
 
 // Register root container before running this function!
-func Register(factory info.MachineInfoFactory, fsInfo fs.FsInfo) error {
+func Register(factory info.MachineInfoFactory, fsInfo fs.FsInfo, ignoreMetrics container.MetricSet) error {
 	client, err := docker.NewClient(*ArgDockerEndpoint)
 	if err != nil {
 		return fmt.Errorf("unable to communicate with docker daemon: %v", err)
@@ -224,6 +228,7 @@ func Register(factory info.MachineInfoFactory, fsInfo fs.FsInfo) error {
 		usesAufsDriver:     usesAufsDriver,
 		cgroupSubsystems:   cgroupSubsystems,
 		fsInfo:             fsInfo,
+		ignoreMetrics:      ignoreMetrics,
 	}
 	container.RegisterContainerHandlerFactory(f)
 	return nil
@@ -21,7 +21,11 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"os/exec"
 	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +33,7 @@ import (
 	"github.com/docker/libcontainer/cgroups"
 	cgroup_fs "github.com/docker/libcontainer/cgroups/fs"
 	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
 	"github.com/google/cadvisor/container"
 	containerLibcontainer "github.com/google/cadvisor/container/libcontainer"
 	"github.com/google/cadvisor/fs"
@@ -39,11 +44,38 @@ import (
 // Relative path from Docker root to the libcontainer per-container state.
 const pathToLibcontainerState = "execdriver/native"
 
+// Relative path from Docker root to the Docker per-container state.
+const pathToContainersDir = "containers"
+
 // Path to aufs dir where all the files exist.
 // aufs/layers is ignored here since it does not hold a lot of data.
 // aufs/mnt contains the mount points used to compose the rootfs. Hence it is also ignored.
 var pathToAufsDir = "aufs/diff"
 
+// Directory holding the image metadata of each storage driver.
+const pathToImageDir = "image"
+
+// Directory of the writable layer of a container within the directory of its
+// storage driver, by storage driver, formatted with the mount ID of the
+// container. The devicemapper writable layers are thin devices instead.
+var writableLayerDirs = map[string]string{
+	"aufs":     "diff/%s",
+	"overlay":  "%s/upper",
+	"overlay2": "%s/diff",
+}
+
+// Returns the status of the devicemapper thin devices, overridden in tests.
+var dmsetupStatus = func() ([]byte, error) {
+	return exec.Command("dmsetup", "status", "--target", "thin").Output()
+}
+
+// How long to wait for Docker to record the exit of a container whose cgroups
+// were removed, and how often to check.
+var (
+	exitStatusTimeout      = time.Second
+	exitStatusPollInterval = 100 * time.Millisecond
+)
+
 type dockerContainerHandler struct {
 	client             *docker.Client
 	name               string
@@ -72,6 +104,42 @@ type dockerContainerHandler struct {
 
 	// Time at which this container was created.
 	creationTime time.Time
+
+	// Labels of the container.
+	labels map[string]string
+
+	// Environment variables of the container that can be reported.
+	env map[string]string
+
+	// Image the container was created from, its digest and tags.
+	image       string
+	imageDigest string
+	repoTags    []string
+
+	// Path to the Docker config of the container.
+	dockerConfigPath string
+
+	// Path to the log file of the container, empty if it is not logged to
+	// a file (e.g. with the journald log driver).
+	logPath string
+
+	// Storage driver of the container and the ID its writable layer is named
+	// after by the storage driver.
+	storageDriver string
+	mountId       string
+
+	// Directory of the writable layer of the container, empty for storage
+	// drivers without one.
+	writableLayerDir string
+
+	// IP address of the container, empty when it uses the host network.
+	ipAddress string
+
+	// Version of the cgroup hierarchies in cgroupPaths.
+	cgroupVersion int
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
 }
 
 func DockerStateDir() string {
@@ -86,6 +154,7 @@ func newDockerContainerHandler(
 	dockerRootDir string,
 	usesAufsDriver bool,
 	cgroupSubsystems *containerLibcontainer.CgroupSubsystems,
+	ignoreMetrics container.MetricSet,
 ) (container.ContainerHandler, error) {
 	// Create the cgroup paths.
 	cgroupPaths := make(map[string]string, len(cgroupSubsystems.MountPoints))
@@ -110,6 +179,8 @@ func newDockerContainerHandler(
 		},
 		usesAufsDriver: usesAufsDriver,
 		fsInfo:         fsInfo,
+		cgroupVersion:  cgroupSubsystems.Version,
+		ignoreMetrics:  ignoreMetrics,
 	}
 	handler.storageDirs = append(handler.storageDirs, path.Join(dockerRootDir, pathToAufsDir, id))
 
@@ -119,14 +190,225 @@ func newDockerContainerHandler(
 		return nil, fmt.Errorf("failed to inspect container %q: %v", id, err)
 	}
 	handler.creationTime = ctnr.Created
+	if handler.creationTime.IsZero() {
+		// Fall back to the creation time of the cgroups, as for raw containers.
+		handler.creationTime = containerLibcontainer.GetCgroupCreationTime(cgroupPaths)
+	}
+	if ctnr.NetworkSettings != nil {
+		handler.ipAddress = ctnr.NetworkSettings.IPAddress
+	}
+	if ctnr.Config != nil {
+		handler.env = container.FilterEnv(ctnr.Config.Env)
+		handler.image = ctnr.Config.Image
+	}
 
 	// Add the name and bare ID as aliases of the container.
 	handler.aliases = append(handler.aliases, strings.TrimPrefix(ctnr.Name, "/"))
 	handler.aliases = append(handler.aliases, id)
 
+	// The Docker client we depend on does not know about labels, read them from the Docker config.
+	handler.dockerConfigPath = path.Join(dockerRootDir, pathToContainersDir, id, "config.json")
+	config, err := readDockerConfig(handler.dockerConfigPath)
+	if err != nil {
+		glog.V(2).Infof("failed to read labels of container %q: %v", id, err)
+	} else {
+		handler.labels = config.Config.Labels
+		handler.logPath = config.LogPath
+		handler.storageDriver = config.Driver
+		handler.mountId = readMountId(dockerRootDir, config.Driver, id)
+		if format, ok := writableLayerDirs[config.Driver]; ok {
+			handler.writableLayerDir = path.Join(dockerRootDir, config.Driver, fmt.Sprintf(format, handler.mountId))
+		}
+		if config.Driver == "aufs" {
+			// Docker 1.10 and later name the aufs dirs after the mount ID.
+			handler.storageDirs[0] = handler.writableLayerDir
+		}
+		repositoriesPath := path.Join(dockerRootDir, pathToImageDir, config.Driver, "repositories.json")
+		handler.repoTags, handler.imageDigest, err = readImageRefs(repositoriesPath, config.Image, handler.image)
+		if err != nil {
+			glog.V(2).Infof("failed to read the tags of the image of container %q: %v", id, err)
+		}
+	}
+	if handler.imageDigest == "" {
+		// Images pulled by digest carry it in their name.
+		_, handler.imageDigest = container.ParseImageReference(handler.image)
+	}
+
 	return handler, nil
 }
 
+// The parts of the Docker config of a container the Docker client we depend
+// on does not know about. Docker versions without label support have no
+// labels in their config.
+type dockerConfig struct {
+	RestartCount int
+	State        struct {
+		Running    bool
+		StartedAt  time.Time
+		FinishedAt time.Time
+		ExitCode   int
+		OOMKilled  bool
+	}
+	// ID of the image of the container, e.g. "sha256:...".
+	Image string
+	// Storage driver of the container, e.g. "overlay2".
+	Driver string
+	// Log file of the json-file log driver.
+	LogPath string
+	Config  struct {
+		Labels map[string]string
+	}
+	AppArmorProfile string
+	// "unconfined", the JSON profile given with --security-opt, or empty for
+	// the default profile of the daemon.
+	SeccompProfile string
+}
+
+// Returns the name of the seccomp profile of a Docker config: "custom" for
+// profiles given by content.
+func seccompProfileName(profile string) string {
+	if strings.HasPrefix(strings.TrimSpace(profile), "{") {
+		return "custom"
+	}
+	return profile
+}
+
+// The references to the images known to Docker, from the repositories.json
+// file of its storage driver, e.g.:
+// {"Repositories": {"nginx": {"nginx:1.9": "sha256:...", "nginx@sha256:...": "sha256:..."}}}
+type dockerRepositories struct {
+	// Image IDs by reference, by repository.
+	Repositories map[string]map[string]string
+}
+
+// Returns the tags of the image with the given ID, sorted, and its digest
+// from the repositories.json file at repositoriesPath. When the image has
+// several digests, the one of the repository of image, the name the
+// container was created with, is returned.
+func readImageRefs(repositoriesPath string, imageID string, image string) ([]string, string, error) {
+	if imageID == "" {
+		return nil, "", nil
+	}
+	out, err := ioutil.ReadFile(repositoriesPath)
+	if err != nil {
+		return nil, "", err
+	}
+	var repositories dockerRepositories
+	err = json.Unmarshal(out, &repositories)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse Docker repositories at %q: %v", repositoriesPath, err)
+	}
+	var tags, digests, repoDigests []string
+	for repo, refs := range repositories.Repositories {
+		for ref, id := range refs {
+			if id != imageID {
+				continue
+			}
+			i := strings.Index(ref, "@")
+			if i < 0 {
+				tags = append(tags, ref)
+				continue
+			}
+			digests = append(digests, ref[i+1:])
+			if repo == repoOf(image) {
+				repoDigests = append(repoDigests, ref[i+1:])
+			}
+		}
+	}
+	sort.Strings(tags)
+	if len(repoDigests) > 0 {
+		digests = repoDigests
+	}
+	if len(digests) == 0 {
+		return tags, "", nil
+	}
+	sort.Strings(digests)
+	return tags, digests[0], nil
+}
+
+// Returns the repository of an image reference, e.g. "nginx" for "nginx:1.9".
+func repoOf(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// Returns the size of a log file along with its rotated files (<path>.1,
+// <path>.2, ...). A log file which does not exist yet has no size.
+func getLogSize(logPath string) (uint64, error) {
+	files, err := filepath.Glob(logPath + "*")
+	if err != nil {
+		return 0, err
+	}
+	var size uint64
+	for _, file := range files {
+		if file != logPath && !strings.HasPrefix(file, logPath+".") {
+			continue
+		}
+		fileInfo, err := os.Stat(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Rotated away since it was listed.
+				continue
+			}
+			return 0, err
+		}
+		size += uint64(fileInfo.Size())
+	}
+	return size, nil
+}
+
+// Returns the ID the layers of the container with the given ID are named
+// after by its storage driver: its mount ID since Docker 1.10, its ID before.
+func readMountId(dockerRootDir string, driver string, id string) string {
+	out, err := ioutil.ReadFile(path.Join(dockerRootDir, pathToImageDir, driver, "layerdb", "mounts", id, "mount-id"))
+	if err != nil {
+		return id
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Returns the bytes mapped by the devicemapper thin device of the container
+// with the given mount ID, from the status of the thin devices, e.g.
+// "docker-8:1-1234-<mount ID>: 0 20971520 thin 2048 20971519" for 2048
+// sectors of 512 bytes.
+func getThinDeviceUsage(mountId string) (uint64, error) {
+	out, err := dmsetupStatus()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the status of the thin devices: %v", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[3] != "thin" || !strings.HasSuffix(fields[0], "-"+mountId+":") {
+			continue
+		}
+		sectors, err := strconv.ParseUint(fields[4], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse the status of thin device %q: %v", fields[0], err)
+		}
+		return sectors * 512, nil
+	}
+	return 0, fmt.Errorf("no thin device found for mount ID %q", mountId)
+}
+
+// Reads the Docker config at configPath.
+func readDockerConfig(configPath string) (*dockerConfig, error) {
+	out, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var config dockerConfig
+	err = json.Unmarshal(out, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Docker config at %q: %v", configPath, err)
+	}
+	return &config, nil
+}
+
 func (self *dockerContainerHandler) ContainerReference() (info.ContainerReference, error) {
 	return info.ContainerReference{
 		Name:      self.name,
@@ -242,22 +524,69 @@ func (self *dockerContainerHandler) GetSpec() (info.ContainerSpec, error) {
 
 	spec := libcontainerConfigToContainerSpec(libcontainerConfig, mi)
 	spec.CreationTime = self.creationTime
-	if self.usesAufsDriver {
+	spec.Labels = self.labels
+	spec.Env = self.env
+	spec.Image = self.image
+	spec.ImageDigest = self.imageDigest
+	spec.RepoTags = self.repoTags
+	spec.CgroupVersion = self.cgroupVersion
+	spec.Controllers = containerLibcontainer.GetCgroupControllers(self.cgroupPaths)
+	// The limits are in the Docker config, the soft limits are only in the
+	// cgroup. So is the CPU quota of containers updated with "docker update".
+	var cgroupMemory info.MemorySpec
+	containerLibcontainer.GetMemorySpec(self.cgroupPaths["memory"], &cgroupMemory)
+	spec.Memory.SoftLimit = cgroupMemory.SoftLimit
+	spec.Memory.Low = cgroupMemory.Low
+	spec.Memory.High = cgroupMemory.High
+	spec.Cpu.MaxLimit = containerLibcontainer.GetCpuMaxLimit(self.cgroupPaths["cpu"])
+	containerLibcontainer.GetCpusetSpec(self.cgroupPaths["cpuset"], &spec.Cpu)
+	// Docker updates the restart metadata in its config whenever it restarts
+	// the container, the security profiles are set when it is created.
+	if config, err := readDockerConfig(self.dockerConfigPath); err != nil {
+		glog.V(2).Infof("failed to read restart metadata of container %q: %v", self.id, err)
+	} else {
+		spec.RestartCount = config.RestartCount
+		spec.LastStartTime = config.State.StartedAt
+		spec.SeccompProfile = seccompProfileName(config.SeccompProfile)
+		spec.AppArmorProfile = config.AppArmorProfile
+	}
+	if self.hasFsStats() {
 		spec.HasFilesystem = true
 	}
+	if self.ignoreMetrics.Has(container.DiskIoMetrics) {
+		spec.HasDiskIo = false
+	}
+	if self.ignoreMetrics.HasAll(container.NetworkMetrics) {
+		spec.HasNetwork = false
+	}
 
 	return spec, err
 }
 
+// Returns whether filesystem stats are collected: the usage is only known
+// for the aufs storage driver, the size of the writable layer for the aufs,
+// overlay, overlay2 and devicemapper ones and the size of the log files for
+// all of them.
+func (self *dockerContainerHandler) hasFsStats() bool {
+	if self.ignoreMetrics.Has(container.DiskUsageMetrics) {
+		return false
+	}
+	return self.usesAufsDriver || self.writableLayerDir != "" || self.storageDriver == "devicemapper" || self.logPath != ""
+}
+
 func (self *dockerContainerHandler) getFsStats(stats *info.ContainerStats) error {
-	// No support for non-aufs storage drivers.
-	if !self.usesAufsDriver {
+	if !self.hasFsStats() {
 		return nil
 	}
 
-	// As of now we assume that all the storage dirs are on the same device.
+	// As of now we assume that all the storage dirs and the log files are on
+	// the same device as the directory of the container in the Docker root.
 	// The first storage dir will be that of the image layers.
-	deviceInfo, err := self.fsInfo.GetDirFsDevice(self.storageDirs[0])
+	dir := path.Dir(self.dockerConfigPath)
+	if self.usesAufsDriver {
+		dir = self.storageDirs[0]
+	}
+	deviceInfo, err := self.fsInfo.GetDirFsDevice(dir)
 	if err != nil {
 		return err
 	}
@@ -277,37 +606,59 @@ func (self *dockerContainerHandler) getFsStats(stats *info.ContainerStats) error
 
 	fsStat := info.FsStats{Device: deviceInfo.Device, Limit: limit}
 
-	var usage uint64 = 0
-	for _, dir := range self.storageDirs {
-		// TODO(Vishh): Add support for external mounts.
-		dirUsage, err := self.fsInfo.GetDirUsage(dir)
+	if self.usesAufsDriver {
+		var usage uint64 = 0
+		for i, dir := range self.storageDirs {
+			// TODO(Vishh): Add support for external mounts.
+			dirUsage, err := self.fsInfo.GetDirUsage(dir)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				// The aufs diff dir of the container is its writable layer.
+				fsStat.WritableLayerSize = dirUsage
+			}
+			usage += dirUsage
+		}
+		fsStat.Usage = usage
+	} else if self.writableLayerDir != "" {
+		fsStat.WritableLayerSize, err = self.fsInfo.GetDirUsage(self.writableLayerDir)
+		if err != nil {
+			return err
+		}
+	} else if self.storageDriver == "devicemapper" {
+		fsStat.WritableLayerSize, err = getThinDeviceUsage(self.mountId)
+		if err != nil {
+			return err
+		}
+	}
+	if self.logPath != "" {
+		fsStat.LogSize, err = getLogSize(self.logPath)
 		if err != nil {
 			return err
 		}
-		usage += dirUsage
 	}
-	fsStat.Usage = usage
 	stats.Filesystem = append(stats.Filesystem, fsStat)
 
 	return nil
 }
 
-func (self *dockerContainerHandler) GetStats() (stats *info.ContainerStats, err error) {
+func (self *dockerContainerHandler) GetStats() (*info.ContainerStats, error) {
+	return self.GetPartialStats(nil)
+}
+
+func (self *dockerContainerHandler) GetPartialStats(skip container.MetricSet) (stats *info.ContainerStats, err error) {
 	state, err := self.readLibcontainerState()
 	if err != nil {
 		return nil, err
 	}
 
-	stats, err = containerLibcontainer.GetStats(self.cgroupPaths, state)
-	if err != nil {
-		return stats, err
-	}
-	err = self.getFsStats(stats)
-	if err != nil {
-		return stats, err
-	}
+	statsErr := &container.StatsError{}
+	stats, err = containerLibcontainer.GetStats(self.cgroupPaths, state, self.ignoreMetrics.Union(skip))
+	statsErr.Add("", err)
+	statsErr.Add(container.SubsystemFilesystem, self.getFsStats(stats))
 
-	return stats, nil
+	return stats, statsErr.OrNil()
 }
 
 func (self *dockerContainerHandler) ListContainers(listType container.ListType) ([]info.ContainerReference, error) {
@@ -347,6 +698,14 @@ func (self *dockerContainerHandler) GetCgroupPath(resource string) (string, erro
 	return path, nil
 }
 
+func (self *dockerContainerHandler) GetContainerIPAddress() string {
+	if self.ipAddress == "" {
+		// The container uses the network of the host.
+		return "127.0.0.1"
+	}
+	return self.ipAddress
+}
+
 func (self *dockerContainerHandler) ListThreads(listType container.ListType) ([]int, error) {
 	return nil, nil
 }
@@ -364,6 +723,31 @@ func (self *dockerContainerHandler) StopWatchingSubcontainers() error {
 	return nil
 }
 
+// Returns how the container exited from its Docker config. The cgroups of a
+// container are removed shortly before Docker records its exit, so the config
+// is read again until it does. Containers run with --rm may have no config
+// left.
+func (self *dockerContainerHandler) GetExitStatus() (*info.ContainerExitStatus, error) {
+	deadline := time.Now().Add(exitStatusTimeout)
+	for {
+		config, err := readDockerConfig(self.dockerConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		if !config.State.Running {
+			return &info.ContainerExitStatus{
+				ExitCode:   config.State.ExitCode,
+				OomKilled:  config.State.OOMKilled,
+				FinishedAt: config.State.FinishedAt,
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Docker did not record the exit of container %q", self.id)
+		}
+		time.Sleep(exitStatusPollInterval)
+	}
+}
+
 func (self *dockerContainerHandler) Exists() bool {
 	// We consider the container existing if both libcontainer config and state files exist.
 	return utils.FileExists(self.libcontainerConfigPath) && utils.FileExists(self.libcontainerStatePath)
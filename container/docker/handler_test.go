@@ -0,0 +1,279 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/cadvisor/fs"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDockerConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configPath := path.Join(dir, "config.json")
+	err = ioutil.WriteFile(configPath, []byte(`{
+		"ID": "abc",
+		"RestartCount": 3,
+		"State": {"Running": true, "StartedAt": "2015-10-01T10:00:00.5Z"},
+		"Config": {"Image": "nginx", "Labels": {"app": "web"}},
+		"Image": "sha256:abc",
+		"Driver": "overlay2",
+		"LogPath": "/var/lib/docker/containers/abc/abc-json.log",
+		"AppArmorProfile": "docker-default",
+		"SeccompProfile": "unconfined"
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := readDockerConfig(configPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 3, config.RestartCount)
+		assert.True(t, config.State.StartedAt.Equal(time.Date(2015, 10, 1, 10, 0, 0, 5e8, time.UTC)))
+		assert.Equal(t, map[string]string{"app": "web"}, config.Config.Labels)
+		assert.Equal(t, "docker-default", config.AppArmorProfile)
+		assert.Equal(t, "sha256:abc", config.Image)
+		assert.Equal(t, "overlay2", config.Driver)
+		assert.Equal(t, "/var/lib/docker/containers/abc/abc-json.log", config.LogPath)
+		assert.Equal(t, "unconfined", seccompProfileName(config.SeccompProfile))
+	}
+
+	// Configs of older Docker versions have no labels.
+	err = ioutil.WriteFile(configPath, []byte(`{"ID": "abc", "Config": {"Image": "nginx"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = readDockerConfig(configPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, config.RestartCount)
+		assert.Nil(t, config.Config.Labels)
+		assert.Equal(t, "", seccompProfileName(config.SeccompProfile))
+	}
+
+	_, err = readDockerConfig(path.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestGetLogSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logPath := path.Join(dir, "abc-json.log")
+
+	size, err := getLogSize(logPath)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), size)
+
+	for file, content := range map[string]string{
+		"abc-json.log":   "current",
+		"abc-json.log.1": "rotated",
+		"abc-json.logs":  "unrelated",
+		"config.json":    "{}",
+	} {
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	size, err = getLogSize(logPath)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len("current")+len("rotated")), size)
+}
+
+// Filesystems where every directory is on sda1 and uses 100 bytes.
+type fakeFsInfo struct {
+	fs.FsInfo
+}
+
+func (self *fakeFsInfo) GetDirFsDevice(dir string) (*fs.DeviceInfo, error) {
+	return &fs.DeviceInfo{Device: "sda1"}, nil
+}
+
+func (self *fakeFsInfo) GetDirUsage(dir string) (uint64, error) {
+	return 100, nil
+}
+
+type fakeMachineInfoFactory struct {
+	info.MachineInfoFactory
+}
+
+func (self *fakeMachineInfoFactory) GetMachineInfo() (*info.MachineInfo, error) {
+	return &info.MachineInfo{Filesystems: []info.FsInfo{{Device: "sda1", Capacity: 1000}}}, nil
+}
+
+func TestGetFsStatsLogSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logPath := path.Join(dir, "abc-json.log")
+	if err := ioutil.WriteFile(logPath, []byte("output"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	handler := &dockerContainerHandler{
+		fsInfo:             &fakeFsInfo{},
+		machineInfoFactory: &fakeMachineInfoFactory{},
+		storageDirs:        []string{path.Join(dir, "aufs")},
+		logPath:            logPath,
+	}
+
+	// The log size is reported whatever the storage driver.
+	stats := &info.ContainerStats{}
+	assert.NoError(t, handler.getFsStats(stats))
+	assert.Equal(t, []info.FsStats{{Device: "sda1", Limit: 1000, LogSize: 6}}, stats.Filesystem)
+
+	handler.usesAufsDriver = true
+	stats = &info.ContainerStats{}
+	assert.NoError(t, handler.getFsStats(stats))
+	assert.Equal(t, []info.FsStats{{Device: "sda1", Limit: 1000, Usage: 100, WritableLayerSize: 100, LogSize: 6}}, stats.Filesystem)
+}
+
+func TestGetFsStatsWritableLayer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mountsDir := path.Join(dir, pathToImageDir, "overlay2", "layerdb", "mounts", "abc")
+	if err := os.MkdirAll(mountsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(mountsDir, "mount-id"), []byte("def\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "def", readMountId(dir, "overlay2", "abc"))
+	// Containers created before Docker 1.10 have no mount ID.
+	assert.Equal(t, "ghi", readMountId(dir, "overlay2", "ghi"))
+
+	handler := &dockerContainerHandler{
+		fsInfo:             &fakeFsInfo{},
+		machineInfoFactory: &fakeMachineInfoFactory{},
+		storageDriver:      "overlay2",
+		writableLayerDir:   path.Join(dir, "overlay2", "def", "diff"),
+	}
+	stats := &info.ContainerStats{}
+	assert.NoError(t, handler.getFsStats(stats))
+	assert.Equal(t, []info.FsStats{{Device: "sda1", Limit: 1000, WritableLayerSize: 100}}, stats.Filesystem)
+
+	defer func(status func() ([]byte, error)) { dmsetupStatus = status }(dmsetupStatus)
+	dmsetupStatus = func() ([]byte, error) {
+		return []byte("docker-8:1-1234-xyz: 0 20971520 thin 10 20971519\ndocker-8:1-1234-def: 0 20971520 thin 2048 20971519\n"), nil
+	}
+	handler = &dockerContainerHandler{
+		fsInfo:             &fakeFsInfo{},
+		machineInfoFactory: &fakeMachineInfoFactory{},
+		storageDriver:      "devicemapper",
+		mountId:            "def",
+	}
+	stats = &info.ContainerStats{}
+	assert.NoError(t, handler.getFsStats(stats))
+	assert.Equal(t, []info.FsStats{{Device: "sda1", Limit: 1000, WritableLayerSize: 2048 * 512}}, stats.Filesystem)
+
+	// The thin devices of stopped containers are not active.
+	handler.mountId = "stopped"
+	assert.Error(t, handler.getFsStats(&info.ContainerStats{}))
+}
+
+func TestGetExitStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	handler := &dockerContainerHandler{id: "abc", dockerConfigPath: path.Join(dir, "config.json")}
+	defer func(timeout, interval time.Duration) {
+		exitStatusTimeout, exitStatusPollInterval = timeout, interval
+	}(exitStatusTimeout, exitStatusPollInterval)
+	exitStatusTimeout, exitStatusPollInterval = 50*time.Millisecond, 10*time.Millisecond
+
+	// Containers removed on exit have no config left.
+	_, err = handler.GetExitStatus()
+	assert.Error(t, err)
+
+	err = ioutil.WriteFile(handler.dockerConfigPath, []byte(`{"State": {"Running": true}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = handler.GetExitStatus()
+	assert.Error(t, err)
+
+	err = ioutil.WriteFile(handler.dockerConfigPath, []byte(`{
+		"State": {"Running": false, "ExitCode": 137, "OOMKilled": true, "FinishedAt": "2015-10-01T10:00:00Z"}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exitStatus, err := handler.GetExitStatus()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 137, exitStatus.ExitCode)
+		assert.True(t, exitStatus.OomKilled)
+		assert.True(t, exitStatus.FinishedAt.Equal(time.Date(2015, 10, 1, 10, 0, 0, 0, time.UTC)))
+	}
+}
+
+func TestSeccompProfileName(t *testing.T) {
+	assert.Equal(t, "custom", seccompProfileName(`{"defaultAction": "SCMP_ACT_ERRNO", "syscalls": []}`))
+	assert.Equal(t, "unconfined", seccompProfileName("unconfined"))
+	assert.Equal(t, "", seccompProfileName(""))
+}
+
+func TestReadImageRefs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repositoriesPath := path.Join(dir, "repositories.json")
+	err = ioutil.WriteFile(repositoriesPath, []byte(`{"Repositories": {
+		"nginx": {"nginx:1.9": "sha256:abc", "nginx:latest": "sha256:abc", "nginx@sha256:d1": "sha256:abc", "nginx:1.8": "sha256:def"},
+		"mirror.example.com/nginx": {"mirror.example.com/nginx:1.9": "sha256:abc", "mirror.example.com/nginx@sha256:d0": "sha256:abc"}
+	}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, digest, err := readImageRefs(repositoriesPath, "sha256:abc", "nginx:1.9")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"mirror.example.com/nginx:1.9", "nginx:1.9", "nginx:latest"}, tags)
+		// The digest of the repository the container was created from.
+		assert.Equal(t, "sha256:d1", digest)
+	}
+	_, digest, err = readImageRefs(repositoriesPath, "sha256:abc", "sha256:abc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "sha256:d0", digest)
+	}
+
+	// Images not pulled from a registry have no digest.
+	tags, digest, err = readImageRefs(repositoriesPath, "sha256:def", "nginx:1.8")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"nginx:1.8"}, tags)
+		assert.Equal(t, "", digest)
+	}
+
+	_, _, err = readImageRefs(path.Join(dir, "missing.json"), "sha256:abc", "nginx")
+	assert.Error(t, err)
+}
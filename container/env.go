@@ -0,0 +1,74 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"flag"
+	"path"
+	"strings"
+)
+
+var exposeEnv = flag.Bool("expose_container_env", false, "Whether to report the environment variables of Docker and containerd containers in their spec, filtered by -container_env_allowlist and -container_env_denylist")
+var envAllowlist = flag.String("container_env_allowlist", "", "Comma separated list of glob patterns of the names of the environment variables reported with -expose_container_env. Empty reports all of them but the denied ones")
+var envDenylist = flag.String("container_env_denylist", "*PASSWORD*,*PASSWD*,*SECRET*,*TOKEN*,*KEY*,*CREDENTIAL*", "Comma separated list of glob patterns of the names of the environment variables never reported with -expose_container_env")
+
+// Returns the environment variables, as "name=value" strings, of a container
+// which can be reported in its spec. Returns nil unless the environment of
+// containers is exposed.
+func FilterEnv(env []string) map[string]string {
+	if !*exposeEnv {
+		return nil
+	}
+	return filterEnv(env, splitPatterns(*envAllowlist), splitPatterns(*envDenylist))
+}
+
+func splitPatterns(patterns string) []string {
+	var ret []string
+	for _, pattern := range strings.Split(patterns, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			ret = append(ret, strings.ToUpper(pattern))
+		}
+	}
+	return ret
+}
+
+// Returns whether the name matches one of the patterns, ignoring case.
+func matchesAny(name string, patterns []string) bool {
+	name = strings.ToUpper(name)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the variables whose names match an allowed pattern, or any name if
+// there are none, and no denied pattern.
+func filterEnv(env []string, allowed, denied []string) map[string]string {
+	ret := make(map[string]string, len(env))
+	for _, variable := range env {
+		parts := strings.SplitN(variable, "=", 2)
+		name, value := parts[0], ""
+		if len(parts) == 2 {
+			value = parts[1]
+		}
+		if name == "" || (len(allowed) > 0 && !matchesAny(name, allowed)) || matchesAny(name, denied) {
+			continue
+		}
+		ret[name] = value
+	}
+	return ret
+}
@@ -56,6 +56,18 @@ func HasFactories() bool {
 	return len(factories) != 0
 }
 
+// Returns the names of the registered factories in the order they are asked to handle containers.
+func FactoryNames() []string {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for _, factory := range factories {
+		names = append(names, factory.String())
+	}
+	return names
+}
+
 // Create a new ContainerHandler for the specified container.
 func NewContainerHandler(name string) (ContainerHandler, error) {
 	factoriesLock.RLock()
@@ -0,0 +1,39 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"strings"
+)
+
+// Returns the tag and digest an image reference names, e.g. "nginx:1.9" and
+// "" for "nginx:1.9", or "" and "sha256:..." for "nginx@sha256:...".
+// References without a tag or digest name the "latest" tag. Image IDs name
+// neither.
+func ParseImageReference(ref string) (repoTag string, digest string) {
+	if ref == "" || strings.HasPrefix(ref, "sha256:") {
+		return "", ""
+	}
+	if i := strings.Index(ref, "@"); i >= 0 {
+		// The tag, if any, is not what the image was pulled by.
+		return "", ref[i+1:]
+	}
+	// The last component of the repository may hold a tag, the ones before a
+	// registry port.
+	if strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		return ref, ""
+	}
+	return ref + ":latest", ""
+}
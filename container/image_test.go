@@ -0,0 +1,40 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageReference(t *testing.T) {
+	for _, test := range []struct {
+		ref, tag, digest string
+	}{
+		{"nginx:1.9", "nginx:1.9", ""},
+		{"nginx", "nginx:latest", ""},
+		{"registry.example.com:5000/team/app", "registry.example.com:5000/team/app:latest", ""},
+		{"registry.example.com:5000/team/app:v2", "registry.example.com:5000/team/app:v2", ""},
+		{"nginx@sha256:0123abcd", "", "sha256:0123abcd"},
+		{"nginx:1.9@sha256:0123abcd", "", "sha256:0123abcd"},
+		{"sha256:4567ef", "", ""},
+		{"", "", ""},
+	} {
+		tag, digest := ParseImageReference(test.ref)
+		assert.Equal(t, test.tag, tag, test.ref)
+		assert.Equal(t, test.digest, digest, test.ref)
+	}
+}
@@ -15,16 +15,30 @@
 package libcontainer
 
 import (
+	"flag"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/libcontainer"
 	"github.com/docker/libcontainer/cgroups"
 	cgroupfs "github.com/docker/libcontainer/cgroups/fs"
 	"github.com/docker/libcontainer/network"
+	"github.com/google/cadvisor/container"
 	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/utils/sysinfo"
 )
 
+var includeLoopback = flag.Bool("network_include_loopback", false, "Whether to report the stats of the loopback interface of containers along with their other network interfaces")
+var diskIoActiveDevicesOnly = flag.Bool("diskio_active_devices_only", false, "Whether to only report the diskio stats of the block devices a container performed I/O on, leaving out the devices whose counters are all zero")
+var diskIoNamesFromPartitions = flag.Bool("diskio_device_names_from_partitions", false, "Whether to name the block devices of the diskio stats from /proc/partitions rather than from the links of /sys/dev/block")
+
 type CgroupSubsystems struct {
 	// Cgroup subsystem mounts.
 	// e.g.: "/sys/fs/cgroup/cpu" -> ["cpu", "cpuacct"]
@@ -33,6 +47,9 @@ type CgroupSubsystems struct {
 	// Cgroup subsystem to their mount location.
 	// e.g.: "cpu" -> "/sys/fs/cgroup/cpu"
 	MountPoints map[string]string
+
+	// Version of the cgroup hierarchies the mounts belong to.
+	Version int
 }
 
 // Get information about the cgroup subsystems.
@@ -58,12 +75,253 @@ func GetCgroupSubsystems() (CgroupSubsystems, error) {
 		}
 	}
 
+	// libcontainer only finds the per-subsystem hierarchies, the unified
+	// hierarchy is never used.
 	return CgroupSubsystems{
 		Mounts:      supportedCgroups,
 		MountPoints: mountPoints,
+		Version:     1,
 	}, nil
 }
 
+// Get the creation time of the container with the given cgroup paths: the
+// lowest modification time of its cgroup directories across hierarchies.
+// Returns the zero time if none of them can be read.
+func GetCgroupCreationTime(cgroupPaths map[string]string) time.Time {
+	var lowestTime time.Time
+	for _, cgroupPath := range cgroupPaths {
+		// The modified time of the cgroup directory is when the container was created.
+		fi, err := os.Stat(cgroupPath)
+		if err == nil && (lowestTime.IsZero() || fi.ModTime().Before(lowestTime)) {
+			lowestTime = fi.ModTime()
+		}
+	}
+	return lowestTime
+}
+
+// Get the controllers enabled for the cgroup of a container on the unified
+// (v2) hierarchy, sorted, from the cgroup.controllers file of one of its
+// cgroup paths. Returns nil on cgroup v1, whose cgroups have no such file.
+func GetCgroupControllers(cgroupPaths map[string]string) []string {
+	for _, cgroupPath := range cgroupPaths {
+		out, err := ioutil.ReadFile(path.Join(cgroupPath, "cgroup.controllers"))
+		if err != nil {
+			continue
+		}
+		controllers := strings.Fields(string(out))
+		sort.Strings(controllers)
+		return controllers
+	}
+	return nil
+}
+
+// Reads a memory limit from a cgroup file, "max" being unlimited. Returns
+// false if the file is missing or invalid.
+func readMemoryLimit(memoryPath, file string) (uint64, bool) {
+	out, err := ioutil.ReadFile(path.Join(memoryPath, file))
+	if err != nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(string(out))
+	if value == "max" {
+		return math.MaxUint64, true
+	}
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return limit, true
+}
+
+// Reads the memory limits of a container from its memory cgroup, of either
+// version. Limits whose files are missing are left unchanged. The swap limit
+// is the one of the memory and swap usage, as in cgroup v1.
+func GetMemorySpec(memoryPath string, spec *info.MemorySpec) {
+	// cgroup v1.
+	if limit, ok := readMemoryLimit(memoryPath, "memory.limit_in_bytes"); ok {
+		spec.Limit = limit
+	}
+	if limit, ok := readMemoryLimit(memoryPath, "memory.memsw.limit_in_bytes"); ok {
+		spec.SwapLimit = limit
+	}
+	if limit, ok := readMemoryLimit(memoryPath, "memory.soft_limit_in_bytes"); ok {
+		spec.SoftLimit = limit
+	}
+
+	// cgroup v2.
+	if limit, ok := readMemoryLimit(memoryPath, "memory.max"); ok {
+		spec.Limit = limit
+	}
+	if swap, ok := readMemoryLimit(memoryPath, "memory.swap.max"); ok {
+		spec.SwapLimit = math.MaxUint64
+		if spec.Limit != math.MaxUint64 && swap != math.MaxUint64 {
+			spec.SwapLimit = spec.Limit + swap
+		}
+	}
+	if low, ok := readMemoryLimit(memoryPath, "memory.low"); ok {
+		spec.Low = low
+	}
+	if high, ok := readMemoryLimit(memoryPath, "memory.high"); ok {
+		spec.High = high
+	}
+}
+
+// Reads the CPU hard limit of a container from its cpu cgroup, of either
+// version, in milli-cpus: the CPU time it may use per period of the CFS
+// scheduler. Returns 0 if it is unlimited or the files are missing.
+func GetCpuMaxLimit(cpuPath string) uint64 {
+	var quota, period string
+	if out, err := ioutil.ReadFile(path.Join(cpuPath, "cpu.max")); err == nil {
+		// cgroup v2, e.g. "50000 100000" or "max 100000".
+		fields := strings.Fields(string(out))
+		if len(fields) != 2 {
+			return 0
+		}
+		quota, period = fields[0], fields[1]
+	} else {
+		out, err := ioutil.ReadFile(path.Join(cpuPath, "cpu.cfs_quota_us"))
+		if err != nil {
+			return 0
+		}
+		quota = strings.TrimSpace(string(out))
+		if out, err = ioutil.ReadFile(path.Join(cpuPath, "cpu.cfs_period_us")); err != nil {
+			return 0
+		}
+		period = strings.TrimSpace(string(out))
+	}
+	// The quota is "max" on cgroup v2 and -1 on cgroup v1 when unlimited.
+	q, err := strconv.ParseUint(quota, 10, 64)
+	if err != nil {
+		return 0
+	}
+	p, err := strconv.ParseUint(period, 10, 64)
+	if err != nil || p == 0 {
+		return 0
+	}
+	return q * 1000 / p
+}
+
+// Reads the CPUs and memory nodes a container can use from its cpuset cgroup,
+// of either version. The effective sets are read when available since the
+// configured ones may be empty, inheriting the parent's on cgroup v2, or be
+// restricted by the parent cgroup or CPU hotplug. Sets whose files are missing
+// are left unchanged.
+func GetCpusetSpec(cpusetPath string, spec *info.CpuSpec) {
+	if cpus := readCpuset(cpusetPath, "cpuset.cpus.effective", "cpuset.effective_cpus", "cpuset.cpus"); cpus != "" {
+		spec.CpuSet = cpus
+	}
+	if mems := readCpuset(cpusetPath, "cpuset.mems.effective", "cpuset.effective_mems", "cpuset.mems"); mems != "" {
+		spec.Mems = mems
+	}
+}
+
+// Returns the content of the first of the cpuset files which is not empty, or
+// "" if there are none.
+func readCpuset(cpusetPath string, files ...string) string {
+	for _, file := range files {
+		out, err := ioutil.ReadFile(path.Join(cpusetPath, file))
+		if err != nil {
+			continue
+		}
+		if set := strings.TrimSpace(string(out)); set != "" {
+			return set
+		}
+	}
+	return ""
+}
+
+// Control files of each cgroup subsystem dumped by GetCgroupConfig, of both
+// cgroup versions.
+var cgroupConfigFiles = map[string][]string{
+	"cpu": {
+		"cpu.shares", "cpu.cfs_quota_us", "cpu.cfs_period_us", "cpu.rt_runtime_us", "cpu.rt_period_us",
+		"cpu.weight", "cpu.max",
+	},
+	"cpuset": {
+		"cpuset.cpus", "cpuset.mems",
+		"cpuset.cpus.effective", "cpuset.mems.effective",
+	},
+	"memory": {
+		"memory.limit_in_bytes", "memory.soft_limit_in_bytes", "memory.memsw.limit_in_bytes", "memory.kmem.limit_in_bytes",
+		"memory.swappiness", "memory.use_hierarchy", "memory.oom_control",
+		"memory.min", "memory.low", "memory.high", "memory.max", "memory.swap.max", "memory.oom.group",
+	},
+	"blkio": {
+		"blkio.weight", "blkio.weight_device",
+		"blkio.throttle.read_bps_device", "blkio.throttle.write_bps_device",
+		"blkio.throttle.read_iops_device", "blkio.throttle.write_iops_device",
+		"io.weight", "io.max",
+	},
+	"pids": {"pids.max"},
+}
+
+// Get the settings of a container from the control files of its cgroups, as
+// a map from file name to content. cgroupPath returns the cgroup of the
+// container for a subsystem, as ContainerHandler.GetCgroupPath() does.
+// Subsystems without a cgroup and missing files, e.g. the ones of the other
+// cgroup version, are left out.
+func GetCgroupConfig(cgroupPath func(subsystem string) (string, error)) (map[string]string, error) {
+	config := make(map[string]string)
+	for subsystem, files := range cgroupConfigFiles {
+		dir, err := cgroupPath(subsystem)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			out, err := ioutil.ReadFile(path.Join(dir, file))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			config[file] = strings.TrimSpace(string(out))
+		}
+	}
+	return config, nil
+}
+
+// Get how cgroups are mounted on the machine, one of the info.CgroupMode* constants.
+func GetCgroupMode() (string, error) {
+	mountInfo, err := ioutil.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return "", err
+	}
+	return cgroupModeFromMountInfo(string(mountInfo))
+}
+
+func cgroupModeFromMountInfo(mountInfo string) (string, error) {
+	hasV1 := false
+	hasV2 := false
+	for _, line := range strings.Split(mountInfo, "\n") {
+		// The filesystem type is the first field after the " - " separator.
+		parts := strings.SplitN(line, " - ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[1])
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "cgroup":
+			hasV1 = true
+		case "cgroup2":
+			hasV2 = true
+		}
+	}
+	switch {
+	case hasV1 && hasV2:
+		return info.CgroupModeHybrid, nil
+	case hasV1:
+		return info.CgroupModeLegacy, nil
+	case hasV2:
+		return info.CgroupModeUnified, nil
+	default:
+		return "", fmt.Errorf("failed to find cgroup mounts")
+	}
+}
+
 // Cgroup subsystems we support listing (should be the minimal set we need stats from).
 var supportedSubsystems map[string]struct{} = map[string]struct{}{
 	"cpu":     {},
@@ -71,25 +329,615 @@ var supportedSubsystems map[string]struct{} = map[string]struct{}{
 	"memory":  {},
 	"cpuset":  {},
 	"blkio":   {},
+	"pids":    {},
+	"hugetlb": {},
+	// Only read to attribute GPUs to containers.
+	"devices": {},
+}
+
+// Cgroup hierarchies the stats of each subsystem are read from.
+var subsystemCgroups = map[string][]string{
+	container.SubsystemCpu:    {"cpu", "cpuacct", "cpuset"},
+	container.SubsystemMemory: {"memory"},
+	container.SubsystemDiskIo: {"blkio"},
 }
 
-// Get stats of the specified container
-func GetStats(cgroupPaths map[string]string, state *libcontainer.State) (*info.ContainerStats, error) {
+// Get stats of the specified container. Failures are reported per subsystem
+// in a *container.StatsError, the stats of the other subsystems are returned.
+// The metrics in ignoreMetrics are not collected.
+func GetStats(cgroupPaths map[string]string, state *libcontainer.State, ignoreMetrics container.MetricSet) (*info.ContainerStats, error) {
 	// TODO(vmarmol): Use libcontainer's Stats() in the new API when that is ready.
-	stats := &libcontainer.ContainerStats{}
+	stats := &libcontainer.ContainerStats{
+		CgroupStats: cgroups.NewStats(),
+	}
+	statsErr := &container.StatsError{}
+
+	// Read each subsystem on its own so that one failing does not hide the others.
+	for subsystem, hierarchies := range subsystemCgroups {
+		if subsystem == container.SubsystemDiskIo && ignoreMetrics.Has(container.DiskIoMetrics) {
+			continue
+		}
+		paths := make(map[string]string, len(hierarchies))
+		for _, hierarchy := range hierarchies {
+			if cgroupPath, ok := cgroupPaths[hierarchy]; ok {
+				paths[hierarchy] = cgroupPath
+			}
+		}
+		cgroupStats, err := cgroupfs.GetStats(paths)
+		if err != nil {
+			statsErr.Add(subsystem, err)
+			continue
+		}
+		switch subsystem {
+		case container.SubsystemCpu:
+			stats.CgroupStats.CpuStats = cgroupStats.CpuStats
+		case container.SubsystemMemory:
+			stats.CgroupStats.MemoryStats = cgroupStats.MemoryStats
+		case container.SubsystemDiskIo:
+			stats.CgroupStats.BlkioStats = cgroupStats.BlkioStats
+		}
+	}
 
 	var err error
-	stats.CgroupStats, err = cgroupfs.GetStats(cgroupPaths)
+	if !ignoreMetrics.Has(container.NetworkUsageMetrics) {
+		stats.NetworkStats, err = network.GetStats(&state.NetworkState)
+		statsErr.Add(container.SubsystemNetwork, err)
+	}
+
+	ret := toContainerStats(stats)
+	if memoryPath, ok := cgroupPaths["memory"]; ok {
+		ret.Memory.OomKillCount, err = GetOomKillCount(memoryPath)
+		statsErr.Add(container.SubsystemMemory, err)
+		statsErr.Add(container.SubsystemMemory, GetSwapStats(memoryPath, &ret.Memory))
+	}
+	if !ignoreMetrics.Has(container.DiskIoMetrics) {
+		// Hosts on the unified hierarchy have no blkio files, the stats are in io.stat.
+		if blkioPath, ok := cgroupPaths["blkio"]; ok {
+			ioStats, err := GetIoStats(path.Join(blkioPath, "io.stat"))
+			if err != nil {
+				statsErr.Add(container.SubsystemDiskIo, err)
+			} else if ioStats != nil {
+				ret.DiskIo = *ioStats
+			}
+		}
+		if *diskIoNamesFromPartitions {
+			statsErr.Add(container.SubsystemDiskIo, setDeviceNamesFromPartitions(&ret.DiskIo, procPartitions))
+		} else {
+			setDeviceNames(&ret.DiskIo, sysDevBlock)
+		}
+		if *diskIoActiveDevicesOnly {
+			removeIdleDevices(&ret.DiskIo)
+		}
+	}
+	ret.PSI, err = GetPSIStats(pressureFile(cgroupPaths, "cpu", "cpu.pressure"), pressureFile(cgroupPaths, "memory", "memory.pressure"), pressureFile(cgroupPaths, "blkio", "io.pressure"))
+	statsErr.Add(container.SubsystemPsi, err)
+
+	if !ignoreMetrics.Has(container.ProcessMetrics) {
+		ret.Processes, err = getProcessStats(cgroupPaths)
+		statsErr.Add(container.SubsystemProcesses, err)
+	}
+	if pidsPath, ok := cgroupPaths["pids"]; ok {
+		ret.Pids, err = GetPidsStats(pidsPath)
+		statsErr.Add(container.SubsystemProcesses, err)
+	}
+	if hugetlbPath, ok := cgroupPaths["hugetlb"]; ok {
+		ret.Memory.HugeTlb, err = GetHugeTlbStats(hugetlbPath)
+		statsErr.Add(container.SubsystemMemory, err)
+	}
+
+	// The sockets of the container are listed in the network namespace of its init process.
+	if state.InitPid > 0 {
+		procNetDir := path.Join("/proc", strconv.Itoa(state.InitPid), "net")
+		if !ignoreMetrics.Has(container.NetworkUsageMetrics) {
+			// Without a host veth the totals are those of the interfaces of the container.
+			err = GetInterfaceStats(&ret.Network, procNetDir, state.NetworkState.VethHost == "")
+			statsErr.Add(container.SubsystemNetwork, err)
+		}
+		err = GetSocketStats(&ret.Network, procNetDir, ignoreMetrics)
+		statsErr.Add(container.SubsystemNetwork, err)
+	}
+	return ret, statsErr.OrNil()
+}
+
+// Fills in the stats of each interface of the network namespace whose
+// /proc/net directory is procNetDir. The totals of stats are set to the sums
+// of those of the interfaces if setTotals is true.
+func GetInterfaceStats(stats *info.NetworkStats, procNetDir string, setTotals bool) error {
+	interfaces, err := sysinfo.GetInterfaceStats(procNetDir, *includeLoopback)
+	if err != nil {
+		return err
+	}
+	stats.Interfaces = interfaces
+	if !setTotals {
+		return nil
+	}
+	for _, iface := range interfaces {
+		stats.RxBytes += iface.RxBytes
+		stats.RxPackets += iface.RxPackets
+		stats.RxErrors += iface.RxErrors
+		stats.RxDropped += iface.RxDropped
+		stats.TxBytes += iface.TxBytes
+		stats.TxPackets += iface.TxPackets
+		stats.TxErrors += iface.TxErrors
+		stats.TxDropped += iface.TxDropped
+	}
+	return nil
+}
+
+// Get the stats of the processes in the cpu cgroup of the container, nil if
+// it has none.
+func getProcessStats(cgroupPaths map[string]string) (*info.ProcessStats, error) {
+	cgroupPath, ok := cgroupPaths["cpu"]
+	if !ok {
+		return nil, nil
+	}
+	pids, err := cgroups.ReadProcsFile(cgroupPath)
 	if err != nil {
-		return &info.ContainerStats{}, err
+		return nil, err
 	}
+	stats, err := sysinfo.GetProcessStats("/proc", pids)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
 
-	stats.NetworkStats, err = network.GetStats(&state.NetworkState)
+// Reads the number of tasks of the pids cgroup at pidsPath and its limit, nil
+// if the cgroup has no pids.current file.
+func GetPidsStats(pidsPath string) (*info.PidsStats, error) {
+	current, err := ioutil.ReadFile(path.Join(pidsPath, "pids.current"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats := &info.PidsStats{}
+	stats.Current, err = strconv.ParseUint(strings.TrimSpace(string(current)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed pids.current in %q: %v", pidsPath, err)
+	}
+	max, err := ioutil.ReadFile(path.Join(pidsPath, "pids.max"))
 	if err != nil {
-		return &info.ContainerStats{}, err
+		// The root cgroup has no limit.
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return nil, err
+	}
+	if value := strings.TrimSpace(string(max)); value != "max" {
+		stats.Limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed pids.max in %q: %v", pidsPath, err)
+		}
 	}
+	return stats, nil
+}
 
-	return toContainerStats(stats), nil
+// Reads the usage of the hugepages of each size of the hugetlb cgroup at
+// hugetlbPath, from the hugetlb.<size>.usage_in_bytes, max_usage_in_bytes
+// and failcnt files of cgroup v1 or the hugetlb.<size>.current and events
+// files of cgroup v2. Returns nil if the cgroup has none of them, or does not
+// exist.
+func GetHugeTlbStats(hugetlbPath string) (map[string]info.HugeTlbStats, error) {
+	files, err := ioutil.ReadDir(hugetlbPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stats map[string]info.HugeTlbStats
+	for _, file := range files {
+		// e.g. hugetlb.2MB.usage_in_bytes, but not hugetlb.2MB.rsvd.current.
+		parts := strings.Split(file.Name(), ".")
+		if len(parts) != 3 || parts[0] != "hugetlb" || (parts[2] != "usage_in_bytes" && parts[2] != "current") {
+			continue
+		}
+		pageSize := parts[1]
+		var pageStats info.HugeTlbStats
+		prefix := path.Join(hugetlbPath, "hugetlb."+pageSize+".")
+		if parts[2] == "usage_in_bytes" {
+			pageStats.Usage, err = readUint64File(prefix + "usage_in_bytes")
+			if err == nil {
+				pageStats.MaxUsage, err = readUint64File(prefix + "max_usage_in_bytes")
+			}
+			if err == nil {
+				pageStats.Failcnt, err = readUint64File(prefix + "failcnt")
+			}
+		} else {
+			pageStats.Usage, err = readUint64File(prefix + "current")
+			if err == nil {
+				pageStats.Failcnt, err = readHugeTlbEvents(prefix + "events")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if stats == nil {
+			stats = make(map[string]info.HugeTlbStats)
+		}
+		stats[pageSize] = pageStats
+	}
+	return stats, nil
+}
+
+// Reads a file holding an unsigned integer.
+func readUint64File(file string) (uint64, error) {
+	out, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed %q: %v", file, err)
+	}
+	return value, nil
+}
+
+// Reads the number of allocations which hit the limit from a cgroup v2
+// hugetlb.<size>.events file, e.g. "max 3". Returns 0 if it is missing.
+func readHugeTlbEvents(file string) (uint64, error) {
+	out, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "max" {
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("malformed %q: %v", file, err)
+			}
+			return value, nil
+		}
+	}
+	return 0, nil
+}
+
+// Fills in the TCP and UDP socket stats of the network namespace whose
+// /proc/net directory is procNetDir, unless they are in ignoreMetrics.
+func GetSocketStats(stats *info.NetworkStats, procNetDir string, ignoreMetrics container.MetricSet) error {
+	if ignoreMetrics.Has(container.NetworkTcpUsageMetrics) && ignoreMetrics.Has(container.NetworkUdpUsageMetrics) {
+		return nil
+	}
+	tcp, udp, err := sysinfo.GetSocketStats(procNetDir)
+	if err != nil {
+		return err
+	}
+	if !ignoreMetrics.Has(container.NetworkTcpUsageMetrics) {
+		stats.Tcp = tcp
+	}
+	if !ignoreMetrics.Has(container.NetworkUdpUsageMetrics) {
+		stats.Udp = udp
+	}
+	return nil
+}
+
+// Returns the path of a pressure file in the cgroup of the given subsystem, or
+// an empty path if the subsystem is not mounted.
+func pressureFile(cgroupPaths map[string]string, subsystem, file string) string {
+	cgroupPath, ok := cgroupPaths[subsystem]
+	if !ok {
+		return ""
+	}
+	return path.Join(cgroupPath, file)
+}
+
+// Returns the number of processes of the memory cgroup at memoryPath killed by
+// the OOM killer. It is the oom_kill field of memory.oom_control on cgroup v1
+// and of memory.events on cgroup v2. Kernels older than 4.13 have no such
+// field, 0 is returned for them.
+func GetOomKillCount(memoryPath string) (uint64, error) {
+	for _, file := range []string{"memory.oom_control", "memory.events"} {
+		out, err := ioutil.ReadFile(path.Join(memoryPath, file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 || fields[0] != "oom_kill" {
+				continue
+			}
+			count, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("malformed oom_kill count %q in %q: %v", fields[1], file, err)
+			}
+			return count, nil
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
+// Reads the swap usage of the memory cgroup at memoryPath on cgroup v2, from
+// memory.swap.current, and the pages swapped in and out, from the pswpin and
+// pswpout fields of memory.stat which only recent kernels report. On cgroup
+// v1 the swap usage is the total_swap field of memory.stat, already read with
+// the other memory stats, and the pages swapped are not counted per cgroup.
+func GetSwapStats(memoryPath string, stats *info.MemoryStats) error {
+	swap, err := readUint64File(path.Join(memoryPath, "memory.swap.current"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	stats.Swap = swap
+	out, err := ioutil.ReadFile(path.Join(memoryPath, "memory.stat"))
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || (fields[0] != "pswpin" && fields[0] != "pswpout") {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed %s count %q in memory.stat: %v", fields[0], fields[1], err)
+		}
+		if fields[0] == "pswpin" {
+			stats.SwapIn = value
+		} else {
+			stats.SwapOut = value
+		}
+	}
+	return nil
+}
+
+// Reads the per device stats of a cgroup v2 io.stat file, e.g.:
+// 8:0 rbytes=90430464 wbytes=299008000 rios=8950 wios=1252 dbytes=0 dios=0
+// The bytes and operations are reported as the Read, Write and Total ops of
+// IoServiceBytes and IoServiced, as the blkio files of cgroup v1 are. Returns
+// nil if the file does not exist.
+func GetIoStats(ioStatFile string) (*info.DiskIoStats, error) {
+	out, err := ioutil.ReadFile(ioStatFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats := &info.DiskIoStats{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		bytes := info.PerDiskStats{Stats: map[string]uint64{}}
+		ios := info.PerDiskStats{Stats: map[string]uint64{}}
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &bytes.Major, &bytes.Minor); err != nil {
+			return nil, fmt.Errorf("malformed device %q in %q: %v", fields[0], ioStatFile, err)
+		}
+		ios.Major, ios.Minor = bytes.Major, bytes.Minor
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("malformed field %q in %q", field, ioStatFile)
+			}
+			var op string
+			var disk *info.PerDiskStats
+			switch kv[0] {
+			case "rbytes":
+				op, disk = "Read", &bytes
+			case "wbytes":
+				op, disk = "Write", &bytes
+			case "rios":
+				op, disk = "Read", &ios
+			case "wios":
+				op, disk = "Write", &ios
+			default:
+				// Discards and the stats of io.latency have no cgroup v1 counterpart.
+				continue
+			}
+			value, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed field %q in %q: %v", field, ioStatFile, err)
+			}
+			disk.Stats[op] = value
+			disk.Stats["Total"] += value
+		}
+		stats.IoServiceBytes = append(stats.IoServiceBytes, bytes)
+		stats.IoServiced = append(stats.IoServiced, ios)
+	}
+	return stats, nil
+}
+
+// Directory of the links from the major:minor numbers of block devices to
+// their sysfs directories.
+var sysDevBlock = "/sys/dev/block"
+
+// The block devices of the host with their numbers.
+var procPartitions = "/proc/partitions"
+
+// Returns the per device stats of the disk stats.
+func perDiskStats(stats *info.DiskIoStats) []*[]info.PerDiskStats {
+	return []*[]info.PerDiskStats{
+		&stats.IoServiceBytes, &stats.IoServiced, &stats.IoQueued, &stats.Sectors,
+		&stats.IoServiceTime, &stats.IoWaitTime, &stats.IoMerged, &stats.IoTime,
+	}
+}
+
+// Sets the names of the devices of the disk stats to the ones returned by
+// name for their major:minor numbers, e.g. "8:0".
+func nameDevices(stats *info.DiskIoStats, name func(device string) string) {
+	names := map[string]string{}
+	for _, perDisk := range perDiskStats(stats) {
+		for i := range *perDisk {
+			disk := &(*perDisk)[i]
+			device := fmt.Sprintf("%d:%d", disk.Major, disk.Minor)
+			deviceName, ok := names[device]
+			if !ok {
+				deviceName = name(device)
+				names[device] = deviceName
+			}
+			disk.Device = deviceName
+		}
+	}
+}
+
+// Sets the names of the devices of the disk stats from the links in
+// sysDevBlockDir. Devices without a link are left unnamed.
+func setDeviceNames(stats *info.DiskIoStats, sysDevBlockDir string) {
+	nameDevices(stats, func(device string) string {
+		if target, err := os.Readlink(path.Join(sysDevBlockDir, device)); err == nil {
+			return path.Base(target)
+		}
+		return ""
+	})
+}
+
+// Sets the names of the devices of the disk stats from partitionsFile, in the
+// format of /proc/partitions. Devices it does not list are left unnamed.
+func setDeviceNamesFromPartitions(stats *info.DiskIoStats, partitionsFile string) error {
+	out, err := ioutil.ReadFile(partitionsFile)
+	if err != nil {
+		return err
+	}
+	names := parsePartitions(string(out))
+	nameDevices(stats, func(device string) string {
+		return names[device]
+	})
+	return nil
+}
+
+// Returns the names of the block devices listed in the content of
+// /proc/partitions by their major:minor numbers. After a header line, each
+// line lists the major and minor numbers, size and name of a device, e.g.:
+// 8 1 524288 sda1
+func parsePartitions(content string) map[string]string {
+	names := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		major, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			// The header.
+			continue
+		}
+		minor, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		names[fmt.Sprintf("%d:%d", major, minor)] = fields[3]
+	}
+	return names
+}
+
+// Removes the stats of the devices whose counters are all zero in all the
+// disk stats, i.e. the devices the cgroup performed no I/O on.
+func removeIdleDevices(stats *info.DiskIoStats) {
+	type device struct {
+		major, minor uint64
+	}
+	active := map[device]bool{}
+	for _, perDisk := range perDiskStats(stats) {
+		for _, disk := range *perDisk {
+			for _, value := range disk.Stats {
+				if value != 0 {
+					active[device{disk.Major, disk.Minor}] = true
+					break
+				}
+			}
+		}
+	}
+	for _, perDisk := range perDiskStats(stats) {
+		var kept []info.PerDiskStats
+		for _, disk := range *perDisk {
+			if active[device{disk.Major, disk.Minor}] {
+				kept = append(kept, disk)
+			}
+		}
+		*perDisk = kept
+	}
+}
+
+// Reads the pressure stall information of the cpu, memory and io pressure
+// files. Missing files are skipped, nil is returned when none exists.
+func GetPSIStats(cpuFile, memoryFile, ioFile string) (*info.PSIStats, error) {
+	psi := &info.PSIStats{}
+	found := false
+	for _, resource := range []struct {
+		file  string
+		stats **info.PSIResourceStats
+	}{
+		{cpuFile, &psi.Cpu},
+		{memoryFile, &psi.Memory},
+		{ioFile, &psi.Io},
+	} {
+		if resource.file == "" {
+			continue
+		}
+		out, err := ioutil.ReadFile(resource.file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stats, err := parsePressure(string(out))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %v", resource.file, err)
+		}
+		*resource.stats = stats
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return psi, nil
+}
+
+// Parses a pressure file, e.g.:
+// some avg10=0.12 avg60=0.05 avg300=0.01 total=1234
+// full avg10=0.00 avg60=0.00 avg300=0.00 total=56
+func parsePressure(content string) (*info.PSIResourceStats, error) {
+	stats := &info.PSIResourceStats{}
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var data *info.PSIData
+		switch fields[0] {
+		case "some":
+			data = &stats.Some
+		case "full":
+			data = &stats.Full
+		default:
+			return nil, fmt.Errorf("unknown pressure line %q", line)
+		}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("malformed pressure field %q", field)
+			}
+			var err error
+			switch kv[0] {
+			case "avg10":
+				data.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				data.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				data.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				data.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("malformed pressure field %q: %v", field, err)
+			}
+		}
+	}
+	return stats, nil
 }
 
 func DiskStatsCopy(blkio_stats []cgroups.BlkioStatEntry) (stat []info.PerDiskStats) {
@@ -150,6 +998,9 @@ func toContainerStats(libcontainerStats *libcontainer.ContainerStats) *info.Cont
 			ret.Cpu.Usage.PerCpu[i] = s.CpuStats.CpuUsage.PercpuUsage[i]
 			ret.Cpu.Usage.Total += s.CpuStats.CpuUsage.PercpuUsage[i]
 		}
+		ret.Cpu.NrPeriods = s.CpuStats.ThrottlingData.Periods
+		ret.Cpu.NrThrottled = s.CpuStats.ThrottlingData.ThrottledPeriods
+		ret.Cpu.ThrottledTime = s.CpuStats.ThrottlingData.ThrottledTime
 
 		ret.DiskIo.IoServiceBytes = DiskStatsCopy(s.BlkioStats.IoServiceBytesRecursive)
 		ret.DiskIo.IoServiced = DiskStatsCopy(s.BlkioStats.IoServicedRecursive)
@@ -169,6 +1020,10 @@ func toContainerStats(libcontainerStats *libcontainer.ContainerStats) *info.Cont
 			ret.Memory.ContainerData.Pgmajfault = v
 			ret.Memory.HierarchicalData.Pgmajfault = v
 		}
+		ret.Memory.Cache = s.MemoryStats.Stats["total_cache"]
+		ret.Memory.RSS = s.MemoryStats.Stats["total_rss"]
+		ret.Memory.Swap = s.MemoryStats.Stats["total_swap"]
+		ret.Memory.Failcnt = s.MemoryStats.Failcnt
 		if v, ok := s.MemoryStats.Stats["total_inactive_anon"]; ok {
 			ret.Memory.WorkingSet = ret.Memory.Usage - v
 			if v, ok := s.MemoryStats.Stats["total_active_file"]; ok {
@@ -176,9 +1031,15 @@ func toContainerStats(libcontainerStats *libcontainer.ContainerStats) *info.Cont
 			}
 		}
 	}
-	// TODO(vishh): Perform a deep copy or alias libcontainer network stats.
-	if libcontainerStats.NetworkStats != nil {
-		ret.Network = *(*info.NetworkStats)(libcontainerStats.NetworkStats)
+	if n := libcontainerStats.NetworkStats; n != nil {
+		ret.Network.RxBytes = n.RxBytes
+		ret.Network.RxPackets = n.RxPackets
+		ret.Network.RxErrors = n.RxErrors
+		ret.Network.RxDropped = n.RxDropped
+		ret.Network.TxBytes = n.TxBytes
+		ret.Network.TxPackets = n.TxPackets
+		ret.Network.TxErrors = n.TxErrors
+		ret.Network.TxDropped = n.TxDropped
 	}
 
 	return ret
@@ -0,0 +1,745 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package libcontainer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/libcontainer"
+	"github.com/docker/libcontainer/cgroups"
+	"github.com/google/cadvisor/container"
+	info "github.com/google/cadvisor/info/v1"
+)
+
+const (
+	procMount    = "20 1 0:4 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw\n"
+	cpuMount     = "26 25 0:22 / /sys/fs/cgroup/cpu,cpuacct rw,nosuid,nodev,noexec,relatime shared:8 - cgroup cgroup rw,cpu,cpuacct\n"
+	unifiedMount = "27 25 0:23 / /sys/fs/cgroup/unified rw,nosuid,nodev,noexec,relatime shared:9 - cgroup2 cgroup2 rw\n"
+)
+
+func TestCgroupModeFromMountInfo(t *testing.T) {
+	testCases := []struct {
+		mountInfo string
+		mode      string
+	}{
+		{procMount + cpuMount, info.CgroupModeLegacy},
+		{procMount + cpuMount + unifiedMount, info.CgroupModeHybrid},
+		{procMount + unifiedMount, info.CgroupModeUnified},
+	}
+	for _, tc := range testCases {
+		mode, err := cgroupModeFromMountInfo(tc.mountInfo)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tc.mountInfo, err)
+		} else if mode != tc.mode {
+			t.Errorf("expected mode %q for %q but got %q", tc.mode, tc.mountInfo, mode)
+		}
+	}
+
+	if _, err := cgroupModeFromMountInfo(procMount); err == nil {
+		t.Errorf("expected an error without cgroup mounts")
+	}
+}
+
+func TestGetPSIStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pressure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// Older kernels do not report full stalls for cpu.
+	cpuPressure := "some avg10=1.50 avg60=0.75 avg300=0.25 total=123456\n"
+	if err := ioutil.WriteFile(path.Join(dir, "cpu.pressure"), []byte(cpuPressure), 0644); err != nil {
+		t.Fatal(err)
+	}
+	memoryPressure := "some avg10=0.00 avg60=0.00 avg300=0.00 total=10\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=5\n"
+	if err := ioutil.WriteFile(path.Join(dir, "memory.pressure"), []byte(memoryPressure), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	psi, err := GetPSIStats(path.Join(dir, "cpu.pressure"), path.Join(dir, "memory.pressure"), path.Join(dir, "io.pressure"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedCpu := info.PSIResourceStats{Some: info.PSIData{Avg10: 1.5, Avg60: 0.75, Avg300: 0.25, Total: 123456}}
+	if psi.Cpu == nil || *psi.Cpu != expectedCpu {
+		t.Errorf("expected cpu pressure %+v, got %+v", expectedCpu, psi.Cpu)
+	}
+	if psi.Memory == nil || psi.Memory.Full.Total != 5 {
+		t.Errorf("expected a full memory stall total of 5, got %+v", psi.Memory)
+	}
+	if psi.Io != nil {
+		t.Errorf("expected no io pressure without io.pressure, got %+v", psi.Io)
+	}
+
+	psi, err = GetPSIStats(path.Join(dir, "missing"), "", "")
+	if err != nil || psi != nil {
+		t.Errorf("expected no pressure stall information without pressure files, got %+v, %v", psi, err)
+	}
+}
+
+func TestParsePressureMalformed(t *testing.T) {
+	for _, content := range []string{"partial avg10=0.00", "some avg10", "some avg10=abc"} {
+		if _, err := parsePressure(content); err == nil {
+			t.Errorf("expected an error parsing %q", content)
+		}
+	}
+}
+
+func TestGetIoStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioStat := "8:16 rbytes=1024 wbytes=4096 rios=2 wios=8 dbytes=0 dios=0\n253:0 rbytes=512 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n"
+	if err := ioutil.WriteFile(path.Join(dir, "io.stat"), []byte(ioStat), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := GetIoStats(path.Join(dir, "io.stat"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.IoServiceBytes) != 2 || len(stats.IoServiced) != 2 {
+		t.Fatalf("expected the stats of 2 devices, got %+v", stats)
+	}
+	bytes := stats.IoServiceBytes[0]
+	if bytes.Major != 8 || bytes.Minor != 16 || bytes.Stats["Read"] != 1024 || bytes.Stats["Write"] != 4096 || bytes.Stats["Total"] != 5120 {
+		t.Errorf("unexpected bytes of 8:16: %+v", bytes)
+	}
+	ios := stats.IoServiced[1]
+	if ios.Major != 253 || ios.Minor != 0 || ios.Stats["Read"] != 1 || ios.Stats["Total"] != 1 {
+		t.Errorf("unexpected operations of 253:0: %+v", ios)
+	}
+
+	// Name the devices from their sysfs links.
+	sysDir := path.Join(dir, "block")
+	if err := os.Mkdir(sysDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../devices/pci0000:00/0000:00:1f.2/ata2/host1/target1:0:0/1:0:0:0/block/sdb", path.Join(sysDir, "8:16")); err != nil {
+		t.Fatal(err)
+	}
+	setDeviceNames(stats, sysDir)
+	if stats.IoServiceBytes[0].Device != "sdb" || stats.IoServiced[0].Device != "sdb" {
+		t.Errorf("expected 8:16 to be named sdb, got %+v", stats)
+	}
+	if stats.IoServiceBytes[1].Device != "" {
+		t.Errorf("expected 253:0 to be unnamed, got %q", stats.IoServiceBytes[1].Device)
+	}
+
+	stats, err = GetIoStats(path.Join(dir, "missing"))
+	if err != nil || stats != nil {
+		t.Errorf("expected no stats without io.stat, got %+v, %v", stats, err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "io.stat"), []byte("8:16 rbytes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetIoStats(path.Join(dir, "io.stat")); err == nil {
+		t.Error("expected an error parsing a malformed io.stat")
+	}
+}
+
+func TestSetDeviceNamesFromPartitions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "partitions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	partitions := "major minor  #blocks  name\n\n   8        0  488386584 sda\n   8        1     524288 sda1\n 253        0   20971520 dm-0\n"
+	if err := ioutil.WriteFile(path.Join(dir, "partitions"), []byte(partitions), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &info.DiskIoStats{
+		IoServiceBytes: []info.PerDiskStats{{Major: 8, Minor: 0}, {Major: 7, Minor: 0}},
+		IoTime:         []info.PerDiskStats{{Major: 253, Minor: 0}},
+	}
+	if err := setDeviceNamesFromPartitions(stats, path.Join(dir, "partitions")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.IoServiceBytes[0].Device != "sda" || stats.IoTime[0].Device != "dm-0" {
+		t.Errorf("expected 8:0 and 253:0 to be named sda and dm-0, got %+v", stats)
+	}
+	if stats.IoServiceBytes[1].Device != "" {
+		t.Errorf("expected 7:0 to be unnamed, got %q", stats.IoServiceBytes[1].Device)
+	}
+
+	if err := setDeviceNamesFromPartitions(stats, path.Join(dir, "missing")); err == nil {
+		t.Error("expected an error without /proc/partitions")
+	}
+}
+
+func TestRemoveIdleDevices(t *testing.T) {
+	stats := &info.DiskIoStats{
+		IoServiceBytes: []info.PerDiskStats{
+			{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": 0, "Write": 0}},
+			{Major: 8, Minor: 16, Stats: map[string]uint64{"Read": 0, "Write": 0}},
+			{Major: 253, Minor: 0, Stats: map[string]uint64{"Read": 512, "Write": 0}},
+		},
+		// Only queued operations, without bytes transferred yet.
+		IoQueued: []info.PerDiskStats{
+			{Major: 8, Minor: 0, Stats: map[string]uint64{"Total": 1}},
+			{Major: 8, Minor: 16, Stats: map[string]uint64{"Total": 0}},
+		},
+		IoTime: []info.PerDiskStats{
+			{Major: 8, Minor: 16, Stats: map[string]uint64{"Count": 0}},
+		},
+	}
+	removeIdleDevices(stats)
+	if len(stats.IoServiceBytes) != 2 || stats.IoServiceBytes[0].Minor != 0 || stats.IoServiceBytes[1].Major != 253 {
+		t.Errorf("expected the bytes of 8:0 and 253:0, got %+v", stats.IoServiceBytes)
+	}
+	if len(stats.IoQueued) != 1 || stats.IoQueued[0].Minor != 0 {
+		t.Errorf("expected the queued operations of 8:0, got %+v", stats.IoQueued)
+	}
+	if stats.IoTime != nil {
+		t.Errorf("expected no time stats, got %+v", stats.IoTime)
+	}
+}
+
+func TestGetCgroupCreationTime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	created := time.Unix(1000, 0)
+	for name, mtime := range map[string]time.Time{"cpu": created.Add(time.Minute), "memory": created} {
+		if err := os.Mkdir(path.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path.Join(dir, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cgroupPaths := map[string]string{
+		"cpu":    path.Join(dir, "cpu"),
+		"memory": path.Join(dir, "memory"),
+		"blkio":  path.Join(dir, "blkio"),
+	}
+	if creationTime := GetCgroupCreationTime(cgroupPaths); !creationTime.Equal(created) {
+		t.Errorf("expected creation time %v, got %v", created, creationTime)
+	}
+	if creationTime := GetCgroupCreationTime(map[string]string{"blkio": path.Join(dir, "blkio")}); !creationTime.IsZero() {
+		t.Errorf("expected no creation time without cgroup directories, got %v", creationTime)
+	}
+}
+
+func writeCgroupFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetMemorySpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	v1 := path.Join(dir, "v1")
+	v2 := path.Join(dir, "v2")
+	for _, d := range []string{v1, v2} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCgroupFiles(t, v1, map[string]string{
+		"memory.limit_in_bytes":       "1073741824\n",
+		"memory.memsw.limit_in_bytes": "2147483648\n",
+		"memory.soft_limit_in_bytes":  "536870912\n",
+	})
+	writeCgroupFiles(t, v2, map[string]string{
+		"memory.max":      "1073741824\n",
+		"memory.swap.max": "1073741824\n",
+		"memory.low":      "268435456\n",
+		"memory.high":     "max\n",
+	})
+
+	var spec info.MemorySpec
+	GetMemorySpec(v1, &spec)
+	expected := info.MemorySpec{Limit: 1 << 30, SwapLimit: 2 << 30, SoftLimit: 512 << 20}
+	if spec != expected {
+		t.Errorf("expected cgroup v1 memory spec %+v, got %+v", expected, spec)
+	}
+
+	spec = info.MemorySpec{}
+	GetMemorySpec(v2, &spec)
+	expected = info.MemorySpec{Limit: 1 << 30, SwapLimit: 2 << 30, Low: 256 << 20, High: math.MaxUint64}
+	if spec != expected {
+		t.Errorf("expected cgroup v2 memory spec %+v, got %+v", expected, spec)
+	}
+
+	// Unlimited swap.
+	writeCgroupFiles(t, v2, map[string]string{"memory.swap.max": "max\n"})
+	GetMemorySpec(v2, &spec)
+	if spec.SwapLimit != math.MaxUint64 {
+		t.Errorf("expected unlimited swap, got %d", spec.SwapLimit)
+	}
+
+	// Missing files leave the limits unchanged.
+	spec = info.MemorySpec{Limit: 42}
+	GetMemorySpec(path.Join(dir, "missing"), &spec)
+	if spec != (info.MemorySpec{Limit: 42}) {
+		t.Errorf("expected the memory spec to be unchanged, got %+v", spec)
+	}
+}
+
+func TestToContainerStatsMemory(t *testing.T) {
+	stats := &libcontainer.ContainerStats{
+		CgroupStats: cgroups.NewStats(),
+	}
+	stats.CgroupStats.MemoryStats.Usage = 1000
+	stats.CgroupStats.MemoryStats.Failcnt = 7
+	stats.CgroupStats.MemoryStats.Stats = map[string]uint64{
+		"cache":               100,
+		"total_cache":         400,
+		"total_rss":           500,
+		"total_swap":          50,
+		"total_inactive_anon": 100,
+		"total_active_file":   200,
+	}
+
+	memory := toContainerStats(stats).Memory
+	expected := info.MemoryStats{Usage: 1000, WorkingSet: 700, Cache: 400, RSS: 500, Swap: 50, Failcnt: 7}
+	if !reflect.DeepEqual(memory, expected) {
+		t.Errorf("expected memory stats %+v, got %+v", expected, memory)
+	}
+}
+
+func TestGetOomKillCount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Kernels older than 4.13 report no OOM kills.
+	oomControl := path.Join(dir, "memory.oom_control")
+	if err := ioutil.WriteFile(oomControl, []byte("oom_kill_disable 0\nunder_oom 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	count, err := GetOomKillCount(dir)
+	if err != nil || count != 0 {
+		t.Errorf("expected no OOM kills, got %d (error: %v)", count, err)
+	}
+
+	if err := ioutil.WriteFile(oomControl, []byte("oom_kill_disable 0\nunder_oom 0\noom_kill 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	count, err = GetOomKillCount(dir)
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 OOM kills, got %d (error: %v)", count, err)
+	}
+
+	// cgroup v2 reports them in memory.events.
+	if err := os.Remove(oomControl); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "memory.events"), []byte("low 0\nhigh 12\nmax 40\noom 2\noom_kill 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	count, err = GetOomKillCount(dir)
+	if err != nil || count != 1 {
+		t.Errorf("expected 1 OOM kill, got %d (error: %v)", count, err)
+	}
+
+	count, err = GetOomKillCount(path.Join(dir, "missing"))
+	if err != nil || count != 0 {
+		t.Errorf("expected no OOM kills without a memory cgroup, got %d (error: %v)", count, err)
+	}
+}
+
+func TestGetPidsStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stats, err := GetPidsStats(dir)
+	if err != nil || stats != nil {
+		t.Errorf("expected no stats without a pids cgroup, got %+v (error: %v)", stats, err)
+	}
+
+	// The root cgroup has no pids.max.
+	if err := ioutil.WriteFile(path.Join(dir, "pids.current"), []byte("42\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = GetPidsStats(dir)
+	if err != nil || stats == nil || *stats != (info.PidsStats{Current: 42}) {
+		t.Errorf("unexpected stats %+v (error: %v)", stats, err)
+	}
+
+	if err := ioutil.WriteFile(path.Join(dir, "pids.max"), []byte("max\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = GetPidsStats(dir)
+	if err != nil || stats == nil || *stats != (info.PidsStats{Current: 42}) {
+		t.Errorf("unexpected stats of an unlimited cgroup %+v (error: %v)", stats, err)
+	}
+
+	if err := ioutil.WriteFile(path.Join(dir, "pids.max"), []byte("100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = GetPidsStats(dir)
+	if err != nil || stats == nil || *stats != (info.PidsStats{Current: 42, Limit: 100}) {
+		t.Errorf("unexpected stats of a limited cgroup %+v (error: %v)", stats, err)
+	}
+}
+
+func TestToContainerStatsThrottling(t *testing.T) {
+	stats := &libcontainer.ContainerStats{
+		CgroupStats: cgroups.NewStats(),
+	}
+	stats.CgroupStats.CpuStats.ThrottlingData = cgroups.ThrottlingData{
+		Periods:          500,
+		ThrottledPeriods: 20,
+		ThrottledTime:    1500000000,
+	}
+
+	cpu := toContainerStats(stats).Cpu
+	if cpu.NrPeriods != 500 || cpu.NrThrottled != 20 || cpu.ThrottledTime != 1500000000 {
+		t.Errorf("unexpected throttling stats %+v", cpu)
+	}
+}
+
+func TestGetSocketStatsIgnored(t *testing.T) {
+	missing := "/nonexistent/proc/net"
+	stats := &info.NetworkStats{}
+	if err := GetSocketStats(stats, missing, container.MetricSet{}); err == nil {
+		t.Errorf("expected an error reading sockets from %q", missing)
+	}
+
+	// Nothing is read when both socket metrics are disabled.
+	ignoreMetrics := container.MetricSet{container.NetworkTcpUsageMetrics: {}, container.NetworkUdpUsageMetrics: {}}
+	if err := GetSocketStats(stats, missing, ignoreMetrics); err != nil {
+		t.Errorf("expected no error with socket metrics disabled, got %v", err)
+	}
+}
+
+func TestGetInterfaceStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc-net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(path.Join(dir, "dev"), []byte(`Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0:    2000      20    0    0    0     0          0         0     3000      30    0    0    0     0       0          0
+  net1:    5000      50    0    0    0     0          0         0     6000      60    0    0    0     0       0          0
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &info.NetworkStats{RxBytes: 1}
+	if err := GetInterfaceStats(stats, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Interfaces) != 2 || stats.Interfaces[1].Name != "net1" {
+		t.Errorf("expected the stats of eth0 and net1, got %+v", stats.Interfaces)
+	}
+	if stats.RxBytes != 1 {
+		t.Errorf("expected the totals to be kept, got %d bytes received", stats.RxBytes)
+	}
+
+	// The loopback interface is not part of the totals.
+	stats = &info.NetworkStats{}
+	if err := GetInterfaceStats(stats, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if stats.RxBytes != 7000 || stats.TxPackets != 90 {
+		t.Errorf("expected the totals of eth0 and net1, got %+v", stats)
+	}
+}
+
+func TestGetHugeTlbStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hugetlb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles := func(files map[string]string) {
+		for name, content := range files {
+			if err := ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	stats, err := GetHugeTlbStats(dir)
+	if err != nil || stats != nil {
+		t.Errorf("expected no stats without hugetlb files, got %+v, %v", stats, err)
+	}
+	stats, err = GetHugeTlbStats(path.Join(dir, "missing"))
+	if err != nil || stats != nil {
+		t.Errorf("expected no stats without hugetlb cgroup, got %+v, %v", stats, err)
+	}
+
+	// cgroup v1.
+	writeFiles(map[string]string{
+		"hugetlb.2MB.usage_in_bytes":     "4194304\n",
+		"hugetlb.2MB.max_usage_in_bytes": "8388608\n",
+		"hugetlb.2MB.failcnt":            "3\n",
+		"hugetlb.2MB.limit_in_bytes":     "8388608\n",
+		"hugetlb.1GB.usage_in_bytes":     "0\n",
+		"hugetlb.1GB.max_usage_in_bytes": "0\n",
+		"hugetlb.1GB.failcnt":            "0\n",
+	})
+	stats, err = GetHugeTlbStats(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]info.HugeTlbStats{
+		"2MB": {Usage: 4194304, MaxUsage: 8388608, Failcnt: 3},
+		"1GB": {},
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+
+	// cgroup v2, without the reservations.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(map[string]string{
+		"hugetlb.2MB.current":      "2097152\n",
+		"hugetlb.2MB.max":          "max\n",
+		"hugetlb.2MB.events":       "max 5\n",
+		"hugetlb.2MB.rsvd.current": "4194304\n",
+	})
+	stats, err = GetHugeTlbStats(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = map[string]info.HugeTlbStats{"2MB": {Usage: 2097152, Failcnt: 5}}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+
+	writeFiles(map[string]string{"hugetlb.2MB.current": "lots\n"})
+	if _, err := GetHugeTlbStats(dir); err == nil {
+		t.Error("expected an error reading a malformed usage")
+	}
+}
+
+func TestGetSwapStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// cgroup v1 has no memory.swap.current, the swap usage comes from memory.stat.
+	stats := info.MemoryStats{Swap: 4096}
+	if err := GetSwapStats(dir, &stats); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (info.MemoryStats{Swap: 4096}); !reflect.DeepEqual(stats, expected) {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+
+	if err := ioutil.WriteFile(path.Join(dir, "memory.swap.current"), []byte("8192\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "memory.stat"), []byte("anon 4096\npgfault 12\npswpin 3\npswpout 5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GetSwapStats(dir, &stats); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (info.MemoryStats{Swap: 8192, SwapIn: 3, SwapOut: 5}); !reflect.DeepEqual(stats, expected) {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+
+	if err := ioutil.WriteFile(path.Join(dir, "memory.stat"), []byte("pswpin x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GetSwapStats(dir, &stats); err == nil {
+		t.Error("expected an error for a malformed pswpin count")
+	}
+}
+
+func TestGetCgroupConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"cpu/cpu.cfs_quota_us":         "50000\n",
+		"cpu/cpu.cfs_period_us":        "100000\n",
+		"memory/memory.limit_in_bytes": "1073741824\n",
+		"memory/memory.oom_control":    "oom_kill_disable 0\nunder_oom 0\n",
+		// Not a setting.
+		"memory/memory.usage_in_bytes": "4096\n",
+		"pids/pids.max":                "max\n",
+	}
+	for file, content := range files {
+		if err := os.MkdirAll(path.Join(dir, path.Dir(file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The blkio subsystem is not mounted.
+	cgroupPath := func(subsystem string) (string, error) {
+		if subsystem == "blkio" {
+			return "", fmt.Errorf("could not find path for resource %q", subsystem)
+		}
+		return path.Join(dir, subsystem), nil
+	}
+	config, err := GetCgroupConfig(cgroupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"cpu.cfs_quota_us":      "50000",
+		"cpu.cfs_period_us":     "100000",
+		"memory.limit_in_bytes": "1073741824",
+		"memory.oom_control":    "oom_kill_disable 0\nunder_oom 0",
+		"pids.max":              "max",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %v, got %v", expected, config)
+	}
+}
+
+func TestGetCpuMaxLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(file, content string) {
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if limit := GetCpuMaxLimit(dir); limit != 0 {
+		t.Errorf("expected no limit without cpu cgroup files, got %d", limit)
+	}
+
+	write("cpu.cfs_period_us", "100000\n")
+	write("cpu.cfs_quota_us", "-1\n")
+	if limit := GetCpuMaxLimit(dir); limit != 0 {
+		t.Errorf("expected no limit without quota, got %d", limit)
+	}
+	write("cpu.cfs_quota_us", "150000\n")
+	if limit := GetCpuMaxLimit(dir); limit != 1500 {
+		t.Errorf("expected a limit of 1500 milli-cpus, got %d", limit)
+	}
+
+	// cgroup v2.
+	write("cpu.max", "max 100000\n")
+	if limit := GetCpuMaxLimit(dir); limit != 0 {
+		t.Errorf("expected no limit without quota, got %d", limit)
+	}
+	write("cpu.max", "25000 50000\n")
+	if limit := GetCpuMaxLimit(dir); limit != 500 {
+		t.Errorf("expected a limit of 500 milli-cpus, got %d", limit)
+	}
+}
+
+func TestGetCpusetSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpuset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(file, content string) {
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := info.CpuSpec{CpuSet: "0-7"}
+	GetCpusetSpec(dir, &spec)
+	if spec.CpuSet != "0-7" || spec.Mems != "" {
+		t.Errorf("expected the spec to be unchanged without cpuset files, got %+v", spec)
+	}
+
+	// cgroup v1.
+	write("cpuset.cpus", "0-3\n")
+	write("cpuset.mems", "0\n")
+	GetCpusetSpec(dir, &spec)
+	if spec.CpuSet != "0-3" || spec.Mems != "0" {
+		t.Errorf("expected the configured cpuset, got %+v", spec)
+	}
+	write("cpuset.effective_cpus", "2-3\n")
+	write("cpuset.effective_mems", "0\n")
+	GetCpusetSpec(dir, &spec)
+	if spec.CpuSet != "2-3" || spec.Mems != "0" {
+		t.Errorf("expected the effective cpuset, got %+v", spec)
+	}
+
+	// cgroup v2, where the configured sets are empty when inherited.
+	write("cpuset.cpus", "\n")
+	write("cpuset.mems", "\n")
+	write("cpuset.cpus.effective", "0-1,4\n")
+	write("cpuset.mems.effective", "0-1\n")
+	GetCpusetSpec(dir, &spec)
+	if spec.CpuSet != "0-1,4" || spec.Mems != "0-1" {
+		t.Errorf("expected the effective cpuset, got %+v", spec)
+	}
+}
+
+func TestGetCgroupControllers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// cgroup v1.
+	cgroupPaths := map[string]string{"cpu": path.Join(dir, "cpu"), "memory": path.Join(dir, "memory")}
+	if controllers := GetCgroupControllers(cgroupPaths); controllers != nil {
+		t.Errorf("expected no controllers on cgroup v1, got %v", controllers)
+	}
+
+	// All the subsystems share the cgroup of the unified hierarchy.
+	unified := path.Join(dir, "unified")
+	if err := os.Mkdir(unified, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(unified, "cgroup.controllers"), []byte("memory pids cpu\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cgroupPaths = map[string]string{"cpu": unified, "memory": unified, "blkio": unified}
+	expected := []string{"cpu", "memory", "pids"}
+	if controllers := GetCgroupControllers(cgroupPaths); !reflect.DeepEqual(controllers, expected) {
+		t.Errorf("expected controllers %v, got %v", expected, controllers)
+	}
+}
@@ -60,6 +60,11 @@ func (self *MockContainerHandler) GetStats() (*info.ContainerStats, error) {
 	return args.Get(0).(*info.ContainerStats), args.Error(1)
 }
 
+func (self *MockContainerHandler) GetPartialStats(skip MetricSet) (*info.ContainerStats, error) {
+	args := self.Called(skip)
+	return args.Get(0).(*info.ContainerStats), args.Error(1)
+}
+
 func (self *MockContainerHandler) ListContainers(listType ListType) ([]info.ContainerReference, error) {
 	args := self.Called(listType)
 	return args.Get(0).([]info.ContainerReference), args.Error(1)
@@ -90,6 +95,11 @@ func (self *MockContainerHandler) Exists() bool {
 	return args.Get(0).(bool)
 }
 
+func (self *MockContainerHandler) GetContainerIPAddress() string {
+	args := self.Called()
+	return args.Get(0).(string)
+}
+
 func (self *MockContainerHandler) GetCgroupPath(path string) (string, error) {
 	args := self.Called(path)
 	return args.Get(0).(string), args.Error(1)
@@ -33,6 +33,9 @@ type rawFactory struct {
 
 	// Information about mounted filesystems.
 	fsInfo fs.FsInfo
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
 }
 
 func (self *rawFactory) String() string {
@@ -40,7 +43,7 @@ func (self *rawFactory) String() string {
 }
 
 func (self *rawFactory) NewContainerHandler(name string) (container.ContainerHandler, error) {
-	return newRawContainerHandler(name, self.cgroupSubsystems, self.machineInfoFactory, self.fsInfo)
+	return newRawContainerHandler(name, self.cgroupSubsystems, self.machineInfoFactory, self.fsInfo, self.ignoreMetrics)
 }
 
 // The raw factory can handle any container.
@@ -48,7 +51,7 @@ func (self *rawFactory) CanHandle(name string) (bool, error) {
 	return true, nil
 }
 
-func Register(machineInfoFactory info.MachineInfoFactory, fsInfo fs.FsInfo) error {
+func Register(machineInfoFactory info.MachineInfoFactory, fsInfo fs.FsInfo, ignoreMetrics container.MetricSet) error {
 	cgroupSubsystems, err := libcontainer.GetCgroupSubsystems()
 	if err != nil {
 		return fmt.Errorf("failed to get cgroup subsystems: %v", err)
@@ -62,6 +65,7 @@ func Register(machineInfoFactory info.MachineInfoFactory, fsInfo fs.FsInfo) erro
 		machineInfoFactory: machineInfoFactory,
 		fsInfo:             fsInfo,
 		cgroupSubsystems:   &cgroupSubsystems,
+		ignoreMetrics:      ignoreMetrics,
 	}
 	container.RegisterContainerHandlerFactory(factory)
 	return nil
@@ -18,11 +18,9 @@ package raw
 import (
 	"fmt"
 	"io/ioutil"
-	"os"
 	"path"
 	"strconv"
 	"strings"
-	"time"
 
 	"code.google.com/p/go.exp/inotify"
 	dockerlibcontainer "github.com/docker/libcontainer"
@@ -69,9 +67,12 @@ type rawContainerHandler struct {
 
 	fsInfo         fs.FsInfo
 	externalMounts []mount
+
+	// Metrics which are not collected.
+	ignoreMetrics container.MetricSet
 }
 
-func newRawContainerHandler(name string, cgroupSubsystems *libcontainer.CgroupSubsystems, machineInfoFactory info.MachineInfoFactory, fsInfo fs.FsInfo) (container.ContainerHandler, error) {
+func newRawContainerHandler(name string, cgroupSubsystems *libcontainer.CgroupSubsystems, machineInfoFactory info.MachineInfoFactory, fsInfo fs.FsInfo, ignoreMetrics container.MetricSet) (container.ContainerHandler, error) {
 	// Create the cgroup paths.
 	cgroupPaths := make(map[string]string, len(cgroupSubsystems.MountPoints))
 	for key, val := range cgroupSubsystems.MountPoints {
@@ -118,6 +119,7 @@ func newRawContainerHandler(name string, cgroupSubsystems *libcontainer.CgroupSu
 		fsInfo:             fsInfo,
 		hasNetwork:         hasNetwork,
 		externalMounts:     externalMounts,
+		ignoreMetrics:      ignoreMetrics,
 	}, nil
 }
 
@@ -178,18 +180,7 @@ func (self *rawContainerHandler) GetSpec() (info.ContainerSpec, error) {
 	// The raw driver assumes unified hierarchy containers.
 
 	// Get the lowest creation time from all hierarchies as the container creation time.
-	now := time.Now()
-	lowestTime := now
-	for _, cgroupPath := range self.cgroupPaths {
-		// The modified time of the cgroup directory is when the container was created.
-		fi, err := os.Stat(cgroupPath)
-		if err == nil && fi.ModTime().Before(lowestTime) {
-			lowestTime = fi.ModTime()
-		}
-	}
-	if lowestTime != now {
-		spec.CreationTime = lowestTime
-	}
+	spec.CreationTime = libcontainer.GetCgroupCreationTime(self.cgroupPaths)
 
 	// Get machine info.
 	mi, err := self.machineInfoFactory.GetMachineInfo()
@@ -203,6 +194,7 @@ func (self *rawContainerHandler) GetSpec() (info.ContainerSpec, error) {
 		if utils.FileExists(cpuRoot) {
 			spec.HasCpu = true
 			spec.Cpu.Limit = readInt64(cpuRoot, "cpu.shares")
+			spec.Cpu.MaxLimit = libcontainer.GetCpuMaxLimit(cpuRoot)
 		}
 	}
 
@@ -214,6 +206,7 @@ func (self *rawContainerHandler) GetSpec() (info.ContainerSpec, error) {
 			spec.HasCpu = true
 			mask := readString(cpusetRoot, "cpuset.cpus")
 			spec.Cpu.Mask = utils.FixCpuMask(mask, mi.NumCores)
+			libcontainer.GetCpusetSpec(cpusetRoot, &spec.Cpu)
 		}
 	}
 
@@ -222,21 +215,20 @@ func (self *rawContainerHandler) GetSpec() (info.ContainerSpec, error) {
 	if ok {
 		if utils.FileExists(memoryRoot) {
 			spec.HasMemory = true
-			spec.Memory.Limit = readInt64(memoryRoot, "memory.limit_in_bytes")
-			spec.Memory.SwapLimit = readInt64(memoryRoot, "memory.memsw.limit_in_bytes")
+			libcontainer.GetMemorySpec(memoryRoot, &spec.Memory)
 		}
 	}
 
 	// Fs.
-	if self.name == "/" || self.externalMounts != nil {
+	if (self.name == "/" || self.externalMounts != nil) && !self.ignoreMetrics.Has(container.DiskUsageMetrics) {
 		spec.HasFilesystem = true
 	}
 
 	//Network
-	spec.HasNetwork = self.hasNetwork
+	spec.HasNetwork = self.hasNetwork && !self.ignoreMetrics.HasAll(container.NetworkMetrics)
 
 	// DiskIo.
-	if blkioRoot, ok := self.cgroupPaths["blkio"]; ok && utils.FileExists(blkioRoot) {
+	if blkioRoot, ok := self.cgroupPaths["blkio"]; ok && utils.FileExists(blkioRoot) && !self.ignoreMetrics.Has(container.DiskIoMetrics) {
 		spec.HasDiskIo = true
 	}
 
@@ -245,13 +237,21 @@ func (self *rawContainerHandler) GetSpec() (info.ContainerSpec, error) {
 	if err != nil {
 		return spec, err
 	}
-	if len(nd) != 0 {
+	// Only the usage of the interfaces is collected for the root container.
+	if len(nd) != 0 && !self.ignoreMetrics.Has(container.NetworkUsageMetrics) {
 		spec.HasNetwork = true
 	}
+	spec.CgroupVersion = self.cgroupSubsystems.Version
+	spec.Controllers = libcontainer.GetCgroupControllers(self.cgroupPaths)
+
 	return spec, nil
 }
 
 func (self *rawContainerHandler) getFsStats(stats *info.ContainerStats) error {
+	if self.ignoreMetrics.Has(container.DiskUsageMetrics) {
+		return nil
+	}
+
 	// Get Filesystem information only for the root cgroup.
 	if self.name == "/" {
 		filesystems, err := self.fsInfo.GetGlobalFsInfo()
@@ -311,30 +311,44 @@ func (self *rawContainerHandler) getFsStats(stats *info.ContainerStats) error {
 }
 
 func (self *rawContainerHandler) GetStats() (*info.ContainerStats, error) {
-	stats, err := libcontainer.GetStats(self.cgroupPaths, &self.libcontainerState)
-	if err != nil {
-		return stats, err
-	}
+	return self.GetPartialStats(nil)
+}
 
-	err = self.getFsStats(stats)
-	if err != nil {
-		return stats, err
+func (self *rawContainerHandler) GetPartialStats(skip container.MetricSet) (*info.ContainerStats, error) {
+	ignoreMetrics := self.ignoreMetrics.Union(skip)
+	statsErr := &container.StatsError{}
+	stats, err := libcontainer.GetStats(self.cgroupPaths, &self.libcontainerState, ignoreMetrics)
+	statsErr.Add("", err)
+	statsErr.Add(container.SubsystemFilesystem, self.getFsStats(stats))
+
+	// The root cgroup has no pressure files, the system-wide ones apply to it.
+	if self.name == "/" && stats.PSI == nil {
+		stats.PSI, err = libcontainer.GetPSIStats("/proc/pressure/cpu", "/proc/pressure/memory", "/proc/pressure/io")
+		statsErr.Add(container.SubsystemPsi, err)
 	}
 
 	// Fill in network stats for root.
 	nd, err := self.GetRootNetworkDevices()
 	if err != nil {
-		return stats, err
+		statsErr.Add(container.SubsystemNetwork, err)
+		return stats, statsErr.OrNil()
 	}
 	if len(nd) != 0 {
 		// ContainerStats only reports stat for one network device.
 		// TODO(rjnagal): Handle multiple physical network devices.
-		stats.Network, err = sysinfo.GetNetworkStats(nd[0].Name)
-		if err != nil {
-			return stats, err
+		if !ignoreMetrics.Has(container.NetworkUsageMetrics) {
+			stats.Network, err = sysinfo.GetNetworkStats(nd[0].Name)
+			if err != nil {
+				statsErr.Add(container.SubsystemNetwork, err)
+				return stats, statsErr.OrNil()
+			}
+			err = libcontainer.GetInterfaceStats(&stats.Network, "/proc/net", false)
+			statsErr.Add(container.SubsystemNetwork, err)
 		}
+		err = libcontainer.GetSocketStats(&stats.Network, "/proc/net", ignoreMetrics)
+		statsErr.Add(container.SubsystemNetwork, err)
 	}
-	return stats, nil
+	return stats, statsErr.OrNil()
 }
 
 func (self *rawContainerHandler) GetCgroupPath(resource string) (string, error) {
@@ -345,6 +359,11 @@ func (self *rawContainerHandler) GetCgroupPath(resource string) (string, error)
 	return path, nil
 }
 
+func (self *rawContainerHandler) GetContainerIPAddress() string {
+	// Raw containers use the network of the host.
+	return "127.0.0.1"
+}
+
 // Lists all directories under "path" and outputs the results as children of "parent".
 func listDirectories(dirpath string, parent string, recursive bool, output map[string]struct{}) error {
 	// Ignore if this hierarchy does not exist.
@@ -16,6 +16,9 @@ package events
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -38,6 +41,16 @@ type EventManager interface {
 	AddEvent(e *Event) error
 	// Removes a watch instance from the EventManager's watchers map
 	StopWatch(watch_id int)
+	// Removes the stored events for which matches returns true and returns
+	// how many were removed
+	RemoveEvents(matches func(*Event) bool) (int, error)
+	// Returns the epoch of the sequence numbers of the events, which changes
+	// whenever they start again from 1, e.g. when cAdvisor restarts without
+	// storing the events on disk
+	Epoch() int64
+	// Stops all the watches, closing their channels, and closes the on-disk
+	// log of events if there is one
+	Close() error
 }
 
 // Events  holds a slice of *Event objects with a potential field
@@ -52,6 +65,8 @@ type events struct {
 	// satisfy the request of a given watch object in watchers, the event
 	// is sent over the channel to that caller of WatchEvents
 	watchers map[int]*watch
+	// watchers indexed by the container they watch, guarded by watcherLock
+	watchIndex *watchIndex
 	// lock that blocks eventlist from being accessed until a writer releases it
 	eventsLock sync.RWMutex
 	// lock that blocks watchers from being accessed until a writer releases it
@@ -59,6 +74,13 @@ type events struct {
 	// receives notices when a watch event ends and needs to be removed from
 	// the watchers list
 	lastId int
+	// if set, events are also stored on disk so they survive restarts
+	log *eventLog
+	// sequence number of the last added event, guarded by eventsLock
+	lastSequence uint64
+	// epoch of the sequence numbers, the time they started from 1 in
+	// nanoseconds since the Unix epoch
+	epoch int64
 }
 
 // initialized by a call to WatchEvents(), a watch struct will then be added
@@ -95,6 +117,9 @@ type Event struct {
 	// the original event object and all of its extraneous data, ex. an
 	// OomInstance
 	EventData EventDataInterface
+	// sequence number assigned by the EventManager when the event is added,
+	// increasing with each added event. Starts at 1
+	Sequence uint64 `json:",omitempty"`
 }
 
 // Request holds a set of parameters by which Event objects may be screened.
@@ -120,6 +145,25 @@ type Request struct {
 	// if IncludeSubcontainers is false, only events occurring in the specific
 	// container, and not the subcontainers, will be returned
 	IncludeSubcontainers bool
+	// if set, only events whose absolute container name matches the
+	// regular expression satisfy the request
+	ContainerRegexp *regexp.Regexp
+	// number of events to skip from the start of the chronologically ordered
+	// result, or ordered by Sequence if Since is set, applied after
+	// MaxEventsReturned. Events with identical timestamps keep the order in
+	// which they were added
+	Offset int
+	// maximum number of events returned after skipping Offset events. If
+	// Limit is <= 0 all remaining events are returned
+	Limit int
+	// events with a Sequence lower than or equal to Since do not satisfy
+	// the request, so that clients can poll for the events added after the
+	// last one they received. The events are then ordered by Sequence, and
+	// MaxEventsReturned keeps the first ones so that none are skipped
+	Since uint64
+	// epoch of the EventManager Since was read from. Since is ignored if it
+	// differs from the current epoch, as the sequence numbers started again
+	SinceEpoch int64
 }
 
 // EventType is an enumerated type which lists the categories under which
@@ -130,6 +174,9 @@ const (
 	TypeOom EventType = iota
 	TypeContainerCreation
 	TypeContainerDeletion
+	// A container was started too often, see RestartLoopDetector. The
+	// EventData is a RestartLoop
+	TypeRestartLoop
 )
 
 // a general interface which populates the Event field EventData. The actual
@@ -152,11 +199,53 @@ func NewEventChannel(watchId int) *EventChannel {
 // returns a pointer to an initialized Events object
 func NewEventManager() *events {
 	return &events{
-		eventlist: make(EventSlice, 0),
-		watchers:  make(map[int]*watch),
+		eventlist:  make(EventSlice, 0),
+		watchers:   make(map[int]*watch),
+		watchIndex: newWatchIndex(),
+		epoch:      time.Now().UnixNano(),
 	}
 }
 
+// returns a pointer to an initialized Events object that also stores events
+// in dir, using up to maxBytes of disk. The events stored within retention
+// are loaded back
+func NewPersistentEventManager(dir string, maxBytes int64, retention time.Duration) (*events, error) {
+	log, err := newEventLog(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := log.load(time.Now().Add(-retention))
+	if err != nil {
+		return nil, err
+	}
+	glog.Infof("Loaded %d events from %q", len(loaded), dir)
+	self := NewEventManager()
+	self.eventlist = loaded
+	self.log = log
+	// Events stored before sequence numbers were assigned are numbered after
+	// the ones preceding them.
+	for _, e := range loaded {
+		if e.Sequence <= self.lastSequence {
+			e.Sequence = self.lastSequence + 1
+		}
+		self.lastSequence = e.Sequence
+	}
+	// The sequence numbers go on from the loaded events, in the same epoch.
+	epoch, err := log.loadEpoch()
+	if err == nil && len(loaded) > 0 {
+		self.epoch = epoch
+		return self, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		glog.Warningf("Failed to read the epoch of the events in %q, starting a new one: %v", dir, err)
+	}
+	err = log.saveEpoch(self.epoch)
+	if err != nil {
+		return nil, err
+	}
+	return self, nil
+}
+
 // returns a pointer to an initialized Request object
 func NewRequest() *Request {
 	return &Request{
@@ -196,16 +285,49 @@ func (e EventSlice) Less(i, j int) bool {
 	return e[i].Timestamp.Before(e[j].Timestamp)
 }
 
-// sorts and returns up to the last MaxEventsReturned chronological elements
+// Events sorted by sequence number.
+type eventsBySequence EventSlice
+
+func (e eventsBySequence) Len() int           { return len(e) }
+func (e eventsBySequence) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e eventsBySequence) Less(i, j int) bool { return e[i].Sequence < e[j].Sequence }
+
+// sorts and returns up to the last MaxEventsReturned chronological elements.
+// The sort is stable so events with identical timestamps stay in the order
+// in which they were added. When polling with Since, the events are sorted by
+// sequence number and the first MaxEventsReturned are returned instead, so
+// that the following ones are returned by the next poll
 func getMaxEventsReturned(request *Request, eSlice EventSlice) EventSlice {
-	sort.Sort(eSlice)
 	n := request.MaxEventsReturned
+	if request.Since > 0 {
+		sort.Sort(eventsBySequence(eSlice))
+		if n >= eSlice.Len() || n <= 0 {
+			return eSlice
+		}
+		return eSlice[:n]
+	}
+	sort.Stable(eSlice)
 	if n >= eSlice.Len() || n <= 0 {
 		return eSlice
 	}
 	return eSlice[eSlice.Len()-n:]
 }
 
+// returns the page of the sorted events selected by the Offset and Limit of
+// the request
+func getRequestedPage(request *Request, eSlice EventSlice) EventSlice {
+	if request.Offset > 0 {
+		if request.Offset >= eSlice.Len() {
+			return EventSlice{}
+		}
+		eSlice = eSlice[request.Offset:]
+	}
+	if request.Limit > 0 && request.Limit < eSlice.Len() {
+		eSlice = eSlice[:request.Limit]
+	}
+	return eSlice
+}
+
 // If the request wants all subcontainers, this returns if the request's
 // container path is a prefix of the event container path.  Otherwise,
 // it checks that the container paths of the event and request are
@@ -235,18 +357,39 @@ func checkIfEventSatisfiesRequest(request *Request, event *Event) bool {
 	if request.EventType[event.EventType] != true {
 		return false
 	}
+	if request.Since > 0 && event.Sequence <= request.Since {
+		return false
+	}
+	if request.ContainerRegexp != nil && !request.ContainerRegexp.MatchString(event.ContainerName) {
+		return false
+	}
 	if request.ContainerName != "" {
 		return checkIfIsSubcontainer(request, event)
 	}
 	return true
 }
 
+// Returns whether the event is of a requested type and container and occurred
+// within the requested time frame. MaxEventsReturned, Offset and Limit do not
+// apply to single events.
+func (self *Request) Matches(e *Event) bool {
+	return checkIfEventSatisfiesRequest(self, e)
+}
+
+// Returns the page of the events, as returned for a request without Offset
+// and Limit, selected by the Offset and Limit of the request.
+func (self *Request) Page(events EventSlice) EventSlice {
+	return getRequestedPage(self, events)
+}
+
 // method of Events object that screens Event objects found in the eventlist
 // attribute and if they fit the parameters passed by the Request object,
 // adds it to a slice of *Event objects that is returned. If both MaxEventsReturned
 // and StartTime/EndTime are specified in the request object, then only
 // up to the most recent MaxEventsReturned events in that time range are returned.
+// Offset and Limit then select a page of those events.
 func (self *events) GetEvents(request *Request) (EventSlice, error) {
+	request = self.inCurrentEpoch(request)
 	returnEventList := EventSlice{}
 	self.eventsLock.RLock()
 	defer self.eventsLock.RUnlock()
@@ -256,6 +399,7 @@ func (self *events) GetEvents(request *Request) (EventSlice, error) {
 		}
 	}
 	returnEventList = getMaxEventsReturned(request, returnEventList)
+	returnEventList = getRequestedPage(request, returnEventList)
 	return returnEventList, nil
 }
 
@@ -269,26 +413,57 @@ func (self *events) WatchEvents(request *Request) (*EventChannel, error) {
 		return nil, errors.New(
 			"for a call to watch, request.StartTime and request.EndTime must be uninitialized")
 	}
+	request = self.inCurrentEpoch(request)
 	self.watcherLock.Lock()
 	defer self.watcherLock.Unlock()
 	new_id := self.lastId + 1
 	returnEventChannel := NewEventChannel(new_id)
 	newWatcher := newWatch(request, returnEventChannel)
+	newWatcher.id = new_id
 	self.watchers[new_id] = newWatcher
+	self.watchIndex.add(newWatcher)
 	self.lastId = new_id
 	return returnEventChannel, nil
 }
 
-// helper function to update the event manager's eventlist
+func (self *events) Epoch() int64 {
+	return self.epoch
+}
+
+// Returns the request without its Since if it is from another epoch.
+func (self *events) inCurrentEpoch(request *Request) *Request {
+	if request.Since == 0 || request.SinceEpoch == self.epoch {
+		return request
+	}
+	current := *request
+	current.Since = 0
+	current.SinceEpoch = self.epoch
+	return &current
+}
+
+// helper function to update the event manager's eventlist. Assigns the
+// sequence number of the event and stores it in the on-disk log if there is
+// one, under the same lock as RemoveEvents() so that it is not appended to
+// the log after a rewrite that removed it
 func (self *events) updateEventList(e *Event) {
 	self.eventsLock.Lock()
 	defer self.eventsLock.Unlock()
+	self.lastSequence++
+	e.Sequence = self.lastSequence
 	self.eventlist = append(self.eventlist, e)
+	if self.log != nil {
+		err := self.log.append(e)
+		if err != nil {
+			glog.Errorf("Failed to store event %+v: %v", e, err)
+		}
+	}
 }
 
+// Returns the watches the event satisfies the request of. Only the watches of
+// the container of the event and of its parents are checked.
 func (self *events) findValidWatchers(e *Event) []*watch {
 	watchesToSend := make([]*watch, 0)
-	for _, watcher := range self.watchers {
+	for _, watcher := range self.watchIndex.candidates(e.ContainerName) {
 		watchRequest := watcher.request
 		if checkIfEventSatisfiesRequest(watchRequest, e) {
 			watchesToSend = append(watchesToSend, watcher)
@@ -315,10 +490,52 @@ func (self *events) AddEvent(e *Event) error {
 func (self *events) StopWatch(watchId int) {
 	self.watcherLock.Lock()
 	defer self.watcherLock.Unlock()
-	_, ok := self.watchers[watchId]
+	watcher, ok := self.watchers[watchId]
 	if !ok {
+		// Already stopped, e.g. by Close().
 		glog.Errorf("Could not find watcher instance %v", watchId)
+		return
 	}
-	close(self.watchers[watchId].eventChannel.GetChannel())
+	close(watcher.eventChannel.GetChannel())
 	delete(self.watchers, watchId)
+	self.watchIndex.remove(watcher)
+}
+
+func (self *events) Close() error {
+	self.watcherLock.Lock()
+	for watchId, watcher := range self.watchers {
+		close(watcher.eventChannel.GetChannel())
+		delete(self.watchers, watchId)
+		self.watchIndex.remove(watcher)
+	}
+	self.watcherLock.Unlock()
+	if self.log == nil {
+		return nil
+	}
+	return self.log.close()
+}
+
+// Removes the events for which matches returns true from the eventlist, and
+// from the on-disk log if there is one, so that they are not reloaded on restart.
+func (self *events) RemoveEvents(matches func(*Event) bool) (int, error) {
+	self.eventsLock.Lock()
+	defer self.eventsLock.Unlock()
+	kept := make(EventSlice, 0, len(self.eventlist))
+	for _, e := range self.eventlist {
+		if !matches(e) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(self.eventlist) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	self.eventlist = kept
+	if self.log != nil {
+		err := self.log.rewrite(kept)
+		if err != nil {
+			return removed, fmt.Errorf("removed %d events from memory but failed to remove them from disk: %v", removed, err)
+		}
+	}
+	return removed, nil
 }
@@ -15,6 +15,7 @@
 package events
 
 import (
+	"regexp"
 	"testing"
 	"time"
 
@@ -182,3 +183,117 @@ func TestGetEventsForNoTypeRequested(t *testing.T) {
 	assert.Nil(t, err)
 	checkNumberOfEvents(t, 0, receivedEvents.Len())
 }
+
+func TestGetEventsForContainerRegexp(t *testing.T) {
+	myEventHolder, myRequest, fakeEvent, _ := initializeScenario(t)
+	myRequest.EventType[TypeOom] = true
+	myRequest.ContainerRegexp = regexp.MustCompile("^/docker/")
+	dockerEvent := makeEvent(time.Now(), "/docker/abc")
+
+	myEventHolder.AddEvent(fakeEvent)
+	myEventHolder.AddEvent(dockerEvent)
+
+	receivedEvents, err := myEventHolder.GetEvents(myRequest)
+	assert.Nil(t, err)
+	checkNumberOfEvents(t, 1, receivedEvents.Len())
+	ensureProperEventReturned(t, dockerEvent, receivedEvents[0])
+}
+
+func TestGetEventsPagination(t *testing.T) {
+	myEventHolder, myRequest, _, _ := initializeScenario(t)
+	myRequest.EventType[TypeOom] = true
+
+	// Events sharing a timestamp must keep the order in which they were added.
+	now := time.Now()
+	addedEvents := EventSlice{
+		makeEvent(now.Add(time.Second), "/a"),
+		makeEvent(now, "/b"),
+		makeEvent(now, "/c"),
+		makeEvent(now, "/d"),
+	}
+	for _, e := range addedEvents {
+		myEventHolder.AddEvent(e)
+	}
+
+	myRequest.Offset = 1
+	myRequest.Limit = 2
+	receivedEvents, err := myEventHolder.GetEvents(myRequest)
+	assert.Nil(t, err)
+	checkNumberOfEvents(t, 2, receivedEvents.Len())
+	ensureProperEventReturned(t, addedEvents[2], receivedEvents[0])
+	ensureProperEventReturned(t, addedEvents[3], receivedEvents[1])
+
+	myRequest.Offset = 4
+	receivedEvents, err = myEventHolder.GetEvents(myRequest)
+	assert.Nil(t, err)
+	checkNumberOfEvents(t, 0, receivedEvents.Len())
+}
+
+func TestRemoveEvents(t *testing.T) {
+	myEventHolder, myRequest, oldEvent, newEvent := initializeScenario(t)
+	creation := &Event{ContainerName: "/", Timestamp: oldEvent.Timestamp, EventType: TypeContainerCreation}
+	myEventHolder.AddEvent(oldEvent)
+	myEventHolder.AddEvent(creation)
+	myEventHolder.AddEvent(newEvent)
+
+	// Only remove the OOM events before the new one.
+	myRequest.EventType[TypeOom] = true
+	myRequest.EndTime = newEvent.Timestamp.Add(-time.Second)
+	removed, err := myEventHolder.RemoveEvents(myRequest.Matches)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	myRequest.EndTime = time.Time{}
+	myRequest.EventType[TypeContainerCreation] = true
+	receivedEvents, err := myEventHolder.GetEvents(myRequest)
+	assert.Nil(t, err)
+	checkNumberOfEvents(t, 2, receivedEvents.Len())
+	ensureProperEventReturned(t, creation, receivedEvents[0])
+	ensureProperEventReturned(t, newEvent, receivedEvents[1])
+
+	removed, err = myEventHolder.RemoveEvents(myRequest.Matches)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, removed)
+}
+
+func TestGetEventsSince(t *testing.T) {
+	manager := NewEventManager()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, manager.AddEvent(makeEvent(now.Add(time.Duration(i)*time.Second), "/a")))
+	}
+	// Added late with an earlier timestamp.
+	assert.Nil(t, manager.AddEvent(makeEvent(now.Add(-time.Second), "/a")))
+	request := NewRequest()
+	request.EventType[TypeOom] = true
+	request.Since = 1
+	request.SinceEpoch = manager.Epoch()
+
+	returned, err := manager.GetEvents(request)
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(returned)) {
+		assert.Equal(t, uint64(2), returned[0].Sequence)
+		assert.Equal(t, uint64(4), returned[2].Sequence)
+	}
+
+	// The first events after Since are kept, by sequence number.
+	request.MaxEventsReturned = 2
+	returned, err = manager.GetEvents(request)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(returned)) {
+		assert.Equal(t, uint64(2), returned[0].Sequence)
+		assert.Equal(t, uint64(3), returned[1].Sequence)
+	}
+	request.MaxEventsReturned = 0
+
+	request.Since = 4
+	returned, err = manager.GetEvents(request)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(returned))
+
+	// Since is ignored in another epoch.
+	request.SinceEpoch--
+	returned, err = manager.GetEvents(request)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(returned))
+}
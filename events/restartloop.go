@@ -0,0 +1,81 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// RestartLoop is the EventData of TypeRestartLoop events.
+type RestartLoop struct {
+	// Number of times the container was started within Window.
+	Starts int           `json:"starts"`
+	Window time.Duration `json:"window"`
+}
+
+// Detects containers started more than threshold times within window, e.g.
+// when a crashing container keeps being restarted by Docker or recreated
+// under the same name by its orchestrator.
+type RestartLoopDetector struct {
+	threshold int
+	window    time.Duration
+
+	lock sync.Mutex
+	// Start times within window, by container name.
+	starts map[string][]time.Time
+}
+
+func NewRestartLoopDetector(threshold int, window time.Duration) *RestartLoopDetector {
+	return &RestartLoopDetector{
+		threshold: threshold,
+		window:    window,
+		starts:    make(map[string][]time.Time),
+	}
+}
+
+// Records that the container was seen starting at the given time, and returns
+// the restart loop it reveals, if any. Once reported, a loop is reported again
+// after threshold more starts.
+func (self *RestartLoopDetector) ObserveStart(containerName string, timestamp time.Time) *RestartLoop {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	// Drop the starts which left the window, as well as the containers left
+	// without any so that the map does not grow with every container.
+	cutoff := timestamp.Add(-self.window)
+	for name, times := range self.starts {
+		recent := times[:0]
+		for _, t := range times {
+			if t.After(cutoff) {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) == 0 {
+			delete(self.starts, name)
+		} else {
+			self.starts[name] = recent
+		}
+	}
+	times := append(self.starts[containerName], timestamp)
+	if len(times) <= self.threshold {
+		self.starts[containerName] = times
+		return nil
+	}
+	delete(self.starts, containerName)
+	return &RestartLoop{
+		Starts: len(times),
+		Window: self.window,
+	}
+}
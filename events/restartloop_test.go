@@ -0,0 +1,47 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartLoopDetector(t *testing.T) {
+	detector := NewRestartLoopDetector(2, time.Minute)
+	start := time.Unix(1000, 0)
+	observe := func(name string, offset time.Duration) *RestartLoop {
+		return detector.ObserveStart(name, start.Add(offset))
+	}
+
+	// Containers are tracked by name.
+	assert.Nil(t, observe("/docker/a", 0))
+	assert.Nil(t, observe("/docker/a", 10*time.Second))
+	assert.Nil(t, observe("/docker/b", 20*time.Second))
+	assert.Equal(t, &RestartLoop{Starts: 3, Window: time.Minute}, observe("/docker/a", 30*time.Second))
+
+	// The loop is reported again after as many starts.
+	assert.Nil(t, observe("/docker/a", 40*time.Second))
+	assert.Nil(t, observe("/docker/a", 50*time.Second))
+	assert.NotNil(t, observe("/docker/a", 55*time.Second))
+
+	// Starts out of the window are not counted.
+	assert.Nil(t, observe("/docker/a", 2*time.Minute))
+	assert.Nil(t, observe("/docker/a", 3*time.Minute))
+	assert.Nil(t, observe("/docker/a", 4*time.Minute))
+	assert.Empty(t, detector.starts["/docker/b"])
+}
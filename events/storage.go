@@ -0,0 +1,331 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	segmentPrefix = "events-"
+	segmentSuffix = ".log"
+	// File holding the epoch of the sequence numbers of the stored events.
+	epochFile = "epoch"
+
+	// Number of segments the disk usage limit is split into. The oldest
+	// segment is removed when the limit is reached.
+	numSegments = 10
+)
+
+// On-disk log of events. Events are appended as JSON lines to segment files
+// which are rotated once they reach their share of the disk usage limit.
+type eventLog struct {
+	dir string
+	// Maximum size of all the segments, in bytes.
+	maxBytes int64
+	// Segment events are currently appended to.
+	segment     *os.File
+	segmentSize int64
+	lock        sync.Mutex
+}
+
+// Event as stored on disk. The event data is kept as JSON since its type is
+// unknown when the event is read back.
+type storedEvent struct {
+	ContainerName string
+	Timestamp     time.Time
+	EventType     EventType
+	EventData     json.RawMessage
+	Sequence      uint64
+}
+
+func newEventLog(dir string, maxBytes int64) (*eventLog, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("invalid event storage size limit %d", maxBytes)
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLog{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}, nil
+}
+
+// Returns the names of the segments, oldest first.
+func (self *eventLog) segments() ([]string, error) {
+	files, err := ioutil.ReadDir(self.dir)
+	if err != nil {
+		return nil, err
+	}
+	segments := []string{}
+	for _, f := range files {
+		if !f.IsDir() && strings.HasPrefix(f.Name(), segmentPrefix) && strings.HasSuffix(f.Name(), segmentSuffix) {
+			segments = append(segments, f.Name())
+		}
+	}
+	// Segment names embed a fixed width timestamp.
+	sort.Strings(segments)
+	return segments, nil
+}
+
+// Reads back the stored events that occurred after since.
+func (self *eventLog) load(since time.Time) (EventSlice, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	segments, err := self.segments()
+	if err != nil {
+		return nil, err
+	}
+	loaded := EventSlice{}
+	// A crash while rewriting the log can leave the events both in the old
+	// and the new segments.
+	seen := make(map[uint64]bool)
+	for _, segment := range segments {
+		f, err := os.Open(path.Join(self.dir, segment))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var stored storedEvent
+			err := json.Unmarshal(scanner.Bytes(), &stored)
+			if err != nil {
+				// The last event may be truncated if cAdvisor was killed while writing it.
+				glog.Warningf("Skipping malformed event in %q: %v", segment, err)
+				continue
+			}
+			if stored.Timestamp.Before(since) {
+				continue
+			}
+			if stored.Sequence != 0 {
+				if seen[stored.Sequence] {
+					continue
+				}
+				seen[stored.Sequence] = true
+			}
+			loaded = append(loaded, &Event{
+				ContainerName: stored.ContainerName,
+				Timestamp:     stored.Timestamp,
+				EventType:     stored.EventType,
+				EventData:     stored.EventData,
+				Sequence:      stored.Sequence,
+			})
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read events from %q: %v", segment, err)
+		}
+	}
+	return loaded, nil
+}
+
+// Reads the epoch of the sequence numbers of the stored events.
+func (self *eventLog) loadEpoch() (int64, error) {
+	content, err := ioutil.ReadFile(path.Join(self.dir, epochFile))
+	if err != nil {
+		return 0, err
+	}
+	epoch, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed epoch %q: %v", content, err)
+	}
+	return epoch, nil
+}
+
+// Stores the epoch of the sequence numbers of the events.
+func (self *eventLog) saveEpoch(epoch int64) error {
+	return ioutil.WriteFile(path.Join(self.dir, epochFile), []byte(strconv.FormatInt(epoch, 10)+"\n"), 0644)
+}
+
+// Appends the event to the current segment.
+func (self *eventLog) append(e *Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.write(line)
+}
+
+// Writes an event line to the current segment. The lock must be held.
+func (self *eventLog) write(line []byte) error {
+	var err error
+	if self.segment == nil || self.segmentSize+int64(len(line)) > self.maxBytes/numSegments {
+		err = self.rotate()
+		if err != nil {
+			return err
+		}
+	}
+	n, err := self.segment.Write(line)
+	self.segmentSize += int64(n)
+	return err
+}
+
+// Closes the current segment. Events appended afterwards start a new one.
+func (self *eventLog) close() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.segment == nil {
+		return nil
+	}
+	err := self.segment.Close()
+	self.segment = nil
+	return err
+}
+
+// Returns the name of the segment started at the given time, in nanoseconds.
+func segmentName(started int64) string {
+	return fmt.Sprintf("%s%020d%s", segmentPrefix, started, segmentSuffix)
+}
+
+// Starts a new segment and removes the oldest ones to stay under the size limit.
+func (self *eventLog) rotate() error {
+	if self.segment != nil {
+		self.segment.Close()
+		self.segment = nil
+	}
+	name := segmentName(time.Now().UnixNano())
+	segment, err := os.OpenFile(path.Join(self.dir, name), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	self.segment = segment
+	self.segmentSize = 0
+
+	segments, err := self.segments()
+	if err != nil {
+		return err
+	}
+	// Keep the new segment along with as many of the most recent ones as fit.
+	var size int64
+	for i := len(segments) - 2; i >= 0; i-- {
+		fi, err := os.Stat(path.Join(self.dir, segments[i]))
+		if err != nil {
+			continue
+		}
+		size += fi.Size()
+		if size+self.maxBytes/numSegments > self.maxBytes {
+			err = os.Remove(path.Join(self.dir, segments[i]))
+			if err != nil {
+				glog.Warningf("Failed to remove old events segment %q: %v", segments[i], err)
+			}
+		}
+	}
+	return nil
+}
+
+// Replaces the stored events by the given ones. They are written to new
+// segments, each synced under a temporary name and then renamed, before the
+// old segments are removed, so that a crash never loses the stored events.
+func (self *eventLog) rewrite(remaining EventSlice) error {
+	// Split the events in segments as write does.
+	contents := [][]byte{}
+	var content []byte
+	for _, e := range remaining {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if len(content) > 0 && int64(len(content)+len(line)+1) > self.maxBytes/numSegments {
+			contents = append(contents, content)
+			content = nil
+		}
+		content = append(append(content, line...), '\n')
+	}
+	if len(content) > 0 {
+		contents = append(contents, content)
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.segment != nil {
+		self.segment.Close()
+		self.segment = nil
+	}
+	segments, err := self.segments()
+	if err != nil {
+		return err
+	}
+	started := time.Now().UnixNano()
+	for i, content := range contents {
+		err = writeFileSynced(path.Join(self.dir, segmentName(started+int64(i))), content)
+		if err != nil {
+			return err
+		}
+	}
+	err = syncDir(self.dir)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		err = os.Remove(path.Join(self.dir, segment))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the file under a temporary name, syncs it and renames it, so that
+// the file is either missing or complete.
+func writeFileSynced(name string, content []byte) error {
+	tmp := name + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// Syncs the directory, persisting the files renamed in it.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
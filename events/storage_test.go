@@ -0,0 +1,236 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentEventManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	manager, err := NewPersistentEventManager(dir, 1024*1024, time.Hour)
+	require.Nil(t, err)
+	old := &Event{ContainerName: "/old", Timestamp: time.Now().Add(-2 * time.Hour), EventType: TypeOom}
+	recent := &Event{ContainerName: "/recent", Timestamp: time.Now(), EventType: TypeOom, EventData: map[string]int{"pid": 42}}
+	require.Nil(t, manager.AddEvent(old))
+	require.Nil(t, manager.AddEvent(recent))
+	epoch := manager.Epoch()
+
+	// Only the events within the retention are loaded back.
+	manager, err = NewPersistentEventManager(dir, 1024*1024, time.Hour)
+	require.Nil(t, err)
+	request := NewRequest()
+	request.EventType[TypeOom] = true
+	loaded, err := manager.GetEvents(request)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(loaded))
+	assert.Equal(t, "/recent", loaded[0].ContainerName)
+	assert.Equal(t, uint64(2), loaded[0].Sequence)
+	assert.True(t, recent.Timestamp.Equal(loaded[0].Timestamp))
+	data, err := json.Marshal(loaded[0].EventData)
+	require.Nil(t, err)
+	assert.Equal(t, `{"pid":42}`, string(data))
+
+	// Sequence numbers keep increasing after a restart, in the same epoch.
+	added := &Event{ContainerName: "/added", Timestamp: time.Now(), EventType: TypeOom}
+	require.Nil(t, manager.AddEvent(added))
+	assert.Equal(t, uint64(3), added.Sequence)
+	assert.Equal(t, epoch, manager.Epoch())
+
+	// A new epoch starts when no events are loaded back.
+	manager, err = NewPersistentEventManager(dir, 1024*1024, time.Nanosecond)
+	require.Nil(t, err)
+	assert.NotEqual(t, epoch, manager.Epoch())
+}
+
+func TestPersistentEventManagerRemoveEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	manager, err := NewPersistentEventManager(dir, 1024*1024, time.Hour)
+	require.Nil(t, err)
+	require.Nil(t, manager.AddEvent(&Event{ContainerName: "/a", Timestamp: time.Now(), EventType: TypeOom}))
+	require.Nil(t, manager.AddEvent(&Event{ContainerName: "/b", Timestamp: time.Now(), EventType: TypeOom}))
+	removed, err := manager.RemoveEvents(func(e *Event) bool { return e.ContainerName == "/a" })
+	require.Nil(t, err)
+	assert.Equal(t, 1, removed)
+	require.Nil(t, manager.AddEvent(&Event{ContainerName: "/c", Timestamp: time.Now(), EventType: TypeOom}))
+
+	// The removed events are not loaded back.
+	manager, err = NewPersistentEventManager(dir, 1024*1024, time.Hour)
+	require.Nil(t, err)
+	request := NewRequest()
+	request.EventType[TypeOom] = true
+	loaded, err := manager.GetEvents(request)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(loaded))
+	assert.Equal(t, "/b", loaded[0].ContainerName)
+	assert.Equal(t, "/c", loaded[1].ContainerName)
+}
+
+func TestPersistentEventManagerRemoveEventsWhileAdding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	manager, err := NewPersistentEventManager(dir, 1024*1024, time.Hour)
+	require.Nil(t, err)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				manager.AddEvent(&Event{ContainerName: "/a", Timestamp: time.Now(), EventType: TypeOom})
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for adding := true; adding; {
+		_, err := manager.RemoveEvents(func(e *Event) bool { return e.Sequence%2 == 0 })
+		require.Nil(t, err)
+		select {
+		case <-done:
+			adding = false
+		default:
+		}
+	}
+
+	// The log holds the same events as the memory, no removed event is
+	// appended back after the log was rewritten.
+	request := NewRequest()
+	request.EventType[TypeOom] = true
+	request.MaxEventsReturned = -1
+	inMemory, err := manager.GetEvents(request)
+	require.Nil(t, err)
+	manager, err = NewPersistentEventManager(dir, 1024*1024, time.Hour)
+	require.Nil(t, err)
+	loaded, err := manager.GetEvents(request)
+	require.Nil(t, err)
+	sequences := func(events EventSlice) []uint64 {
+		sort.Sort(eventsBySequence(events))
+		ret := make([]uint64, 0, len(events))
+		for _, e := range events {
+			ret = append(ret, e.Sequence)
+		}
+		return ret
+	}
+	assert.Equal(t, sequences(inMemory), sequences(loaded))
+}
+
+func TestEventLogMalformedLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	lines := `{"ContainerName":"/a","Timestamp":"2015-06-01T00:00:00Z","EventType":0,"EventData":null}` + "\n" + `{"ContainerName":"/b","Tim`
+	require.Nil(t, ioutil.WriteFile(path.Join(dir, segmentPrefix+"00000000000000000001"+segmentSuffix), []byte(lines), 0644))
+	log, err := newEventLog(dir, 1024)
+	require.Nil(t, err)
+	loaded, err := log.load(time.Time{})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(loaded))
+	assert.Equal(t, "/a", loaded[0].ContainerName)
+}
+
+func TestEventLogRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Segments of 200 bytes, each holding one event.
+	log, err := newEventLog(dir, 200*numSegments)
+	require.Nil(t, err)
+	for i := 0; i < 3*numSegments; i++ {
+		e := &Event{ContainerName: "/" + strings.Repeat("a", 100), Timestamp: time.Now(), EventType: TypeContainerCreation}
+		require.Nil(t, log.append(e))
+	}
+
+	segments, err := log.segments()
+	require.Nil(t, err)
+	assert.Equal(t, numSegments, len(segments))
+	var size int64
+	for _, segment := range segments {
+		fi, err := os.Stat(path.Join(dir, segment))
+		require.Nil(t, err)
+		size += fi.Size()
+	}
+	assert.True(t, size <= log.maxBytes, "%d bytes are stored, more than the limit of %d", size, log.maxBytes)
+}
+
+func TestEventLogRewrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Segments of 200 bytes, each holding one event.
+	log, err := newEventLog(dir, 200*numSegments)
+	require.Nil(t, err)
+	var events EventSlice
+	for i := 0; i < 4; i++ {
+		e := &Event{ContainerName: "/" + strings.Repeat("a", 100), Timestamp: time.Now(), EventType: TypeOom, Sequence: uint64(i + 1)}
+		require.Nil(t, log.append(e))
+		events = append(events, e)
+	}
+	old, err := log.segments()
+	require.Nil(t, err)
+	require.Equal(t, 4, len(old))
+
+	// The kept events are split in segments like appended ones, and replace
+	// the old segments.
+	require.Nil(t, log.rewrite(events[1:]))
+	segments, err := log.segments()
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(segments))
+	for _, segment := range segments {
+		assert.NotContains(t, old, segment)
+	}
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(files))
+
+	// A crash before the old segments are removed leaves the events twice,
+	// but they are loaded once.
+	for _, segment := range segments {
+		content, err := ioutil.ReadFile(path.Join(dir, segment))
+		require.Nil(t, err)
+		require.Nil(t, ioutil.WriteFile(path.Join(dir, segmentPrefix+"0"+strings.TrimPrefix(segment, segmentPrefix)), content, 0644))
+	}
+	loaded, err := log.load(time.Time{})
+	require.Nil(t, err)
+	require.Equal(t, 3, len(loaded))
+	for i, e := range loaded {
+		assert.Equal(t, uint64(i+2), e.Sequence)
+	}
+}
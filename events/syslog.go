@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Names of the event types in syslog messages, as in the -event_*_types flags.
+var syslogEventTypes = map[EventType]string{
+	TypeOom:               "oom",
+	TypeContainerCreation: "creation",
+	TypeContainerDeletion: "deletion",
+	TypeRestartLoop:       "restart_loop",
+}
+
+// The methods of *syslog.Writer used to write events.
+type syslogWriter interface {
+	Err(m string) error
+	Warning(m string) error
+	Info(m string) error
+	Close() error
+}
+
+// Writes the events of an EventManager to syslog, one message of key=value
+// pairs per event. OOMs are logged as errors, restart loops as warnings and
+// the creation and deletion of containers as info.
+type Syslog struct {
+	writer syslogWriter
+
+	manager EventManager
+	watchId int
+	// Closed once all the watched events are written.
+	done chan struct{}
+}
+
+// Returns a Syslog writing to the local syslog daemon with the given tag, with
+// the daemon facility.
+func NewSyslog(tag string) (*Syslog, error) {
+	writer, err := syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %v", err)
+	}
+	return &Syslog{writer: writer}, nil
+}
+
+// Starts writing the events added to manager which satisfy request. The
+// request must not have a StartTime or EndTime.
+func (self *Syslog) Start(manager EventManager, request *Request) error {
+	eventChannel, err := manager.WatchEvents(request)
+	if err != nil {
+		return err
+	}
+	self.manager = manager
+	self.watchId = eventChannel.GetWatchId()
+	self.done = make(chan struct{})
+	go self.writeEvents(eventChannel.GetChannel())
+	return nil
+}
+
+// Stops watching events, waits for the pending ones to be written and
+// closes the connection to syslog.
+func (self *Syslog) Stop() {
+	self.manager.StopWatch(self.watchId)
+	<-self.done
+	self.writer.Close()
+}
+
+func (self *Syslog) writeEvents(events chan *Event) {
+	defer close(self.done)
+	for e := range events {
+		msg := syslogMessage(e)
+		var err error
+		switch e.EventType {
+		case TypeOom:
+			err = self.writer.Err(msg)
+		case TypeRestartLoop:
+			err = self.writer.Warning(msg)
+		default:
+			err = self.writer.Info(msg)
+		}
+		if err != nil {
+			glog.Errorf("Failed to write event %+v to syslog: %v", e, err)
+		}
+	}
+}
+
+// Returns the syslog message of an event, e.g.
+// event=oom container="/docker/abc" timestamp=2015-06-01T10:00:00Z sequence=3
+// followed by the data of the event as JSON, if any.
+func syslogMessage(e *Event) string {
+	msg := fmt.Sprintf("event=%s container=%q timestamp=%s sequence=%d", syslogEventTypes[e.EventType], e.ContainerName, e.Timestamp.UTC().Format(time.RFC3339Nano), e.Sequence)
+	if e.EventData != nil {
+		if data, err := json.Marshal(e.EventData); err == nil {
+			msg += " data=" + string(data)
+		}
+	}
+	return msg
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Records the messages written to it by severity.
+type fakeSyslogWriter struct {
+	lock     sync.Mutex
+	errors   []string
+	warnings []string
+	infos    []string
+	closed   bool
+}
+
+func (self *fakeSyslogWriter) Err(m string) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.errors = append(self.errors, m)
+	return nil
+}
+
+func (self *fakeSyslogWriter) Warning(m string) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.warnings = append(self.warnings, m)
+	return nil
+}
+
+func (self *fakeSyslogWriter) Info(m string) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.infos = append(self.infos, m)
+	return nil
+}
+
+func (self *fakeSyslogWriter) Close() error {
+	self.closed = true
+	return nil
+}
+
+func TestSyslogWritesMatchingEvents(t *testing.T) {
+	manager := NewEventManager()
+	writer := &fakeSyslogWriter{}
+	sink := &Syslog{writer: writer}
+	request := NewRequest()
+	request.EventType[TypeOom] = true
+	request.EventType[TypeContainerCreation] = true
+	request.EventType[TypeRestartLoop] = true
+	require.NoError(t, sink.Start(manager, request))
+
+	now := time.Unix(1000, 0)
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/a", Timestamp: now, EventType: TypeContainerCreation}))
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/b", Timestamp: now, EventType: TypeContainerDeletion}))
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/c", Timestamp: now, EventType: TypeOom}))
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/d", Timestamp: now, EventType: TypeRestartLoop}))
+	// Stopping writes the pending events.
+	sink.Stop()
+
+	assert.Equal(t, []string{`event=creation container="/a" timestamp=1970-01-01T00:16:40Z sequence=1`}, writer.infos)
+	assert.Equal(t, []string{`event=oom container="/c" timestamp=1970-01-01T00:16:40Z sequence=3`}, writer.errors)
+	assert.Equal(t, []string{`event=restart_loop container="/d" timestamp=1970-01-01T00:16:40Z sequence=4`}, writer.warnings)
+	assert.True(t, writer.closed)
+}
+
+func TestSyslogMessageData(t *testing.T) {
+	e := &Event{
+		ContainerName: "/a",
+		Timestamp:     time.Unix(1000, 0),
+		EventType:     TypeContainerDeletion,
+		Sequence:      4,
+		EventData:     map[string]int{"exit_code": 137},
+	}
+	assert.Equal(t, `event=deletion container="/a" timestamp=1970-01-01T00:16:40Z sequence=4 data={"exit_code":137}`, syslogMessage(e))
+}
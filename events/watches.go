@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"strings"
+)
+
+// Index of watches by the container they are restricted to, so that an added
+// event is only checked against the watches of its container and of its
+// parents rather than against every watch.
+//
+// Containers are indexed by the components of their name split on "/": the
+// components of the name of a watched container are a prefix of the ones of
+// all the containers it matches, including subcontainers. Watches not
+// restricted to a container are checked against every event.
+type watchIndex struct {
+	root *watchIndexNode
+	// Watches of all the containers.
+	unscoped map[int]*watch
+}
+
+type watchIndexNode struct {
+	children map[string]*watchIndexNode
+	// Watches of the container named by the path to this node, and of its
+	// subcontainers, by watch id.
+	container map[int]*watch
+	subtree   map[int]*watch
+}
+
+func newWatchIndex() *watchIndex {
+	return &watchIndex{
+		root:     newWatchIndexNode(),
+		unscoped: make(map[int]*watch),
+	}
+}
+
+func newWatchIndexNode() *watchIndexNode {
+	return &watchIndexNode{
+		children:  make(map[string]*watchIndexNode),
+		container: make(map[int]*watch),
+		subtree:   make(map[int]*watch),
+	}
+}
+
+func (self *watchIndexNode) empty() bool {
+	return len(self.children) == 0 && len(self.container) == 0 && len(self.subtree) == 0
+}
+
+// Returns the watches of w's node, nil if w is not restricted to a container.
+func (self *watchIndexNode) watches(w *watch) map[int]*watch {
+	if w.request.IncludeSubcontainers {
+		return self.subtree
+	}
+	return self.container
+}
+
+func (self *watchIndex) add(w *watch) {
+	if w.request.ContainerName == "" {
+		self.unscoped[w.id] = w
+		return
+	}
+	node := self.root
+	for _, component := range strings.Split(w.request.ContainerName, "/") {
+		child, ok := node.children[component]
+		if !ok {
+			child = newWatchIndexNode()
+			node.children[component] = child
+		}
+		node = child
+	}
+	node.watches(w)[w.id] = w
+}
+
+func (self *watchIndex) remove(w *watch) {
+	if w.request.ContainerName == "" {
+		delete(self.unscoped, w.id)
+		return
+	}
+	components := strings.Split(w.request.ContainerName, "/")
+	path := []*watchIndexNode{self.root}
+	for _, component := range components {
+		child, ok := path[len(path)-1].children[component]
+		if !ok {
+			return
+		}
+		path = append(path, child)
+	}
+	delete(path[len(path)-1].watches(w), w.id)
+	// Drop the nodes left without watches.
+	for i := len(path) - 1; i > 0 && path[i].empty(); i-- {
+		delete(path[i-1].children, components[i-1])
+	}
+}
+
+// Returns the watches which may match an event of the given container.
+func (self *watchIndex) candidates(containerName string) []*watch {
+	candidates := make([]*watch, 0, len(self.unscoped))
+	for _, w := range self.unscoped {
+		candidates = append(candidates, w)
+	}
+	node := self.root
+	for _, component := range strings.Split(containerName, "/") {
+		child, ok := node.children[component]
+		if !ok {
+			return candidates
+		}
+		node = child
+		for _, w := range node.subtree {
+			candidates = append(candidates, w)
+		}
+	}
+	for _, w := range node.container {
+		candidates = append(candidates, w)
+	}
+	return candidates
+}
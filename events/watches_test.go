@@ -0,0 +1,87 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func candidateIds(index *watchIndex, containerName string) []int {
+	ids := []int{}
+	for _, w := range index.candidates(containerName) {
+		ids = append(ids, w.id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func TestWatchIndex(t *testing.T) {
+	index := newWatchIndex()
+	watches := []*watch{
+		{id: 1, request: &Request{ContainerName: "/docker", IncludeSubcontainers: true}},
+		{id: 2, request: &Request{ContainerName: "/docker/abc"}},
+		{id: 3, request: &Request{ContainerName: "/dockerd", IncludeSubcontainers: true}},
+		{id: 4, request: &Request{}},
+		{id: 5, request: &Request{ContainerName: "/docker"}},
+	}
+	for _, w := range watches {
+		index.add(w)
+	}
+
+	assert.Equal(t, []int{1, 2, 4}, candidateIds(index, "/docker/abc"))
+	assert.Equal(t, []int{1, 4}, candidateIds(index, "/docker/abc/def"))
+	assert.Equal(t, []int{1, 4, 5}, candidateIds(index, "/docker"))
+	assert.Equal(t, []int{3, 4}, candidateIds(index, "/dockerd"))
+	assert.Equal(t, []int{4}, candidateIds(index, "/system.slice"))
+
+	// Nodes left without watches are dropped.
+	index.remove(watches[1])
+	index.remove(watches[2])
+	assert.Equal(t, []int{1, 4}, candidateIds(index, "/docker/abc"))
+	assert.Equal(t, 1, len(index.root.children[""].children))
+	index.remove(watches[0])
+	index.remove(watches[4])
+	index.remove(watches[3])
+	assert.True(t, index.root.empty())
+	assert.Equal(t, []int{}, candidateIds(index, "/docker"))
+}
+
+func TestWatchEventsRoutedByContainer(t *testing.T) {
+	manager := NewEventManager()
+	subtree := NewRequest()
+	subtree.ContainerName = "/docker"
+	subtree.IncludeSubcontainers = true
+	subtree.EventType[TypeOom] = true
+	subtreeChannel, err := manager.WatchEvents(subtree)
+	assert.NoError(t, err)
+	other := NewRequest()
+	other.ContainerName = "/dockerd"
+	other.IncludeSubcontainers = true
+	other.EventType[TypeOom] = true
+	otherChannel, err := manager.WatchEvents(other)
+	assert.NoError(t, err)
+
+	event := makeEvent(createOldTime(t), "/docker/abc")
+	assert.NoError(t, manager.AddEvent(event))
+	assert.Equal(t, event, <-subtreeChannel.GetChannel())
+	assert.Equal(t, 0, len(otherChannel.GetChannel()))
+
+	manager.StopWatch(subtreeChannel.GetWatchId())
+	manager.StopWatch(otherChannel.GetWatchId())
+	assert.True(t, manager.watchIndex.root.empty())
+}
@@ -0,0 +1,160 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Number of events waiting to be posted beyond which new events are dropped.
+const webhookQueueSize = 100
+
+// Posts the events of an EventManager as JSON to a webhook, one event per
+// request. Failed posts are retried with exponential backoff. Events are
+// queued rather than posted from AddEvent, so that a slow or unreachable
+// webhook does not hold up the event manager.
+type Webhook struct {
+	url    string
+	client *http.Client
+	// Events waiting to be posted.
+	queue chan *Event
+	// Number of times a failed post is retried and the delay before the
+	// first retry, doubled after each retry up to maxBackoff.
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	manager EventManager
+	watchId int
+	// Closed to stop retrying, and once all queued events are handled.
+	quit chan struct{}
+	done chan struct{}
+}
+
+// Returns a Webhook posting events to url.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		url:            url,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		queue:          make(chan *Event, webhookQueueSize),
+		maxRetries:     5,
+		initialBackoff: time.Second,
+		maxBackoff:     time.Minute,
+	}
+}
+
+// Starts posting the events added to manager which satisfy request. The
+// request must not have a StartTime or EndTime.
+func (self *Webhook) Start(manager EventManager, request *Request) error {
+	eventChannel, err := manager.WatchEvents(request)
+	if err != nil {
+		return err
+	}
+	self.manager = manager
+	self.watchId = eventChannel.GetWatchId()
+	self.quit = make(chan struct{})
+	self.done = make(chan struct{})
+	go self.enqueue(eventChannel.GetChannel())
+	go self.postEvents()
+	return nil
+}
+
+// Stops watching events and waits for the post in progress, if any. Events
+// still queued are dropped.
+func (self *Webhook) Stop() {
+	close(self.quit)
+	self.manager.StopWatch(self.watchId)
+	<-self.done
+}
+
+// Queues the watched events, dropping them when the queue is full.
+func (self *Webhook) enqueue(events chan *Event) {
+	for e := range events {
+		select {
+		case self.queue <- e:
+		default:
+			glog.Warningf("Dropping event %+v, %d events are already waiting to be posted to %q", e, len(self.queue), self.url)
+		}
+	}
+	close(self.queue)
+}
+
+func (self *Webhook) postEvents() {
+	defer close(self.done)
+	for e := range self.queue {
+		select {
+		case <-self.quit:
+			continue
+		default:
+		}
+		self.postWithRetries(e)
+	}
+}
+
+// Posts an event, retrying with backoff until it succeeds, the error cannot
+// be fixed by retrying or the retries are exhausted.
+func (self *Webhook) postWithRetries(e *Event) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		glog.Errorf("Failed to encode event %+v: %v", e, err)
+		return
+	}
+	backoff := self.initialBackoff
+	for retry := 0; ; retry++ {
+		retriable, err := self.post(body)
+		if err == nil {
+			return
+		}
+		if !retriable || retry == self.maxRetries {
+			glog.Errorf("Failed to post event %+v to %q: %v", e, self.url, err)
+			return
+		}
+		glog.V(2).Infof("Failed to post event %+v to %q, retrying in %v: %v", e, self.url, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-self.quit:
+			return
+		}
+		backoff *= 2
+		if backoff > self.maxBackoff {
+			backoff = self.maxBackoff
+		}
+	}
+}
+
+// Posts body to the webhook. Returns whether a failed post may succeed when
+// retried: client errors other than timeouts and rate limiting are final.
+func (self *Webhook) post(body []byte) (bool, error) {
+	resp, err := self.client.Post(self.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// Read the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return true, nil
+	}
+	retriable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retriable, fmt.Errorf("unexpected status %q", resp.Status)
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Records the events posted to it, answering with the given statuses first.
+type fakeWebhookServer struct {
+	lock     sync.Mutex
+	statuses []int
+	attempts int
+	events   []Event
+	posted   chan struct{}
+}
+
+func (self *fakeWebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.attempts++
+	if len(self.statuses) > 0 {
+		status := self.statuses[0]
+		self.statuses = self.statuses[1:]
+		w.WriteHeader(status)
+		if status >= 300 {
+			self.posted <- struct{}{}
+			return
+		}
+	}
+	var e Event
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	self.events = append(self.events, e)
+	self.posted <- struct{}{}
+}
+
+func startWebhook(t *testing.T, statuses ...int) (*events, *Webhook, *fakeWebhookServer, func()) {
+	server := &fakeWebhookServer{statuses: statuses, posted: make(chan struct{}, 10)}
+	httpServer := httptest.NewServer(server)
+	manager := NewEventManager()
+	webhook := NewWebhook(httpServer.URL)
+	webhook.initialBackoff = time.Millisecond
+	request := NewRequest()
+	request.EventType[TypeOom] = true
+	require.NoError(t, webhook.Start(manager, request))
+	return manager, webhook, server, func() {
+		webhook.Stop()
+		httpServer.Close()
+	}
+}
+
+func waitForPosts(t *testing.T, server *fakeWebhookServer, count int) {
+	for i := 0; i < count; i++ {
+		select {
+		case <-server.posted:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for post %d", i+1)
+		}
+	}
+}
+
+func TestWebhookPostsMatchingEvents(t *testing.T) {
+	manager, _, server, stop := startWebhook(t)
+	defer stop()
+
+	now := time.Unix(1000, 0).UTC()
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/a", Timestamp: now, EventType: TypeContainerCreation}))
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/b", Timestamp: now, EventType: TypeOom}))
+	waitForPosts(t, server, 1)
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	require.Equal(t, 1, len(server.events))
+	assert.Equal(t, "/b", server.events[0].ContainerName)
+	assert.Equal(t, TypeOom, server.events[0].EventType)
+	assert.Equal(t, uint64(2), server.events[0].Sequence)
+	assert.True(t, now.Equal(server.events[0].Timestamp))
+}
+
+func TestWebhookRetries(t *testing.T) {
+	manager, _, server, stop := startWebhook(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	defer stop()
+
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/a", Timestamp: time.Now(), EventType: TypeOom}))
+	waitForPosts(t, server, 3)
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	assert.Equal(t, 3, server.attempts)
+	assert.Equal(t, 1, len(server.events))
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	manager, _, server, stop := startWebhook(t, http.StatusBadRequest)
+	defer stop()
+
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/a", Timestamp: time.Now(), EventType: TypeOom}))
+	require.NoError(t, manager.AddEvent(&Event{ContainerName: "/b", Timestamp: time.Now(), EventType: TypeOom}))
+	waitForPosts(t, server, 2)
+
+	// The first event was dropped, the second one posted.
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	assert.Equal(t, 2, server.attempts)
+	require.Equal(t, 1, len(server.events))
+	assert.Equal(t, "/b", server.events[0].ContainerName)
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	auth "github.com/abbot/go-http-auth"
+)
+
+// Wraps handler so that only requests authenticated as one of the users of
+// the htpasswd file are served, others get a 401.
+func RequireBasicAuth(htpasswdFile, realm string, handler http.Handler) http.Handler {
+	authenticator := auth.NewBasicAuthenticator(realm, auth.HtpasswdFileProvider(htpasswdFile))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authenticator.CheckAuth(r) == "" {
+			authenticator.RequireAuth(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Wraps handler so that only requests made over TLS with a verified client
+// certificate are served, others get a 401.
+func RequireClientCert(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "a client certificate signed by a trusted CA is required", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Returns the TLS config of a server using the given certificate and key.
+// If clientCaFile is set, the certificates clients present are verified
+// against the CAs it holds. HTTP/2 is offered to the clients supporting it.
+func NewTLSConfig(certFile, keyFile, clientCaFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS certificate: %v", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		// http.Server serves HTTP/2 on the TLS connections negotiating it.
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if clientCaFile != "" {
+		pem, err := ioutil.ReadFile(clientCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in the client CA file %q", clientCaFile)
+		}
+		config.ClientCAs = pool
+		// Requests without a certificate are rejected by RequireClientCert
+		// with a 401 rather than by a failed handshake.
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
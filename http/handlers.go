@@ -87,7 +87,7 @@ func RegisterHandlers(mux httpMux.Mux, containerManager manager.Manager, httpAut
 
 	collector := metrics.NewPrometheusCollector(containerManager)
 	prometheus.MustRegister(collector)
-	http.Handle(prometheusEndpoint, prometheus.Handler())
+	http.Handle(prometheusEndpoint, metrics.NewPrometheusHandler(collector, prometheus.Handler()))
 
 	return nil
 }
@@ -23,6 +23,12 @@ type CpuSpec struct {
 	Limit    uint64 `json:"limit"`
 	MaxLimit uint64 `json:"max_limit"`
 	Mask     string `json:"mask,omitempty"`
+	// CPUs the processes of the container can run on, from the effective
+	// cpuset of its cgroup, e.g. "0-3,8". Empty if unknown.
+	CpuSet string `json:"cpuset,omitempty"`
+	// Memory nodes the container can allocate memory on, from the effective
+	// cpuset of its cgroup, e.g. "0-1". Empty if unknown.
+	Mems string `json:"mems,omitempty"`
 }
 
 type MemorySpec struct {
@@ -37,6 +43,21 @@ type MemorySpec struct {
 	// The amount of swap space requested. Default is unlimited (-1).
 	// Units: bytes.
 	SwapLimit uint64 `json:"swap_limit,omitempty"`
+
+	// Usage the container is pushed back to when the machine runs low on
+	// memory (cgroup v1 memory.soft_limit_in_bytes).
+	// Units: bytes.
+	SoftLimit uint64 `json:"soft_limit,omitempty"`
+
+	// Usage under which the memory of the container is protected from
+	// reclaim when possible (cgroup v2 memory.low).
+	// Units: bytes.
+	Low uint64 `json:"low,omitempty"`
+
+	// Usage over which the container is throttled and its memory reclaimed
+	// (cgroup v2 memory.high).
+	// Units: bytes.
+	High uint64 `json:"high,omitempty"`
 }
 
 type ContainerSpec struct {
@@ -55,6 +76,55 @@ type ContainerSpec struct {
 
 	// HasDiskIo when true, indicates that DiskIo stats will be available.
 	HasDiskIo bool `json:"has_diskio"`
+
+	// Labels of the container (e.g.: Docker labels).
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Version of the cgroup hierarchy the stats of the container are read
+	// from: 1 for the per-subsystem (v1) hierarchies, 2 for the unified one.
+	CgroupVersion int `json:"cgroup_version,omitempty"`
+
+	// Controllers enabled for the cgroup of the container on the unified
+	// (v2) hierarchy, from its cgroup.controllers file. The stats of the
+	// resources of disabled controllers (e.g. io) are not collected. Empty on
+	// cgroup v1, where all the mounted controllers are enabled.
+	Controllers []string `json:"controllers,omitempty"`
+
+	// Annotations attached to the container through the API by external
+	// systems, e.g. the version of the deployment it is part of. They are
+	// kept by cAdvisor only, in memory, and are not read from the runtime.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Image the container was started from, if known to its runtime.
+	Image string `json:"image,omitempty"`
+
+	// Digest of the image, e.g. "sha256:...", if known to the runtime of the
+	// container. Unlike tags, it identifies the content of the image.
+	ImageDigest string `json:"image_digest,omitempty"`
+
+	// Tags of the image, e.g. "nginx:1.9", if known to the runtime of the
+	// container.
+	RepoTags []string `json:"repo_tags,omitempty"`
+
+	// Environment variables of the container, only reported when enabled
+	// with -expose_container_env.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Number of times the runtime restarted the container, if known to it.
+	RestartCount int `json:"restart_count,omitempty"`
+
+	// Time at which the container was last (re)started, if known to its runtime.
+	LastStartTime time.Time `json:"last_start_time,omitempty"`
+
+	// Seccomp profile the container is confined by: its name, "unconfined"
+	// or "custom" when the runtime only knows its content. Empty if unknown.
+	SeccompProfile string `json:"seccomp_profile,omitempty"`
+
+	// AppArmor profile the processes of the container run under, if known.
+	AppArmorProfile string `json:"apparmor_profile,omitempty"`
+
+	// Custom metrics exported by applications in the container.
+	CustomMetrics []MetricSpec `json:"custom_metrics,omitempty"`
 }
 
 // Container reference contains enough information to uniquely identify a container
@@ -82,15 +152,16 @@ func (self ContainerReferenceSlice) Less(i, j int) bool { return self[i].Name <
 // It specifies how much data users want to get about a container
 type ContainerInfoRequest struct {
 	// Max number of stats to return. Specify -1 for all stats currently available.
-	// If start and end time are specified this limit is ignored.
-	// Default: 60
+	// If start and end time are specified this is an upper bound on the stats
+	// returned for the range, the most recent ones are kept.
+	// Default: 60, or no limit when start and end time are specified.
 	NumStats int `json:"num_stats,omitempty"`
 
-	// Start time for which to query information.
+	// Start time for which to query information (RFC 3339 in JSON).
 	// If ommitted, the beginning of time is assumed.
 	Start time.Time `json:"start,omitempty"`
 
-	// End time for which to query information.
+	// End time for which to query information (RFC 3339 in JSON).
 	// If ommitted, current time is assumed.
 	End time.Time `json:"end,omitempty"`
 }
@@ -265,17 +336,44 @@ type CpuUsage struct {
 // All CPU usage metrics are cumulative from the creation of the container
 type CpuStats struct {
 	Usage CpuUsage `json:"usage"`
-	// Smoothed average of number of runnable threads x 1000.
+	// Smoothed average of number of runnable and uninterruptible threads x 1000.
 	// We multiply by thousand to avoid using floats, but preserving precision.
-	// Load is smoothed over the last 10 seconds. Instantaneous value can be read
-	// from LoadStats.NrRunning.
+	// Load is smoothed over the last 10 seconds. Instantaneous values can be read
+	// from LoadStats.NrRunning and LoadStats.NrUninterruptible.
+	// Only populated when the cpu load reader is enabled.
 	LoadAverage int32 `json:"load_average"`
+	// CFS bandwidth control of containers with a CPU quota: number of
+	// enforcement periods elapsed, number of them in which the container was
+	// throttled, and total time it was throttled for.
+	// Units: ThrottledTime in nanoseconds.
+	NrPeriods     uint64 `json:"nr_periods"`
+	NrThrottled   uint64 `json:"nr_throttled"`
+	ThrottledTime uint64 `json:"throttled_time"`
 }
 
 type PerDiskStats struct {
-	Major uint64            `json:"major"`
-	Minor uint64            `json:"minor"`
-	Stats map[string]uint64 `json:"stats"`
+	// Name of the device, e.g. "sda". Empty if it could not be resolved.
+	Device string            `json:"device,omitempty"`
+	Major  uint64            `json:"major"`
+	Minor  uint64            `json:"minor"`
+	Stats  map[string]uint64 `json:"stats"`
+}
+
+type LatencyBucket struct {
+	// Upper bound of the bucket, inclusive.
+	// Units: nanoseconds.
+	UpperBound uint64 `json:"upper_bound"`
+	// Number of operations in the bucket.
+	Count uint64 `json:"count"`
+}
+
+type PerDiskLatency struct {
+	Major uint64 `json:"major"`
+	Minor uint64 `json:"minor"`
+	// Histograms of the read and write service times. Buckets are not
+	// cumulative, the last bucket has an upper bound of math.MaxUint64.
+	Read  []LatencyBucket `json:"read"`
+	Write []LatencyBucket `json:"write"`
 }
 
 type DiskIoStats struct {
@@ -287,6 +385,11 @@ type DiskIoStats struct {
 	IoWaitTime     []PerDiskStats `json:"io_wait_time,omitempty"`
 	IoMerged       []PerDiskStats `json:"io_merged,omitempty"`
 	IoTime         []PerDiskStats `json:"io_time,omitempty"`
+	// Distribution of the service time of the operations served since the
+	// container was first seen. The cgroup only reports cumulative service
+	// times, so all operations served within one housekeeping interval are
+	// counted in the bucket of their average service time.
+	IoServiceTimeHistogram []PerDiskLatency `json:"io_service_time_histogram,omitempty"`
 }
 
 type MemoryStats struct {
@@ -300,10 +403,54 @@ type MemoryStats struct {
 	// Units: Bytes.
 	WorkingSet uint64 `json:"working_set"`
 
+	// The amount of page cache memory, which can be reclaimed under memory
+	// pressure. Part of "usage".
+	// Units: Bytes.
+	Cache uint64 `json:"cache"`
+
+	// The amount of anonymous and swap cache memory (includes transparent
+	// hugepages). Part of "usage".
+	// Units: Bytes.
+	RSS uint64 `json:"rss"`
+
+	// The amount of swap used, only reported when swap accounting is enabled
+	// in the kernel.
+	// Units: Bytes.
+	Swap uint64 `json:"swap"`
+
+	// Cumulative count of the pages swapped in and out of memory, only
+	// reported on cgroup v2 by kernels which count them per cgroup.
+	SwapIn  uint64 `json:"swap_in"`
+	SwapOut uint64 `json:"swap_out"`
+
+	// Cumulative count of the times the memory usage hit the limit of the
+	// container, from memory.failcnt.
+	Failcnt uint64 `json:"failcnt"`
+
+	// Cumulative count of the processes of the container killed by the OOM
+	// killer, only reported by kernels 4.13 and newer.
+	OomKillCount uint64 `json:"oom_kill_count"`
+
+	// Usage of the hugepages of each size, e.g. "2MB", by the container,
+	// from its hugetlb cgroup. Hugepages are not part of "usage".
+	HugeTlb map[string]HugeTlbStats `json:"hugetlb,omitempty"`
+
 	ContainerData    MemoryStatsMemoryData `json:"container_data,omitempty"`
 	HierarchicalData MemoryStatsMemoryData `json:"hierarchical_data,omitempty"`
 }
 
+type HugeTlbStats struct {
+	// Current usage of hugepages of the size.
+	// Units: Bytes.
+	Usage uint64 `json:"usage"`
+	// Maximum usage ever recorded, only reported on cgroup v1.
+	// Units: Bytes.
+	MaxUsage uint64 `json:"max_usage,omitempty"`
+	// Cumulative count of the hugepage allocations which failed because of
+	// the limit of the container.
+	Failcnt uint64 `json:"failcnt"`
+}
+
 type MemoryStatsMemoryData struct {
 	Pgfault    uint64 `json:"pgfault"`
 	Pgmajfault uint64 `json:"pgmajfault"`
@@ -326,6 +473,56 @@ type NetworkStats struct {
 	TxErrors uint64 `json:"tx_errors"`
 	// Cumulative count of packets dropped while transmitting.
 	TxDropped uint64 `json:"tx_dropped"`
+	// Counts of the TCP connections by state.
+	Tcp TcpStats `json:"tcp"`
+	// Counts of the UDP sockets.
+	Udp UdpStats `json:"udp"`
+	// Stats of each interface in the network namespace of the container.
+	Interfaces []InterfaceStats `json:"interfaces,omitempty"`
+}
+
+// Counters of a network interface, as seen from inside the container.
+type InterfaceStats struct {
+	// Name of the interface (e.g. eth0).
+	Name string `json:"name"`
+	// Cumulative count of bytes received.
+	RxBytes uint64 `json:"rx_bytes"`
+	// Cumulative count of packets received.
+	RxPackets uint64 `json:"rx_packets"`
+	// Cumulative count of receive errors encountered.
+	RxErrors uint64 `json:"rx_errors"`
+	// Cumulative count of packets dropped while receiving.
+	RxDropped uint64 `json:"rx_dropped"`
+	// Cumulative count of bytes transmitted.
+	TxBytes uint64 `json:"tx_bytes"`
+	// Cumulative count of packets transmitted.
+	TxPackets uint64 `json:"tx_packets"`
+	// Cumulative count of transmit errors encountered.
+	TxErrors uint64 `json:"tx_errors"`
+	// Cumulative count of packets dropped while transmitting.
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+// Number of IPv4 and IPv6 TCP connections in each state.
+type TcpStats struct {
+	Established uint64 `json:"established"`
+	SynSent     uint64 `json:"syn_sent"`
+	SynRecv     uint64 `json:"syn_recv"`
+	FinWait1    uint64 `json:"fin_wait1"`
+	FinWait2    uint64 `json:"fin_wait2"`
+	TimeWait    uint64 `json:"time_wait"`
+	Close       uint64 `json:"close"`
+	CloseWait   uint64 `json:"close_wait"`
+	LastAck     uint64 `json:"last_ack"`
+	Listen      uint64 `json:"listen"`
+	Closing     uint64 `json:"closing"`
+}
+
+type UdpStats struct {
+	// Number of IPv4 and IPv6 UDP sockets.
+	Sockets uint64 `json:"sockets"`
+	// Cumulative count of datagrams dropped by the open sockets.
+	Dropped uint64 `json:"dropped"`
 }
 
 type FsStats struct {
@@ -338,6 +535,14 @@ type FsStats struct {
 	// Number of bytes that is consumed by the container on this filesystem.
 	Usage uint64 `json:"usage"`
 
+	// Number of bytes used by the writable layer of the root filesystem of
+	// the container, part of Usage when Usage is known.
+	WritableLayerSize uint64 `json:"writable_layer_size,omitempty"`
+
+	// Number of bytes used by the log files the runtime writes the output of
+	// the container to, including rotated ones. Not part of Usage.
+	LogSize uint64 `json:"log_size,omitempty"`
+
 	// Number of reads completed
 	// This is the total number of reads completed successfully.
 	ReadsCompleted uint64 `json:"reads_completed"`
@@ -401,11 +606,95 @@ type ContainerStats struct {
 	Memory    MemoryStats  `json:"memory,omitempty"`
 	Network   NetworkStats `json:"network,omitempty"`
 
+	// Period the stats were downsampled over, in nanoseconds, 0 for stats
+	// at full resolution. Downsampled stats hold the counters of the last
+	// sample of the period and the gauges averaged over it.
+	Resolution time.Duration `json:"resolution_ns,omitempty"`
+
 	// Filesystem statistics
 	Filesystem []FsStats `json:"filesystem,omitempty"`
 
 	// Task load stats
 	TaskStats LoadStats `json:"task_stats,omitempty"`
+
+	// Pressure stall information, only available on kernels supporting it.
+	PSI *PSIStats `json:"psi,omitempty"`
+
+	// GPUs used by the container, only set on hosts with NVIDIA devices.
+	Accelerators []AcceleratorStats `json:"accelerators,omitempty"`
+
+	// Custom metrics exported by applications in the container, by metric name.
+	CustomMetrics map[string][]MetricVal `json:"custom_metrics,omitempty"`
+
+	// Processes of the container and the files they opened, nil if they
+	// could not be listed.
+	Processes *ProcessStats `json:"processes,omitempty"`
+
+	// Tasks of the pids cgroup of the container, nil if it has none.
+	Pids *PidsStats `json:"pids,omitempty"`
+}
+
+type PidsStats struct {
+	// Number of tasks (processes and threads) in the cgroup, from pids.current.
+	Current uint64 `json:"current"`
+	// Maximum number of tasks of the cgroup, from pids.max. 0 if unlimited.
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+type ProcessStats struct {
+	// Number of processes of the container.
+	ProcessCount uint64 `json:"process_count"`
+	// Number of file descriptors opened by the process of the container
+	// using the largest share of its limit, and that limit (soft
+	// RLIMIT_NOFILE). Both are 0 if there is no process.
+	OpenFDs uint64 `json:"open_fds"`
+	MaxFDs  uint64 `json:"max_fds"`
+	// Context switches of the threads of all the processes, voluntary ones
+	// when a thread blocked (e.g. on I/O) and involuntary ones when it was
+	// preempted. The switches of exited processes are not counted.
+	VoluntaryCtxSwitches   uint64 `json:"voluntary_ctx_switches"`
+	InvoluntaryCtxSwitches uint64 `json:"involuntary_ctx_switches"`
+}
+
+type AcceleratorStats struct {
+	// Manufacturer of the accelerator, e.g. "nvidia".
+	Make string `json:"make"`
+	// Model of the accelerator, e.g. "Tesla K80".
+	Model string `json:"model"`
+	// Unique ID of the accelerator.
+	ID string `json:"id"`
+	// Total memory of the accelerator, in bytes.
+	MemoryTotal uint64 `json:"memory_total"`
+	// Memory of the accelerator used by the processes of the container, in bytes.
+	MemoryUsed uint64 `json:"memory_used"`
+	// Percent of time the accelerator was busy over the last sample period,
+	// for all the processes using it.
+	DutyCycle uint64 `json:"duty_cycle"`
+}
+
+type PSIData struct {
+	// Percentage of time tasks were stalled on the resource, averaged
+	// over the last 10, 60 and 300 seconds.
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	// Cumulative stall time.
+	// Units: microseconds.
+	Total uint64 `json:"total"`
+}
+
+type PSIResourceStats struct {
+	// Stalls of some of the tasks.
+	Some PSIData `json:"some"`
+	// Stalls of all the tasks at once. Not reported for cpu by older kernels.
+	Full PSIData `json:"full"`
+}
+
+type PSIStats struct {
+	// Each resource is only set if its pressure file exists.
+	Cpu    *PSIResourceStats `json:"cpu,omitempty"`
+	Memory *PSIResourceStats `json:"memory,omitempty"`
+	Io     *PSIResourceStats `json:"io,omitempty"`
 }
 
 func timeEq(t1, t2 time.Time, tolerance time.Duration) bool {
@@ -0,0 +1,51 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "time"
+
+// Details of an OOM kill, sent as the data of OOM events.
+// The field names are kept untagged to match the data previously sent for
+// OOM events so existing clients keep working.
+type OomKillDetails struct {
+	// Process ID of the killed process.
+	Pid int
+
+	// Name of the killed process.
+	ProcessName string
+
+	// Time at which the process was killed, accurate to the second.
+	TimeOfDeath time.Time
+
+	// The absolute name of the container that OOMed.
+	ContainerName string
+
+	// Memory limit of the container at the time of the kill. 0 if unknown.
+	// Units: bytes.
+	MemoryLimit uint64 `json:",omitempty"`
+}
+
+// How a container exited, sent as the data of deletion events when its
+// runtime reports it.
+type ContainerExitStatus struct {
+	// Exit code of the main process of the container.
+	ExitCode int `json:"exit_code"`
+
+	// Whether the container was killed for running out of memory.
+	OomKilled bool `json:"oom_killed"`
+
+	// Time at which the container exited.
+	FinishedAt time.Time `json:"finished_at"`
+}
@@ -30,6 +30,25 @@ type Node struct {
 	Caches []Cache `json:"caches"`
 }
 
+type NumaNode struct {
+	Id int `json:"node_id"`
+	// Logical CPUs of the node.
+	Cpus []int `json:"cpus"`
+	// Memory of the node, in bytes.
+	MemoryTotal uint64 `json:"memory_total"`
+	MemoryFree  uint64 `json:"memory_free"`
+	// Huge pages of the node, by page size.
+	HugePages []HugePagesInfo `json:"hugepages,omitempty"`
+}
+
+type HugePagesInfo struct {
+	// Size of the pages, in kB.
+	PageSize uint64 `json:"page_size"`
+	// Number of pages reserved and number of them not in use.
+	NumPages  uint64 `json:"num_pages"`
+	FreePages uint64 `json:"free_pages"`
+}
+
 type Core struct {
 	Id      int     `json:"core_id"`
 	Threads []int   `json:"thread_ids"`
@@ -143,8 +162,38 @@ type MachineInfo struct {
 	// Machine Topology
 	// Describes cpu/memory layout and hierarchy.
 	Topology []Node `json:"topology"`
+
+	// NUMA nodes of the machine, as listed in /sys/devices/system/node.
+	// Empty if the kernel does not report them.
+	NumaNodes []NumaNode `json:"numa_nodes,omitempty"`
+
+	// How cgroups are mounted on the machine, one of the CgroupMode* constants.
+	CgroupMode string `json:"cgroup_mode,omitempty"`
+
+	// The cloud the machine runs in, one of the CloudProvider* constants, and
+	// the type, region and availability zone of its instance. Only detected
+	// when cAdvisor is started with -detect_cloud_provider, empty otherwise.
+	CloudProvider string `json:"cloud_provider,omitempty"`
+	InstanceType  string `json:"instance_type,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Zone          string `json:"zone,omitempty"`
 }
 
+const (
+	// Only the per-subsystem (v1) cgroup hierarchies are mounted.
+	CgroupModeLegacy = "legacy"
+	// Both v1 hierarchies and the unified (v2) hierarchy are mounted.
+	CgroupModeHybrid = "hybrid"
+	// Only the unified (v2) cgroup hierarchy is mounted.
+	CgroupModeUnified = "unified"
+)
+
+const (
+	CloudProviderGCE   = "gce"
+	CloudProviderAWS   = "aws"
+	CloudProviderAzure = "azure"
+)
+
 type VersionInfo struct {
 	// Kernel version.
 	KernelVersion string `json:"kernel_version"`
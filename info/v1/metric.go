@@ -0,0 +1,65 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+)
+
+// Type of a custom metric.
+type MetricType string
+
+const (
+	// Instantaneous value, may increase or decrease.
+	MetricGauge MetricType = "gauge"
+
+	// A counter-like value that is only expected to increase.
+	MetricCumulative MetricType = "cumulative"
+)
+
+// Type of the values of a custom metric.
+type DataType string
+
+const (
+	IntType   DataType = "int"
+	FloatType DataType = "float"
+)
+
+// Spec of a custom metric exported by an application in a container.
+type MetricSpec struct {
+	// The name of the metric.
+	Name string `json:"name"`
+
+	// Type of the metric.
+	Type MetricType `json:"type"`
+
+	// Data type of the values of the metric.
+	Format DataType `json:"format"`
+
+	// Units of the values of the metric, e.g. "requests".
+	Units string `json:"units"`
+}
+
+// A value of a custom metric.
+type MetricVal struct {
+	// Time at which the value was collected.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Value of metrics of the int type.
+	IntValue int64 `json:"int_value,omitempty"`
+
+	// Value of metrics of the float type.
+	FloatValue float64 `json:"float_value,omitempty"`
+}
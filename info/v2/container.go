@@ -36,6 +36,11 @@ type CpuSpec struct {
 	// Cpu affinity mask.
 	// TODO(rjnagal): Add a library to convert mask string to set of cpu bitmask.
 	Mask string `json:"mask,omitempty"`
+	// CPUs the container can run on, from the effective cpuset of its
+	// cgroup, e.g. "0-3,8".
+	CpuSet string `json:"cpuset,omitempty"`
+	// Memory nodes the container can allocate memory on, e.g. "0-1".
+	Mems string `json:"mems,omitempty"`
 }
 
 type MemorySpec struct {
@@ -50,12 +55,35 @@ type MemorySpec struct {
 	// The amount of swap space requested. Default is unlimited (-1).
 	// Units: bytes.
 	SwapLimit uint64 `json:"swap_limit,omitempty"`
+
+	// Usage the container is pushed back to when the machine runs low on
+	// memory (cgroup v1 memory.soft_limit_in_bytes).
+	// Units: bytes.
+	SoftLimit uint64 `json:"soft_limit,omitempty"`
+
+	// Usage under which the memory of the container is protected from
+	// reclaim when possible (cgroup v2 memory.low).
+	// Units: bytes.
+	Low uint64 `json:"low,omitempty"`
+
+	// Usage over which the container is throttled and its memory reclaimed
+	// (cgroup v2 memory.high).
+	// Units: bytes.
+	High uint64 `json:"high,omitempty"`
 }
 
 type ContainerSpec struct {
 	// Time at which the container was created.
 	CreationTime time.Time `json:"creation_time,omitempty"`
 
+	// Time elapsed since the container was created, when the spec was
+	// requested, in nanoseconds. Not set if the creation time is unknown.
+	Uptime time.Duration `json:"uptime_ns,omitempty"`
+
+	// Names of the containers holding this one, from its parent up to the
+	// root container, e.g. ["/kubepods/burstable", "/kubepods", "/"].
+	Ancestry []string `json:"ancestry,omitempty"`
+
 	// Other names by which the container is known within a certain namespace.
 	// This is unique within that namespace.
 	Aliases []string `json:"aliases,omitempty"`
@@ -69,14 +97,59 @@ type ContainerSpec struct {
 
 	HasMemory bool       `json:"has_memory"`
 	Memory    MemorySpec `json:"memory,omitempty"`
+
+	// Labels of the container (e.g.: Docker labels).
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Version of the cgroup hierarchy the stats of the container are read from (1 or 2).
+	CgroupVersion int `json:"cgroup_version,omitempty"`
+
+	// Controllers enabled for the cgroup of the container on cgroup v2.
+	Controllers []string `json:"controllers,omitempty"`
+
+	// Annotations attached to the container through the API.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Image the container was started from, if known to its runtime.
+	Image string `json:"image,omitempty"`
+
+	// Digest of the image, e.g. "sha256:...", if known to the runtime of the
+	// container. Unlike tags, it identifies the content of the image.
+	ImageDigest string `json:"image_digest,omitempty"`
+
+	// Tags of the image, e.g. "nginx:1.9", if known to the runtime of the
+	// container.
+	RepoTags []string `json:"repo_tags,omitempty"`
+
+	// Environment variables of the container, only reported when enabled
+	// with -expose_container_env.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Number of times the runtime restarted the container, if known to it.
+	RestartCount int `json:"restart_count,omitempty"`
+
+	// Time at which the container was last (re)started, if known to its runtime.
+	LastStartTime time.Time `json:"last_start_time,omitempty"`
+
+	// Seccomp profile the container is confined by: its name, "unconfined"
+	// or "custom" when the runtime only knows its content. Empty if unknown.
+	SeccompProfile string `json:"seccomp_profile,omitempty"`
+
+	// AppArmor profile the processes of the container run under, if known.
+	AppArmorProfile string `json:"apparmor_profile,omitempty"`
+
+	// Custom metrics exported by applications in the container.
+	CustomMetrics []v1.MetricSpec `json:"custom_metrics,omitempty"`
 }
 
 type ContainerStats struct {
 	// The time of this stat point.
 	Timestamp time.Time `json:"timestamp"`
+	// Period the stats were downsampled over, in nanoseconds, 0 for stats at full resolution.
+	Resolution time.Duration `json:"resolution_ns,omitempty"`
 	// CPU statistics
-	HasCpu bool        `json:"has_cpu"`
-	Cpu    v1.CpuStats `json:"cpu,omitempty"`
+	HasCpu bool     `json:"has_cpu"`
+	Cpu    CpuStats `json:"cpu,omitempty"`
 	// Disk IO statistics
 	HasDiskIo bool           `json:"has_diskio"`
 	DiskIo    v1.DiskIoStats `json:"diskio,omitempty"`
@@ -92,6 +165,23 @@ type ContainerStats struct {
 	// Task load statistics
 	HasLoad bool         `json:"has_load"`
 	Load    v1.LoadStats `json:"load_stats,omitempty"`
+	// Pressure stall information, only set when the kernel supports it.
+	PSI *v1.PSIStats `json:"psi,omitempty"`
+	// GPUs used by the container, only set on hosts with NVIDIA devices.
+	Accelerators []v1.AcceleratorStats `json:"accelerators,omitempty"`
+	// Custom metrics exported by applications in the container, by metric name.
+	CustomMetrics map[string][]v1.MetricVal `json:"custom_metrics,omitempty"`
+	// Processes of the container and their open file descriptors.
+	Processes *v1.ProcessStats `json:"processes,omitempty"`
+	// Tasks of the pids cgroup of the container and their limit.
+	Pids *v1.PidsStats `json:"pids,omitempty"`
+}
+
+type CpuStats struct {
+	v1.CpuStats
+	// CPU usage since the previous sample, in fractional cores. Only set for
+	// samples following another one.
+	CpuCores float64 `json:"cpu_cores,omitempty"`
 }
 
 type Percentiles struct {
@@ -102,8 +192,14 @@ type Percentiles struct {
 	Mean uint64 `json:"mean"`
 	// Max seen over the collected sample.
 	Max uint64 `json:"max"`
+	// 50th percentile over the collected sample.
+	Fifty uint64 `json:"fifty"`
 	// 90th percentile over the collected sample.
 	Ninety uint64 `json:"ninety"`
+	// 95th percentile over the collected sample.
+	NinetyFive uint64 `json:"ninetyfive"`
+	// 99th percentile over the collected sample.
+	NinetyNine uint64 `json:"ninetynine"`
 }
 
 type Usage struct {
@@ -111,9 +207,9 @@ type Usage struct {
 	// If we have data for half a day, we'll still process DayUsage,
 	// but set PercentComplete to 50.
 	PercentComplete int32 `json:"percent_complete"`
-	// Mean, Max, and 90p cpu rate value in milliCpus/seconds. Converted to milliCpus to avoid floats.
+	// Mean, Max, and 50p, 90p, 95p and 99p cpu rate value in milliCpus/seconds. Converted to milliCpus to avoid floats.
 	Cpu Percentiles `json:"cpu"`
-	// Mean, Max, and 90p memory size in bytes.
+	// Mean, Max, and 50p, 90p, 95p and 99p memory size in bytes.
 	Memory Percentiles `json:"memory"`
 }
 
@@ -136,6 +232,20 @@ type DerivedStats struct {
 	HourUsage Usage `json:"hour_usage"`
 	// Percentile in last day.
 	DayUsage Usage `json:"day_usage"`
+	// Capacity left before the latest usage hits the limits.
+	Headroom Headroom `json:"headroom"`
+}
+
+// Capacity left before a container hits its limits, as percentages of the
+// limits between 0 and 100. Nil for the resources which are not tracked or
+// whose limit is unknown.
+type Headroom struct {
+	// CPU limit left by the latest cpu rate. The limit is the CPU quota of
+	// the container, or the number of cores it can run on without quota.
+	Cpu *float64 `json:"cpu,omitempty"`
+	// Memory limit left by the latest memory usage. Containers without memory
+	// limit are limited by the memory capacity of the machine.
+	Memory *float64 `json:"memory,omitempty"`
 }
 
 type FsInfo struct {
@@ -155,6 +265,173 @@ type FsInfo struct {
 	Labels []string `json:"labels"`
 }
 
+// Share of the resources of the machine used by a container, as fractions of
+// the machine totals between 0 and 1.
+type ContainerShare struct {
+	// Time of the most recent stats sample.
+	Timestamp time.Time `json:"timestamp"`
+
+	// CPU time used per second between the two most recent samples, divided
+	// by the number of cores of the machine.
+	Cpu float64 `json:"cpu"`
+	// Memory usage and working set, divided by the memory capacity of the
+	// machine.
+	Memory           float64 `json:"memory"`
+	MemoryWorkingSet float64 `json:"memory_working_set"`
+}
+
+// Per-second rates computed from two consecutive stats samples of a container.
+type ContainerRates struct {
+	// Time of the most recent of the two samples.
+	Timestamp time.Time `json:"timestamp"`
+	// Time elapsed between the two samples, in nanoseconds.
+	Interval time.Duration `json:"interval_ns"`
+
+	// CPU time used per second, in cores.
+	CpuUsage  float64 `json:"cpu_usage_cores"`
+	CpuUser   float64 `json:"cpu_user_cores"`
+	CpuSystem float64 `json:"cpu_system_cores"`
+	// Fraction of the CFS enforcement periods in which the container was
+	// throttled, 0 for containers without a CPU quota.
+	CpuThrottledFraction float64 `json:"cpu_throttled_fraction"`
+
+	RxBytes   float64 `json:"rx_bytes_per_second"`
+	RxPackets float64 `json:"rx_packets_per_second"`
+	TxBytes   float64 `json:"tx_bytes_per_second"`
+	TxPackets float64 `json:"tx_packets_per_second"`
+
+	// Summed over all the disks.
+	DiskReadBytes  float64 `json:"disk_read_bytes_per_second"`
+	DiskWriteBytes float64 `json:"disk_write_bytes_per_second"`
+	DiskReads      float64 `json:"disk_reads_per_second"`
+	DiskWrites     float64 `json:"disk_writes_per_second"`
+}
+
+// Metrics containers can be ranked by.
+const (
+	// CPU time used per second, in cores.
+	TopByCpu = "cpu"
+	// Memory working set, in bytes.
+	TopByMemory = "memory"
+	// Bytes received per second.
+	TopByNetworkRx = "network_rx"
+	// Bytes read and written per second, summed over all the disks.
+	TopByDiskIo = "diskio"
+)
+
+// A container ranked by a metric of its latest stats.
+type TopContainer struct {
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	// Time of the latest stats sample the value was computed from.
+	Timestamp time.Time `json:"timestamp"`
+	// Value of the metric the container was ranked by.
+	Value float64 `json:"value"`
+}
+
+// Resources used by the running containers of an image.
+type ImageUsage struct {
+	Image string `json:"image"`
+	// Number of containers of the image with stats.
+	Containers int `json:"containers"`
+	// CPU time used per second between the two latest stats samples of
+	// each container, in cores. Containers with a single sample are left out.
+	Cpu float64 `json:"cpu"`
+	// Memory usage and working set summed over the latest stats sample of
+	// each container, in bytes.
+	Memory           uint64 `json:"memory"`
+	MemoryWorkingSet uint64 `json:"memory_working_set"`
+}
+
+const (
+	TopologyAdded   = "added"
+	TopologyRemoved = "removed"
+)
+
+// A container added to or removed from the containers cAdvisor tracks.
+type TopologyChange struct {
+	// TopologyAdded or TopologyRemoved.
+	Type string `json:"type"`
+	// Absolute name of the container.
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	// Name of the closest tracked container holding this one, empty for
+	// the root container.
+	Parent    string    `json:"parent,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// A process running in a container.
+type ProcessInfo struct {
+	Pid  int    `json:"pid"`
+	Ppid int    `json:"ppid"`
+	User string `json:"user"`
+	// Command line of the process, or its name for kernel threads.
+	Command string `json:"command"`
+	// CPU usage over a short sampling window, in percent of one core.
+	CpuPercent float64 `json:"cpu_percent"`
+	// Resident set size in bytes.
+	Rss uint64 `json:"rss"`
+}
+
+// Containers whose stats are requested in a single batch request.
+type BatchRequest struct {
+	ContainerNames []string `json:"container_names"`
+}
+
+// Result of a batch request for one container: its stats or why they could not be retrieved.
+type BatchStats struct {
+	Stats []ContainerStats `json:"stats,omitempty"`
+	Error string           `json:"error,omitempty"`
+}
+
+// Result of deleting events.
+type DeletedEvents struct {
+	// Number of events deleted.
+	Count int `json:"count"`
+}
+
+// Interval between housekeepings of a container.
+type HousekeepingInterval struct {
+	// Interval in milliseconds. Zero stands for the global housekeeping interval.
+	IntervalMs int64 `json:"interval_ms"`
+}
+
+// Collection status of the stats of a subsystem (e.g. "network") across all containers.
+type SubsystemHealth struct {
+	// Last time the stats of the subsystem were collected for a container.
+	LastSuccessTime time.Time `json:"last_success_time,omitempty"`
+	// Number of times collecting the stats of the subsystem of a container failed.
+	ErrorCount uint64 `json:"error_count"`
+	// Time and description, including the container, of the last failure.
+	LastErrorTime time.Time `json:"last_error_time,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	// Number of stats samples dropped, by storage backends which are not
+	// written to while they are unhealthy.
+	DroppedCount uint64 `json:"dropped_count,omitempty"`
+}
+
+// A container along with its cgroups, as resolved from its cgroup path or one
+// of its names.
+type ResolvedContainer struct {
+	// Absolute name of the container, which is also the path of its cgroups
+	// relative to the root of the cgroup hierarchies.
+	Name string `json:"name"`
+	// Other names by which the container is known within its namespace.
+	Aliases []string `json:"aliases,omitempty"`
+	// Namespace under which the aliases of the container are unique.
+	Namespace string `json:"namespace,omitempty"`
+	// Image the container was started from, if known to its runtime.
+	Image string `json:"image,omitempty"`
+	// Labels of the container.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Absolute path of the cgroup of the container in each hierarchy, e.g.
+	// "memory" -> "/sys/fs/cgroup/memory/docker/<id>".
+	CgroupPaths map[string]string `json:"cgroup_paths,omitempty"`
+}
+
 type RequestOptions struct {
 	// Type of container identifier specified - "name", "dockerid", dockeralias"
 	IdType string `json:"type"`
@@ -162,4 +439,7 @@ type RequestOptions struct {
 	Count int `json:"count"`
 	// Whether to include stats for child subcontainers.
 	Recursive bool `json:"recursive"`
+	// Number of levels of subcontainers below the container included by
+	// recursive requests, all of them if 0.
+	Depth int `json:"depth,omitempty"`
 }
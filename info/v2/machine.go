@@ -59,6 +59,16 @@ type Attributes struct {
 	// Machine Topology
 	// Describes cpu/memory layout and hierarchy.
 	Topology []v1.Node `json:"topology"`
+
+	// Names of the container handlers detected on this machine (e.g.: "docker", "raw").
+	ContainerHandlers []string `json:"container_handlers,omitempty"`
+
+	// The cloud the machine runs in and the type, region and availability
+	// zone of its instance, when detected.
+	CloudProvider string `json:"cloud_provider,omitempty"`
+	InstanceType  string `json:"instance_type,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Zone          string `json:"zone,omitempty"`
 }
 
 func GetAttributes(mi *v1.MachineInfo, vi *v1.VersionInfo) Attributes {
@@ -76,5 +86,9 @@ func GetAttributes(mi *v1.MachineInfo, vi *v1.VersionInfo) Attributes {
 		DiskMap:            mi.DiskMap,
 		NetworkDevices:     mi.NetworkDevices,
 		Topology:           mi.Topology,
+		CloudProvider:      mi.CloudProvider,
+		InstanceType:       mi.InstanceType,
+		Region:             mi.Region,
+		Zone:               mi.Zone,
 	}
 }
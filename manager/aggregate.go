@@ -0,0 +1,140 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/google/cadvisor/container"
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// A container whose subtree is aggregated, see GetAggregatedContainerInfo.
+type aggregatedContainer struct {
+	name  string
+	stats []*info.ContainerStats
+	// Whether the container has network stats.
+	hasNetwork bool
+	// Link of the network namespace of the container, e.g. "net:[4026531993]",
+	// empty if unknown.
+	networkNamespace string
+}
+
+// Returns the network stats to sum over the containers of a subtree: the
+// ones of a single container of each network namespace, as the containers of
+// a pod share the namespace of its pause container, and the ones of the
+// leaves among the containers whose namespace is unknown.
+func networkStatsToSum(containers []aggregatedContainer) [][]*info.ContainerStats {
+	parents := make(map[string]bool, len(containers))
+	for _, cont := range containers {
+		if cont.name == "/" {
+			continue
+		}
+		for p := path.Dir(cont.name); ; p = path.Dir(p) {
+			parents[p] = true
+			if p == "/" || p == "." {
+				break
+			}
+		}
+	}
+	// Sorted so that the same container of each namespace is picked.
+	sort.Sort(byAggregatedName(containers))
+	namespaces := make(map[string]bool)
+	var ret [][]*info.ContainerStats
+	for _, cont := range containers {
+		if !cont.hasNetwork {
+			continue
+		}
+		if cont.networkNamespace != "" {
+			if namespaces[cont.networkNamespace] {
+				continue
+			}
+			namespaces[cont.networkNamespace] = true
+		} else if parents[cont.name] {
+			continue
+		}
+		ret = append(ret, cont.stats)
+	}
+	return ret
+}
+
+type byAggregatedName []aggregatedContainer
+
+func (s byAggregatedName) Len() int           { return len(s) }
+func (s byAggregatedName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byAggregatedName) Less(i, j int) bool { return s[i].name < s[j].name }
+
+// Returns the link of the network namespace of the processes of a container,
+// empty if it has none or the namespace cannot be read.
+func getNetworkNamespace(cont *containerData) string {
+	pids, err := cont.handler.ListProcesses(container.ListSelf)
+	if err != nil || len(pids) == 0 {
+		return ""
+	}
+	ns, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pids[0]))
+	if err != nil {
+		return ""
+	}
+	return ns
+}
+
+// Returns the samples of a container with its CPU and memory stats, which the
+// cgroups account hierarchically so they already include the subcontainers,
+// and the network stats summed over networks. Each sample of the container is
+// summed with the latest sample of each network taken at or before it,
+// networks without such a sample are left out of it. Other stats are dropped
+// as they do not add up.
+//
+// The returned samples are copies, the given ones are left unchanged.
+func aggregateStats(stats []*info.ContainerStats, networks [][]*info.ContainerStats) []*info.ContainerStats {
+	ret := make([]*info.ContainerStats, 0, len(stats))
+	// Index of the next sample of each network to consider, their samples
+	// are sorted from oldest to newest like the ones of the container.
+	next := make([]int, len(networks))
+	for _, s := range stats {
+		sum := &info.ContainerStats{
+			Timestamp:  s.Timestamp,
+			Resolution: s.Resolution,
+			Cpu:        s.Cpu,
+			Memory:     s.Memory,
+		}
+		sum.Cpu.Usage.PerCpu = append([]uint64(nil), s.Cpu.Usage.PerCpu...)
+		for i, networkStats := range networks {
+			for next[i] < len(networkStats) && !networkStats[next[i]].Timestamp.After(s.Timestamp) {
+				next[i]++
+			}
+			if next[i] > 0 {
+				addNetworkStats(sum, networkStats[next[i]-1])
+			}
+		}
+		ret = append(ret, sum)
+	}
+	return ret
+}
+
+// Adds the network stats of a sample to the ones of sum.
+func addNetworkStats(sum, s *info.ContainerStats) {
+	sum.Network.RxBytes += s.Network.RxBytes
+	sum.Network.RxPackets += s.Network.RxPackets
+	sum.Network.RxErrors += s.Network.RxErrors
+	sum.Network.RxDropped += s.Network.RxDropped
+	sum.Network.TxBytes += s.Network.TxBytes
+	sum.Network.TxPackets += s.Network.TxPackets
+	sum.Network.TxErrors += s.Network.TxErrors
+	sum.Network.TxDropped += s.Network.TxDropped
+}
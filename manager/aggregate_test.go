@@ -0,0 +1,89 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func sample(seconds int64, cpu uint64, perCpu []uint64, memory, rxBytes uint64) *info.ContainerStats {
+	s := &info.ContainerStats{Timestamp: time.Unix(seconds, 0)}
+	s.Cpu.Usage.Total = cpu
+	s.Cpu.Usage.PerCpu = perCpu
+	s.Memory.Usage = memory
+	s.Network.RxBytes = rxBytes
+	s.Network.Interfaces = []info.InterfaceStats{{Name: "eth0", RxBytes: rxBytes}}
+	return s
+}
+
+func TestAggregateStats(t *testing.T) {
+	stats := []*info.ContainerStats{
+		sample(10, 1, []uint64{1}, 100, 0),
+		sample(20, 2, []uint64{2}, 100, 0),
+	}
+	networks := [][]*info.ContainerStats{
+		// Housekept slightly after the container.
+		{sample(5, 10, []uint64{4, 6}, 1000, 7), sample(11, 20, []uint64{8, 12}, 2000, 8), sample(21, 30, nil, 3000, 9)},
+		// Started between the two samples.
+		{sample(15, 100, []uint64{100}, 10000, 70)},
+		{},
+	}
+
+	aggregated := aggregateStats(stats, networks)
+	assert.Equal(t, 2, len(aggregated))
+
+	// The CPU and memory usage of the cgroup already include its children.
+	assert.Equal(t, time.Unix(10, 0), aggregated[0].Timestamp)
+	assert.Equal(t, uint64(1), aggregated[0].Cpu.Usage.Total)
+	assert.Equal(t, []uint64{1}, aggregated[0].Cpu.Usage.PerCpu)
+	assert.Equal(t, uint64(100), aggregated[0].Memory.Usage)
+	assert.Equal(t, uint64(7), aggregated[0].Network.RxBytes)
+	assert.Nil(t, aggregated[0].Network.Interfaces)
+
+	assert.Equal(t, time.Unix(20, 0), aggregated[1].Timestamp)
+	assert.Equal(t, uint64(2), aggregated[1].Cpu.Usage.Total)
+	assert.Equal(t, uint64(100), aggregated[1].Memory.Usage)
+	assert.Equal(t, uint64(78), aggregated[1].Network.RxBytes)
+
+	// The stats of the container are left unchanged.
+	aggregated[0].Cpu.Usage.PerCpu[0] = 5
+	assert.Equal(t, []uint64{1}, stats[0].Cpu.Usage.PerCpu)
+}
+
+func TestNetworkStatsToSum(t *testing.T) {
+	stats := func(rxBytes uint64) []*info.ContainerStats {
+		return []*info.ContainerStats{sample(10, 0, nil, 0, rxBytes)}
+	}
+	containers := []aggregatedContainer{
+		{name: "/kubepods", stats: stats(1000), hasNetwork: true, networkNamespace: "net:[1]"},
+		// The containers of a pod share the namespace of its pause container.
+		{name: "/kubepods/pod1/pause", stats: stats(10), hasNetwork: true, networkNamespace: "net:[2]"},
+		{name: "/kubepods/pod1/app", stats: stats(10), hasNetwork: true, networkNamespace: "net:[2]"},
+		{name: "/kubepods/pod1", stats: stats(0)},
+		{name: "/kubepods/pod2/app", stats: stats(20), hasNetwork: true, networkNamespace: "net:[3]"},
+		// Without a known namespace, only leaves are counted.
+		{name: "/kubepods/pod3", stats: stats(300), hasNetwork: true},
+		{name: "/kubepods/pod3/app", stats: stats(30), hasNetwork: true},
+	}
+	var rxBytes []uint64
+	for _, s := range networkStatsToSum(containers) {
+		rxBytes = append(rxBytes, s[0].Network.RxBytes)
+	}
+	assert.Equal(t, []uint64{1000, 10, 20, 30}, rxBytes)
+}
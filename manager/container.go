@@ -24,11 +24,13 @@ import (
 
 	"github.com/docker/docker/pkg/units"
 	"github.com/golang/glog"
+	"github.com/google/cadvisor/collector"
 	"github.com/google/cadvisor/container"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/google/cadvisor/info/v2"
 	"github.com/google/cadvisor/storage/memory"
 	"github.com/google/cadvisor/summary"
+	"github.com/google/cadvisor/utils/accelerators"
 	"github.com/google/cadvisor/utils/cpuload"
 )
 
@@ -36,9 +38,13 @@ import (
 var HousekeepingInterval = flag.Duration("housekeeping_interval", 1*time.Second, "Interval between container housekeepings")
 var maxHousekeepingInterval = flag.Duration("max_housekeeping_interval", 60*time.Second, "Largest interval to allow between container housekeepings")
 var allowDynamicHousekeeping = flag.Bool("allow_dynamic_housekeeping", true, "Whether to allow the housekeeping interval to be dynamic")
+var networkHousekeepingFactor = flag.Int("network_housekeeping_factor", 1, "Collect the network stats of containers every this many housekeepings, the last ones collected are reported in between")
 
-// Decay value used for load average smoothing. Interval length of 10 seconds is used.
-var loadDecay = math.Exp(float64(-1 * (*HousekeepingInterval).Seconds() / 10))
+// Smallest housekeeping interval that can be set for a container through the API.
+const minHousekeepingInterval = 100 * time.Millisecond
+
+// Time constant used for load average smoothing.
+const loadSmoothingPeriod = 10 * time.Second
 
 type containerInfo struct {
 	info.ContainerReference
@@ -52,17 +58,49 @@ type containerData struct {
 	memoryStorage        *memory.InMemoryStorage
 	lock                 sync.Mutex
 	loadReader           cpuload.CpuLoadReader
+	nvidiaManager        *accelerators.NvidiaManager
+	health               *collectionHealth
 	summaryReader        *summary.StatsSummary
 	loadAvg              float64 // smoothed load average seen so far.
+	lastLoadSample       time.Time
+	diskLatency          diskLatencyTracker
 	housekeepingInterval time.Duration
 	lastUpdatedTime      time.Time
+	lastSpecUpdatedTime  time.Time
 	lastErrorTime        time.Time
 
+	// Interval housekeeping starts from and goes back to when dynamic
+	// housekeeping is enabled. Guarded by lock.
+	baseHousekeepingInterval time.Duration
+	// Whether baseHousekeepingInterval changed since the last housekeeping.
+	// Guarded by lock.
+	baseHousekeepingIntervalChanged bool
+
 	// Whether to log the usage of this container when it is updated.
 	logUsage bool
 
+	// Number of stats collections so far and the network stats of the last
+	// one which collected them.
+	statsCollections int
+	lastNetwork      info.NetworkStats
+
+	// Collectors of the custom metrics of the applications in the container,
+	// nil if there are none. Set before housekeeping starts.
+	collectorManager collector.CollectorManager
+	// Latest values of the custom metrics, by metric name.
+	customMetrics map[string][]info.MetricVal
+
+	// Channels the stats of the container are streamed through as they are
+	// collected, by watch ID. Guarded by lock, nil once housekeeping stopped.
+	statsWatchers map[int]chan *info.ContainerInfo
+
+	// Annotations set through the API, reported in the spec. Guarded by lock.
+	annotations map[string]string
+
 	// Tells the container to stop.
 	stop chan bool
+	// Closed once housekeeping stopped.
+	stopped chan struct{}
 }
 
 func (c *containerData) Start() error {
@@ -102,6 +140,22 @@ func (c *containerData) GetInfo() (*containerInfo, error) {
 	return &c.info, nil
 }
 
+// Returns the info of the container with its spec updated as by GetInfo, but
+// without listing its subcontainers again, so that the specs of all the
+// containers can be gathered without walking their cgroups.
+func (c *containerData) GetSpecInfo() (*containerInfo, error) {
+	if time.Since(c.lastUpdatedTime) > 5*time.Second && time.Since(c.lastSpecUpdatedTime) > 5*time.Second {
+		err := c.updateSpec()
+		if err != nil {
+			return nil, err
+		}
+		c.lastSpecUpdatedTime = time.Now()
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return &c.info, nil
+}
+
 func (c *containerData) DerivedStats() (v2.DerivedStats, error) {
 	if c.summaryReader == nil {
 		return v2.DerivedStats{}, fmt.Errorf("derived stats not enabled for container %q", c.info.Name)
@@ -109,7 +163,7 @@ func (c *containerData) DerivedStats() (v2.DerivedStats, error) {
 	return c.summaryReader.DerivedStats()
 }
 
-func newContainerData(containerName string, memoryStorage *memory.InMemoryStorage, handler container.ContainerHandler, loadReader cpuload.CpuLoadReader, logUsage bool) (*containerData, error) {
+func newContainerData(containerName string, memoryStorage *memory.InMemoryStorage, handler container.ContainerHandler, loadReader cpuload.CpuLoadReader, nvidiaManager *accelerators.NvidiaManager, health *collectionHealth, logUsage bool) (*containerData, error) {
 	if memoryStorage == nil {
 		return nil, fmt.Errorf("nil memory storage")
 	}
@@ -126,10 +180,15 @@ func newContainerData(containerName string, memoryStorage *memory.InMemoryStorag
 		memoryStorage:        memoryStorage,
 		housekeepingInterval: *HousekeepingInterval,
 		loadReader:           loadReader,
+		nvidiaManager:        nvidiaManager,
+		health:               health,
 		logUsage:             logUsage,
 		loadAvg:              -1.0, // negative value indicates uninitialized.
+		statsWatchers:        make(map[int]chan *info.ContainerInfo),
 		stop:                 make(chan bool, 1),
+		stopped:              make(chan struct{}),
 	}
+	cont.baseHousekeepingInterval = cont.housekeepingInterval
 	cont.info.ContainerReference = ref
 
 	err = cont.updateSpec()
@@ -145,9 +204,55 @@ func newContainerData(containerName string, memoryStorage *memory.InMemoryStorag
 	return cont, nil
 }
 
+// Sets the interval between housekeepings of the container, it is clamped to
+// minHousekeepingInterval. A zero interval goes back to the global one.
+// Returns the interval that was set.
+func (self *containerData) SetHousekeepingInterval(interval time.Duration) time.Duration {
+	if interval == 0 {
+		interval = *HousekeepingInterval
+	} else if interval < minHousekeepingInterval {
+		interval = minHousekeepingInterval
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.baseHousekeepingInterval = interval
+	self.baseHousekeepingIntervalChanged = true
+	return interval
+}
+
+// Sets the annotations reported in the spec of the container, replacing the
+// ones set before. An empty map removes them.
+func (self *containerData) SetAnnotations(annotations map[string]string) {
+	var copied map[string]string
+	if len(annotations) > 0 {
+		copied = make(map[string]string, len(annotations))
+		for k, v := range annotations {
+			copied[k] = v
+		}
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.annotations = copied
+	self.info.Spec.Annotations = copied
+}
+
+// Returns the base housekeeping interval and whether it changed since the last call.
+func (self *containerData) getBaseHousekeepingInterval() (time.Duration, bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	changed := self.baseHousekeepingIntervalChanged
+	self.baseHousekeepingIntervalChanged = false
+	return self.baseHousekeepingInterval, changed
+}
+
 // Determine when the next housekeeping should occur.
 func (self *containerData) nextHousekeeping(lastHousekeeping time.Time) time.Time {
-	if *allowDynamicHousekeeping {
+	baseInterval, changed := self.getBaseHousekeepingInterval()
+	if changed {
+		// Use a new interval right away, regardless of the dynamic housekeeping state.
+		self.housekeepingInterval = baseInterval
+		glog.V(3).Infof("Setting housekeeping interval for %q to %v", self.info.Name, self.housekeepingInterval)
+	} else if *allowDynamicHousekeeping {
 		var empty time.Time
 		stats, err := self.memoryStorage.RecentStats(self.info.Name, empty, empty, 2)
 		if err != nil {
@@ -163,9 +268,9 @@ func (self *containerData) nextHousekeeping(lastHousekeeping time.Time) time.Tim
 					self.housekeepingInterval = *maxHousekeepingInterval
 				}
 				glog.V(3).Infof("Raising housekeeping interval for %q to %v", self.info.Name, self.housekeepingInterval)
-			} else if self.housekeepingInterval != *HousekeepingInterval {
+			} else if self.housekeepingInterval != baseInterval {
 				// Lower interval back to the baseline.
-				self.housekeepingInterval = *HousekeepingInterval
+				self.housekeepingInterval = baseInterval
 				glog.V(3).Infof("Lowering housekeeping interval for %q to %v", self.info.Name, self.housekeepingInterval)
 			}
 		}
@@ -188,6 +293,8 @@ func (c *containerData) housekeeping() {
 		select {
 		case <-c.stop:
 			// Stop housekeeping when signaled.
+			c.closeStatsWatchers()
+			close(c.stopped)
 			return
 		default:
 			// Perform housekeeping.
@@ -257,30 +364,104 @@ func (c *containerData) updateSpec() error {
 	}
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	if c.collectorManager != nil {
+		spec.CustomMetrics = c.collectorManager.GetSpec()
+	}
+	spec.Annotations = c.annotations
 	c.info.Spec = spec
 	return nil
 }
 
-// Calculate new smoothed load average using the new sample of runnable threads.
-// The decay used ensures that the load will stabilize on a new constant value within
-// 10 seconds.
-func (c *containerData) updateLoad(newLoad uint64) {
+// Calculate new smoothed load average using the new sample of runnable and
+// uninterruptible threads taken at sampleTime. The decay depends on the time
+// elapsed since the previous sample so the load stabilizes on a new constant
+// value within 10 seconds regardless of the (possibly dynamic) housekeeping interval.
+func (c *containerData) updateLoad(newLoad uint64, sampleTime time.Time) {
 	if c.loadAvg < 0 {
 		c.loadAvg = float64(newLoad) // initialize to the first seen sample for faster stabilization.
 	} else {
-		c.loadAvg = c.loadAvg*loadDecay + float64(newLoad)*(1.0-loadDecay)
+		elapsed := sampleTime.Sub(c.lastLoadSample)
+		decay := math.Exp(-elapsed.Seconds() / loadSmoothingPeriod.Seconds())
+		c.loadAvg = c.loadAvg*decay + float64(newLoad)*(1.0-decay)
 	}
+	c.lastLoadSample = sampleTime
 	glog.V(3).Infof("New load for %q: %v. latest sample: %d", c.info.Name, c.loadAvg, newLoad)
 }
 
+// Sets the stats of the GPUs used by the container. Failures are not fatal
+// to the other stats.
+func (c *containerData) updateAccelerators(stats *info.ContainerStats) {
+	// The device cgroup may not be mounted, GPUs are then only found through processes.
+	devicesPath, _ := c.handler.GetCgroupPath("devices")
+	pids, err := c.handler.ListProcesses(container.ListSelf)
+	if err != nil {
+		glog.V(4).Infof("Failed to list processes of %q for GPU stats: %v", c.info.Name, err)
+	}
+	stats.Accelerators, err = c.nvidiaManager.GetStats(devicesPath, pids)
+	if err != nil {
+		glog.V(2).Infof("Failed to get GPU stats of %q: %v", c.info.Name, err)
+	}
+}
+
+// Collects the custom metrics that are due and sets the latest values of all
+// of them in stats. Failures are not fatal to the other stats.
+func (c *containerData) updateCustomStats(stats *info.ContainerStats) {
+	_, metrics, err := c.collectorManager.Collect()
+	if err != nil && c.allowErrorLogging() {
+		glog.Warningf("Failed to collect the custom metrics of %q: %v", c.info.Name, err)
+	}
+	if c.customMetrics == nil {
+		c.customMetrics = make(map[string][]info.MetricVal)
+	}
+	for name, values := range metrics {
+		c.customMetrics[name] = values
+	}
+	// The stored stats keep their own map since it is updated by later collections.
+	stats.CustomMetrics = make(map[string][]info.MetricVal, len(c.customMetrics))
+	for name, values := range c.customMetrics {
+		stats.CustomMetrics[name] = values
+	}
+}
+
+// Collects the stats of the container. The network stats are only collected
+// every *networkHousekeepingFactor collections by the handlers which can leave
+// them out, the last ones collected are reported in between.
+func (c *containerData) getStats() (*info.ContainerStats, error) {
+	partial, ok := c.handler.(container.PartialStatsHandler)
+	if !ok || *networkHousekeepingFactor <= 1 {
+		return c.handler.GetStats()
+	}
+	collectNetwork := c.statsCollections%*networkHousekeepingFactor == 0
+	c.statsCollections++
+	if collectNetwork {
+		stats, err := c.handler.GetStats()
+		if stats != nil {
+			c.lastNetwork = stats.Network
+		}
+		return stats, err
+	}
+	stats, err := partial.GetPartialStats(container.NetworkMetrics)
+	if stats != nil {
+		stats.Network = c.lastNetwork
+	}
+	return stats, err
+}
+
 func (c *containerData) updateStats() error {
-	stats, statsErr := c.handler.GetStats()
+	stats, statsErr := c.getStats()
 	if statsErr != nil {
 		// Ignore errors if the container is dead.
 		if !c.handler.Exists() {
 			return nil
 		}
-
+	}
+	if c.health != nil {
+		c.lock.Lock()
+		spec := c.info.Spec
+		c.lock.Unlock()
+		c.health.record(c.info.Name, &spec, statsErr, time.Now())
+	}
+	if statsErr != nil {
 		// Stats may be partially populated, push those before we return an error.
 		statsErr = fmt.Errorf("%v, continuing to push stats", statsErr)
 	}
@@ -296,11 +477,18 @@ func (c *containerData) updateStats() error {
 				return fmt.Errorf("failed to get load stat for %q - path %q, error %s", c.info.Name, path, err)
 			}
 			stats.TaskStats = loadStats
-			c.updateLoad(loadStats.NrRunning)
+			c.updateLoad(loadStats.NrRunning+loadStats.NrUninterruptible, stats.Timestamp)
 			// convert to 'milliLoad' to avoid floats and preserve precision.
 			stats.Cpu.LoadAverage = int32(c.loadAvg * 1000)
 		}
 	}
+	if c.nvidiaManager != nil {
+		c.updateAccelerators(stats)
+	}
+	if c.collectorManager != nil {
+		c.updateCustomStats(stats)
+	}
+	c.diskLatency.update(&stats.DiskIo)
 	if c.summaryReader != nil {
 		err := c.summaryReader.AddSample(*stats)
 		if err != nil {
@@ -320,6 +508,7 @@ func (c *containerData) updateStats() error {
 	if err != nil {
 		return err
 	}
+	c.notifyStatsWatchers(stats)
 	return statsErr
 }
 
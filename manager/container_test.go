@@ -18,6 +18,7 @@ package manager
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -40,7 +41,7 @@ func setupContainerData(t *testing.T, spec info.ContainerSpec) (*containerData,
 		nil,
 	)
 	memoryStorage := memory.New(60, nil)
-	ret, err := newContainerData(containerName, memoryStorage, mockHandler, nil, false)
+	ret, err := newContainerData(containerName, memoryStorage, mockHandler, nil, nil, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -141,6 +142,24 @@ func TestUpdateStats(t *testing.T) {
 	mockHandler.AssertExpectations(t)
 }
 
+func TestGetStatsNetworkHousekeepingFactor(t *testing.T) {
+	defer func(factor int) { *networkHousekeepingFactor = factor }(*networkHousekeepingFactor)
+	*networkHousekeepingFactor = 3
+
+	cd, mockHandler, _ := newTestContainerData(t)
+	mockHandler.On("GetStats").Return(&info.ContainerStats{Network: info.NetworkStats{RxBytes: 100}}, nil)
+	mockHandler.On("GetPartialStats", container.NetworkMetrics).Return(&info.ContainerStats{}, nil)
+
+	// The network stats collected first are reported until the next collection.
+	for i := 0; i < 4; i++ {
+		stats, err := cd.getStats()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), stats.Network.RxBytes)
+	}
+	mockHandler.AssertNumberOfCalls(t, "GetStats", 2)
+	mockHandler.AssertNumberOfCalls(t, "GetPartialStats", 2)
+}
+
 func TestUpdateSpec(t *testing.T) {
 	spec := itest.GenerateRandomContainerSpec(4)
 	cd, mockHandler, _ := newTestContainerData(t)
@@ -202,3 +221,98 @@ func TestGetInfo(t *testing.T) {
 		t.Errorf("received wrong container name: received %v; should be %v", info.Name, mockHandler.Name)
 	}
 }
+
+func TestUpdateLoad(t *testing.T) {
+	cd, _, _ := newTestContainerData(t)
+	start := time.Now()
+
+	// The first sample initializes the average.
+	cd.updateLoad(4, start)
+	assert.Equal(t, 4.0, cd.loadAvg)
+
+	// A sample after the smoothing period moves the average 1-1/e of the way.
+	cd.updateLoad(0, start.Add(loadSmoothingPeriod))
+	assert.InDelta(t, 4.0/math.E, cd.loadAvg, 0.0001)
+
+	// A sample with no elapsed time does not change the average.
+	cd.updateLoad(100, start.Add(loadSmoothingPeriod))
+	assert.InDelta(t, 4.0/math.E, cd.loadAvg, 0.0001)
+}
+
+func TestSetHousekeepingInterval(t *testing.T) {
+	cd, _, _ := newTestContainerData(t)
+	start := time.Now()
+
+	assert.Equal(t, 2*time.Second, cd.SetHousekeepingInterval(2*time.Second))
+	assert.Equal(t, start.Add(2*time.Second), cd.nextHousekeeping(start))
+
+	// Intervals are clamped to the minimum.
+	assert.Equal(t, minHousekeepingInterval, cd.SetHousekeepingInterval(time.Millisecond))
+	assert.Equal(t, start.Add(minHousekeepingInterval), cd.nextHousekeeping(start))
+
+	// A zero interval goes back to the global one.
+	assert.Equal(t, *HousekeepingInterval, cd.SetHousekeepingInterval(0))
+	assert.Equal(t, start.Add(*HousekeepingInterval), cd.nextHousekeeping(start))
+}
+
+func TestSetAnnotations(t *testing.T) {
+	cd, _, _ := newTestContainerData(t)
+	annotations := map[string]string{"version": "1.2"}
+	cd.SetAnnotations(annotations)
+	annotations["version"] = "changed"
+	assert.Equal(t, map[string]string{"version": "1.2"}, cd.info.Spec.Annotations)
+
+	// They are kept when the spec is read again.
+	require.Nil(t, cd.updateSpec())
+	assert.Equal(t, map[string]string{"version": "1.2"}, cd.info.Spec.Annotations)
+
+	cd.SetAnnotations(map[string]string{})
+	require.Nil(t, cd.updateSpec())
+	assert.Nil(t, cd.info.Spec.Annotations)
+}
+
+func TestStatsWatchers(t *testing.T) {
+	stats := itest.GenerateRandomStats(1, 4, 1*time.Second)[0]
+	cd, mockHandler, _ := newTestContainerData(t)
+	mockHandler.On("GetStats").Return(stats, nil)
+
+	watched := make(chan *info.ContainerInfo, 1)
+	removed := make(chan *info.ContainerInfo, 1)
+	assert.True(t, cd.addStatsWatcher(1, watched))
+	assert.True(t, cd.addStatsWatcher(2, removed))
+	cd.removeStatsWatcher(2)
+	_, ok := <-removed
+	assert.False(t, ok)
+
+	require.Nil(t, cd.updateStats())
+	cinfo := <-watched
+	assert.Equal(t, containerName, cinfo.Name)
+	assert.Equal(t, []*info.ContainerStats{stats}, cinfo.Stats)
+
+	// Stats are dropped rather than blocking on a slow watcher.
+	require.Nil(t, cd.updateStats())
+	require.Nil(t, cd.updateStats())
+
+	// Channels are closed when housekeeping stops.
+	cd.closeStatsWatchers()
+	<-watched
+	_, ok = <-watched
+	assert.False(t, ok)
+	assert.False(t, cd.addStatsWatcher(3, make(chan *info.ContainerInfo)))
+}
+
+func TestGetSpecInfo(t *testing.T) {
+	spec := itest.GenerateRandomContainerSpec(4)
+	cd, mockHandler, _ := setupContainerData(t, spec)
+
+	// The subcontainers are not listed.
+	cinfo, err := cd.GetSpecInfo()
+	require.Nil(t, err)
+	assert.Equal(t, spec, cinfo.Spec)
+	mockHandler.AssertNotCalled(t, "ListContainers", container.ListSelf)
+
+	// The spec was just updated.
+	_, err = cd.GetSpecInfo()
+	require.Nil(t, err)
+	mockHandler.AssertNumberOfCalls(t, "GetSpec", 2)
+}
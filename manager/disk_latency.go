@@ -0,0 +1,140 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Upper bounds of the disk latency histogram buckets. Slower operations
+// fall in a last, unbounded, bucket.
+var diskLatencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+type diskKey struct {
+	major uint64
+	minor uint64
+}
+
+// Builds per-disk latency histograms out of the cumulative blkio service
+// counters of successive samples. It is only used from the housekeeping
+// goroutine of its container.
+type diskLatencyTracker struct {
+	// Serviced operations and service time (in ns) of the last sample.
+	lastServiced    map[diskKey]map[string]uint64
+	lastServiceTime map[diskKey]map[string]uint64
+	histograms      map[diskKey]*info.PerDiskLatency
+}
+
+func newLatencyBuckets() []info.LatencyBucket {
+	buckets := make([]info.LatencyBucket, len(diskLatencyBuckets)+1)
+	for i, bound := range diskLatencyBuckets {
+		buckets[i].UpperBound = uint64(bound.Nanoseconds())
+	}
+	buckets[len(diskLatencyBuckets)].UpperBound = math.MaxUint64
+	return buckets
+}
+
+// Adds the operations of type op served between two samples to the buckets.
+func addLatency(buckets []info.LatencyBucket, op string, start, end map[string]uint64, startTime, endTime map[string]uint64) {
+	if start == nil || startTime == nil {
+		return
+	}
+	// Counters go backwards when the cgroup stats are reset.
+	if end[op] <= start[op] || endTime[op] < startTime[op] {
+		return
+	}
+	ops := end[op] - start[op]
+	average := (endTime[op] - startTime[op]) / ops
+	for i := range buckets {
+		if average <= buckets[i].UpperBound {
+			buckets[i].Count += ops
+			return
+		}
+	}
+}
+
+func toDiskStatsMap(stats []info.PerDiskStats) map[diskKey]map[string]uint64 {
+	ret := make(map[diskKey]map[string]uint64, len(stats))
+	for _, disk := range stats {
+		ret[diskKey{disk.Major, disk.Minor}] = disk.Stats
+	}
+	return ret
+}
+
+// Updates the histograms with the given sample and sets them in stats.
+func (self *diskLatencyTracker) update(stats *info.DiskIoStats) {
+	serviced := toDiskStatsMap(stats.IoServiced)
+	serviceTime := toDiskStatsMap(stats.IoServiceTime)
+	if self.histograms == nil {
+		self.histograms = make(map[diskKey]*info.PerDiskLatency)
+	}
+	for key := range serviced {
+		if _, ok := serviceTime[key]; !ok {
+			continue
+		}
+		histogram, ok := self.histograms[key]
+		if !ok {
+			histogram = &info.PerDiskLatency{
+				Major: key.major,
+				Minor: key.minor,
+				Read:  newLatencyBuckets(),
+				Write: newLatencyBuckets(),
+			}
+			self.histograms[key] = histogram
+		}
+		addLatency(histogram.Read, "Read", self.lastServiced[key], serviced[key], self.lastServiceTime[key], serviceTime[key])
+		addLatency(histogram.Write, "Write", self.lastServiced[key], serviced[key], self.lastServiceTime[key], serviceTime[key])
+	}
+	self.lastServiced = serviced
+	self.lastServiceTime = serviceTime
+
+	if len(self.histograms) == 0 {
+		return
+	}
+	// Stats are kept in storage, hand out copies of the histograms.
+	stats.IoServiceTimeHistogram = make([]info.PerDiskLatency, 0, len(self.histograms))
+	for _, histogram := range self.histograms {
+		latency := *histogram
+		latency.Read = append([]info.LatencyBucket(nil), histogram.Read...)
+		latency.Write = append([]info.LatencyBucket(nil), histogram.Write...)
+		stats.IoServiceTimeHistogram = append(stats.IoServiceTimeHistogram, latency)
+	}
+	sort.Sort(perDiskLatencySorter(stats.IoServiceTimeHistogram))
+}
+
+type perDiskLatencySorter []info.PerDiskLatency
+
+func (s perDiskLatencySorter) Len() int      { return len(s) }
+func (s perDiskLatencySorter) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s perDiskLatencySorter) Less(i, j int) bool {
+	if s[i].Major != s[j].Major {
+		return s[i].Major < s[j].Major
+	}
+	return s[i].Minor < s[j].Minor
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diskIoSample(reads, readTime, writes, writeTime uint64) info.DiskIoStats {
+	return info.DiskIoStats{
+		IoServiced: []info.PerDiskStats{
+			{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": reads, "Write": writes}},
+		},
+		IoServiceTime: []info.PerDiskStats{
+			{Major: 8, Minor: 0, Stats: map[string]uint64{"Read": readTime, "Write": writeTime}},
+		},
+	}
+}
+
+// Returns the count of the bucket with the given upper bound.
+func bucketCount(buckets []info.LatencyBucket, bound time.Duration) uint64 {
+	for _, b := range buckets {
+		if b.UpperBound == uint64(bound.Nanoseconds()) {
+			return b.Count
+		}
+	}
+	return 0
+}
+
+func TestDiskLatencyTracker(t *testing.T) {
+	tracker := diskLatencyTracker{}
+
+	// The first sample only sets the baseline.
+	stats := diskIoSample(100, uint64(time.Second), 10, uint64(time.Second))
+	tracker.update(&stats)
+	require.Equal(t, 1, len(stats.IoServiceTimeHistogram))
+	for _, b := range stats.IoServiceTimeHistogram[0].Read {
+		assert.Equal(t, uint64(0), b.Count)
+	}
+
+	// 10 reads averaging 2ms and 4 writes averaging 50ms.
+	stats = diskIoSample(110, uint64(time.Second+20*time.Millisecond), 14, uint64(time.Second+200*time.Millisecond))
+	tracker.update(&stats)
+	require.Equal(t, 1, len(stats.IoServiceTimeHistogram))
+	histogram := stats.IoServiceTimeHistogram[0]
+	assert.Equal(t, uint64(8), histogram.Major)
+	assert.Equal(t, len(diskLatencyBuckets)+1, len(histogram.Read))
+	assert.Equal(t, uint64(10), bucketCount(histogram.Read, 5*time.Millisecond))
+	assert.Equal(t, uint64(4), bucketCount(histogram.Write, 50*time.Millisecond))
+
+	// Counters going backwards are ignored and the histograms are kept.
+	stats = diskIoSample(5, 0, 1, 0)
+	tracker.update(&stats)
+	assert.Equal(t, uint64(10), bucketCount(stats.IoServiceTimeHistogram[0].Read, 5*time.Millisecond))
+
+	// 1 read of 2s lands in the unbounded bucket.
+	stats = diskIoSample(6, uint64(2*time.Second), 1, 0)
+	tracker.update(&stats)
+	read := stats.IoServiceTimeHistogram[0].Read
+	assert.Equal(t, uint64(1), read[len(read)-1].Count)
+
+	// Histograms handed out are copies.
+	read[0].Count = 42
+	stats = diskIoSample(6, uint64(2*time.Second), 1, 0)
+	tracker.update(&stats)
+	assert.Equal(t, uint64(0), stats.IoServiceTimeHistogram[0].Read[0].Count)
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/cadvisor/container"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+)
+
+// Tracks, for each subsystem, when its stats were last collected and how
+// often collecting them failed.
+type collectionHealth struct {
+	lock       sync.Mutex
+	subsystems map[string]*v2.SubsystemHealth
+}
+
+func newCollectionHealth() *collectionHealth {
+	subsystems := make(map[string]*v2.SubsystemHealth)
+	for _, subsystem := range []string{container.SubsystemCpu, container.SubsystemMemory, container.SubsystemNetwork, container.SubsystemDiskIo, container.SubsystemFilesystem} {
+		subsystems[subsystem] = &v2.SubsystemHealth{}
+	}
+	return &collectionHealth{
+		subsystems: subsystems,
+	}
+}
+
+// Returns the subsystems the stats of a container with the given spec are collected for.
+func specSubsystems(spec *info.ContainerSpec) []string {
+	var subsystems []string
+	if spec.HasCpu {
+		subsystems = append(subsystems, container.SubsystemCpu)
+	}
+	if spec.HasMemory {
+		subsystems = append(subsystems, container.SubsystemMemory)
+	}
+	if spec.HasNetwork {
+		subsystems = append(subsystems, container.SubsystemNetwork)
+	}
+	if spec.HasDiskIo {
+		subsystems = append(subsystems, container.SubsystemDiskIo)
+	}
+	if spec.HasFilesystem {
+		subsystems = append(subsystems, container.SubsystemFilesystem)
+	}
+	return subsystems
+}
+
+// Records the outcome of collecting the stats of a container. Errors which
+// are not a *container.StatsError are failures of all its subsystems.
+func (self *collectionHealth) record(containerName string, spec *info.ContainerSpec, err error, now time.Time) {
+	failures := map[string]error{}
+	if statsErr, ok := err.(*container.StatsError); ok {
+		failures = statsErr.Errors
+	} else if err != nil {
+		for _, subsystem := range specSubsystems(spec) {
+			failures[subsystem] = err
+		}
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for _, subsystem := range specSubsystems(spec) {
+		if _, failed := failures[subsystem]; !failed {
+			self.subsystems[subsystem].LastSuccessTime = now
+		}
+	}
+	for subsystem, failure := range failures {
+		health, ok := self.subsystems[subsystem]
+		if !ok {
+			health = &v2.SubsystemHealth{}
+			self.subsystems[subsystem] = health
+		}
+		health.ErrorCount++
+		health.LastErrorTime = now
+		health.LastError = fmt.Sprintf("%s: %v", containerName, failure)
+	}
+}
+
+// Returns a copy of the status of each subsystem.
+func (self *collectionHealth) get() map[string]v2.SubsystemHealth {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	ret := make(map[string]v2.SubsystemHealth, len(self.subsystems))
+	for subsystem, health := range self.subsystems {
+		ret[subsystem] = *health
+	}
+	return ret
+}
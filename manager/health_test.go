@@ -0,0 +1,69 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/cadvisor/container"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionHealth(t *testing.T) {
+	health := newCollectionHealth()
+	spec := &info.ContainerSpec{HasCpu: true, HasMemory: true, HasNetwork: true}
+	first := time.Unix(100, 0)
+	second := time.Unix(200, 0)
+	third := time.Unix(300, 0)
+
+	health.record("/a", spec, nil, first)
+	statsErr := &container.StatsError{}
+	statsErr.Add(container.SubsystemNetwork, errors.New("permission denied"))
+	health.record("/b", spec, statsErr, second)
+	health.record("/c", &info.ContainerSpec{HasCpu: true}, errors.New("no state"), third)
+
+	status := health.get()
+	assert.Equal(t, 5, len(status))
+	assert.Equal(t, second, status["cpu"].LastSuccessTime)
+	assert.Equal(t, uint64(1), status["cpu"].ErrorCount)
+	assert.Equal(t, "/c: no state", status["cpu"].LastError)
+	assert.Equal(t, second, status["memory"].LastSuccessTime)
+	assert.Equal(t, uint64(0), status["memory"].ErrorCount)
+
+	// Network stats stopped being collected.
+	assert.Equal(t, first, status["network"].LastSuccessTime)
+	assert.Equal(t, uint64(1), status["network"].ErrorCount)
+	assert.Equal(t, second, status["network"].LastErrorTime)
+	assert.Equal(t, "/b: permission denied", status["network"].LastError)
+
+	// Subsystems no container has were never collected.
+	assert.True(t, status["filesystem"].LastSuccessTime.IsZero())
+	assert.Equal(t, uint64(0), status["diskio"].ErrorCount)
+}
+
+func TestStatsError(t *testing.T) {
+	statsErr := &container.StatsError{}
+	statsErr.Add(container.SubsystemNetwork, nil)
+	assert.Nil(t, statsErr.OrNil())
+
+	other := &container.StatsError{}
+	other.Add(container.SubsystemMemory, errors.New("no memory cgroup"))
+	statsErr.Add(container.SubsystemFilesystem, errors.New("no device"))
+	statsErr.Add("", other)
+	assert.Equal(t, "failed to get stats of filesystem: no device; memory: no memory cgroup", statsErr.OrNil().Error())
+}
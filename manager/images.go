@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+)
+
+type byWorkingSet []v2.ImageUsage
+
+func (s byWorkingSet) Len() int      { return len(s) }
+func (s byWorkingSet) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byWorkingSet) Less(i, j int) bool {
+	if s[i].MemoryWorkingSet != s[j].MemoryWorkingSet {
+		return s[i].MemoryWorkingSet > s[j].MemoryWorkingSet
+	}
+	return s[i].Image < s[j].Image
+}
+
+func (self *manager) GetImageUsage() ([]v2.ImageUsage, error) {
+	cpuRate := topMetrics[v2.TopByCpu]
+	images := map[string]*v2.ImageUsage{}
+	for _, cont := range self.getSubcontainers("/", 0) {
+		cinfo, err := cont.GetSpecInfo()
+		if err != nil || cinfo.Spec.Image == "" {
+			continue
+		}
+		stats, err := self.memoryStorage.RecentStats(cinfo.Name, time.Time{}, time.Time{}, 2)
+		if err != nil || len(stats) == 0 {
+			continue
+		}
+		usage, ok := images[cinfo.Spec.Image]
+		if !ok {
+			usage = &v2.ImageUsage{Image: cinfo.Spec.Image}
+			images[cinfo.Spec.Image] = usage
+		}
+		cur := stats[len(stats)-1]
+		var prev *info.ContainerStats
+		if len(stats) == 2 {
+			prev = stats[0]
+		}
+		usage.Containers++
+		if cpu, ok := cpuRate(prev, cur); ok {
+			usage.Cpu += cpu
+		}
+		usage.Memory += cur.Memory.Usage
+		usage.MemoryWorkingSet += cur.Memory.WorkingSet
+	}
+	ret := make([]v2.ImageUsage, 0, len(images))
+	for _, usage := range images {
+		ret = append(ret, *usage)
+	}
+	sort.Sort(byWorkingSet(ret))
+	return ret, nil
+}
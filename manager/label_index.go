@@ -0,0 +1,104 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"strings"
+)
+
+// Index of container names by label. It is not thread-safe, the manager
+// guards it with the containers lock.
+type labelIndex struct {
+	// Label key -> label value -> set of names of the containers with that label.
+	labels map[string]map[string]map[string]bool
+	// Container name -> labels of the container.
+	containerLabels map[string]map[string]string
+}
+
+func (self *labelIndex) add(containerName string, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	if self.labels == nil {
+		self.labels = make(map[string]map[string]map[string]bool)
+		self.containerLabels = make(map[string]map[string]string)
+	}
+	self.containerLabels[containerName] = labels
+	for key, value := range labels {
+		values, ok := self.labels[key]
+		if !ok {
+			values = make(map[string]map[string]bool)
+			self.labels[key] = values
+		}
+		names, ok := values[value]
+		if !ok {
+			names = make(map[string]bool)
+			values[value] = names
+		}
+		names[containerName] = true
+	}
+}
+
+func (self *labelIndex) remove(containerName string) {
+	labels, ok := self.containerLabels[containerName]
+	if !ok {
+		return
+	}
+	delete(self.containerLabels, containerName)
+	for key, value := range labels {
+		values, ok := self.labels[key]
+		if !ok {
+			continue
+		}
+		delete(values[value], containerName)
+		if len(values[value]) == 0 {
+			delete(values, value)
+		}
+		if len(values) == 0 {
+			delete(self.labels, key)
+		}
+	}
+}
+
+// Returns the names of the containers that have all the selected labels. Label
+// values must be equal to the selected values, or start with them if prefix is true.
+// No containers are returned for an empty selector.
+func (self *labelIndex) find(selector map[string]string, prefix bool) []string {
+	var matches map[string]bool
+	for key, selected := range selector {
+		keyMatches := make(map[string]bool)
+		for value, names := range self.labels[key] {
+			if value != selected && !(prefix && strings.HasPrefix(value, selected)) {
+				continue
+			}
+			for name := range names {
+				// Only keep containers matching all previous selectors.
+				if matches == nil || matches[name] {
+					keyMatches[name] = true
+				}
+			}
+		}
+		matches = keyMatches
+		if len(matches) == 0 {
+			break
+		}
+	}
+
+	ret := make([]string, 0, len(matches))
+	for name := range matches {
+		ret = append(ret, name)
+	}
+	return ret
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func find(index *labelIndex, selector map[string]string, prefix bool) []string {
+	names := index.find(selector, prefix)
+	sort.Strings(names)
+	return names
+}
+
+func TestLabelIndex(t *testing.T) {
+	index := &labelIndex{}
+	index.add("/docker/a", map[string]string{"app": "nginx", "tier": "frontend"})
+	index.add("/docker/b", map[string]string{"app": "nginx-canary", "tier": "frontend"})
+	index.add("/docker/c", map[string]string{"app": "redis", "tier": "backend"})
+
+	assert.Equal(t, []string{"/docker/a"}, find(index, map[string]string{"app": "nginx"}, false))
+	assert.Equal(t, []string{"/docker/a", "/docker/b"}, find(index, map[string]string{"app": "nginx"}, true))
+	assert.Equal(t, []string{"/docker/b"}, find(index, map[string]string{"app": "nginx-", "tier": "frontend"}, true))
+	assert.Equal(t, []string{}, find(index, map[string]string{"app": "redis", "tier": "frontend"}, false))
+	assert.Equal(t, []string{}, find(index, map[string]string{"missing": "label"}, false))
+	assert.Equal(t, []string{}, find(index, map[string]string{}, false))
+
+	index.remove("/docker/a")
+	assert.Equal(t, []string{}, find(index, map[string]string{"app": "nginx"}, false))
+	assert.Equal(t, []string{"/docker/b"}, find(index, map[string]string{"tier": "frontend"}, false))
+	_, ok := index.labels["app"]["nginx"]
+	assert.False(t, ok)
+}
@@ -27,9 +27,11 @@ import (
 	dclient "github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
 	"github.com/google/cadvisor/container/docker"
+	"github.com/google/cadvisor/container/libcontainer"
 	"github.com/google/cadvisor/fs"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/google/cadvisor/utils"
+	"github.com/google/cadvisor/utils/cloudinfo"
 	"github.com/google/cadvisor/utils/sysfs"
 	"github.com/google/cadvisor/utils/sysinfo"
 	version "github.com/google/cadvisor/version"
@@ -44,6 +46,11 @@ var memoryCapacityRegexp = regexp.MustCompile("MemTotal: *([0-9]+) kB")
 var machineIdFilePath = flag.String("machine_id_file", "/etc/machine-id,/var/lib/dbus/machine-id", "Comma-separated list of files to check for machine-id. Use the first one that exists.")
 var bootIdFilePath = flag.String("boot_id_file", "/proc/sys/kernel/random/boot_id", "Comma-separated list of files to check for boot-id. Use the first one that exists.")
 
+// Directory the NUMA nodes of the machine are listed in.
+var numaNodesDir = "/sys/devices/system/node"
+
+var detectCloudProvider = flag.Bool("detect_cloud_provider", false, "Whether to detect the cloud provider of the machine and read the type, region and zone of its instance from the metadata service of the provider")
+
 func getClockSpeed(procInfo []byte) (uint64, error) {
 	// First look through sys to find a max supported cpu frequency.
 	const maxFreqFile = "/sys/devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq"
@@ -262,6 +269,11 @@ func getMachineInfo(sysFs sysfs.SysFs, fsInfo fs.FsInfo) (*info.MachineInfo, err
 		glog.Errorf("Failed to get topology information: %v", err)
 	}
 
+	numaNodes, err := sysinfo.GetNumaNodes(numaNodesDir)
+	if err != nil {
+		glog.Errorf("Failed to get NUMA nodes: %v", err)
+	}
+
 	systemUUID, err := sysinfo.GetSystemUUID(sysFs)
 	if err != nil {
 		glog.Errorf("Failed to get system UUID: %v", err)
@@ -274,11 +286,28 @@ func getMachineInfo(sysFs sysfs.SysFs, fsInfo fs.FsInfo) (*info.MachineInfo, err
 		DiskMap:        diskMap,
 		NetworkDevices: netDevices,
 		Topology:       topology,
+		NumaNodes:      numaNodes,
 		MachineID:      getInfoFromFiles(*machineIdFilePath),
 		SystemUUID:     systemUUID,
 		BootID:         getInfoFromFiles(*bootIdFilePath),
 	}
 
+	machineInfo.CgroupMode, err = libcontainer.GetCgroupMode()
+	if err != nil {
+		glog.Errorf("Failed to get cgroup mode: %v", err)
+	}
+
+	if *detectCloudProvider {
+		cloudInfo, err := cloudinfo.GetCloudInfo()
+		if err != nil {
+			glog.Errorf("Failed to get the instance metadata of cloud provider %q: %v", cloudInfo.Provider, err)
+		}
+		machineInfo.CloudProvider = cloudInfo.Provider
+		machineInfo.InstanceType = cloudInfo.InstanceType
+		machineInfo.Region = cloudInfo.Region
+		machineInfo.Zone = cloudInfo.Zone
+	}
+
 	for _, fs := range filesystems {
 		machineInfo.Filesystems = append(machineInfo.Filesystems, info.FsInfo{Device: fs.Device, Capacity: fs.Capacity})
 	}
@@ -26,21 +26,42 @@ import (
 
 	"github.com/docker/libcontainer/cgroups"
 	"github.com/golang/glog"
+	"github.com/google/cadvisor/collector"
 	"github.com/google/cadvisor/container"
+	"github.com/google/cadvisor/container/containerd"
+	"github.com/google/cadvisor/container/crio"
 	"github.com/google/cadvisor/container/docker"
+	"github.com/google/cadvisor/container/libcontainer"
 	"github.com/google/cadvisor/container/raw"
 	"github.com/google/cadvisor/events"
 	"github.com/google/cadvisor/fs"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/google/cadvisor/info/v2"
 	"github.com/google/cadvisor/storage/memory"
+	"github.com/google/cadvisor/summary"
+	"github.com/google/cadvisor/utils/accelerators"
 	"github.com/google/cadvisor/utils/cpuload"
 	"github.com/google/cadvisor/utils/oomparser"
 	"github.com/google/cadvisor/utils/sysfs"
+	"github.com/google/cadvisor/utils/sysinfo"
 )
 
 var globalHousekeepingInterval = flag.Duration("global_housekeeping_interval", 1*time.Minute, "Interval between global housekeepings")
 var logCadvisorUsage = flag.Bool("log_cadvisor_usage", false, "Whether to log the usage of the cAdvisor container")
+var enableLoadReader = flag.Bool("enable_load_reader", false, "Whether to enable the cpu load reader used to compute per-container load averages")
+var eventStorageDir = flag.String("event_storage_dir", "", "Directory where events are stored so they survive restarts. Events are only kept in memory if empty")
+var eventStorageMaxBytes = flag.Int64("event_storage_max_bytes", 100*1024*1024, "Maximum disk usage of the events stored in -event_storage_dir, the oldest events are removed when it is reached")
+var eventStorageRetention = flag.Duration("event_storage_retention", 24*time.Hour, "How old the events reloaded from -event_storage_dir at startup can be")
+var eventWebhookUrl = flag.String("event_webhook_url", "", "URL each new event of the types in -event_webhook_types is posted to as JSON. Events are not posted if empty")
+var ShutdownTimeout = flag.Duration("shutdown_timeout", 10*time.Second, "How long to wait on exit, first for pending requests to complete, then for the collection of stats to stop and the storage driver to write the stats it buffered")
+var eventWebhookTypes = flag.String("event_webhook_types", "oom,creation,deletion", "Comma separated list of the types of the events posted to -event_webhook_url: oom, creation, deletion and restart_loop")
+
+var eventSyslog = flag.Bool("event_syslog", false, "Write each new event of the types in -event_syslog_types to syslog")
+
+var eventSyslogTypes = flag.String("event_syslog_types", "oom,creation,deletion", "Comma separated list of the types of the events written to syslog with -event_syslog: oom, creation, deletion and restart_loop")
+
+var eventRestartLoopCount = flag.Int("event_restart_loop_count", 0, "Number of times a container can be started within -event_restart_loop_window before a restart_loop event is added. 0 disables the detection")
+var eventRestartLoopWindow = flag.Duration("event_restart_loop_window", 10*time.Minute, "Time window over which the starts of containers are counted to detect restart loops")
 
 // The Manager interface defines operations for starting a manager and getting
 // container and machine information.
@@ -51,6 +72,10 @@ type Manager interface {
 	// Stops the manager.
 	Stop() error
 
+	// Stops the manager and the collection of stats, then writes the stats
+	// buffered by the storage driver, before exiting.
+	Shutdown() error
+
 	// Get information about a container.
 	GetContainerInfo(containerName string, query *info.ContainerInfoRequest) (*info.ContainerInfo, error)
 
@@ -66,18 +91,73 @@ type Manager interface {
 	// Gets spec for all containers based on request options.
 	GetContainerSpec(containerName string, options v2.RequestOptions) (map[string]v2.ContainerSpec, error)
 
+	// Gets the specs of the containers like GetContainerSpec, without listing
+	// the subcontainers of each container again.
+	GetContainerSpecs(containerName string, options v2.RequestOptions) (map[string]v2.ContainerSpec, error)
+
 	// Gets summary stats for all containers based on request options.
 	GetDerivedStats(containerName string, options v2.RequestOptions) (map[string]v2.DerivedStats, error)
 
 	// Get info for all requested containers based on the request options.
 	GetRequestedContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error)
 
+	// Get info for the requested container with its CPU, memory and network
+	// stats summed with the ones of all its subcontainers.
+	GetAggregatedContainerInfo(containerName string, options v2.RequestOptions) (*info.ContainerInfo, error)
+
+	// Get the containers without subcontainers with the highest value of the
+	// given metric (one of the v2.TopBy* constants), highest first.
+	GetTopContainers(by string, limit int) ([]v2.TopContainer, error)
+
+	// Get the resources used by the running containers of each image, sorted
+	// by decreasing memory working set.
+	GetImageUsage() ([]v2.ImageUsage, error)
+
+	// Get info for all requested containers with only their most recent stats.
+	// Containers without stats yet are left out.
+	GetLatestContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error)
+
+	// Get info for all containers with the selected labels. Label values must match
+	// the selected values exactly, or start with them if prefixMatch is true.
+	GetContainersInfoByLabels(selector map[string]string, prefixMatch bool, options v2.RequestOptions) (map[string]*info.ContainerInfo, error)
+
+	// Get the processes running in the requested containers, along with their
+	// CPU usage sampled over a short interval.
+	GetProcessList(containerName string, options v2.RequestOptions) ([]v2.ProcessInfo, error)
+
+	// Get the container with the given cgroup path, either relative to the
+	// root of the cgroup hierarchies (i.e. its name) or absolute.
+	ResolveCgroup(cgroupPath string) (v2.ResolvedContainer, error)
+
+	// Get the container with the given absolute name or alias.
+	ResolveName(name string) (v2.ResolvedContainer, error)
+
+	// Get the settings of a container read from the control files of its
+	// cgroups, as a map from file name to content.
+	GetCgroupConfig(containerName string) (map[string]string, error)
+
+	// Get the collection status of the stats of each subsystem, and the
+	// status of the writes to the storage backends reporting it, as
+	// "storage/<backend>".
+	GetCollectionHealth() map[string]v2.SubsystemHealth
+
+	// Set the interval between housekeepings of a container, a zero interval
+	// restores the global one. Returns the interval that was set after clamping.
+	SetHousekeepingInterval(containerName string, interval time.Duration) (time.Duration, error)
+
+	// Set the annotations of a container, replacing the ones set before. They
+	// are reported in its spec until it goes away.
+	SetAnnotations(containerName string, annotations map[string]string) error
+
 	// Get information about the machine.
 	GetMachineInfo() (*info.MachineInfo, error)
 
 	// Get version information about different components we depend on.
 	GetVersionInfo() (*info.VersionInfo, error)
 
+	// Get machine and version information along with the detected container handlers.
+	GetAttributes() (v2.Attributes, error)
+
 	// Get filesystem information for a given label.
 	// Returns information for all global filesystems if label is empty.
 	GetFsInfo(label string) ([]v2.FsInfo, error)
@@ -88,11 +168,42 @@ type Manager interface {
 	// Get past events that have been detected and that fit the request.
 	GetPastEvents(request *events.Request) (events.EventSlice, error)
 
+	// Get the epoch of the sequence numbers of the events.
+	GetEventsEpoch() int64
+
+	// Delete the past events that fit the request. Returns how many were deleted.
+	DeleteEvents(request *events.Request) (int, error)
+
 	CloseEventChannel(watch_id int)
+
+	// Get the stats of a container streamed through the returned channel as
+	// they are collected.
+	WatchStats(containerName string) (*StatsChannel, error)
+
+	// Stop streaming stats through a channel returned by WatchStats().
+	CloseStatsChannel(statsChannel *StatsChannel)
+
+	// Get the containers currently tracked and the containers added and
+	// removed afterwards, streamed through the returned channel.
+	WatchTopology() (*TopologyChannel, error)
+
+	// Stop streaming changes through a channel returned by WatchTopology().
+	CloseTopologyChannel(topologyChannel *TopologyChannel)
+}
+
+// Returned when a requested container is not known, e.g. because it was
+// removed between being listed and being requested.
+type ContainerNotFoundError struct {
+	Name string
 }
 
-// New takes a memory storage and returns a new manager.
-func New(memoryStorage *memory.InMemoryStorage, sysfs sysfs.SysFs) (Manager, error) {
+func (self *ContainerNotFoundError) Error() string {
+	return fmt.Sprintf("unknown container %q", self.Name)
+}
+
+// New takes a memory storage and returns a new manager. The metrics in
+// ignoreMetrics are not collected for any container.
+func New(memoryStorage *memory.InMemoryStorage, sysfs sysfs.SysFs, ignoreMetrics container.MetricSet) (Manager, error) {
 	if memoryStorage == nil {
 		return nil, fmt.Errorf("manager requires memory storage")
 	}
@@ -116,6 +227,7 @@ func New(memoryStorage *memory.InMemoryStorage, sysfs sysfs.SysFs) (Manager, err
 		fsInfo:            fsInfo,
 		cadvisorContainer: selfContainer,
 		startupTime:       time.Now(),
+		health:            newCollectionHealth(),
 	}
 
 	machineInfo, err := getMachineInfo(sysfs, fsInfo)
@@ -132,16 +244,38 @@ func New(memoryStorage *memory.InMemoryStorage, sysfs sysfs.SysFs) (Manager, err
 	newManager.versionInfo = *versionInfo
 	glog.Infof("Version: %+v", newManager.versionInfo)
 
-	newManager.eventHandler = events.NewEventManager()
+	if *eventStorageDir != "" {
+		newManager.eventHandler, err = events.NewPersistentEventManager(*eventStorageDir, *eventStorageMaxBytes, *eventStorageRetention)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		newManager.eventHandler = events.NewEventManager()
+	}
+	if *eventRestartLoopCount > 0 {
+		newManager.restartLoopDetector = events.NewRestartLoopDetector(*eventRestartLoopCount, *eventRestartLoopWindow)
+	}
 
 	// Register Docker container factory.
-	err = docker.Register(newManager, fsInfo)
+	err = docker.Register(newManager, fsInfo, ignoreMetrics)
 	if err != nil {
 		glog.Errorf("Docker container factory registration failed: %v.", err)
 	}
 
+	// Register CRI-O container factory.
+	err = crio.Register(newManager, ignoreMetrics)
+	if err != nil {
+		glog.Infof("CRI-O container factory registration failed: %v.", err)
+	}
+
+	// Register containerd container factory.
+	err = containerd.Register(newManager, ignoreMetrics)
+	if err != nil {
+		glog.Infof("containerd container factory registration failed: %v.", err)
+	}
+
 	// Register the raw driver.
-	err = raw.Register(newManager, fsInfo)
+	err = raw.Register(newManager, fsInfo, ignoreMetrics)
 	if err != nil {
 		glog.Errorf("Registration of the raw container factory failed: %v", err)
 	}
@@ -159,8 +293,10 @@ type namespacedContainerName struct {
 }
 
 type manager struct {
-	containers             map[namespacedContainerName]*containerData
-	containersLock         sync.RWMutex
+	containers     map[namespacedContainerName]*containerData
+	containersLock sync.RWMutex
+	// Index of the containers by label, guarded by containersLock.
+	labels                 labelIndex
 	memoryStorage          *memory.InMemoryStorage
 	fsInfo                 fs.FsInfo
 	machineInfo            info.MachineInfo
@@ -169,14 +305,32 @@ type manager struct {
 	cadvisorContainer      string
 	dockerContainersRegexp *regexp.Regexp
 	loadReader             cpuload.CpuLoadReader
+	nvidiaManager          *accelerators.NvidiaManager
+	health                 *collectionHealth
 	eventHandler           events.EventManager
-	startupTime            time.Time
+	// Nil if restart loops are not detected.
+	restartLoopDetector *events.RestartLoopDetector
+	eventWebhook        *events.Webhook
+	eventSyslog         *events.Syslog
+	startupTime         time.Time
+	// ID of the last stats watch, accessed atomically.
+	lastStatsWatchId int32
+	// Channels the containers added and removed are sent through, guarded
+	// by containersLock.
+	topologyWatchers    map[int]chan v2.TopologyChange
+	lastTopologyWatchId int
+	// Deletion events waiting for the exit status of their container.
+	pendingDeletions sync.WaitGroup
+	// Guards the NUMA nodes of machineInfo, whose free memory is refreshed
+	// when the machine info is requested.
+	numaLock            sync.Mutex
+	numaNodesUpdateTime time.Time
 }
 
 // Start the container manager.
 func (self *manager) Start() error {
-	// TODO(rjnagal): Skip creating cpu load reader while we improve resource usage and accuracy.
-	if false {
+	// TODO(rjnagal): Enable cpu load reader by default once we improve resource usage and accuracy.
+	if *enableLoadReader {
 		// Create cpu load reader.
 		cpuLoadReader, err := cpuload.New()
 		if err != nil {
@@ -192,12 +346,51 @@ func (self *manager) Start() error {
 		}
 	}
 
+	// GPU stats are only collected on hosts with NVIDIA devices.
+	nvidiaManager, err := accelerators.New()
+	if err != nil {
+		glog.V(1).Infof("Not collecting GPU stats: %v", err)
+	} else {
+		self.nvidiaManager = nvidiaManager
+	}
+
 	// Watch for OOMs.
-	err := self.watchForNewOoms()
+	err = self.watchForNewOoms()
 	if err != nil {
 		glog.Errorf("Failed to start OOM watcher, will not get OOM events: %v", err)
 	}
 
+	if *eventWebhookUrl != "" {
+		request, err := eventTypesRequest(*eventWebhookTypes)
+		if err != nil {
+			return err
+		}
+		webhook := events.NewWebhook(*eventWebhookUrl)
+		err = webhook.Start(self.eventHandler, request)
+		if err != nil {
+			return err
+		}
+		self.eventWebhook = webhook
+		glog.Infof("Posting events to %q", *eventWebhookUrl)
+	}
+
+	if *eventSyslog {
+		request, err := eventTypesRequest(*eventSyslogTypes)
+		if err != nil {
+			return err
+		}
+		sink, err := events.NewSyslog("cadvisor")
+		if err != nil {
+			return err
+		}
+		err = sink.Start(self.eventHandler, request)
+		if err != nil {
+			return err
+		}
+		self.eventSyslog = sink
+		glog.Infof("Writing events to syslog")
+	}
+
 	// If there are no factories, don't start any housekeeping and serve the information we do have.
 	if !container.HasFactories() {
 		return nil
@@ -248,9 +441,87 @@ func (self *manager) Stop() error {
 		self.loadReader.Stop()
 		self.loadReader = nil
 	}
+	if self.eventWebhook != nil {
+		self.eventWebhook.Stop()
+		self.eventWebhook = nil
+	}
+	if self.eventSyslog != nil {
+		self.eventSyslog.Stop()
+		self.eventSyslog = nil
+	}
 	return nil
 }
 
+// Stops the manager, then collecting the stats of all containers so that the
+// storage driver can write the stats it buffered before exiting. Waits at
+// most -shutdown_timeout on the containers and the storage driver.
+func (self *manager) Shutdown() error {
+	deadline := time.After(*ShutdownTimeout)
+	if err := self.Stop(); err != nil {
+		return err
+	}
+
+	self.containersLock.Lock()
+	conts := make(map[*containerData]struct{}, len(self.containers))
+	for _, cont := range self.containers {
+		// Containers are listed under each of their aliases.
+		conts[cont] = struct{}{}
+	}
+	self.containers = make(map[namespacedContainerName]*containerData)
+	self.containersLock.Unlock()
+	for cont := range conts {
+		cont.Stop()
+	}
+	for cont := range conts {
+		select {
+		case <-cont.stopped:
+		case <-deadline:
+			return fmt.Errorf("timed out waiting for the housekeeping of container %q to stop", cont.info.Name)
+		}
+	}
+
+	self.pendingDeletions.Wait()
+	if err := self.eventHandler.Close(); err != nil {
+		glog.Errorf("Failed to close events: %v", err)
+	}
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- self.memoryStorage.Close()
+	}()
+	select {
+	case err := <-closed:
+		return err
+	case <-deadline:
+		return fmt.Errorf("timed out waiting for the storage driver to write buffered stats")
+	}
+}
+
+// Returns the request selecting the events of the comma separated types
+// posted to the event webhook or written to syslog.
+func eventTypesRequest(types string) (*events.Request, error) {
+	request := events.NewRequest()
+	for _, name := range strings.Split(types, ",") {
+		switch strings.TrimSpace(name) {
+		case "oom":
+			request.EventType[events.TypeOom] = true
+		case "creation":
+			request.EventType[events.TypeContainerCreation] = true
+		case "deletion":
+			request.EventType[events.TypeContainerDeletion] = true
+		case "restart_loop":
+			request.EventType[events.TypeRestartLoop] = true
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown event type %q in %q, expected oom, creation, deletion or restart_loop", name, types)
+		}
+	}
+	if len(request.EventType) == 0 {
+		return nil, fmt.Errorf("no event type selected in %q", types)
+	}
+	return request, nil
+}
+
 func (self *manager) globalHousekeeping(quit chan error) {
 	// Long housekeeping is either 100ms or half of the housekeeping interval.
 	longHousekeeping := 100 * time.Millisecond
@@ -297,7 +568,7 @@ func (self *manager) getContainerData(containerName string) (*containerData, err
 		}]
 	}()
 	if !ok {
-		return nil, fmt.Errorf("unknown container %q", containerName)
+		return nil, &ContainerNotFoundError{Name: containerName}
 	}
 	return cont, nil
 }
@@ -313,6 +584,11 @@ func (self *manager) GetDerivedStats(containerName string, options v2.RequestOpt
 		if err != nil {
 			return nil, err
 		}
+		cinfo, err := cont.GetSpecInfo()
+		if err != nil {
+			return nil, err
+		}
+		d.Headroom = summary.GetHeadroom(d.LatestUsage, cinfo.Spec, uint64(self.machineInfo.MemoryCapacity))
 		stats[name] = d
 	}
 	return stats, nil
@@ -335,6 +611,22 @@ func (self *manager) GetContainerSpec(containerName string, options v2.RequestOp
 	return specs, nil
 }
 
+func (self *manager) GetContainerSpecs(containerName string, options v2.RequestOptions) (map[string]v2.ContainerSpec, error) {
+	conts, err := self.getRequestedContainers(containerName, options)
+	if err != nil {
+		return nil, err
+	}
+	specs := make(map[string]v2.ContainerSpec)
+	for name, cont := range conts {
+		cinfo, err := cont.GetSpecInfo()
+		if err != nil {
+			return nil, err
+		}
+		specs[name] = self.getV2Spec(cinfo)
+	}
+	return specs, nil
+}
+
 // Get V2 container spec from v1 container info.
 func (self *manager) getV2Spec(cinfo *containerInfo) v2.ContainerSpec {
 	specV1 := self.getAdjustedSpec(cinfo)
@@ -347,17 +639,60 @@ func (self *manager) getV2Spec(cinfo *containerInfo) v2.ContainerSpec {
 		specV2.Cpu.Limit = specV1.Cpu.Limit
 		specV2.Cpu.MaxLimit = specV1.Cpu.MaxLimit
 		specV2.Cpu.Mask = specV1.Cpu.Mask
+		specV2.Cpu.CpuSet = specV1.Cpu.CpuSet
+		specV2.Cpu.Mems = specV1.Cpu.Mems
 	}
 	if specV1.HasMemory {
 		specV2.Memory.Limit = specV1.Memory.Limit
 		specV2.Memory.Reservation = specV1.Memory.Reservation
 		specV2.Memory.SwapLimit = specV1.Memory.SwapLimit
+		specV2.Memory.SoftLimit = specV1.Memory.SoftLimit
+		specV2.Memory.Low = specV1.Memory.Low
+		specV2.Memory.High = specV1.Memory.High
 	}
 	specV2.Aliases = cinfo.Aliases
 	specV2.Namespace = cinfo.Namespace
+	specV2.Labels = specV1.Labels
+	specV2.CgroupVersion = specV1.CgroupVersion
+	specV2.Controllers = specV1.Controllers
+	specV2.Annotations = specV1.Annotations
+	specV2.Image = specV1.Image
+	specV2.ImageDigest = specV1.ImageDigest
+	specV2.RepoTags = specV1.RepoTags
+	specV2.Env = specV1.Env
+	specV2.RestartCount = specV1.RestartCount
+	specV2.LastStartTime = specV1.LastStartTime
+	specV2.SeccompProfile = specV1.SeccompProfile
+	specV2.AppArmorProfile = specV1.AppArmorProfile
+	specV2.CustomMetrics = specV1.CustomMetrics
+	if !specV1.CreationTime.IsZero() {
+		specV2.Uptime = time.Since(specV1.CreationTime)
+	}
+	specV2.Ancestry = self.getAncestry(cinfo.Name)
 	return specV2
 }
 
+// Returns the names of the containers holding the given one, from its parent
+// up to the root container. Cgroups which are not containers (e.g. with
+// --docker_only) are skipped.
+func (self *manager) getAncestry(containerName string) []string {
+	self.containersLock.RLock()
+	defer self.containersLock.RUnlock()
+	return self.getAncestryLocked(containerName)
+}
+
+// Same as getAncestry, with containersLock held.
+func (self *manager) getAncestryLocked(containerName string) []string {
+	var ancestry []string
+	for name := containerName; name != "/" && name != "."; {
+		name = path.Dir(name)
+		if _, ok := self.containers[namespacedContainerName{Name: name}]; ok {
+			ancestry = append(ancestry, name)
+		}
+	}
+	return ancestry
+}
+
 func (self *manager) getAdjustedSpec(cinfo *containerInfo) info.ContainerSpec {
 	spec := cinfo.Spec
 
@@ -407,12 +742,14 @@ func (self *manager) getContainer(containerName string) (*containerData, error)
 	defer self.containersLock.RUnlock()
 	cont, ok := self.containers[namespacedContainerName{Name: containerName}]
 	if !ok {
-		return nil, fmt.Errorf("unknown container %q", containerName)
+		return nil, &ContainerNotFoundError{Name: containerName}
 	}
 	return cont, nil
 }
 
-func (self *manager) getSubcontainers(containerName string) map[string]*containerData {
+// Returns the container and its subcontainers down to depth levels below it,
+// all of them if depth is 0.
+func (self *manager) getSubcontainers(containerName string, depth int) map[string]*containerData {
 	self.containersLock.RLock()
 	defer self.containersLock.RUnlock()
 	containersMap := make(map[string]*containerData, len(self.containers))
@@ -422,14 +759,27 @@ func (self *manager) getSubcontainers(containerName string) map[string]*containe
 	for i := range self.containers {
 		name := self.containers[i].info.Name
 		if name == containerName || strings.HasPrefix(name, matchedName) {
+			if depth > 0 && subcontainerDepth(containerName, name) > depth {
+				continue
+			}
 			containersMap[self.containers[i].info.Name] = self.containers[i]
 		}
 	}
 	return containersMap
 }
 
+// Returns the number of levels name is below containerName, of which it is a
+// subcontainer, e.g. 2 for /docker/abc below /.
+func subcontainerDepth(containerName, name string) int {
+	relative := strings.Trim(strings.TrimPrefix(name, containerName), "/")
+	if relative == "" {
+		return 0
+	}
+	return strings.Count(relative, "/") + 1
+}
+
 func (self *manager) SubcontainersInfo(containerName string, query *info.ContainerInfoRequest) ([]*info.ContainerInfo, error) {
-	containersMap := self.getSubcontainers(containerName)
+	containersMap := self.getSubcontainers(containerName, 0)
 
 	containers := make([]*containerData, 0, len(containersMap))
 	for _, cont := range containersMap {
@@ -476,7 +826,7 @@ func (self *manager) getDockerContainer(containerName string) (*containerData, e
 		Name:      containerName,
 	}]
 	if !ok {
-		return nil, fmt.Errorf("unable to find Docker container %q", containerName)
+		return nil, &ContainerNotFoundError{Name: containerName}
 	}
 	return cont, nil
 }
@@ -533,42 +883,199 @@ func (self *manager) GetRequestedContainersInfo(containerName string, options v2
 	return containersMap, nil
 }
 
+func (self *manager) GetAggregatedContainerInfo(containerName string, options v2.RequestOptions) (*info.ContainerInfo, error) {
+	cont, err := self.getRequestedContainer(containerName, options.IdType)
+	if err != nil {
+		return nil, err
+	}
+	cinfo, err := self.containerDataToContainerInfo(cont, &info.ContainerInfoRequest{NumStats: options.Count})
+	if err != nil {
+		return nil, err
+	}
+	if len(cinfo.Stats) == 0 {
+		return cinfo, nil
+	}
+
+	// Subcontainers may be housekept up to the max interval apart from the
+	// container, get their stats from that long before its first sample.
+	query := info.ContainerInfoRequest{
+		NumStats: -1,
+		Start:    cinfo.Stats[0].Timestamp.Add(-*maxHousekeepingInterval),
+	}
+	var containers []aggregatedContainer
+	for name, subcontainer := range self.getSubcontainers(cinfo.Name, 0) {
+		subinfo := cinfo
+		if name != cinfo.Name {
+			subinfo, err = self.containerDataToContainerInfo(subcontainer, &query)
+			if err != nil {
+				// Skip containers with errors, we try to degrade gracefully.
+				continue
+			}
+		}
+		containers = append(containers, aggregatedContainer{
+			name:             name,
+			stats:            subinfo.Stats,
+			hasNetwork:       subinfo.Spec.HasNetwork,
+			networkNamespace: getNetworkNamespace(subcontainer),
+		})
+	}
+	cinfo.Stats = aggregateStats(cinfo.Stats, networkStatsToSum(containers))
+	return cinfo, nil
+}
+
+func (self *manager) GetLatestContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	containers, err := self.getRequestedContainers(containerName, options)
+	if err != nil {
+		return nil, err
+	}
+	containersMap := make(map[string]*info.ContainerInfo, len(containers))
+	for name, cont := range containers {
+		cinfo, err := cont.GetInfo()
+		if err != nil {
+			continue
+		}
+		// Only the latest sample is copied out of storage.
+		stats, err := self.memoryStorage.LatestStats(cinfo.Name)
+		if err != nil || stats == nil {
+			continue
+		}
+		containersMap[name] = &info.ContainerInfo{
+			ContainerReference: cinfo.ContainerReference,
+			Subcontainers:      cinfo.Subcontainers,
+			Spec:               self.getAdjustedSpec(cinfo),
+			Stats:              []*info.ContainerStats{stats},
+		}
+	}
+	return containersMap, nil
+}
+
+func (self *manager) GetContainersInfoByLabels(selector map[string]string, prefixMatch bool, options v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	containers := func() []*containerData {
+		self.containersLock.RLock()
+		defer self.containersLock.RUnlock()
+
+		names := self.labels.find(selector, prefixMatch)
+		containers := make([]*containerData, 0, len(names))
+		for _, name := range names {
+			if cont, ok := self.containers[namespacedContainerName{Name: name}]; ok {
+				containers = append(containers, cont)
+			}
+		}
+		return containers
+	}()
+
+	containersMap := make(map[string]*info.ContainerInfo, len(containers))
+	query := info.ContainerInfoRequest{
+		NumStats: options.Count,
+	}
+	for _, cont := range containers {
+		info, err := self.containerDataToContainerInfo(cont, &query)
+		if err != nil {
+			// Skip containers with errors, we try to degrade gracefully.
+			continue
+		}
+		containersMap[info.Name] = info
+	}
+	return containersMap, nil
+}
+
+func (self *manager) GetCollectionHealth() map[string]v2.SubsystemHealth {
+	health := self.health.get()
+	for backend, h := range self.memoryStorage.BackendHealth() {
+		health["storage/"+backend] = h
+	}
+	return health
+}
+
+func (self *manager) GetProcessList(containerName string, options v2.RequestOptions) ([]v2.ProcessInfo, error) {
+	conts, err := self.getRequestedContainers(containerName, options)
+	if err != nil {
+		return nil, err
+	}
+	pids := []int{}
+	seen := make(map[int]bool)
+	for _, cont := range conts {
+		contPids, err := cont.handler.ListProcesses(container.ListSelf)
+		if err != nil {
+			return nil, err
+		}
+		for _, pid := range contPids {
+			if !seen[pid] {
+				seen[pid] = true
+				pids = append(pids, pid)
+			}
+		}
+	}
+	return sampleProcesses("/proc", pids), nil
+}
+
+func (self *manager) SetHousekeepingInterval(containerName string, interval time.Duration) (time.Duration, error) {
+	cont, err := self.getContainerData(containerName)
+	if err != nil {
+		return 0, err
+	}
+	interval = cont.SetHousekeepingInterval(interval)
+	glog.V(2).Infof("Set housekeeping interval of %q to %v", containerName, interval)
+	return interval, nil
+}
+
+func (self *manager) SetAnnotations(containerName string, annotations map[string]string) error {
+	cont, err := self.getContainerData(containerName)
+	if err != nil {
+		return err
+	}
+	cont.SetAnnotations(annotations)
+	glog.V(2).Infof("Set annotations of %q to %v", containerName, annotations)
+	return nil
+}
+
+func (self *manager) GetCgroupConfig(containerName string) (map[string]string, error) {
+	cont, err := self.getContainerData(containerName)
+	if err != nil {
+		return nil, err
+	}
+	return libcontainer.GetCgroupConfig(cont.handler.GetCgroupPath)
+}
+
 func (self *manager) getRequestedContainers(containerName string, options v2.RequestOptions) (map[string]*containerData, error) {
 	containersMap := make(map[string]*containerData)
+	if !options.Recursive {
+		cont, err := self.getRequestedContainer(containerName, options.IdType)
+		if err != nil {
+			return containersMap, err
+		}
+		containersMap[cont.info.Name] = cont
+		return containersMap, nil
+	}
 	switch options.IdType {
 	case v2.TypeName:
-		if options.Recursive == false {
-			cont, err := self.getContainer(containerName)
-			if err != nil {
-				return containersMap, err
-			}
-			containersMap[cont.info.Name] = cont
-		} else {
-			containersMap = self.getSubcontainers(containerName)
-			if len(containersMap) == 0 {
-				return containersMap, fmt.Errorf("unknown container: %q", containerName)
-			}
+		containersMap = self.getSubcontainers(containerName, options.Depth)
+		if len(containersMap) == 0 {
+			return containersMap, &ContainerNotFoundError{Name: containerName}
 		}
 	case v2.TypeDocker:
-		if options.Recursive == false {
-			containerName = strings.TrimPrefix(containerName, "/")
-			cont, err := self.getDockerContainer(containerName)
-			if err != nil {
-				return containersMap, err
-			}
-			containersMap[cont.info.Name] = cont
-		} else {
-			if containerName != "/" {
-				return containersMap, fmt.Errorf("invalid request for docker container %q with subcontainers", containerName)
-			}
-			containersMap = self.getAllDockerContainers()
+		if containerName != "/" {
+			return containersMap, fmt.Errorf("invalid request for docker container %q with subcontainers", containerName)
 		}
+		containersMap = self.getAllDockerContainers()
 	default:
 		return containersMap, fmt.Errorf("invalid request type %q", options.IdType)
 	}
 	return containersMap, nil
 }
 
+// Returns the container a non recursive request is for.
+func (self *manager) getRequestedContainer(containerName string, idType string) (*containerData, error) {
+	switch idType {
+	case v2.TypeName:
+		return self.getContainer(containerName)
+	case v2.TypeDocker:
+		return self.getDockerContainer(strings.TrimPrefix(containerName, "/"))
+	default:
+		return nil, fmt.Errorf("invalid request type %q", idType)
+	}
+}
+
 func (self *manager) GetFsInfo(label string) ([]v2.FsInfo, error) {
 	var empty time.Time
 	// Get latest data from filesystems hanging off root container.
@@ -609,14 +1116,39 @@ func (self *manager) GetFsInfo(label string) ([]v2.FsInfo, error) {
 }
 
 func (m *manager) GetMachineInfo() (*info.MachineInfo, error) {
+	m.numaLock.Lock()
+	defer m.numaLock.Unlock()
+	// The free memory and huge pages of the NUMA nodes change all the time.
+	// They are read again at most every second, as the handlers get the
+	// machine info along with the spec of each container.
+	if len(m.machineInfo.NumaNodes) > 0 && time.Since(m.numaNodesUpdateTime) > time.Second {
+		numaNodes, err := sysinfo.GetNumaNodes(numaNodesDir)
+		if err != nil {
+			glog.V(2).Infof("Failed to refresh NUMA nodes: %v", err)
+		} else if len(numaNodes) > 0 {
+			m.machineInfo.NumaNodes = numaNodes
+		}
+		m.numaNodesUpdateTime = time.Now()
+	}
 	// Copy and return the MachineInfo.
-	return &m.machineInfo, nil
+	machineInfo := m.machineInfo
+	return &machineInfo, nil
 }
 
 func (m *manager) GetVersionInfo() (*info.VersionInfo, error) {
 	return &m.versionInfo, nil
 }
 
+func (m *manager) GetAttributes() (v2.Attributes, error) {
+	machineInfo, err := m.GetMachineInfo()
+	if err != nil {
+		return v2.Attributes{}, err
+	}
+	attributes := v2.GetAttributes(machineInfo, &m.versionInfo)
+	attributes.ContainerHandlers = container.FactoryNames()
+	return attributes, nil
+}
+
 // Create a container.
 func (m *manager) createContainer(containerName string) error {
 	handler, err := container.NewContainerHandler(containerName)
@@ -624,7 +1156,7 @@ func (m *manager) createContainer(containerName string) error {
 		return err
 	}
 	logUsage := *logCadvisorUsage && containerName == m.cadvisorContainer
-	cont, err := newContainerData(containerName, m.memoryStorage, handler, m.loadReader, logUsage)
+	cont, err := newContainerData(containerName, m.memoryStorage, handler, m.loadReader, m.nvidiaManager, m.health, logUsage)
 	if err != nil {
 		return err
 	}
@@ -652,6 +1184,8 @@ func (m *manager) createContainer(containerName string) error {
 				Name:      alias,
 			}] = cont
 		}
+		m.labels.add(containerName, cont.info.Spec.Labels)
+		m.notifyTopologyWatchers(v2.TopologyAdded, cont)
 
 		return false
 	}()
@@ -683,12 +1217,91 @@ func (m *manager) createContainer(containerName string) error {
 		}
 	}
 
+	err = m.detectRestartLoop(cont)
+	if err != nil {
+		return err
+	}
+
+	err = m.registerCollectors(cont)
+	if err != nil {
+		glog.Warningf("Failed to register the collectors of container %q: %v", containerName, err)
+	}
+
 	// Start the container's housekeeping.
 	cont.Start()
 
 	return nil
 }
 
+// Adds a restart loop event if the container, which was just seen starting,
+// was started too many times recently. The time it is seen starting is used
+// rather than its creation time, which Docker keeps across restarts.
+func (m *manager) detectRestartLoop(cont *containerData) error {
+	if m.restartLoopDetector == nil {
+		return nil
+	}
+	contRef, err := cont.handler.ContainerReference()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	loop := m.restartLoopDetector.ObserveStart(contRef.Name, now)
+	if loop == nil {
+		return nil
+	}
+	glog.Warningf("Container %q was started %d times within %v", contRef.Name, loop.Starts, loop.Window)
+	return m.eventHandler.AddEvent(&events.Event{
+		ContainerName: contRef.Name,
+		Timestamp:     now,
+		EventType:     events.TypeRestartLoop,
+		EventData:     loop,
+	})
+}
+
+// Prefix of the container labels pointing to the configuration of a collector
+// of the custom metrics of an application in the container, relative to
+// -collector_config_dir, e.g. "io.cadvisor.metric.nginx=nginx.json".
+const collectorLabelPrefix = "io.cadvisor.metric."
+
+// Registers the collectors configured by the labels of the container.
+func (m *manager) registerCollectors(cont *containerData) error {
+	var collectors []collector.Collector
+	for label, configPath := range cont.info.Spec.Labels {
+		if !strings.HasPrefix(label, collectorLabelPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(label, collectorLabelPrefix)
+		configFile, err := collector.ReadConfigFile(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read the configuration of collector %q: %v", name, err)
+		}
+		c, err := collector.NewGenericCollector(name, configFile, cont.handler.GetContainerIPAddress())
+		if err != nil {
+			return err
+		}
+		collectors = append(collectors, c)
+	}
+	if len(collectors) == 0 {
+		return nil
+	}
+
+	collectorManager, err := collector.NewCollectorManager()
+	if err != nil {
+		return err
+	}
+	for _, c := range collectors {
+		err = collectorManager.RegisterCollector(c)
+		if err != nil {
+			return err
+		}
+	}
+	cont.lock.Lock()
+	defer cont.lock.Unlock()
+	cont.collectorManager = collectorManager
+	cont.info.Spec.CustomMetrics = collectorManager.GetSpec()
+	return nil
+}
+
 func (m *manager) destroyContainer(containerName string) error {
 	m.containersLock.Lock()
 	defer m.containersLock.Unlock()
@@ -716,6 +1329,9 @@ func (m *manager) destroyContainer(containerName string) error {
 			Name:      alias,
 		})
 	}
+	m.labels.remove(containerName)
+	m.memoryStorage.RemoveContainer(cont.info.Name)
+	m.notifyTopologyWatchers(v2.TopologyRemoved, cont)
 	glog.V(2).Infof("Destroyed container: %q (aliases: %v, namespace: %q)", containerName, cont.info.Aliases, cont.info.Namespace)
 
 	contRef, err := cont.handler.ContainerReference()
@@ -728,11 +1344,32 @@ func (m *manager) destroyContainer(containerName string) error {
 		Timestamp:     time.Now(),
 		EventType:     events.TypeContainerDeletion,
 	}
-	err = m.eventHandler.AddEvent(newEvent)
+	if handler, ok := cont.handler.(container.ExitStatusHandler); ok {
+		// The runtime may take a while to record how the container exited,
+		// so the event is added once it does rather than holding back the
+		// other watch events.
+		m.pendingDeletions.Add(1)
+		go func() {
+			defer m.pendingDeletions.Done()
+			m.addDeletionEvent(newEvent, handler)
+		}()
+		return nil
+	}
+	return m.eventHandler.AddEvent(newEvent)
+}
+
+// Adds the deletion event of a container with how it exited, if its handler
+// knows.
+func (m *manager) addDeletionEvent(event *events.Event, handler container.ExitStatusHandler) {
+	exitStatus, err := handler.GetExitStatus()
 	if err != nil {
-		return err
+		glog.V(2).Infof("Failed to get the exit status of container %q: %v", event.ContainerName, err)
+	} else if exitStatus != nil {
+		event.EventData = exitStatus
+	}
+	if err := m.eventHandler.AddEvent(event); err != nil {
+		glog.Errorf("Failed to add the deletion event of container %q: %v", event.ContainerName, err)
 	}
-	return nil
 }
 
 // Detect all containers that have been added or deleted from the specified container.
@@ -878,7 +1515,7 @@ func (self *manager) watchForNewOoms() error {
 				ContainerName: oomInstance.ContainerName,
 				Timestamp:     oomInstance.TimeOfDeath,
 				EventType:     events.TypeOom,
-				EventData:     oomInstance,
+				EventData:     self.getOomKillDetails(oomInstance),
 			}
 			glog.V(1).Infof("Created an oom event: %v", newEvent)
 			err := self.eventHandler.AddEvent(newEvent)
@@ -890,6 +1527,29 @@ func (self *manager) watchForNewOoms() error {
 	return nil
 }
 
+// Builds the details of an OOM event. If the kernel did not report the memory
+// limit of the container, the limit from its last known spec is used.
+func (self *manager) getOomKillDetails(oomInstance *oomparser.OomInstance) *info.OomKillDetails {
+	details := &info.OomKillDetails{
+		Pid:           oomInstance.Pid,
+		ProcessName:   oomInstance.ProcessName,
+		TimeOfDeath:   oomInstance.TimeOfDeath,
+		ContainerName: oomInstance.ContainerName,
+		MemoryLimit:   oomInstance.MemoryLimit,
+	}
+	if details.MemoryLimit == 0 {
+		cont, err := self.getContainerData(oomInstance.ContainerName)
+		if err == nil {
+			cont.lock.Lock()
+			if cont.info.Spec.HasMemory {
+				details.MemoryLimit = cont.info.Spec.Memory.Limit
+			}
+			cont.lock.Unlock()
+		}
+	}
+	return details
+}
+
 // can be called by the api which will take events returned on the channel
 func (self *manager) WatchForEvents(request *events.Request) (*events.EventChannel, error) {
 	return self.eventHandler.WatchEvents(request)
@@ -900,6 +1560,15 @@ func (self *manager) GetPastEvents(request *events.Request) (events.EventSlice,
 	return self.eventHandler.GetEvents(request)
 }
 
+func (self *manager) GetEventsEpoch() int64 {
+	return self.eventHandler.Epoch()
+}
+
+// can be called by the api to purge the events satisfying the request
+func (self *manager) DeleteEvents(request *events.Request) (int, error) {
+	return self.eventHandler.RemoveEvents(request.Matches)
+}
+
 // called by the api when a client is no longer listening to the channel
 func (self *manager) CloseEventChannel(watch_id int) {
 	self.eventHandler.StopWatch(watch_id)
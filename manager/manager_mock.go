@@ -15,6 +15,8 @@
 package manager
 
 import (
+	"time"
+
 	"github.com/google/cadvisor/events"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/google/cadvisor/info/v2"
@@ -35,6 +37,11 @@ func (c *ManagerMock) Stop() error {
 	return args.Error(0)
 }
 
+func (c *ManagerMock) Shutdown() error {
+	args := c.Called()
+	return args.Error(0)
+}
+
 func (c *ManagerMock) GetContainerInfo(name string, query *info.ContainerInfoRequest) (*info.ContainerInfo, error) {
 	args := c.Called(name, query)
 	return args.Get(0).(*info.ContainerInfo), args.Error(1)
@@ -60,6 +67,11 @@ func (c *ManagerMock) GetContainerSpec(containerName string, options v2.RequestO
 	return args.Get(0).(map[string]v2.ContainerSpec), args.Error(1)
 }
 
+func (c *ManagerMock) GetContainerSpecs(containerName string, options v2.RequestOptions) (map[string]v2.ContainerSpec, error) {
+	args := c.Called(containerName, options)
+	return args.Get(0).(map[string]v2.ContainerSpec), args.Error(1)
+}
+
 func (c *ManagerMock) GetDerivedStats(containerName string, options v2.RequestOptions) (map[string]v2.DerivedStats, error) {
 	args := c.Called(containerName, options)
 	return args.Get(0).(map[string]v2.DerivedStats), args.Error(1)
@@ -70,6 +82,84 @@ func (c *ManagerMock) GetRequestedContainersInfo(containerName string, options v
 	return args.Get(0).(map[string]*info.ContainerInfo), args.Error(1)
 }
 
+func (c *ManagerMock) GetContainersInfoByLabels(selector map[string]string, prefixMatch bool, options v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	args := c.Called(selector, prefixMatch, options)
+	return args.Get(0).(map[string]*info.ContainerInfo), args.Error(1)
+}
+
+func (c *ManagerMock) GetAggregatedContainerInfo(containerName string, options v2.RequestOptions) (*info.ContainerInfo, error) {
+	args := c.Called(containerName, options)
+	return args.Get(0).(*info.ContainerInfo), args.Error(1)
+}
+
+func (c *ManagerMock) GetTopContainers(by string, limit int) ([]v2.TopContainer, error) {
+	args := c.Called(by, limit)
+	return args.Get(0).([]v2.TopContainer), args.Error(1)
+}
+
+func (c *ManagerMock) GetImageUsage() ([]v2.ImageUsage, error) {
+	args := c.Called()
+	return args.Get(0).([]v2.ImageUsage), args.Error(1)
+}
+
+func (c *ManagerMock) GetLatestContainersInfo(containerName string, options v2.RequestOptions) (map[string]*info.ContainerInfo, error) {
+	args := c.Called(containerName, options)
+	return args.Get(0).(map[string]*info.ContainerInfo), args.Error(1)
+}
+
+func (c *ManagerMock) GetProcessList(containerName string, options v2.RequestOptions) ([]v2.ProcessInfo, error) {
+	args := c.Called(containerName, options)
+	return args.Get(0).([]v2.ProcessInfo), args.Error(1)
+}
+
+func (c *ManagerMock) ResolveCgroup(cgroupPath string) (v2.ResolvedContainer, error) {
+	args := c.Called(cgroupPath)
+	return args.Get(0).(v2.ResolvedContainer), args.Error(1)
+}
+
+func (c *ManagerMock) ResolveName(name string) (v2.ResolvedContainer, error) {
+	args := c.Called(name)
+	return args.Get(0).(v2.ResolvedContainer), args.Error(1)
+}
+
+func (c *ManagerMock) GetCgroupConfig(containerName string) (map[string]string, error) {
+	args := c.Called(containerName)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (c *ManagerMock) GetCollectionHealth() map[string]v2.SubsystemHealth {
+	args := c.Called()
+	return args.Get(0).(map[string]v2.SubsystemHealth)
+}
+
+func (c *ManagerMock) WatchStats(containerName string) (*StatsChannel, error) {
+	args := c.Called(containerName)
+	return args.Get(0).(*StatsChannel), args.Error(1)
+}
+
+func (c *ManagerMock) CloseStatsChannel(statsChannel *StatsChannel) {
+	c.Called(statsChannel)
+}
+
+func (c *ManagerMock) WatchTopology() (*TopologyChannel, error) {
+	args := c.Called()
+	return args.Get(0).(*TopologyChannel), args.Error(1)
+}
+
+func (c *ManagerMock) CloseTopologyChannel(topologyChannel *TopologyChannel) {
+	c.Called(topologyChannel)
+}
+
+func (c *ManagerMock) SetHousekeepingInterval(containerName string, interval time.Duration) (time.Duration, error) {
+	args := c.Called(containerName, interval)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (c *ManagerMock) SetAnnotations(containerName string, annotations map[string]string) error {
+	args := c.Called(containerName, annotations)
+	return args.Error(0)
+}
+
 func (c *ManagerMock) WatchForEvents(queryuest *events.Request, passedChannel chan *events.Event) error {
 	args := c.Called(queryuest, passedChannel)
 	return args.Error(0)
@@ -80,6 +170,16 @@ func (c *ManagerMock) GetPastEvents(queryuest *events.Request) (events.EventSlic
 	return args.Get(0).(events.EventSlice), args.Error(1)
 }
 
+func (c *ManagerMock) GetEventsEpoch() int64 {
+	args := c.Called()
+	return args.Get(0).(int64)
+}
+
+func (c *ManagerMock) DeleteEvents(request *events.Request) (int, error) {
+	args := c.Called(request)
+	return args.Int(0), args.Error(1)
+}
+
 func (c *ManagerMock) GetMachineInfo() (*info.MachineInfo, error) {
 	args := c.Called()
 	return args.Get(0).(*info.MachineInfo), args.Error(1)
@@ -90,6 +190,11 @@ func (c *ManagerMock) GetVersionInfo() (*info.VersionInfo, error) {
 	return args.Get(0).(*info.VersionInfo), args.Error(1)
 }
 
+func (c *ManagerMock) GetAttributes() (v2.Attributes, error) {
+	args := c.Called()
+	return args.Get(0).(v2.Attributes), args.Error(1)
+}
+
 func (c *ManagerMock) GetFsInfo() ([]v2.FsInfo, error) {
 	args := c.Called()
 	return args.Get(0).([]v2.FsInfo), args.Error(1)
@@ -17,17 +17,26 @@
 package manager
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/cadvisor/container"
 	"github.com/google/cadvisor/container/docker"
+	"github.com/google/cadvisor/events"
 	info "github.com/google/cadvisor/info/v1"
 	itest "github.com/google/cadvisor/info/v1/test"
+	"github.com/google/cadvisor/info/v2"
 	"github.com/google/cadvisor/storage/memory"
+	stest "github.com/google/cadvisor/storage/test"
 	"github.com/google/cadvisor/utils/sysfs/fakesysfs"
+	"github.com/stretchr/testify/mock"
 )
 
 // TODO(vmarmol): Refactor these tests.
@@ -52,7 +61,7 @@ func createManagerAndAddContainers(
 			spec,
 			nil,
 		).Once()
-		cont, err := newContainerData(name, memoryStorage, mockHandler, nil, false)
+		cont, err := newContainerData(name, memoryStorage, mockHandler, nil, nil, nil, false)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -203,9 +212,474 @@ func TestDockerContainersInfo(t *testing.T) {
 	}
 }
 
+func TestGetRequestedContainersDepth(t *testing.T) {
+	containers := []string{"/", "/docker", "/docker/abc", "/docker/abc/nested", "/system"}
+	query := &info.ContainerInfoRequest{NumStats: 1}
+	m, _, _ := expectManagerWithContainers(containers, query, t)
+
+	testCases := []struct {
+		name     string
+		depth    int
+		expected []string
+	}{
+		{"/", 0, containers},
+		{"/", 1, []string{"/", "/docker", "/system"}},
+		{"/", 2, []string{"/", "/docker", "/docker/abc", "/system"}},
+		{"/docker", 1, []string{"/docker", "/docker/abc"}},
+	}
+	for _, testCase := range testCases {
+		result, err := m.getRequestedContainers(testCase.name, v2.RequestOptions{IdType: v2.TypeName, Recursive: true, Depth: testCase.depth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for name := range result {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, testCase.expected) {
+			t.Errorf("expected %v below %q with depth %d, got %v", testCase.expected, testCase.name, testCase.depth, names)
+		}
+	}
+}
+
 func TestNewNilManager(t *testing.T) {
-	_, err := New(nil, nil)
+	_, err := New(nil, nil, container.MetricSet{})
 	if err == nil {
 		t.Fatalf("Expected nil manager to return error")
 	}
 }
+
+func TestGetV2SpecUptime(t *testing.T) {
+	m := &manager{}
+	cinfo := &containerInfo{
+		Spec: info.ContainerSpec{CreationTime: time.Now().Add(-time.Hour)},
+	}
+	uptime := m.getV2Spec(cinfo).Uptime
+	if uptime < time.Hour || uptime > time.Hour+time.Minute {
+		t.Errorf("expected an uptime of about an hour, got %v", uptime)
+	}
+
+	// The uptime is unknown along with the creation time.
+	cinfo.Spec.CreationTime = time.Time{}
+	if uptime := m.getV2Spec(cinfo).Uptime; uptime != 0 {
+		t.Errorf("expected no uptime without a creation time, got %v", uptime)
+	}
+}
+
+func TestGetAncestry(t *testing.T) {
+	m := createManagerAndAddContainers(
+		memory.New(1, nil),
+		&fakesysfs.FakeSysFs{},
+		[]string{"/", "/kubepods", "/kubepods/burstable/pod1"},
+		func(h *container.MockContainerHandler) {},
+		t,
+	)
+	// /kubepods/burstable is not a container.
+	if ancestry := m.getAncestry("/kubepods/burstable/pod1"); !reflect.DeepEqual(ancestry, []string{"/kubepods", "/"}) {
+		t.Errorf("unexpected ancestry of /kubepods/burstable/pod1: %v", ancestry)
+	}
+	if ancestry := m.getAncestry("/kubepods"); !reflect.DeepEqual(ancestry, []string{"/"}) {
+		t.Errorf("unexpected ancestry of /kubepods: %v", ancestry)
+	}
+	if ancestry := m.getAncestry("/"); ancestry != nil {
+		t.Errorf("expected the root container to have no ancestry, got %v", ancestry)
+	}
+}
+
+func TestWatchTopology(t *testing.T) {
+	m := createManagerAndAddContainers(
+		memory.New(1, nil),
+		&fakesysfs.FakeSysFs{},
+		[]string{"/kubepods/pod1", "/", "/kubepods"},
+		func(h *container.MockContainerHandler) {},
+		t,
+	)
+	m.eventHandler = events.NewEventManager()
+	topologyChannel, err := m.WatchTopology()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var containers []string
+	for _, change := range topologyChannel.GetContainers() {
+		if change.Type != v2.TopologyAdded {
+			t.Errorf("expected the current containers to be added, got %+v", change)
+		}
+		containers = append(containers, change.Name+"<"+change.Parent)
+	}
+	if expected := []string{"/<", "/kubepods</", "/kubepods/pod1</kubepods"}; !reflect.DeepEqual(containers, expected) {
+		t.Errorf("expected containers %v, got %v", expected, containers)
+	}
+
+	if err := m.destroyContainer("/kubepods/pod1"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case change := <-topologyChannel.GetChannel():
+		if change.Type != v2.TopologyRemoved || change.Name != "/kubepods/pod1" || change.Parent != "/kubepods" {
+			t.Errorf("unexpected change %+v", change)
+		}
+	default:
+		t.Fatal("expected the removal of /kubepods/pod1")
+	}
+
+	// Watchers which fall behind are closed.
+	cont, err := m.getContainerData("/kubepods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= topologyChannelSize; i++ {
+		m.notifyTopologyWatchers(v2.TopologyAdded, cont)
+	}
+	changes := 0
+	for range topologyChannel.GetChannel() {
+		changes++
+	}
+	if changes != topologyChannelSize {
+		t.Errorf("expected %d changes before the channel is closed, got %d", topologyChannelSize, changes)
+	}
+	// Closing it again is a no-op.
+	m.CloseTopologyChannel(topologyChannel)
+}
+
+func TestResolveContainer(t *testing.T) {
+	m := createManagerAndAddContainers(
+		memory.New(1, nil),
+		&fakesysfs.FakeSysFs{},
+		[]string{"/system", "/docker/abc"},
+		func(h *container.MockContainerHandler) {
+			h.On("GetCgroupPath", "memory").Return("/sys/fs/cgroup/memory"+h.Name, nil)
+			h.On("GetCgroupPath", mock.Anything).Return("", errors.New("not mounted"))
+		},
+		t,
+	)
+
+	resolved, err := m.ResolveCgroup("/docker/abc/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Name != "/docker/abc" || resolved.CgroupPaths["memory"] != "/sys/fs/cgroup/memory/docker/abc" || len(resolved.CgroupPaths) != 1 {
+		t.Errorf("unexpected container resolved from its name: %+v", resolved)
+	}
+	resolved, err = m.ResolveCgroup("/sys/fs/cgroup/memory/system")
+	if err != nil || resolved.Name != "/system" {
+		t.Errorf("expected /system to be resolved from its absolute cgroup path, got %+v and error %v", resolved, err)
+	}
+	_, err = m.ResolveCgroup("/sys/fs/cgroup/memory/user")
+	if _, ok := err.(*ContainerNotFoundError); !ok {
+		t.Errorf("expected a ContainerNotFoundError for an unknown cgroup, got %v", err)
+	}
+
+	// Docker containers are also known by their alias.
+	resolved, err = m.ResolveName("abc")
+	if err != nil || resolved.Name != "/docker/abc" {
+		t.Errorf("expected /docker/abc to be resolved from its alias, got %+v and error %v", resolved, err)
+	}
+	_, err = m.ResolveName("def")
+	if _, ok := err.(*ContainerNotFoundError); !ok {
+		t.Errorf("expected a ContainerNotFoundError for an unknown name, got %v", err)
+	}
+}
+
+func TestGetTopContainers(t *testing.T) {
+	memoryStorage := memory.New(10, nil)
+	m := createManagerAndAddContainers(
+		memoryStorage,
+		&fakesysfs.FakeSysFs{},
+		[]string{"/docker", "/docker/a", "/docker/b", "/docker/c"},
+		func(h *container.MockContainerHandler) {
+			h.On("GetSpec").Return(info.ContainerSpec{}, nil)
+			var subcontainers []info.ContainerReference
+			if h.Name == "/docker" {
+				subcontainers = []info.ContainerReference{{Name: "/docker/a"}, {Name: "/docker/b"}, {Name: "/docker/c"}}
+			}
+			h.On("ListContainers", container.ListSelf).Return(subcontainers, nil)
+		},
+		t,
+	)
+	now := time.Now()
+	addStats := func(name string, offset time.Duration, cpu, workingSet uint64) {
+		stats := &info.ContainerStats{Timestamp: now.Add(offset)}
+		stats.Cpu.Usage.Total = cpu
+		stats.Memory.WorkingSet = workingSet
+		if err := memoryStorage.AddStats(info.ContainerReference{Name: name}, stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addStats("/docker", 0, 0, 0)
+	addStats("/docker", time.Second, uint64(10*time.Second), 3000)
+	addStats("/docker/a", 0, 0, 0)
+	addStats("/docker/a", time.Second, uint64(time.Second), 1000)
+	addStats("/docker/b", 0, 0, 0)
+	addStats("/docker/b", 2*time.Second, uint64(6*time.Second), 500)
+	// A single sample has no CPU rate.
+	addStats("/docker/c", 0, uint64(time.Second), 2000)
+
+	top, err := m.GetTopContainers(v2.TopByCpu, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].Name != "/docker/b" || top[0].Value != 3 || top[1].Name != "/docker/a" || top[1].Value != 1 {
+		t.Errorf("unexpected containers ranked by CPU: %+v", top)
+	}
+
+	top, err = m.GetTopContainers(v2.TopByMemory, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].Name != "/docker/c" || top[0].Value != 2000 || top[1].Name != "/docker/a" {
+		t.Errorf("unexpected containers ranked by memory: %+v", top)
+	}
+
+	if _, err := m.GetTopContainers("disk", 10); err == nil {
+		t.Error("expected an error ranking containers by an unknown metric")
+	}
+}
+
+func TestGetImageUsage(t *testing.T) {
+	memoryStorage := memory.New(10, nil)
+	images := map[string]string{"/docker/a": "nginx", "/docker/b": "redis", "/docker/c": "nginx"}
+	m := createManagerAndAddContainers(
+		memoryStorage,
+		&fakesysfs.FakeSysFs{},
+		[]string{"/docker", "/docker/a", "/docker/b", "/docker/c"},
+		func(h *container.MockContainerHandler) {
+			h.On("GetSpec").Return(info.ContainerSpec{Image: images[h.Name]}, nil)
+		},
+		t,
+	)
+	now := time.Now()
+	addStats := func(name string, offset time.Duration, cpu, usage, workingSet uint64) {
+		stats := &info.ContainerStats{Timestamp: now.Add(offset)}
+		stats.Cpu.Usage.Total = cpu
+		stats.Memory.Usage = usage
+		stats.Memory.WorkingSet = workingSet
+		if err := memoryStorage.AddStats(info.ContainerReference{Name: name}, stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addStats("/docker", 0, 0, 10000, 10000)
+	addStats("/docker/a", 0, 0, 0, 0)
+	addStats("/docker/a", time.Second, uint64(time.Second), 2000, 1000)
+	addStats("/docker/b", 0, 0, 0, 0)
+	addStats("/docker/b", time.Second, uint64(2*time.Second), 4000, 3000)
+	// A single sample has no CPU rate but counts for the memory.
+	addStats("/docker/c", 0, uint64(time.Second), 3000, 2500)
+
+	usage, err := m.GetImageUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []v2.ImageUsage{
+		{Image: "nginx", Containers: 2, Cpu: 1, Memory: 5000, MemoryWorkingSet: 3500},
+		{Image: "redis", Containers: 1, Cpu: 2, Memory: 4000, MemoryWorkingSet: 3000},
+	}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("expected image usage %+v, got %+v", expected, usage)
+	}
+}
+
+func TestEventTypesRequest(t *testing.T) {
+	request, err := eventTypesRequest("oom, deletion,restart_loop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[events.EventType]bool{events.TypeOom: true, events.TypeContainerDeletion: true, events.TypeRestartLoop: true}
+	if !reflect.DeepEqual(request.EventType, expected) {
+		t.Errorf("expected event types %v, got %v", expected, request.EventType)
+	}
+
+	for _, types := range []string{"oom,restart", ""} {
+		if _, err := eventTypesRequest(types); err == nil {
+			t.Errorf("expected an error for event types %q", types)
+		}
+	}
+}
+
+func TestDetectRestartLoop(t *testing.T) {
+	m := &manager{
+		eventHandler:        events.NewEventManager(),
+		restartLoopDetector: events.NewRestartLoopDetector(1, time.Minute),
+	}
+	// The creation time of the container does not matter, e.g. for a Docker
+	// container created before cAdvisor started and restarted since.
+	handler := container.NewMockContainerHandler("/docker/restarted")
+	handler.On("GetSpec").Return(info.ContainerSpec{CreationTime: time.Unix(0, 0)}, nil)
+	cont, err := newContainerData("/docker/restarted", memory.New(1, nil), handler, nil, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := events.NewRequest()
+	request.EventType[events.TypeRestartLoop] = true
+	for i, expected := range []int{0, 1} {
+		if err := m.detectRestartLoop(cont); err != nil {
+			t.Fatal(err)
+		}
+		loops, err := m.eventHandler.GetEvents(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loops) != expected {
+			t.Errorf("expected %d restart loop events after %d starts, got %v", expected, i+1, loops)
+		}
+	}
+}
+
+// Handler whose runtime reports how the container exited.
+type exitedContainerHandler struct {
+	*container.MockContainerHandler
+	exitStatus *info.ContainerExitStatus
+}
+
+func (self *exitedContainerHandler) GetExitStatus() (*info.ContainerExitStatus, error) {
+	return self.exitStatus, nil
+}
+
+func TestDestroyContainerExitStatus(t *testing.T) {
+	exitStatus := &info.ContainerExitStatus{ExitCode: 137, OomKilled: true, FinishedAt: time.Now()}
+	m := &manager{
+		containers:    make(map[namespacedContainerName]*containerData),
+		eventHandler:  events.NewEventManager(),
+		memoryStorage: memory.New(1, nil),
+	}
+	mockHandler := func(name string) *container.MockContainerHandler {
+		h := container.NewMockContainerHandler(name)
+		h.On("GetSpec").Return(info.ContainerSpec{}, nil)
+		return h
+	}
+	handlers := map[string]container.ContainerHandler{
+		"/docker/exited": &exitedContainerHandler{mockHandler("/docker/exited"), exitStatus},
+		"/raw":           mockHandler("/raw"),
+	}
+	for name, handler := range handlers {
+		cont, err := newContainerData(name, m.memoryStorage, handler, nil, nil, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.containers[namespacedContainerName{Name: name}] = cont
+		if err := m.destroyContainer(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.pendingDeletions.Wait()
+
+	request := events.NewRequest()
+	request.EventType[events.TypeContainerDeletion] = true
+	request.MaxEventsReturned = 10
+	deletions, err := m.GetPastEvents(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deletions) != 2 {
+		t.Fatalf("expected 2 deletion events, got %d", len(deletions))
+	}
+	for _, event := range deletions {
+		switch event.ContainerName {
+		case "/docker/exited":
+			if event.EventData != exitStatus {
+				t.Errorf("expected the exit status in the deletion event, got %+v", event.EventData)
+			}
+		case "/raw":
+			if event.EventData != nil {
+				t.Errorf("unexpected data in the deletion event of a handler without exit status: %+v", event.EventData)
+			}
+		}
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	backend := &stest.MockStorageDriver{MockCloseMethod: true}
+	backend.On("Close").Return(nil)
+	m := &manager{
+		containers:    make(map[namespacedContainerName]*containerData),
+		eventHandler:  events.NewEventManager(),
+		memoryStorage: memory.New(1, backend),
+	}
+	h := container.NewMockContainerHandler("/docker/abc")
+	h.On("GetSpec").Return(info.ContainerSpec{}, nil)
+	cont, err := newContainerData("/docker/abc", m.memoryStorage, h, nil, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The container is also listed under its alias.
+	m.containers[namespacedContainerName{Name: "/docker/abc"}] = cont
+	m.containers[namespacedContainerName{Namespace: "docker", Name: "web"}] = cont
+	// Stands for the housekeeping of the container.
+	go func() {
+		<-cont.stop
+		close(cont.stopped)
+	}()
+	watch, err := m.WatchForEvents(events.NewRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	backend.AssertExpectations(t)
+	if _, ok := <-watch.GetChannel(); ok {
+		t.Error("expected the event channel to be closed")
+	}
+	if len(m.containers) != 0 {
+		t.Errorf("expected no containers left, got %d", len(m.containers))
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { *ShutdownTimeout = timeout }(*ShutdownTimeout)
+	*ShutdownTimeout = 10 * time.Millisecond
+	m := &manager{
+		containers:    make(map[namespacedContainerName]*containerData),
+		eventHandler:  events.NewEventManager(),
+		memoryStorage: memory.New(1, nil),
+	}
+	h := container.NewMockContainerHandler("/stuck")
+	h.On("GetSpec").Return(info.ContainerSpec{}, nil)
+	cont, err := newContainerData("/stuck", m.memoryStorage, h, nil, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Housekeeping never stops.
+	m.containers[namespacedContainerName{Name: "/stuck"}] = cont
+
+	if err := m.Shutdown(); err == nil {
+		t.Error("expected an error when housekeeping does not stop in time")
+	}
+}
+
+func TestGetMachineInfoRefreshesNumaNodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(dir string) { numaNodesDir = dir }(numaNodesDir)
+	numaNodesDir = dir
+	if err := os.Mkdir(path.Join(dir, "node0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"cpulist": "0-1\n",
+		"meminfo": "Node 0 MemTotal:       1024 kB\nNode 0 MemFree:         256 kB\n",
+	}
+	for file, content := range files {
+		if err := ioutil.WriteFile(path.Join(dir, "node0", file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &manager{
+		machineInfo: info.MachineInfo{
+			NumaNodes: []info.NumaNode{{Id: 0, Cpus: []int{0, 1}, MemoryTotal: 1024 * 1024, MemoryFree: 512 * 1024}},
+		},
+	}
+	machineInfo, err := m.GetMachineInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []info.NumaNode{{Id: 0, Cpus: []int{0, 1}, MemoryTotal: 1024 * 1024, MemoryFree: 256 * 1024}}
+	if !reflect.DeepEqual(machineInfo.NumaNodes, expected) {
+		t.Errorf("expected NUMA nodes %+v, got %+v", expected, machineInfo.NumaNodes)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/user"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/cadvisor/info/v2"
+)
+
+const (
+	// Time between the two samples CPU usage of processes is computed from.
+	processSampleInterval = 250 * time.Millisecond
+
+	// Units of the CPU times in /proc/<pid>/stat. USER_HZ is 100 on all
+	// architectures we run on.
+	clockTicksPerSecond = 100
+)
+
+// Sample of a process read from /proc/<pid>/stat and /proc/<pid>/status.
+type processSample struct {
+	info v2.ProcessInfo
+	// User and system CPU time, in clock ticks.
+	cpuTicks uint64
+}
+
+// Reads a sample of the process from the proc filesystem mounted at procDir.
+func readProcessSample(procDir string, pid int) (processSample, error) {
+	pidDir := path.Join(procDir, strconv.Itoa(pid))
+	stat, err := ioutil.ReadFile(path.Join(pidDir, "stat"))
+	if err != nil {
+		return processSample{}, err
+	}
+	// The command name may contain spaces and parentheses, the fields start
+	// after its last closing parenthesis.
+	end := strings.LastIndex(string(stat), ")")
+	if end < 0 {
+		return processSample{}, fmt.Errorf("malformed stat of process %d: %q", pid, stat)
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 13 {
+		return processSample{}, fmt.Errorf("malformed stat of process %d: %q", pid, stat)
+	}
+	sample := processSample{}
+	sample.info.Pid = pid
+	sample.info.Ppid, err = strconv.Atoi(fields[1])
+	if err != nil {
+		return processSample{}, fmt.Errorf("malformed parent pid of process %d: %v", pid, err)
+	}
+	for _, field := range fields[11:13] {
+		ticks, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return processSample{}, fmt.Errorf("malformed cpu time of process %d: %v", pid, err)
+		}
+		sample.cpuTicks += ticks
+	}
+
+	status, err := ioutil.ReadFile(path.Join(pidDir, "status"))
+	if err != nil {
+		return processSample{}, err
+	}
+	uid := ""
+	for _, line := range strings.Split(string(status), "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.Fields(parts[1])
+		if len(value) == 0 {
+			continue
+		}
+		switch parts[0] {
+		case "Name":
+			sample.info.Command = value[0]
+		case "Uid":
+			// Real, effective, saved and filesystem uids.
+			uid = value[0]
+		case "VmRSS":
+			// Kernel threads have no VmRSS.
+			rss, err := strconv.ParseUint(value[0], 10, 64)
+			if err != nil {
+				return processSample{}, fmt.Errorf("malformed rss of process %d: %v", pid, err)
+			}
+			sample.info.Rss = rss * 1024
+		}
+	}
+	sample.info.User = lookupUser(uid)
+
+	// Prefer the full command line, kernel threads do not have one.
+	cmdline, err := ioutil.ReadFile(path.Join(pidDir, "cmdline"))
+	if err == nil && len(cmdline) > 0 {
+		sample.info.Command = strings.TrimSpace(strings.Replace(string(cmdline), "\x00", " ", -1))
+	}
+	return sample, nil
+}
+
+// Returns the name of the user, or the uid if it is not known. The user
+// database is the one of cAdvisor, which may not be the one of the container.
+func lookupUser(uid string) string {
+	u, err := user.LookupId(uid)
+	if err != nil {
+		return uid
+	}
+	return u.Username
+}
+
+// Reads the processes twice, processSampleInterval apart, to compute their
+// CPU usage. Processes that exit in between are left out.
+func sampleProcesses(procDir string, pids []int) []v2.ProcessInfo {
+	first := make(map[int]uint64, len(pids))
+	for _, pid := range pids {
+		sample, err := readProcessSample(procDir, pid)
+		if err != nil {
+			continue
+		}
+		first[pid] = sample.cpuTicks
+	}
+	start := time.Now()
+	time.Sleep(processSampleInterval)
+
+	processes := make([]v2.ProcessInfo, 0, len(first))
+	for pid, startTicks := range first {
+		sample, err := readProcessSample(procDir, pid)
+		if err != nil {
+			continue
+		}
+		elapsed := time.Since(start).Seconds()
+		if sample.cpuTicks > startTicks && elapsed > 0 {
+			cpuSeconds := float64(sample.cpuTicks-startTicks) / clockTicksPerSecond
+			sample.info.CpuPercent = cpuSeconds / elapsed * 100
+		}
+		processes = append(processes, sample.info)
+	}
+	return processes
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProcFile(t *testing.T, procDir, pid, file, content string) {
+	require.Nil(t, os.MkdirAll(path.Join(procDir, pid), 0755))
+	require.Nil(t, ioutil.WriteFile(path.Join(procDir, pid, file), []byte(content), 0644))
+}
+
+func TestReadProcessSample(t *testing.T) {
+	procDir, err := ioutil.TempDir("", "proc")
+	require.Nil(t, err)
+	defer os.RemoveAll(procDir)
+
+	writeProcFile(t, procDir, "42", "stat", "42 (my (odd) cmd) S 1 42 42 0 -1 4194560 500 0 0 0 150 50 0 0 20 0 1 0 1000 10000000 300 18446744073709551615\n")
+	writeProcFile(t, procDir, "42", "status", "Name:\tmy (odd) cmd\nState:\tS (sleeping)\nUid:\t12345\t12345\t12345\t12345\nVmRSS:\t    1200 kB\n")
+	writeProcFile(t, procDir, "42", "cmdline", "/bin/server\x00--port\x008080\x00")
+
+	sample, err := readProcessSample(procDir, 42)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(200), sample.cpuTicks)
+	assert.Equal(t, 42, sample.info.Pid)
+	assert.Equal(t, 1, sample.info.Ppid)
+	assert.Equal(t, uint64(1200*1024), sample.info.Rss)
+	assert.Equal(t, "/bin/server --port 8080", sample.info.Command)
+	// The uid is reported when there is no such user.
+	assert.Equal(t, "12345", sample.info.User)
+
+	// Kernel threads have no command line nor memory.
+	writeProcFile(t, procDir, "2", "stat", "2 (kthreadd) S 0 0 0 0 -1 2129984 0 0 0 0 0 3 0 0 20 0 1 0 2 0 0 18446744073709551615\n")
+	writeProcFile(t, procDir, "2", "status", "Name:\tkthreadd\nUid:\t0\t0\t0\t0\n")
+	writeProcFile(t, procDir, "2", "cmdline", "")
+	sample, err = readProcessSample(procDir, 2)
+	require.Nil(t, err)
+	assert.Equal(t, "kthreadd", sample.info.Command)
+	assert.Equal(t, uint64(0), sample.info.Rss)
+
+	_, err = readProcessSample(procDir, 3)
+	assert.NotNil(t, err)
+}
+
+func TestSampleProcesses(t *testing.T) {
+	procDir, err := ioutil.TempDir("", "proc")
+	require.Nil(t, err)
+	defer os.RemoveAll(procDir)
+	writeProcFile(t, procDir, "7", "stat", "7 (sleep) S 1 7 7 0 -1 0 0 0 0 0 1 1 0 0 20 0 1 0 1 0 0 0\n")
+	writeProcFile(t, procDir, "7", "status", "Name:\tsleep\nUid:\t0\t0\t0\t0\n")
+
+	// Processes that are gone are left out.
+	processes := sampleProcesses(procDir, []int{7, 8})
+	require.Equal(t, 1, len(processes))
+	assert.Equal(t, 7, processes[0].Pid)
+	assert.Equal(t, 0.0, processes[0].CpuPercent)
+}
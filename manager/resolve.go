@@ -0,0 +1,97 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/google/cadvisor/info/v2"
+)
+
+// Cgroup hierarchies whose paths are reported for resolved containers.
+var resolvedCgroupSubsystems = []string{"cpu", "cpuacct", "cpuset", "memory", "blkio", "devices"}
+
+func (self *manager) ResolveCgroup(cgroupPath string) (v2.ResolvedContainer, error) {
+	cgroupPath = path.Clean(cgroupPath)
+	// Container names are their paths relative to the root of the hierarchies.
+	cont, err := self.getContainer(cgroupPath)
+	if err == nil {
+		return cont.resolve(), nil
+	}
+
+	// Absolute paths of the cgroups (e.g. "/sys/fs/cgroup/memory/docker/<id>")
+	// are matched against the ones of each container.
+	self.containersLock.RLock()
+	defer self.containersLock.RUnlock()
+	for _, cont := range self.containers {
+		for _, subsystem := range resolvedCgroupSubsystems {
+			if p, err := cont.handler.GetCgroupPath(subsystem); err == nil && p == cgroupPath {
+				return cont.resolve(), nil
+			}
+		}
+	}
+	return v2.ResolvedContainer{}, &ContainerNotFoundError{Name: cgroupPath}
+}
+
+func (self *manager) ResolveName(name string) (v2.ResolvedContainer, error) {
+	cont, err := self.getContainer(name)
+	if err == nil {
+		return cont.resolve(), nil
+	}
+
+	// Look for the name among the aliases of all namespaces.
+	self.containersLock.RLock()
+	defer self.containersLock.RUnlock()
+	var found *containerData
+	for namespacedName, cont := range self.containers {
+		if namespacedName.Name != name || namespacedName.Namespace == "" {
+			continue
+		}
+		if found != nil && found != cont {
+			return v2.ResolvedContainer{}, fmt.Errorf("name %q is ambiguous, it is an alias of both %q and %q", name, found.info.Name, cont.info.Name)
+		}
+		found = cont
+	}
+	if found == nil {
+		return v2.ResolvedContainer{}, &ContainerNotFoundError{Name: name}
+	}
+	return found.resolve(), nil
+}
+
+// Returns the names, metadata and cgroup paths of the container.
+func (c *containerData) resolve() v2.ResolvedContainer {
+	c.lock.Lock()
+	resolved := v2.ResolvedContainer{
+		Name:      c.info.Name,
+		Aliases:   c.info.Aliases,
+		Namespace: c.info.Namespace,
+		Image:     c.info.Spec.Image,
+		Labels:    c.info.Spec.Labels,
+	}
+	c.lock.Unlock()
+
+	for _, subsystem := range resolvedCgroupSubsystems {
+		p, err := c.handler.GetCgroupPath(subsystem)
+		if err != nil {
+			continue
+		}
+		if resolved.CgroupPaths == nil {
+			resolved.CgroupPaths = make(map[string]string)
+		}
+		resolved.CgroupPaths[subsystem] = p
+	}
+	return resolved
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Number of stats buffered for a watcher, stats are dropped for watchers
+// falling further behind.
+const statsChannelSize = 10
+
+// Streams the stats of a container as they are collected. Each stats is sent
+// along with the reference and spec of the container in a ContainerInfo.
+type StatsChannel struct {
+	watchId       int
+	containerName string
+	channel       chan *info.ContainerInfo
+}
+
+func NewStatsChannel(watchId int, containerName string) *StatsChannel {
+	return &StatsChannel{
+		watchId:       watchId,
+		containerName: containerName,
+		channel:       make(chan *info.ContainerInfo, statsChannelSize),
+	}
+}
+
+// Returns the channel stats are sent through. It is closed when the container
+// goes away.
+func (self *StatsChannel) GetChannel() chan *info.ContainerInfo {
+	return self.channel
+}
+
+func (self *StatsChannel) GetWatchId() int {
+	return self.watchId
+}
+
+func (self *manager) WatchStats(containerName string) (*StatsChannel, error) {
+	cont, err := self.getContainerData(containerName)
+	if err != nil {
+		return nil, err
+	}
+	statsChannel := NewStatsChannel(int(atomic.AddInt32(&self.lastStatsWatchId, 1)), containerName)
+	if !cont.addStatsWatcher(statsChannel.watchId, statsChannel.channel) {
+		return nil, &ContainerNotFoundError{Name: containerName}
+	}
+	return statsChannel, nil
+}
+
+func (self *manager) CloseStatsChannel(statsChannel *StatsChannel) {
+	cont, err := self.getContainerData(statsChannel.containerName)
+	if err != nil {
+		// The channel was closed along with the container.
+		return
+	}
+	cont.removeStatsWatcher(statsChannel.watchId)
+}
+
+// Registers a channel stats of the container are sent through as they are
+// collected. Returns false if the container is no longer housekept.
+func (c *containerData) addStatsWatcher(watchId int, channel chan *info.ContainerInfo) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.statsWatchers == nil {
+		return false
+	}
+	c.statsWatchers[watchId] = channel
+	return true
+}
+
+func (c *containerData) removeStatsWatcher(watchId int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if channel, ok := c.statsWatchers[watchId]; ok {
+		close(channel)
+		delete(c.statsWatchers, watchId)
+	}
+}
+
+// Sends newly collected stats to the watchers, without blocking on slow ones.
+func (c *containerData) notifyStatsWatchers(stats *info.ContainerStats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.statsWatchers) == 0 {
+		return
+	}
+	cinfo := &info.ContainerInfo{
+		ContainerReference: c.info.ContainerReference,
+		Spec:               c.info.Spec,
+		Stats:              []*info.ContainerStats{stats},
+	}
+	for watchId, channel := range c.statsWatchers {
+		select {
+		case channel <- cinfo:
+		default:
+			glog.V(4).Infof("Dropping stats of %q for slow watcher %d", c.info.Name, watchId)
+		}
+	}
+}
+
+// Closes the channels of all the watchers once housekeeping stops.
+func (c *containerData) closeStatsWatchers() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, channel := range c.statsWatchers {
+		close(channel)
+	}
+	c.statsWatchers = nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+)
+
+// Returns the value of a metric from the two latest stats samples of a
+// container, the first one being nil if there is only one. Returns false if
+// the metric cannot be computed from them.
+type topMetric func(prev, cur *info.ContainerStats) (float64, bool)
+
+var topMetrics = map[string]topMetric{
+	v2.TopByCpu: rateMetric(func(s *info.ContainerStats) uint64 {
+		return s.Cpu.Usage.Total
+	}, float64(time.Second)),
+	v2.TopByMemory: func(prev, cur *info.ContainerStats) (float64, bool) {
+		return float64(cur.Memory.WorkingSet), true
+	},
+	v2.TopByNetworkRx: rateMetric(func(s *info.ContainerStats) uint64 {
+		return s.Network.RxBytes
+	}, 1),
+	v2.TopByDiskIo: rateMetric(func(s *info.ContainerStats) uint64 {
+		sum := uint64(0)
+		for _, disk := range s.DiskIo.IoServiceBytes {
+			sum += disk.Stats["Read"] + disk.Stats["Write"]
+		}
+		return sum
+	}, 1),
+}
+
+// Returns a metric of the per second rate of a counter, divided by unit.
+// Counters that went backwards, e.g. after a restart, have a zero rate.
+func rateMetric(counter func(*info.ContainerStats) uint64, unit float64) topMetric {
+	return func(prev, cur *info.ContainerStats) (float64, bool) {
+		if prev == nil {
+			return 0, false
+		}
+		interval := cur.Timestamp.Sub(prev.Timestamp)
+		if interval <= 0 {
+			return 0, false
+		}
+		if counter(cur) < counter(prev) {
+			return 0, true
+		}
+		return float64(counter(cur)-counter(prev)) / interval.Seconds() / unit, true
+	}
+}
+
+type byValue []v2.TopContainer
+
+func (s byValue) Len() int      { return len(s) }
+func (s byValue) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byValue) Less(i, j int) bool {
+	if s[i].Value != s[j].Value {
+		return s[i].Value > s[j].Value
+	}
+	return s[i].Name < s[j].Name
+}
+
+func (self *manager) GetTopContainers(by string, limit int) ([]v2.TopContainer, error) {
+	metric, ok := topMetrics[by]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q to rank containers by", by)
+	}
+	top := []v2.TopContainer{}
+	for _, cont := range self.getSubcontainers("/", 0) {
+		cinfo, err := cont.GetInfo()
+		if err != nil || len(cinfo.Subcontainers) != 0 {
+			// Parents would outrank the containers they hold.
+			continue
+		}
+		stats, err := self.memoryStorage.RecentStats(cinfo.Name, time.Time{}, time.Time{}, 2)
+		if err != nil || len(stats) == 0 {
+			continue
+		}
+		cur := stats[len(stats)-1]
+		var prev *info.ContainerStats
+		if len(stats) == 2 {
+			prev = stats[0]
+		}
+		value, ok := metric(prev, cur)
+		if !ok {
+			continue
+		}
+		top = append(top, v2.TopContainer{
+			Name:      cinfo.Name,
+			Aliases:   cinfo.Aliases,
+			Namespace: cinfo.Namespace,
+			Timestamp: cur.Timestamp,
+			Value:     value,
+		})
+	}
+	sort.Sort(byValue(top))
+	if limit >= 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/cadvisor/info/v2"
+)
+
+// Number of changes buffered for a watcher. Watchers falling further behind
+// are closed, as they would miss changes otherwise.
+const topologyChannelSize = 100
+
+// Streams the containers added and removed after the watch started.
+type TopologyChannel struct {
+	watchId    int
+	containers []v2.TopologyChange
+	channel    chan v2.TopologyChange
+}
+
+func NewTopologyChannel(watchId int, containers []v2.TopologyChange) *TopologyChannel {
+	return &TopologyChannel{
+		watchId:    watchId,
+		containers: containers,
+		channel:    make(chan v2.TopologyChange, topologyChannelSize),
+	}
+}
+
+// Returns the containers tracked when the watch started, as additions sorted
+// by name so that parents come before their children.
+func (self *TopologyChannel) GetContainers() []v2.TopologyChange {
+	return self.containers
+}
+
+// Returns the channel the changes following GetContainers() are sent
+// through. It is closed if the watcher falls behind.
+func (self *TopologyChannel) GetChannel() chan v2.TopologyChange {
+	return self.channel
+}
+
+func (self *TopologyChannel) GetWatchId() int {
+	return self.watchId
+}
+
+func (self *manager) WatchTopology() (*TopologyChannel, error) {
+	// The lock is held while the containers are added and removed, so that no
+	// change is missed or sent twice.
+	self.containersLock.Lock()
+	defer self.containersLock.Unlock()
+	now := time.Now()
+	var containers []v2.TopologyChange
+	for name, cont := range self.containers {
+		// Skip the aliases.
+		if name.Namespace != "" || name.Name != cont.info.Name {
+			continue
+		}
+		containers = append(containers, self.topologyChange(v2.TopologyAdded, cont, now))
+	}
+	sort.Sort(byTopologyName(containers))
+
+	if self.topologyWatchers == nil {
+		self.topologyWatchers = make(map[int]chan v2.TopologyChange)
+	}
+	self.lastTopologyWatchId++
+	topologyChannel := NewTopologyChannel(self.lastTopologyWatchId, containers)
+	self.topologyWatchers[topologyChannel.watchId] = topologyChannel.channel
+	return topologyChannel, nil
+}
+
+func (self *manager) CloseTopologyChannel(topologyChannel *TopologyChannel) {
+	self.containersLock.Lock()
+	defer self.containersLock.Unlock()
+	if channel, ok := self.topologyWatchers[topologyChannel.watchId]; ok {
+		close(channel)
+		delete(self.topologyWatchers, topologyChannel.watchId)
+	}
+}
+
+// Sends the addition or removal of a container to the watchers, closing the
+// channels of the ones which do not keep up. containersLock must be held.
+func (self *manager) notifyTopologyWatchers(changeType string, cont *containerData) {
+	if len(self.topologyWatchers) == 0 {
+		return
+	}
+	change := self.topologyChange(changeType, cont, time.Now())
+	for watchId, channel := range self.topologyWatchers {
+		select {
+		case channel <- change:
+		default:
+			glog.V(2).Infof("Closing the topology stream of slow watcher %d", watchId)
+			close(channel)
+			delete(self.topologyWatchers, watchId)
+		}
+	}
+}
+
+// containersLock must be held.
+func (self *manager) topologyChange(changeType string, cont *containerData, timestamp time.Time) v2.TopologyChange {
+	change := v2.TopologyChange{
+		Type:      changeType,
+		Name:      cont.info.Name,
+		Aliases:   cont.info.Aliases,
+		Namespace: cont.info.Namespace,
+		Timestamp: timestamp,
+	}
+	if ancestry := self.getAncestryLocked(cont.info.Name); len(ancestry) > 0 {
+		change.Parent = ancestry[0]
+	}
+	return change
+}
+
+type byTopologyName []v2.TopologyChange
+
+func (s byTopologyName) Len() int           { return len(s) }
+func (s byTopologyName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byTopologyName) Less(i, j int) bool { return s[i].Name < s[j].Name }
@@ -15,14 +15,123 @@
 package metrics
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	info "github.com/google/cadvisor/info/v1"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/text"
+	dto "github.com/prometheus/client_model/go"
 )
 
+// Label selectors and image patterns selecting the containers whose metrics
+// are exported.
+var (
+	includeLabels = stringList{}
+	excludeLabels = stringList{}
+	includeImages = stringList{}
+	excludeImages = stringList{}
+)
+
+// Labels attached to all the series exported to Prometheus and written to the
+// storage drivers.
+var staticLabels = labelMap{}
+
+func init() {
+	flag.Var(&includeLabels, "prometheus_include_labels", "Comma separated list of <key>=<value> or <key> label selectors. If set, only the metrics of the containers matching one of them are exported")
+	flag.Var(&excludeLabels, "prometheus_exclude_labels", "Comma separated list of <key>=<value> or <key> label selectors. The metrics of the containers matching one of them are not exported")
+	flag.Var(&includeImages, "prometheus_include_images", "Comma separated list of image name patterns, where * matches any string, e.g. *nginx:*. If set, only the metrics of the containers whose image matches one of them are exported")
+	flag.Var(&excludeImages, "prometheus_exclude_images", "Comma separated list of image name patterns. The metrics of the containers whose image matches one of them are not exported")
+	flag.Var(&staticLabels, "metric_labels", "Comma separated list of <key>=<value> labels attached to all the metrics exported to Prometheus and written to the storage drivers, e.g. zone=us-1,rack=a3. The labels of a series with the same keys take precedence")
+}
+
+// Comma separated list of strings, usable as a flag.
+type stringList []string
+
+func (self *stringList) String() string {
+	return strings.Join(*self, ",")
+}
+
+func (self *stringList) Set(value string) error {
+	*self = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*self = append(*self, item)
+		}
+	}
+	return nil
+}
+
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Comma separated list of <key>=<value> labels, usable as a flag.
+type labelMap map[string]string
+
+func (self *labelMap) String() string {
+	keys := make([]string, 0, len(*self))
+	for key := range *self {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make([]string, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, key+"="+(*self)[key])
+	}
+	return strings.Join(items, ",")
+}
+
+func (self *labelMap) Set(value string) error {
+	labels := labelMap{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("label %q is not of the form <key>=<value>", item)
+		}
+		key := strings.TrimSpace(parts[0])
+		// Names starting with __ are reserved by Prometheus.
+		if !labelNameRegexp.MatchString(key) || strings.HasPrefix(key, "__") {
+			return fmt.Errorf("invalid label name %q", key)
+		}
+		labels[key] = strings.TrimSpace(parts[1])
+	}
+	*self = labels
+	return nil
+}
+
+// Returns the labels set with -metric_labels, which are attached to all the
+// series.
+func StaticLabels() map[string]string {
+	labels := make(map[string]string, len(staticLabels))
+	for key, value := range staticLabels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// Returns the labels whose keys are not among keys.
+func labelsWithout(labels map[string]string, keys []string) map[string]string {
+	ret := make(map[string]string, len(labels))
+	for key, value := range labels {
+		ret[key] = value
+	}
+	for _, key := range keys {
+		delete(ret, key)
+	}
+	return ret
+}
+
 // This will usually be manager.Manager, but can be swapped out for testing.
 type subcontainersInfoProvider interface {
 	// Get information about all subcontainers of the specified container (includes self).
@@ -50,6 +159,23 @@ func fsValues(fsStats []info.FsStats, valueFn func(*info.FsStats) float64) metri
 	return values
 }
 
+// Returns the values of the hugepages of each size, sorted by size label.
+func hugeTlbValues(hugeTlbStats map[string]info.HugeTlbStats, valueFn func(info.HugeTlbStats) float64) metricValues {
+	pageSizes := make([]string, 0, len(hugeTlbStats))
+	for pageSize := range hugeTlbStats {
+		pageSizes = append(pageSizes, pageSize)
+	}
+	sort.Strings(pageSizes)
+	values := make(metricValues, 0, len(pageSizes))
+	for _, pageSize := range pageSizes {
+		values = append(values, metricValue{
+			value:  valueFn(hugeTlbStats[pageSize]),
+			labels: []string{pageSize},
+		})
+	}
+	return values
+}
+
 // A containerMetric describes a multi-dimensional metric used for exposing
 // a certain type of container statistic.
 type containerMetric struct {
@@ -60,25 +186,35 @@ type containerMetric struct {
 	getValues   func(s *info.ContainerStats) metricValues
 }
 
-func (cm *containerMetric) desc() *prometheus.Desc {
-	return prometheus.NewDesc(cm.name, cm.help, append([]string{"name", "id"}, cm.extraLabels...), nil)
+// Returns the description of the metric, with the given labels attached to all
+// its series unless it has labels with the same keys.
+func (cm *containerMetric) desc(constLabels map[string]string) *prometheus.Desc {
+	labels := append([]string{"name", "id"}, cm.extraLabels...)
+	return prometheus.NewDesc(cm.name, cm.help, labels, labelsWithout(constLabels, labels))
 }
 
 // PrometheusCollector implements prometheus.Collector.
 type PrometheusCollector struct {
-	infoProvider     subcontainersInfoProvider
+	infoProvider subcontainersInfoProvider
+	// Selects the containers whose metrics are exported.
+	filter           ContainerFilter
 	errors           prometheus.Gauge
 	containerMetrics []containerMetric
+	// Attached to all the series, unless they have labels with the same keys.
+	labels map[string]string
 }
 
 // NewPrometheusCollector returns a new PrometheusCollector.
 func NewPrometheusCollector(infoProvider subcontainersInfoProvider) *PrometheusCollector {
 	c := &PrometheusCollector{
 		infoProvider: infoProvider,
+		filter:       ContainerLabelFilter(includeLabels, excludeLabels, includeImages, excludeImages),
+		labels:       StaticLabels(),
 		errors: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: "container",
-			Name:      "scrape_error",
-			Help:      "1 if there was an error while getting container metrics, 0 otherwise",
+			Namespace:   "container",
+			Name:        "scrape_error",
+			Help:        "1 if there was an error while getting container metrics, 0 otherwise",
+			ConstLabels: StaticLabels(),
 		}),
 		containerMetrics: []containerMetric{
 			{
@@ -117,6 +253,27 @@ func NewPrometheusCollector(infoProvider subcontainersInfoProvider) *PrometheusC
 					}
 					return values
 				},
+			}, {
+				name:      "container_cpu_cfs_periods_total",
+				help:      "Number of elapsed enforcement period intervals.",
+				valueType: prometheus.CounterValue,
+				getValues: func(s *info.ContainerStats) metricValues {
+					return metricValues{{value: float64(s.Cpu.NrPeriods)}}
+				},
+			}, {
+				name:      "container_cpu_cfs_throttled_periods_total",
+				help:      "Number of throttled period intervals.",
+				valueType: prometheus.CounterValue,
+				getValues: func(s *info.ContainerStats) metricValues {
+					return metricValues{{value: float64(s.Cpu.NrThrottled)}}
+				},
+			}, {
+				name:      "container_cpu_cfs_throttled_seconds_total",
+				help:      "Total time duration the container has been throttled.",
+				valueType: prometheus.CounterValue,
+				getValues: func(s *info.ContainerStats) metricValues {
+					return metricValues{{value: float64(s.Cpu.ThrottledTime) / float64(time.Second)}}
+				},
 			}, {
 				name:      "container_memory_usage_bytes",
 				help:      "Current memory usage in bytes.",
@@ -156,6 +313,26 @@ func NewPrometheusCollector(infoProvider subcontainersInfoProvider) *PrometheusC
 						},
 					}
 				},
+			}, {
+				name:        "container_memory_hugetlb_usage_bytes",
+				help:        "Current usage of hugepages of the given size in bytes.",
+				valueType:   prometheus.GaugeValue,
+				extraLabels: []string{"pagesize"},
+				getValues: func(s *info.ContainerStats) metricValues {
+					return hugeTlbValues(s.Memory.HugeTlb, func(hugetlb info.HugeTlbStats) float64 {
+						return float64(hugetlb.Usage)
+					})
+				},
+			}, {
+				name:        "container_memory_hugetlb_failures_total",
+				help:        "Cumulative count of hugepage allocations of the given size which failed because of the limit of the container.",
+				valueType:   prometheus.CounterValue,
+				extraLabels: []string{"pagesize"},
+				getValues: func(s *info.ContainerStats) metricValues {
+					return hugeTlbValues(s.Memory.HugeTlb, func(hugetlb info.HugeTlbStats) float64 {
+						return float64(hugetlb.Failcnt)
+					})
+				},
 			}, {
 				name:        "container_fs_limit_bytes",
 				help:        "Number of bytes that can be consumed by the container on this filesystem.",
@@ -342,6 +519,26 @@ func NewPrometheusCollector(infoProvider subcontainersInfoProvider) *PrometheusC
 				getValues: func(s *info.ContainerStats) metricValues {
 					return metricValues{{value: float64(s.Network.TxErrors)}}
 				},
+			}, {
+				name:      "container_pids",
+				help:      "Number of tasks in the pids cgroup of the container",
+				valueType: prometheus.GaugeValue,
+				getValues: func(s *info.ContainerStats) metricValues {
+					if s.Pids == nil {
+						return nil
+					}
+					return metricValues{{value: float64(s.Pids.Current)}}
+				},
+			}, {
+				name:      "container_pids_limit",
+				help:      "Maximum number of tasks in the pids cgroup of the container, only set when limited",
+				valueType: prometheus.GaugeValue,
+				getValues: func(s *info.ContainerStats) metricValues {
+					if s.Pids == nil || s.Pids.Limit == 0 {
+						return nil
+					}
+					return metricValues{{value: float64(s.Pids.Limit)}}
+				},
 			}, {
 				name:        "container_tasks_state",
 				help:        "Number of tasks in given state",
@@ -382,7 +579,7 @@ func NewPrometheusCollector(infoProvider subcontainersInfoProvider) *PrometheusC
 func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.errors.Describe(ch)
 	for _, cm := range c.containerMetrics {
-		ch <- cm.desc()
+		ch <- cm.desc(c.labels)
 	}
 }
 
@@ -396,19 +593,183 @@ func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 	for _, container := range containers {
-		id := container.Name
-		name := id
-		if len(container.Aliases) > 0 {
-			name = container.Aliases[0]
+		if !c.filter(container) {
+			continue
 		}
-		stats := container.Stats[0]
-
 		for _, cm := range c.containerMetrics {
-			desc := cm.desc()
-			for _, metricValue := range cm.getValues(stats) {
-				ch <- prometheus.MustNewConstMetric(desc, cm.valueType, float64(metricValue.value), append([]string{name, id}, metricValue.labels...)...)
-			}
+			c.collectContainerMetric(container, &cm, func(metric prometheus.Metric) {
+				ch <- metric
+			})
 		}
 	}
 	c.errors.Collect(ch)
 }
+
+// Calls collect with each value of a metric of a container.
+func (c *PrometheusCollector) collectContainerMetric(container *info.ContainerInfo, cm *containerMetric, collect func(prometheus.Metric)) {
+	id := container.Name
+	name := id
+	if len(container.Aliases) > 0 {
+		name = container.Aliases[0]
+	}
+	stats := container.Stats[0]
+
+	desc := cm.desc(c.labels)
+	for _, metricValue := range cm.getValues(stats) {
+		collect(prometheus.MustNewConstMetric(desc, cm.valueType, float64(metricValue.value), append([]string{name, id}, metricValue.labels...)...))
+	}
+}
+
+// Predicate selecting the containers whose metrics are exported.
+type ContainerFilter func(container *info.ContainerInfo) bool
+
+// Returns a filter accepting the container with the given name and all its
+// subcontainers.
+func ContainerSubtreeFilter(containerName string) ContainerFilter {
+	prefix := strings.TrimSuffix(containerName, "/") + "/"
+	return func(container *info.ContainerInfo) bool {
+		return container.Name == containerName || strings.HasPrefix(container.Name, prefix)
+	}
+}
+
+// Returns a filter accepting only the container with the given name.
+func ContainerNameFilter(containerName string) ContainerFilter {
+	return func(container *info.ContainerInfo) bool {
+		return container.Name == containerName
+	}
+}
+
+// Returns a filter accepting the containers which have one of the labels of
+// includeLabels, unless it is empty, and whose image matches one of the
+// patterns of includeImages, unless it is empty. Containers which have one of
+// the labels of excludeLabels or whose image matches one of excludeImages are
+// rejected. Labels are selected by "<key>=<value>", or "<key>" for any value.
+// The * of image patterns matches any string, including slashes.
+func ContainerLabelFilter(includeLabels, excludeLabels, includeImages, excludeImages []string) ContainerFilter {
+	includeImagesRe := imagePatternsRegexp(includeImages)
+	excludeImagesRe := imagePatternsRegexp(excludeImages)
+	hasLabel := func(container *info.ContainerInfo, selectors []string) bool {
+		for _, selector := range selectors {
+			parts := strings.SplitN(selector, "=", 2)
+			value, ok := container.Spec.Labels[parts[0]]
+			if ok && (len(parts) == 1 || value == parts[1]) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(container *info.ContainerInfo) bool {
+		if len(includeLabels) > 0 && !hasLabel(container, includeLabels) {
+			return false
+		}
+		if includeImagesRe != nil && !includeImagesRe.MatchString(container.Spec.Image) {
+			return false
+		}
+		if excludeImagesRe != nil && excludeImagesRe.MatchString(container.Spec.Image) {
+			return false
+		}
+		return !hasLabel(container, excludeLabels)
+	}
+}
+
+// Returns a regexp matching the strings which match one of the patterns, nil
+// if there are none.
+func imagePatternsRegexp(patterns []string) *regexp.Regexp {
+	if len(patterns) == 0 {
+		return nil
+	}
+	alternatives := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		alternatives = append(alternatives, strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1))
+	}
+	return regexp.MustCompile("^(" + strings.Join(alternatives, "|") + ")$")
+}
+
+// Returns the metrics of the containers accepted by filter, grouped in
+// families sorted by name as in the output of the Prometheus registry.
+func (c *PrometheusCollector) metricFamilies(filter ContainerFilter) ([]*dto.MetricFamily, error) {
+	containers, err := c.infoProvider.SubcontainersInfo("/", &info.ContainerInfoRequest{NumStats: 1})
+	if err != nil {
+		return nil, err
+	}
+	var families []*dto.MetricFamily
+	for _, cm := range c.containerMetrics {
+		family := &dto.MetricFamily{
+			Name: proto.String(cm.name),
+			Help: proto.String(cm.help),
+			Type: dto.MetricType_GAUGE.Enum(),
+		}
+		if cm.valueType == prometheus.CounterValue {
+			family.Type = dto.MetricType_COUNTER.Enum()
+		}
+		for _, container := range containers {
+			if !c.filter(container) || !filter(container) {
+				continue
+			}
+			var writeErr error
+			c.collectContainerMetric(container, &cm, func(metric prometheus.Metric) {
+				dtoMetric := &dto.Metric{}
+				if err := metric.Write(dtoMetric); err != nil {
+					writeErr = err
+					return
+				}
+				family.Metric = append(family.Metric, dtoMetric)
+			})
+			if writeErr != nil {
+				return nil, fmt.Errorf("error collecting metric %s: %v", cm.name, writeErr)
+			}
+		}
+		if len(family.Metric) > 0 {
+			families = append(families, family)
+		}
+	}
+	sort.Sort(familiesByName(families))
+	return families, nil
+}
+
+type familiesByName []*dto.MetricFamily
+
+func (s familiesByName) Len() int           { return len(s) }
+func (s familiesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s familiesByName) Less(i, j int) bool { return s[i].GetName() < s[j].GetName() }
+
+// Writes the metrics of the containers accepted by filter in the Prometheus
+// text format.
+func (c *PrometheusCollector) WriteText(w io.Writer, filter ContainerFilter) error {
+	families, err := c.metricFamilies(filter)
+	if err != nil {
+		return err
+	}
+	for _, family := range families {
+		_, err = text.MetricFamilyToText(w, family)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Content type of the Prometheus text format.
+const TextContentType = `text/plain; version=0.0.4`
+
+// Returns a handler serving the metrics of the collector. The "container"
+// parameter restricts them to a container and its subcontainers, e.g.
+// "?container=/docker". Requests without it are served by defaultHandler.
+func NewPrometheusHandler(c *PrometheusCollector, defaultHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		containerName := r.URL.Query().Get("container")
+		if containerName == "" {
+			defaultHandler.ServeHTTP(w, r)
+			return
+		}
+		var buf bytes.Buffer
+		err := c.WriteText(&buf, ContainerSubtreeFilter(containerName))
+		if err != nil {
+			glog.Warningf("Couldn't get metrics of container %q: %v", containerName, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", TextContentType)
+		w.Write(buf.Bytes())
+	})
+}
@@ -15,9 +15,11 @@
 package metrics
 
 import (
+	"bytes"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -43,6 +45,9 @@ func (p testSubcontainersInfoProvider) SubcontainersInfo(string, *info.Container
 							User:   6,
 							System: 7,
 						},
+						NrPeriods:     723,
+						NrThrottled:   18,
+						ThrottledTime: 1724314000,
 					},
 					Memory: info.MemoryStats{
 						Usage:      8,
@@ -55,6 +60,10 @@ func (p testSubcontainersInfoProvider) SubcontainersInfo(string, *info.Container
 							Pgfault:    12,
 							Pgmajfault: 13,
 						},
+						HugeTlb: map[string]info.HugeTlbStats{
+							"2MB": {Usage: 4194304, MaxUsage: 8388608, Failcnt: 2},
+							"1GB": {Usage: 1073741824},
+						},
 					},
 					Network: info.NetworkStats{
 						RxBytes:   14,
@@ -107,6 +116,10 @@ func (p testSubcontainersInfoProvider) SubcontainersInfo(string, *info.Container
 						NrUninterruptible: 53,
 						NrIoWait:          54,
 					},
+					Pids: &info.PidsStats{
+						Current: 55,
+						Limit:   1024,
+					},
 				},
 			},
 		},
@@ -143,3 +156,148 @@ func TestPrometheusCollector(t *testing.T) {
 		}
 	}
 }
+
+type treeSubcontainersInfoProvider []string
+
+func (p treeSubcontainersInfoProvider) SubcontainersInfo(string, *info.ContainerInfoRequest) ([]*info.ContainerInfo, error) {
+	containers := make([]*info.ContainerInfo, 0, len(p))
+	for i, name := range p {
+		containers = append(containers, &info.ContainerInfo{
+			ContainerReference: info.ContainerReference{Name: name},
+			Stats: []*info.ContainerStats{{
+				Memory: info.MemoryStats{Usage: uint64(i + 1)},
+			}},
+		})
+	}
+	return containers, nil
+}
+
+func TestPrometheusHandlerContainerFilter(t *testing.T) {
+	collector := NewPrometheusCollector(treeSubcontainersInfoProvider{"/", "/docker", "/docker/abc", "/dockerd"})
+	defaultHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("all metrics"))
+	})
+	handler := NewPrometheusHandler(collector, defaultHandler)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/metrics", nil))
+	if rw.Body.String() != "all metrics" {
+		t.Errorf("expected unfiltered requests to be served by the default handler, got %q", rw.Body.String())
+	}
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/metrics?container=/docker", nil))
+	body := rw.Body.String()
+	for _, want := range []string{
+		"# TYPE container_memory_usage_bytes gauge\n",
+		"container_memory_usage_bytes{id=\"/docker\",name=\"/docker\"} 2\n",
+		"container_memory_usage_bytes{id=\"/docker/abc\",name=\"/docker/abc\"} 3\n",
+		"# TYPE container_network_receive_bytes_total counter\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in the metrics, got:\n%s", want, body)
+		}
+	}
+	for _, unwanted := range []string{`id="/"`, `id="/dockerd"`} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("unexpected metrics of container %s in:\n%s", unwanted, body)
+		}
+	}
+	if !strings.HasPrefix(rw.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type %q", rw.Header().Get("Content-Type"))
+	}
+}
+
+func TestContainerLabelFilter(t *testing.T) {
+	container := func(image string, labels map[string]string) *info.ContainerInfo {
+		return &info.ContainerInfo{Spec: info.ContainerSpec{Image: image, Labels: labels}}
+	}
+	monitored := container("gcr.io/team/nginx:1.9", map[string]string{"monitoring": "true"})
+	unmonitored := container("redis:3", map[string]string{"monitoring": "false"})
+	system := container("", nil)
+
+	testCases := []struct {
+		filter   ContainerFilter
+		expected []bool
+	}{
+		{ContainerLabelFilter(nil, nil, nil, nil), []bool{true, true, true}},
+		{ContainerLabelFilter([]string{"monitoring=true"}, nil, nil, nil), []bool{true, false, false}},
+		{ContainerLabelFilter([]string{"monitoring"}, nil, nil, nil), []bool{true, true, false}},
+		{ContainerLabelFilter(nil, []string{"monitoring=false"}, nil, nil), []bool{true, false, true}},
+		{ContainerLabelFilter(nil, nil, []string{"*nginx:*"}, nil), []bool{true, false, false}},
+		{ContainerLabelFilter(nil, nil, nil, []string{"redis:*", "gcr.io/other/*"}), []bool{true, false, true}},
+		{ContainerLabelFilter([]string{"monitoring"}, nil, nil, []string{"*nginx*"}), []bool{false, true, false}},
+	}
+	for i, testCase := range testCases {
+		for j, c := range []*info.ContainerInfo{monitored, unmonitored, system} {
+			if testCase.filter(c) != testCase.expected[j] {
+				t.Errorf("case %d: expected %v for container %d, got %v", i, testCase.expected[j], j, !testCase.expected[j])
+			}
+		}
+	}
+}
+
+func TestPrometheusCollectorFilter(t *testing.T) {
+	collector := NewPrometheusCollector(treeSubcontainersInfoProvider{"/", "/docker", "/docker/abc"})
+	collector.filter = func(container *info.ContainerInfo) bool {
+		return container.Name != "/docker"
+	}
+	var buf bytes.Buffer
+	if err := collector.WriteText(&buf, ContainerSubtreeFilter("/")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `id="/docker"`) {
+		t.Errorf("unexpected metrics of a filtered container in:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `id="/docker/abc"`) {
+		t.Errorf("expected metrics of /docker/abc in:\n%s", buf.String())
+	}
+
+	metrics := make(chan prometheus.Metric, 1000)
+	collector.Collect(metrics)
+	close(metrics)
+	count := 0
+	for metric := range metrics {
+		if strings.Contains(metric.Desc().String(), "container_memory_usage_bytes") {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected the memory usage of 2 containers, got %d", count)
+	}
+}
+
+func TestLabelMapFlag(t *testing.T) {
+	var labels labelMap
+	if err := labels.Set("zone=us-1, rack=a3,,cluster=prod=1"); err != nil {
+		t.Fatal(err)
+	}
+	expected := labelMap{"zone": "us-1", "rack": "a3", "cluster": "prod=1"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected %v, got %v", expected, labels)
+	}
+	if labels.String() != "cluster=prod=1,rack=a3,zone=us-1" {
+		t.Errorf("unexpected string %q", labels.String())
+	}
+	for _, invalid := range []string{"zone", "1zone=a", "zo-ne=a", "__name__=a"} {
+		if err := labels.Set(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestPrometheusCollectorStaticLabels(t *testing.T) {
+	collector := NewPrometheusCollector(treeSubcontainersInfoProvider{"/docker"})
+	collector.labels = map[string]string{"zone": "us-1", "id": "ignored"}
+	var buf bytes.Buffer
+	if err := collector.WriteText(&buf, ContainerSubtreeFilter("/")); err != nil {
+		t.Fatal(err)
+	}
+	want := "container_memory_usage_bytes{id=\"/docker\",name=\"/docker\",zone=\"us-1\"} 1\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %q in the metrics, got:\n%s", want, buf.String())
+	}
+	if strings.Contains(buf.String(), "ignored") {
+		t.Errorf("expected the labels of the series to take precedence in:\n%s", buf.String())
+	}
+}
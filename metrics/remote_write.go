@@ -0,0 +1,301 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/matttproud/golang_protobuf_extensions/ext"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	// Accept header asking the registry handler for length-delimited MetricFamily protos.
+	delimitedProtoAccept = "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited"
+
+	// Largest delay between pushes after consecutive failures.
+	maxRemoteWriteBackoff = 5 * time.Minute
+
+	remoteWriteTimeout = 30 * time.Second
+)
+
+// RemoteWriter periodically pushes the metrics served by a Prometheus
+// registry to a remote-write endpoint.
+type RemoteWriter struct {
+	url      string
+	interval time.Duration
+	// Handler of the registry the metrics are gathered from.
+	source http.Handler
+	client *http.Client
+}
+
+// NewRemoteWriter returns a RemoteWriter pushing the metrics of source to url every interval.
+func NewRemoteWriter(url string, interval time.Duration, source http.Handler) *RemoteWriter {
+	return &RemoteWriter{
+		url:      url,
+		interval: interval,
+		source:   source,
+		client:   &http.Client{Timeout: remoteWriteTimeout},
+	}
+}
+
+// Start pushing metrics in the background.
+func (self *RemoteWriter) Start() {
+	go self.run()
+}
+
+func (self *RemoteWriter) run() {
+	var backoff time.Duration
+	for {
+		wait := self.interval
+		err := self.push()
+		if err != nil {
+			// Back off exponentially while the remote end keeps failing.
+			if backoff == 0 {
+				backoff = self.interval
+			} else {
+				backoff *= 2
+			}
+			if backoff > maxRemoteWriteBackoff {
+				backoff = maxRemoteWriteBackoff
+			}
+			wait = backoff
+			glog.Warningf("Failed to push metrics to %q, retrying in %v: %v", self.url, wait, err)
+		} else {
+			backoff = 0
+		}
+		time.Sleep(wait)
+	}
+}
+
+func (self *RemoteWriter) push() error {
+	families, err := self.gather()
+	if err != nil {
+		return err
+	}
+	body := snappyEncode(encodeWriteRequest(familiesToTimeSeries(families, time.Now())))
+	req, err := http.NewRequest("POST", self.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return nil
+}
+
+// Collects the metrics through the registry handler.
+func (self *RemoteWriter) gather() ([]*dto.MetricFamily, error) {
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", delimitedProtoAccept)
+	w := &bufferResponseWriter{header: make(http.Header)}
+	self.source.ServeHTTP(w, req)
+	if w.status != 0 && w.status != http.StatusOK {
+		return nil, fmt.Errorf("failed to gather metrics: %s", w.body.String())
+	}
+
+	families := []*dto.MetricFamily{}
+	for {
+		family := &dto.MetricFamily{}
+		_, err := ext.ReadDelimited(&w.body, family)
+		if err == io.EOF {
+			return families, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse gathered metrics: %v", err)
+		}
+		families = append(families, family)
+	}
+}
+
+// In-memory http.ResponseWriter.
+type bufferResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (self *bufferResponseWriter) Header() http.Header {
+	return self.header
+}
+
+func (self *bufferResponseWriter) Write(b []byte) (int, error) {
+	return self.body.Write(b)
+}
+
+func (self *bufferResponseWriter) WriteHeader(status int) {
+	self.status = status
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+type labelSorter []label
+
+func (s labelSorter) Len() int           { return len(s) }
+func (s labelSorter) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s labelSorter) Less(i, j int) bool { return s[i].name < s[j].name }
+
+// A single sample of a series, the remote-write protocol is sample based.
+type timeSeries struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// Flattens the metric families into series the way the Prometheus server
+// does when scraping: summaries and histograms are split into their
+// quantiles or buckets, sum and count.
+func familiesToTimeSeries(families []*dto.MetricFamily, now time.Time) []timeSeries {
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	series := []timeSeries{}
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.GetMetric() {
+			timestamp := nowMs
+			if m.TimestampMs != nil {
+				timestamp = m.GetTimestampMs()
+			}
+			add := func(suffix string, value float64, extra ...label) {
+				labels := []label{{"__name__", name + suffix}}
+				for _, lp := range m.GetLabel() {
+					labels = append(labels, label{lp.GetName(), lp.GetValue()})
+				}
+				labels = append(labels, extra...)
+				sort.Sort(labelSorter(labels))
+				series = append(series, timeSeries{labels: labels, value: value, timestamp: timestamp})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				summary := m.GetSummary()
+				for _, q := range summary.GetQuantile() {
+					add("", q.GetValue(), label{"quantile", formatFloat(q.GetQuantile())})
+				}
+				add("_sum", summary.GetSampleSum())
+				add("_count", float64(summary.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				histogram := m.GetHistogram()
+				hasInfBucket := false
+				for _, b := range histogram.GetBucket() {
+					hasInfBucket = math.IsInf(b.GetUpperBound(), 1)
+					add("_bucket", float64(b.GetCumulativeCount()), label{"le", formatFloat(b.GetUpperBound())})
+				}
+				if !hasInfBucket {
+					add("_bucket", float64(histogram.GetSampleCount()), label{"le", "+Inf"})
+				}
+				add("_sum", histogram.GetSampleSum())
+				add("_count", float64(histogram.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Serializes the series as a remote-write WriteRequest protobuf:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(series []timeSeries) []byte {
+	req := proto.NewBuffer(nil)
+	for _, s := range series {
+		ts := proto.NewBuffer(nil)
+		for _, l := range s.labels {
+			lb := proto.NewBuffer(nil)
+			lb.EncodeVarint(1<<3 | proto.WireBytes)
+			lb.EncodeStringBytes(l.name)
+			lb.EncodeVarint(2<<3 | proto.WireBytes)
+			lb.EncodeStringBytes(l.value)
+			ts.EncodeVarint(1<<3 | proto.WireBytes)
+			ts.EncodeRawBytes(lb.Bytes())
+		}
+		sample := proto.NewBuffer(nil)
+		sample.EncodeVarint(1<<3 | proto.WireFixed64)
+		sample.EncodeFixed64(math.Float64bits(s.value))
+		sample.EncodeVarint(2<<3 | proto.WireVarint)
+		sample.EncodeVarint(uint64(s.timestamp))
+		ts.EncodeVarint(2<<3 | proto.WireBytes)
+		ts.EncodeRawBytes(sample.Bytes())
+
+		req.EncodeVarint(1<<3 | proto.WireBytes)
+		req.EncodeRawBytes(ts.Bytes())
+	}
+	return req.Bytes()
+}
+
+// Largest literal emitted in a single snappy element.
+const maxSnappyLiteral = 65536
+
+// Encodes b in the snappy block format using only literals. This does not
+// compress but is valid input for any snappy decoder.
+func snappyEncode(b []byte) []byte {
+	out := proto.NewBuffer(make([]byte, 0, len(b)+len(b)/maxSnappyLiteral*3+16))
+	out.EncodeVarint(uint64(len(b)))
+	buf := out.Bytes()
+	for len(b) > 0 {
+		n := len(b)
+		if n > maxSnappyLiteral {
+			n = maxSnappyLiteral
+		}
+		switch m := n - 1; {
+		case m < 60:
+			buf = append(buf, byte(m)<<2)
+		case m < 1<<8:
+			buf = append(buf, 60<<2, byte(m))
+		default:
+			buf = append(buf, 61<<2, byte(m), byte(m>>8))
+		}
+		buf = append(buf, b[:n]...)
+		b = b[n:]
+	}
+	return buf
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/matttproud/golang_protobuf_extensions/ext"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFamilies() []*dto.MetricFamily {
+	return []*dto.MetricFamily{
+		{
+			Name: proto.String("container_memory_usage_bytes"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: []*dto.LabelPair{
+						{Name: proto.String("name"), Value: proto.String("test")},
+						{Name: proto.String("id"), Value: proto.String("/test")},
+					},
+					Gauge: &dto.Gauge{Value: proto.Float64(8)},
+				},
+			},
+		},
+		{
+			Name: proto.String("request_duration_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Histogram: &dto.Histogram{
+						SampleCount: proto.Uint64(3),
+						SampleSum:   proto.Float64(1.5),
+						Bucket: []*dto.Bucket{
+							{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(2)},
+						},
+					},
+					TimestampMs: proto.Int64(1000),
+				},
+			},
+		},
+	}
+}
+
+func TestFamiliesToTimeSeries(t *testing.T) {
+	series := familiesToTimeSeries(testFamilies(), time.Unix(2, 0))
+	require.Equal(t, 5, len(series))
+
+	assert.Equal(t, []label{{"__name__", "container_memory_usage_bytes"}, {"id", "/test"}, {"name", "test"}}, series[0].labels)
+	assert.Equal(t, 8.0, series[0].value)
+	assert.Equal(t, int64(2000), series[0].timestamp)
+
+	assert.Equal(t, []label{{"__name__", "request_duration_seconds_bucket"}, {"le", "0.5"}}, series[1].labels)
+	assert.Equal(t, 2.0, series[1].value)
+	assert.Equal(t, int64(1000), series[1].timestamp)
+	assert.Equal(t, []label{{"__name__", "request_duration_seconds_bucket"}, {"le", "+Inf"}}, series[2].labels)
+	assert.Equal(t, 3.0, series[2].value)
+	assert.Equal(t, "request_duration_seconds_sum", series[3].labels[0].value)
+	assert.Equal(t, "request_duration_seconds_count", series[4].labels[0].value)
+}
+
+func TestEncodeWriteRequest(t *testing.T) {
+	series := []timeSeries{{labels: []label{{"__name__", "up"}}, value: 1, timestamp: 5}}
+	expected := []byte{
+		0x0a, 0x1d, // timeseries, 29 bytes
+		0x0a, 0x0e, // labels, 14 bytes
+		0x0a, 0x08, '_', '_', 'n', 'a', 'm', 'e', '_', '_',
+		0x12, 0x02, 'u', 'p',
+		0x12, 0x0b, // samples, 11 bytes
+		0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // value 1.0
+		0x10, 0x05, // timestamp
+	}
+	assert.Equal(t, expected, encodeWriteRequest(series))
+}
+
+func TestSnappyEncode(t *testing.T) {
+	assert.Equal(t, []byte{3, 2 << 2, 'a', 'b', 'c'}, snappyEncode([]byte("abc")))
+
+	long := bytes.Repeat([]byte("x"), maxSnappyLiteral+100)
+	encoded := snappyEncode(long)
+	// Uncompressed length varint, a 64KiB literal and a 100 bytes literal.
+	assert.Equal(t, []byte{0xe4, 0x80, 0x04, 61 << 2, 0xff, 0xff}, encoded[:6])
+	rest := encoded[6+maxSnappyLiteral:]
+	assert.Equal(t, []byte{60 << 2, 99}, rest[:2])
+	assert.Equal(t, 100, len(rest[2:]))
+}
+
+func TestRemoteWriterPush(t *testing.T) {
+	source := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, delimitedProtoAccept, r.Header.Get("Accept"))
+		for _, family := range testFamilies() {
+			ext.WriteDelimited(w, family)
+		}
+	})
+
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		received, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	writer := NewRemoteWriter(server.URL, time.Minute, source)
+	require.Nil(t, writer.push())
+	request := decodeSnappyLiterals(t, received)
+	// The memory usage gauge is the first series.
+	assert.True(t, bytes.Contains(request, []byte("container_memory_usage_bytes")))
+}
+
+// Decodes a snappy block made only of literals.
+func decodeSnappyLiterals(t *testing.T, b []byte) []byte {
+	length, n := proto.DecodeVarint(b)
+	require.NotEqual(t, 0, n)
+	b = b[n:]
+	out := []byte{}
+	for len(b) > 0 {
+		tag := b[0]
+		require.Equal(t, byte(0), tag&0x3, "only literals are expected")
+		var literalLength int
+		switch tag >> 2 {
+		case 60:
+			literalLength = int(b[1]) + 1
+			b = b[2:]
+		case 61:
+			literalLength = int(b[1]) | int(b[2])<<8 + 1
+			b = b[3:]
+		default:
+			literalLength = int(tag>>2) + 1
+			b = b[1:]
+		}
+		require.True(t, literalLength <= len(b))
+		out = append(out, b[:literalLength]...)
+		b = b[literalLength:]
+	}
+	require.Equal(t, length, uint64(len(out)))
+	return out
+}
+
+func TestRemoteWriterPushFailure(t *testing.T) {
+	source := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	writer := NewRemoteWriter(server.URL, time.Minute, source)
+	assert.NotNil(t, writer.push())
+}
@@ -95,6 +95,15 @@ const containersHtmlTemplate = `
           {{if .Spec.Memory.SwapLimit}}
           <li class="list-group-item"><span class="stat-label">Swap Limit</span> {{printSize .Spec.Memory.SwapLimit}} <span class="unit-label">{{printUnit .Spec.Memory.SwapLimit}}</span></li>
           {{end}}
+          {{if .Spec.Memory.SoftLimit}}
+          <li class="list-group-item"><span class="stat-label">Soft Limit</span> {{printSize .Spec.Memory.SoftLimit}} <span class="unit-label">{{printUnit .Spec.Memory.SoftLimit}}</span></li>
+          {{end}}
+          {{if .Spec.Memory.Low}}
+          <li class="list-group-item"><span class="stat-label">Low</span> {{printSize .Spec.Memory.Low}} <span class="unit-label">{{printUnit .Spec.Memory.Low}}</span></li>
+          {{end}}
+          {{if .Spec.Memory.High}}
+          <li class="list-group-item"><span class="stat-label">High</span> {{printSize .Spec.Memory.High}} <span class="unit-label">{{printUnit .Spec.Memory.High}}</span></li>
+          {{end}}
 	</ul>
 	{{end}}
       </div>
@@ -0,0 +1,297 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/golang/glog"
+	info "github.com/google/cadvisor/info/v1"
+)
+
+type cassandraStorage struct {
+	session        *gocql.Session
+	machineName    string
+	table          string
+	ttl            time.Duration
+	bufferDuration time.Duration
+	lastWrite      time.Time
+	rows           [][]interface{}
+	lock           sync.Mutex
+	readyToFlush   func() bool
+}
+
+const (
+	colTimestamp          string = "timestamp"
+	colMachineName        string = "machine"
+	colContainerName      string = "container_name"
+	colCpuCumulativeUsage string = "cpu_cumulative_usage"
+	// Memory Usage
+	colMemoryUsage string = "memory_usage"
+	// Working set size
+	colMemoryWorkingSet string = "memory_working_set"
+	// Cumulative count of bytes received.
+	colRxBytes string = "rx_bytes"
+	// Cumulative count of receive errors encountered.
+	colRxErrors string = "rx_errors"
+	// Cumulative count of bytes transmitted.
+	colTxBytes string = "tx_bytes"
+	// Cumulative count of transmit errors encountered.
+	colTxErrors string = "tx_errors"
+)
+
+// Columns holding the stats, in the order they are written and read.
+var statsColumns = []string{
+	colCpuCumulativeUsage,
+	colMemoryUsage,
+	colMemoryWorkingSet,
+	colRxBytes,
+	colRxErrors,
+	colTxBytes,
+	colTxErrors,
+}
+
+const (
+	// Number of times a request is attempted.
+	requestAttempts = 3
+	// Delay before the first retry of a request or reconnection to a node,
+	// doubled on every attempt.
+	retryDelay = time.Second
+	// Timeout for connecting and for each request.
+	requestTimeout = 10 * time.Second
+	// Largest number of rows written in a single batch.
+	maxBatchSize = 50
+)
+
+func (self *cassandraStorage) createTable() error {
+	columns := []string{
+		colMachineName + " text",
+		colContainerName + " text",
+		colTimestamp + " timestamp",
+	}
+	for _, col := range statsColumns {
+		columns = append(columns, col+" bigint")
+	}
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s, PRIMARY KEY ((%s, %s), %s)) WITH CLUSTERING ORDER BY (%s DESC)",
+		self.table, strings.Join(columns, ", "), colMachineName, colContainerName, colTimestamp, colTimestamp)
+	return self.session.Query(query).Exec()
+}
+
+func (self *cassandraStorage) insertQuery() string {
+	columns := append([]string{colMachineName, colContainerName, colTimestamp}, statsColumns...)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", self.table, strings.Join(columns, ", "), placeholders)
+	if ttl := int64(self.ttl / time.Second); ttl > 0 {
+		query = fmt.Sprintf("%s USING TTL %d", query, ttl)
+	}
+	return query
+}
+
+func (self *cassandraStorage) containerStatsToValues(ref info.ContainerReference, stats *info.ContainerStats) []interface{} {
+	containerName := ref.Name
+	if len(ref.Aliases) > 0 {
+		containerName = ref.Aliases[0]
+	}
+	return []interface{}{
+		self.machineName,
+		containerName,
+		stats.Timestamp,
+		// bigint columns are signed.
+		int64(stats.Cpu.Usage.Total),
+		int64(stats.Memory.Usage),
+		int64(stats.Memory.WorkingSet),
+		int64(stats.Network.RxBytes),
+		int64(stats.Network.RxErrors),
+		int64(stats.Network.TxBytes),
+		int64(stats.Network.TxErrors),
+	}
+}
+
+func (self *cassandraStorage) OverrideReadyToFlush(readyToFlush func() bool) {
+	self.readyToFlush = readyToFlush
+}
+
+func (self *cassandraStorage) defaultReadyToFlush() bool {
+	return time.Since(self.lastWrite) >= self.bufferDuration
+}
+
+func (self *cassandraStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	if stats == nil {
+		return nil
+	}
+	var rowsToFlush [][]interface{}
+	func() {
+		// AddStats will be invoked simultaneously from multiple threads and only one of them will perform a write.
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		self.rows = append(self.rows, self.containerStatsToValues(ref, stats))
+		if self.readyToFlush() {
+			rowsToFlush = self.rows
+			self.rows = make([][]interface{}, 0)
+			self.lastWrite = time.Now()
+		}
+	}()
+	return self.write(rowsToFlush)
+}
+
+// Inserts the rows, in unlogged batches of at most maxBatchSize rows.
+func (self *cassandraStorage) write(rows [][]interface{}) error {
+	query := self.insertQuery()
+	for len(rows) > 0 {
+		n := len(rows)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		batch := self.session.NewBatch(gocql.UnloggedBatch)
+		for _, values := range rows[:n] {
+			batch.Query(query, values...)
+		}
+		rows = rows[n:]
+		if err := self.session.ExecuteBatch(batch); err != nil {
+			return fmt.Errorf("failed to write stats to cassandra - %s", err)
+		}
+	}
+	return nil
+}
+
+func (self *cassandraStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
+	if numStats == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s = ? AND %s = ?",
+		colTimestamp, strings.Join(statsColumns, ", "), self.table, colMachineName, colContainerName)
+	if numStats > 0 {
+		query = fmt.Sprintf("%s LIMIT %d", query, numStats)
+	}
+	// Null columns are scanned as 0.
+	var timestamp time.Time
+	values := make([]int64, len(statsColumns))
+	dest := []interface{}{&timestamp}
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+	var statsList []*info.ContainerStats
+	iter := self.session.Query(query, self.machineName, containerName).Iter()
+	for iter.Scan(dest...) {
+		statsList = append(statsList, rowToContainerStats(timestamp, values))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	// Rows are clustered in time descending order, RecentStats() requires
+	// stats in time increasing order.
+	for i, j := 0, len(statsList)-1; i < j; i, j = i+1, j-1 {
+		statsList[i], statsList[j] = statsList[j], statsList[i]
+	}
+	return statsList, nil
+}
+
+// values holds the columns in statsColumns.
+func rowToContainerStats(timestamp time.Time, values []int64) *info.ContainerStats {
+	stats := &info.ContainerStats{Timestamp: timestamp}
+	stats.Cpu.Usage.Total = uint64(values[0])
+	stats.Memory.Usage = uint64(values[1])
+	stats.Memory.WorkingSet = uint64(values[2])
+	stats.Network.RxBytes = uint64(values[3])
+	stats.Network.RxErrors = uint64(values[4])
+	stats.Network.TxBytes = uint64(values[5])
+	stats.Network.TxErrors = uint64(values[6])
+	return stats
+}
+
+// Writes the buffered stats and closes the session.
+func (self *cassandraStorage) Close() error {
+	self.lock.Lock()
+	rows := self.rows
+	self.rows = make([][]interface{}, 0)
+	self.lock.Unlock()
+	err := self.write(rows)
+	self.session.Close()
+	return err
+}
+
+// machineName: A unique identifier to identify the host that current cAdvisor
+// instance is running on.
+// contactPoints: Comma separated list of host:port of Cassandra nodes to connect to.
+// keyspace: Existing keyspace in which the stats table is created.
+// ttl: Time to live of the written rows, rows never expire if ttl is 0.
+func New(machineName,
+	table,
+	keyspace,
+	username,
+	password,
+	contactPoints string,
+	ttl time.Duration,
+	bufferDuration time.Duration,
+) (*cassandraStorage, error) {
+	var hosts []string
+	for _, addr := range strings.Split(contactPoints, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			hosts = append(hosts, addr)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no cassandra contact points specified")
+	}
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Keyspace = keyspace
+	cluster.Consistency = gocql.One
+	cluster.Timeout = requestTimeout
+	cluster.ConnectTimeout = requestTimeout
+	cluster.RetryPolicy = &gocql.ExponentialBackoffRetryPolicy{
+		NumRetries: requestAttempts - 1,
+		Min:        retryDelay,
+		Max:        4 * retryDelay,
+	}
+	cluster.ReconnectionPolicy = &gocql.ExponentialReconnectionPolicy{
+		MaxRetries:      requestAttempts,
+		InitialInterval: retryDelay,
+		MaxInterval:     4 * retryDelay,
+	}
+	if username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: username,
+			Password: password,
+		}
+	}
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cassandra at %v: %v", hosts, err)
+	}
+	glog.V(2).Infof("connected to cassandra at %v", hosts)
+
+	ret := &cassandraStorage{
+		session:        session,
+		machineName:    machineName,
+		table:          fmt.Sprintf("%s.%s", keyspace, table),
+		ttl:            ttl,
+		bufferDuration: bufferDuration,
+		lastWrite:      time.Now(),
+		rows:           make([][]interface{}, 0),
+	}
+	ret.readyToFlush = ret.defaultReadyToFlush
+
+	if err := ret.createTable(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertQuery(t *testing.T) {
+	storage := &cassandraStorage{table: "cadvisor.stats"}
+	assert.Equal(t, "INSERT INTO cadvisor.stats (machine, container_name, timestamp, cpu_cumulative_usage, memory_usage, memory_working_set, rx_bytes, rx_errors, tx_bytes, tx_errors) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", storage.insertQuery())
+
+	storage.ttl = time.Hour
+	assert.Equal(t, "INSERT INTO cadvisor.stats (machine, container_name, timestamp, cpu_cumulative_usage, memory_usage, memory_working_set, rx_bytes, rx_errors, tx_bytes, tx_errors) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL 3600", storage.insertQuery())
+}
+
+func TestContainerStatsToValues(t *testing.T) {
+	storage := &cassandraStorage{machineName: "machine"}
+	ref := info.ContainerReference{Name: "/docker/abc", Aliases: []string{"web", "abc"}}
+	stats := &info.ContainerStats{Timestamp: time.Now()}
+	stats.Cpu.Usage.Total = 1
+	stats.Network.TxErrors = 7
+	values := storage.containerStatsToValues(ref, stats)
+	assert.Equal(t, []interface{}{"machine", "web", stats.Timestamp, int64(1), int64(0), int64(0), int64(0), int64(0), int64(0), int64(7)}, values)
+}
+
+func TestRowToContainerStats(t *testing.T) {
+	now := time.Now()
+	stats := rowToContainerStats(now, []int64{1, 2, 3, 4, 5, 6, 0})
+	assert.Equal(t, now, stats.Timestamp)
+	assert.Equal(t, uint64(1), stats.Cpu.Usage.Total)
+	assert.Equal(t, uint64(3), stats.Memory.WorkingSet)
+	assert.Equal(t, uint64(6), stats.Network.TxBytes)
+	assert.Equal(t, uint64(0), stats.Network.TxErrors)
+}
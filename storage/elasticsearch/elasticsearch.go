@@ -0,0 +1,436 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	info "github.com/google/cadvisor/info/v1"
+)
+
+const (
+	// Indices are not rolled over.
+	RolloverNone = "none"
+	// A new index is used every day, named after the UTC date.
+	RolloverDaily = "daily"
+
+	// Format of the date suffix of daily indices.
+	dayFormat = "2006.01.02"
+	// Timeout of each request.
+	requestTimeout = 10 * time.Second
+	// Largest number of stats returned by a search.
+	maxSearchSize = 10000
+)
+
+// A stats sample as written to Elasticsearch.
+type document struct {
+	Timestamp          time.Time         `json:"timestamp"`
+	MachineName        string            `json:"machine"`
+	ContainerName      string            `json:"container_name"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	CpuCumulativeUsage uint64            `json:"cpu_cumulative_usage"`
+	MemoryUsage        uint64            `json:"memory_usage"`
+	MemoryWorkingSet   uint64            `json:"memory_working_set"`
+	RxBytes            uint64            `json:"rx_bytes"`
+	RxErrors           uint64            `json:"rx_errors"`
+	TxBytes            uint64            `json:"tx_bytes"`
+	TxErrors           uint64            `json:"tx_errors"`
+}
+
+// Mapping of the indices, so that the stats are aggregated as numbers and
+// the names and labels are matched exactly.
+var indexMapping = map[string]interface{}{
+	"mappings": map[string]interface{}{
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{
+				"labels": map[string]interface{}{
+					"path_match": "labels.*",
+					"mapping":    map[string]string{"type": "keyword"},
+				},
+			},
+		},
+		"properties": map[string]interface{}{
+			"timestamp":            map[string]string{"type": "date"},
+			"machine":              map[string]string{"type": "keyword"},
+			"container_name":       map[string]string{"type": "keyword"},
+			"cpu_cumulative_usage": map[string]string{"type": "long"},
+			"memory_usage":         map[string]string{"type": "long"},
+			"memory_working_set":   map[string]string{"type": "long"},
+			"rx_bytes":             map[string]string{"type": "long"},
+			"rx_errors":            map[string]string{"type": "long"},
+			"tx_bytes":             map[string]string{"type": "long"},
+			"tx_errors":            map[string]string{"type": "long"},
+		},
+	},
+}
+
+type elasticStorage struct {
+	client         *http.Client
+	url            string
+	username       string
+	password       string
+	machineName    string
+	labels         map[string]string
+	indexPrefix    string
+	rollover       string
+	maxDocs        uint64
+	bufferDuration time.Duration
+	lastWrite      time.Time
+	documents      []document
+	lock           sync.Mutex
+	readyToFlush   func() bool
+	now            func() time.Time
+
+	// Serializes writes, guards the fields below.
+	writeLock sync.Mutex
+	// Index written to, empty until the first write.
+	index string
+	// Date suffix of the index.
+	day string
+	// Number of times the index of the day was rolled over for holding
+	// maxDocs documents.
+	generation int
+	// Number of documents in the index.
+	indexDocs uint64
+}
+
+// Returns the name of the index of the given day and generation.
+func (self *elasticStorage) indexName(day string, generation int) string {
+	name := self.indexPrefix
+	if day != "" {
+		name = fmt.Sprintf("%s-%s", name, day)
+	}
+	if generation > 0 {
+		name = fmt.Sprintf("%s-%d", name, generation)
+	}
+	return name
+}
+
+// Sends a request and decodes its JSON response into out, if not nil.
+func (self *elasticStorage) do(method, path string, body io.Reader, contentType string, out interface{}) (int, error) {
+	req, err := http.NewRequest(method, self.url+path, body)
+	if err != nil {
+		return 0, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if self.username != "" {
+		req.SetBasicAuth(self.username, self.password)
+	}
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode the response to %s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// Creates the index with the stats mapping unless it already exists, and
+// returns the number of documents it holds.
+func (self *elasticStorage) prepareIndex(index string) (uint64, error) {
+	mapping, err := json.Marshal(indexMapping)
+	if err != nil {
+		return 0, err
+	}
+	status, err := self.do("PUT", "/"+index, bytes.NewReader(mapping), "application/json", nil)
+	if err == nil {
+		return 0, nil
+	}
+	if status != http.StatusBadRequest || !strings.Contains(err.Error(), "resource_already_exists_exception") {
+		return 0, fmt.Errorf("failed to create index %q: %v", index, err)
+	}
+	if self.maxDocs == 0 {
+		return 0, nil
+	}
+	var count struct {
+		Count uint64 `json:"count"`
+	}
+	if _, err := self.do("GET", "/"+index+"/_count", nil, "", &count); err != nil {
+		return 0, fmt.Errorf("failed to count the documents of index %q: %v", index, err)
+	}
+	return count.Count, nil
+}
+
+// Returns the index to write documents to, rolling over to a new one
+// if the day changed or the current one is full. Called with writeLock held.
+func (self *elasticStorage) currentIndex() (string, error) {
+	day := ""
+	if self.rollover == RolloverDaily {
+		day = self.now().UTC().Format(dayFormat)
+	}
+	if self.index != "" && day != self.day {
+		self.index = ""
+		self.generation = 0
+	}
+	for self.index == "" || (self.maxDocs > 0 && self.indexDocs >= self.maxDocs) {
+		if self.index != "" {
+			self.generation++
+		}
+		index := self.indexName(day, self.generation)
+		docs, err := self.prepareIndex(index)
+		if err != nil {
+			self.index = ""
+			return "", err
+		}
+		glog.V(2).Infof("writing stats to elasticsearch index %q holding %d documents", index, docs)
+		self.index = index
+		self.day = day
+		self.indexDocs = docs
+	}
+	return self.index, nil
+}
+
+// Writes the documents with a single bulk request.
+func (self *elasticStorage) write(documents []document) error {
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+
+	index, err := self.currentIndex()
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	action := map[string]interface{}{"index": map[string]string{"_index": index}}
+	for i := range documents {
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(&documents[i]); err != nil {
+			return err
+		}
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if _, err := self.do("POST", "/_bulk", &body, "application/x-ndjson", &resp); err != nil {
+		return err
+	}
+	self.indexDocs += uint64(len(documents))
+	if resp.Errors {
+		for _, item := range resp.Items {
+			for _, result := range item {
+				if len(result.Error) > 0 {
+					return fmt.Errorf("failed to index stats: %s", result.Error)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (self *elasticStorage) containerStatsToDocument(ref info.ContainerReference, stats *info.ContainerStats) document {
+	containerName := ref.Name
+	if len(ref.Aliases) > 0 {
+		containerName = ref.Aliases[0]
+	}
+	return document{
+		Timestamp:          stats.Timestamp,
+		MachineName:        self.machineName,
+		ContainerName:      containerName,
+		Labels:             self.labels,
+		CpuCumulativeUsage: stats.Cpu.Usage.Total,
+		MemoryUsage:        stats.Memory.Usage,
+		MemoryWorkingSet:   stats.Memory.WorkingSet,
+		RxBytes:            stats.Network.RxBytes,
+		RxErrors:           stats.Network.RxErrors,
+		TxBytes:            stats.Network.TxBytes,
+		TxErrors:           stats.Network.TxErrors,
+	}
+}
+
+func (self *elasticStorage) OverrideReadyToFlush(readyToFlush func() bool) {
+	self.readyToFlush = readyToFlush
+}
+
+func (self *elasticStorage) defaultReadyToFlush() bool {
+	return time.Since(self.lastWrite) >= self.bufferDuration
+}
+
+func (self *elasticStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	if stats == nil {
+		return nil
+	}
+	var documentsToFlush []document
+	func() {
+		// AddStats will be invoked simultaneously from multiple threads and only one of them will perform a write.
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		self.documents = append(self.documents, self.containerStatsToDocument(ref, stats))
+		if self.readyToFlush() {
+			documentsToFlush = self.documents
+			self.documents = make([]document, 0)
+			self.lastWrite = time.Now()
+		}
+	}()
+	if len(documentsToFlush) > 0 {
+		if err := self.write(documentsToFlush); err != nil {
+			return fmt.Errorf("failed to write stats to elasticsearch - %s", err)
+		}
+	}
+	return nil
+}
+
+func (self *elasticStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
+	if numStats == 0 {
+		return nil, nil
+	}
+	if numStats < 0 || numStats > maxSearchSize {
+		numStats = maxSearchSize
+	}
+	query := map[string]interface{}{
+		"size": numStats,
+		"sort": []interface{}{map[string]string{"timestamp": "desc"}},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]string{"machine": self.machineName}},
+					map[string]interface{}{"term": map[string]string{"container_name": containerName}},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Hits struct {
+			Hits []struct {
+				Source document `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	// Search all the indices the stats were rolled over to.
+	path := fmt.Sprintf("/%s*/_search", self.indexPrefix)
+	if _, err := self.do("POST", path, bytes.NewReader(body), "application/json", &resp); err != nil {
+		return nil, err
+	}
+
+	// Hits are sorted in time descending order, RecentStats() requires
+	// stats in time increasing order.
+	hits := resp.Hits.Hits
+	statsList := make([]*info.ContainerStats, 0, len(hits))
+	for i := len(hits) - 1; i >= 0; i-- {
+		doc := hits[i].Source
+		stats := &info.ContainerStats{Timestamp: doc.Timestamp}
+		stats.Cpu.Usage.Total = doc.CpuCumulativeUsage
+		stats.Memory.Usage = doc.MemoryUsage
+		stats.Memory.WorkingSet = doc.MemoryWorkingSet
+		stats.Network.RxBytes = doc.RxBytes
+		stats.Network.RxErrors = doc.RxErrors
+		stats.Network.TxBytes = doc.TxBytes
+		stats.Network.TxErrors = doc.TxErrors
+		statsList = append(statsList, stats)
+	}
+	return statsList, nil
+}
+
+// Checks that the Elasticsearch node is reachable and accepts the credentials.
+func (self *elasticStorage) Validate() error {
+	if _, err := self.do("GET", "/", nil, "", nil); err != nil {
+		return fmt.Errorf("failed to reach elasticsearch at %q - %s", self.url, err)
+	}
+	return nil
+}
+
+// Writes the buffered stats.
+func (self *elasticStorage) Close() error {
+	self.lock.Lock()
+	documents := self.documents
+	self.documents = make([]document, 0)
+	self.lock.Unlock()
+	if len(documents) > 0 {
+		if err := self.write(documents); err != nil {
+			return fmt.Errorf("failed to write stats to elasticsearch - %s", err)
+		}
+	}
+	return nil
+}
+
+// machineName: A unique identifier to identify the host that current cAdvisor
+// instance is running on.
+// labels: Written in the "labels" field of all the documents.
+// host: host:port of the Elasticsearch node to write to.
+// indexPrefix: Name of the index, or prefix of the names of the indices when
+// they are rolled over.
+// rollover: RolloverNone or RolloverDaily.
+// maxDocs: Number of documents after which the index is rolled over to a new
+// one, indices are not limited in size if 0.
+func New(machineName string,
+	labels map[string]string,
+	indexPrefix,
+	username,
+	password,
+	host string,
+	isSecure bool,
+	rollover string,
+	maxDocs uint64,
+	bufferDuration time.Duration,
+) (*elasticStorage, error) {
+	if rollover != RolloverNone && rollover != RolloverDaily {
+		return nil, fmt.Errorf("unknown elasticsearch index rollover %q, expected %q or %q", rollover, RolloverNone, RolloverDaily)
+	}
+	if indexPrefix == "" {
+		return nil, fmt.Errorf("no elasticsearch index specified")
+	}
+	scheme := "http"
+	if isSecure {
+		scheme = "https"
+	}
+	ret := &elasticStorage{
+		client:         &http.Client{Timeout: requestTimeout},
+		url:            fmt.Sprintf("%s://%s", scheme, host),
+		username:       username,
+		password:       password,
+		machineName:    machineName,
+		labels:         labels,
+		indexPrefix:    strings.ToLower(indexPrefix),
+		rollover:       rollover,
+		maxDocs:        maxDocs,
+		bufferDuration: bufferDuration,
+		lastWrite:      time.Now(),
+		documents:      make([]document, 0),
+		now:            time.Now,
+	}
+	ret.readyToFlush = ret.defaultReadyToFlush
+	return ret, nil
+}
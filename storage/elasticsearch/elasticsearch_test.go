@@ -0,0 +1,199 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal in-memory Elasticsearch serving the requests of the driver.
+type fakeElasticsearch struct {
+	lock    sync.Mutex
+	indices map[string][]document
+	// Bodies of the index creation requests.
+	mappings map[string]string
+}
+
+func (self *fakeElasticsearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == "PUT" && len(parts) == 1:
+		if _, ok := self.indices[parts[0]]; ok {
+			http.Error(w, `{"error":{"type":"resource_already_exists_exception"}}`, http.StatusBadRequest)
+			return
+		}
+		var mapping map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&mapping)
+		out, _ := json.Marshal(mapping)
+		self.indices[parts[0]] = nil
+		self.mappings[parts[0]] = string(out)
+		fmt.Fprint(w, `{"acknowledged":true}`)
+	case r.Method == "GET" && parts[0] == "":
+		fmt.Fprint(w, `{"tagline":"You Know, for Search"}`)
+	case r.Method == "GET" && len(parts) == 2 && parts[1] == "_count":
+		fmt.Fprintf(w, `{"count":%d}`, len(self.indices[parts[0]]))
+	case r.Method == "POST" && parts[0] == "_bulk":
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var action struct {
+				Index struct {
+					Index string `json:"_index"`
+				} `json:"index"`
+			}
+			json.Unmarshal(scanner.Bytes(), &action)
+			scanner.Scan()
+			var doc document
+			json.Unmarshal(scanner.Bytes(), &doc)
+			self.indices[action.Index.Index] = append(self.indices[action.Index.Index], doc)
+		}
+		fmt.Fprint(w, `{"errors":false,"items":[]}`)
+	case r.Method == "POST" && len(parts) == 2 && parts[1] == "_search":
+		var query struct {
+			Size int `json:"size"`
+		}
+		json.NewDecoder(r.Body).Decode(&query)
+		prefix := strings.TrimSuffix(parts[0], "*")
+		var hits []map[string]document
+		for name, docs := range self.indices {
+			if strings.HasPrefix(name, prefix) {
+				for _, doc := range docs {
+					hits = append(hits, map[string]document{"_source": doc})
+				}
+			}
+		}
+		sort.Sort(byTimestampDesc(hits))
+		if len(hits) > query.Size {
+			hits = hits[:query.Size]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"hits": map[string]interface{}{"hits": hits}})
+	default:
+		http.Error(w, "unexpected request", http.StatusNotFound)
+	}
+}
+
+type byTimestampDesc []map[string]document
+
+func (s byTimestampDesc) Len() int      { return len(s) }
+func (s byTimestampDesc) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byTimestampDesc) Less(i, j int) bool {
+	return s[i]["_source"].Timestamp.After(s[j]["_source"].Timestamp)
+}
+
+func newTestStorage(t *testing.T, rollover string, maxDocs uint64) (*elasticStorage, *fakeElasticsearch, func()) {
+	es := &fakeElasticsearch{indices: make(map[string][]document), mappings: make(map[string]string)}
+	server := httptest.NewServer(es)
+	driver, err := New("machine", nil, "cadvisor", "", "", strings.TrimPrefix(server.URL, "http://"), false, rollover, maxDocs, 0)
+	require.NoError(t, err)
+	driver.OverrideReadyToFlush(func() bool { return true })
+	return driver, es, server.Close
+}
+
+func testStats(seconds int64, cpu uint64) *info.ContainerStats {
+	stats := &info.ContainerStats{Timestamp: time.Unix(seconds, 0).UTC()}
+	stats.Cpu.Usage.Total = cpu
+	return stats
+}
+
+func TestDailyRollover(t *testing.T) {
+	driver, es, stop := newTestStorage(t, RolloverDaily, 0)
+	defer stop()
+	ref := info.ContainerReference{Name: "/a"}
+
+	now := time.Date(2024, 1, 15, 23, 59, 0, 0, time.UTC)
+	driver.now = func() time.Time { return now }
+	assert.NoError(t, driver.AddStats(ref, testStats(1, 10)))
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, driver.AddStats(ref, testStats(2, 20)))
+
+	assert.Equal(t, 1, len(es.indices["cadvisor-2024.01.15"]))
+	assert.Equal(t, 1, len(es.indices["cadvisor-2024.01.16"]))
+	assert.Contains(t, es.mappings["cadvisor-2024.01.16"], `"cpu_cumulative_usage":{"type":"long"}`)
+	assert.Contains(t, es.mappings["cadvisor-2024.01.16"], `"timestamp":{"type":"date"}`)
+
+	stats, err := driver.RecentStats("/a", -1)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(stats))
+	assert.Equal(t, uint64(10), stats[0].Cpu.Usage.Total)
+	assert.Equal(t, uint64(20), stats[1].Cpu.Usage.Total)
+
+	stats, err = driver.RecentStats("/a", 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(stats))
+	assert.Equal(t, uint64(20), stats[0].Cpu.Usage.Total)
+}
+
+func TestSizeRollover(t *testing.T) {
+	driver, es, stop := newTestStorage(t, RolloverNone, 2)
+	defer stop()
+	ref := info.ContainerReference{Name: "/a"}
+
+	// An existing index which is already full.
+	es.indices["cadvisor"] = []document{{}, {}}
+	for i := int64(0); i < 3; i++ {
+		assert.NoError(t, driver.AddStats(ref, testStats(i, uint64(i))))
+	}
+	assert.Equal(t, 2, len(es.indices["cadvisor"]))
+	assert.Equal(t, 2, len(es.indices["cadvisor-1"]))
+	assert.Equal(t, 1, len(es.indices["cadvisor-2"]))
+}
+
+func TestLabels(t *testing.T) {
+	driver, es, stop := newTestStorage(t, RolloverNone, 0)
+	defer stop()
+	driver.labels = map[string]string{"zone": "us-1"}
+
+	assert.NoError(t, driver.AddStats(info.ContainerReference{Name: "/a"}, testStats(1, 10)))
+	require.Equal(t, 1, len(es.indices["cadvisor"]))
+	assert.Equal(t, map[string]string{"zone": "us-1"}, es.indices["cadvisor"][0].Labels)
+	assert.Contains(t, es.mappings["cadvisor"], `"path_match":"labels.*"`)
+}
+
+func TestCloseWritesBufferedStats(t *testing.T) {
+	driver, es, stop := newTestStorage(t, RolloverNone, 0)
+	defer stop()
+	driver.OverrideReadyToFlush(func() bool { return false })
+
+	assert.NoError(t, driver.AddStats(info.ContainerReference{Name: "/a"}, testStats(1, 10)))
+	assert.Equal(t, 0, len(es.indices["cadvisor"]))
+	assert.NoError(t, driver.Close())
+	assert.Equal(t, 1, len(es.indices["cadvisor"]))
+}
+
+func TestValidate(t *testing.T) {
+	driver, _, stop := newTestStorage(t, RolloverDaily, 0)
+	assert.NoError(t, driver.Validate())
+	stop()
+	assert.Error(t, driver.Validate())
+}
+
+func TestNewInvalidRollover(t *testing.T) {
+	_, err := New("machine", nil, "cadvisor", "", "", "localhost:9200", false, "weekly", 0, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphite
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+const (
+	// Number of idle connections to Carbon kept open.
+	maxIdleConns = 4
+	dialTimeout  = 10 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// Characters not allowed in a node of a Graphite metric path.
+var invalidPathChars = regexp.MustCompile("[^a-zA-Z0-9_-]")
+
+// Characters not allowed in the value of a Graphite tag.
+var invalidTagChars = regexp.MustCompile(`[;~\s]`)
+
+type graphiteStorage struct {
+	prefix string
+	// ";<key>=<value>" tags appended to all the metric paths.
+	tags string
+	addr string
+	// Idle connections to Carbon, shared by the concurrent writers.
+	conns chan net.Conn
+	dial  func(addr string) (net.Conn, error)
+}
+
+func (self *graphiteStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	if stats == nil {
+		return nil
+	}
+	data := self.format(ref, stats)
+
+	conn, err := self.getConn()
+	if err != nil {
+		return err
+	}
+	if err = write(conn, data); err != nil {
+		// The connection may have been closed by Carbon while idle, retry once
+		// on a new one.
+		conn.Close()
+		conn, err = self.dial(self.addr)
+		if err != nil {
+			return fmt.Errorf("failed to reconnect to graphite at %q - %s", self.addr, err)
+		}
+		if err = write(conn, data); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to write stats to graphite at %q - %s", self.addr, err)
+		}
+	}
+	self.putConn(conn)
+	return nil
+}
+
+func write(conn net.Conn, data []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err := conn.Write(data)
+	return err
+}
+
+// Returns an idle connection, or a new one if there is none.
+func (self *graphiteStorage) getConn() (net.Conn, error) {
+	select {
+	case conn := <-self.conns:
+		return conn, nil
+	default:
+	}
+	conn, err := self.dial(self.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to graphite at %q - %s", self.addr, err)
+	}
+	return conn, nil
+}
+
+// Keeps a connection for later writes, or closes it if enough are kept.
+func (self *graphiteStorage) putConn(conn net.Conn) {
+	select {
+	case self.conns <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// Formats the stats in the Graphite plaintext protocol, one
+// "<prefix>.<container>.<metric>[;<key>=<value>...] <value> <timestamp>" line
+// per metric.
+func (self *graphiteStorage) format(ref info.ContainerReference, stats *info.ContainerStats) []byte {
+	base := self.prefix + "." + containerPath(ref)
+	timestamp := stats.Timestamp.Unix()
+	var buf bytes.Buffer
+	add := func(metric string, value uint64) {
+		fmt.Fprintf(&buf, "%s.%s%s %d %d\n", base, metric, self.tags, value, timestamp)
+	}
+	add("cpu.usage_total", stats.Cpu.Usage.Total)
+	add("cpu.usage_user", stats.Cpu.Usage.User)
+	add("cpu.usage_system", stats.Cpu.Usage.System)
+	add("memory.usage", stats.Memory.Usage)
+	add("memory.working_set", stats.Memory.WorkingSet)
+	add("network.rx_bytes", stats.Network.RxBytes)
+	add("network.rx_errors", stats.Network.RxErrors)
+	add("network.tx_bytes", stats.Network.TxBytes)
+	add("network.tx_errors", stats.Network.TxErrors)
+	for _, fs := range stats.Filesystem {
+		device := sanitize(strings.TrimPrefix(fs.Device, "/dev/"))
+		add("filesystem."+device+".usage", fs.Usage)
+		add("filesystem."+device+".limit", fs.Limit)
+	}
+	return buf.Bytes()
+}
+
+// Returns the metric path of a container: its first alias, or the nodes of its
+// name, e.g. "docker.abc" for "/docker/abc". The root container is "root".
+func containerPath(ref info.ContainerReference) string {
+	if len(ref.Aliases) > 0 {
+		return sanitize(ref.Aliases[0])
+	}
+	var nodes []string
+	for _, node := range strings.Split(ref.Name, "/") {
+		if node != "" {
+			nodes = append(nodes, sanitize(node))
+		}
+	}
+	if len(nodes) == 0 {
+		return "root"
+	}
+	return strings.Join(nodes, ".")
+}
+
+// Replaces the characters that are not allowed in a node of a metric path.
+func sanitize(node string) string {
+	return invalidPathChars.ReplaceAllString(node, "_")
+}
+
+// Checks that Carbon accepts connections. The connection is kept for the
+// first stats.
+func (self *graphiteStorage) Validate() error {
+	conn, err := self.getConn()
+	if err != nil {
+		return err
+	}
+	self.putConn(conn)
+	return nil
+}
+
+// Stats written to Carbon are not read back.
+func (self *graphiteStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
+	return nil, fmt.Errorf("the graphite storage driver does not support reading stats")
+}
+
+func (self *graphiteStorage) Close() error {
+	for {
+		select {
+		case conn := <-self.conns:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// Returns the labels as Graphite tags, sorted by key.
+func formatTags(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var tags bytes.Buffer
+	for _, key := range keys {
+		value := labels[key]
+		if value == "" {
+			// Tags with empty values are not allowed.
+			continue
+		}
+		fmt.Fprintf(&tags, ";%s=%s", key, invalidTagChars.ReplaceAllString(value, "_"))
+	}
+	return tags.String()
+}
+
+func newStorage(prefix string, labels map[string]string, addr string, dial func(addr string) (net.Conn, error)) *graphiteStorage {
+	return &graphiteStorage{
+		prefix: strings.TrimSuffix(prefix, "."),
+		tags:   formatTags(labels),
+		addr:   addr,
+		conns:  make(chan net.Conn, maxIdleConns),
+		dial:   dial,
+	}
+}
+
+// machineName: A unique identifier to identify the host that current cAdvisor
+// instance is running on.
+// labels: Attached to all the metrics as tags, which need Graphite 1.1 or
+// later.
+// prefix: Prefix of the metric paths, "cadvisor.<machineName>" if empty.
+// addr: host:port of the Carbon plaintext listener.
+func New(machineName string, labels map[string]string, prefix, addr string) (*graphiteStorage, error) {
+	if prefix == "" {
+		prefix = "cadvisor." + sanitize(machineName)
+	}
+	dial := func(addr string) (net.Conn, error) {
+		return net.DialTimeout("tcp", addr, dialTimeout)
+	}
+	return newStorage(prefix, labels, addr, dial), nil
+}
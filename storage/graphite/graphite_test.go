@@ -0,0 +1,140 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphite
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	driver := newStorage("cadvisor.host.", nil, "carbon:2003", nil)
+	stats := &info.ContainerStats{Timestamp: time.Unix(1434000000, 0)}
+	stats.Cpu.Usage.Total = 100
+	stats.Memory.Usage = 2048
+	stats.Filesystem = []info.FsStats{{Device: "/dev/sda1", Usage: 10, Limit: 20}}
+
+	lines := strings.Split(strings.TrimSpace(string(driver.format(info.ContainerReference{Name: "/docker/abc.1"}, stats))), "\n")
+	assert.Equal(t, 11, len(lines))
+	assert.Equal(t, "cadvisor.host.docker.abc_1.cpu.usage_total 100 1434000000", lines[0])
+	assert.Equal(t, "cadvisor.host.docker.abc_1.memory.usage 2048 1434000000", lines[3])
+	assert.Equal(t, "cadvisor.host.docker.abc_1.filesystem.sda1.limit 20 1434000000", lines[10])
+
+	assert.Equal(t, "root", containerPath(info.ContainerReference{Name: "/"}))
+	assert.Equal(t, "web_server", containerPath(info.ContainerReference{Name: "/docker/abc", Aliases: []string{"web.server", "abc"}}))
+}
+
+func TestFormatTags(t *testing.T) {
+	driver := newStorage("cadvisor", map[string]string{"zone": "us-1", "rack": "a;3", "empty": ""}, "carbon:2003", nil)
+	stats := &info.ContainerStats{Timestamp: time.Unix(1434000000, 0)}
+	stats.Cpu.Usage.Total = 100
+
+	lines := strings.Split(string(driver.format(info.ContainerReference{Name: "/"}, stats)), "\n")
+	assert.Equal(t, "cadvisor.root.cpu.usage_total;rack=a_3;zone=us-1 100 1434000000", lines[0])
+}
+
+// Dials connections whose other end is read into received, one line per
+// metric, or fails when dialErr is set.
+type fakeCarbon struct {
+	received chan string
+	servers  []net.Conn
+	dialErr  error
+}
+
+func (self *fakeCarbon) dial(addr string) (net.Conn, error) {
+	if self.dialErr != nil {
+		return nil, self.dialErr
+	}
+	client, server := net.Pipe()
+	self.servers = append(self.servers, server)
+	go func() {
+		scanner := bufio.NewScanner(server)
+		for scanner.Scan() {
+			self.received <- scanner.Text()
+		}
+	}()
+	return client, nil
+}
+
+// Waits for the lines written for one stats sample.
+func (self *fakeCarbon) sample(t *testing.T) []string {
+	var lines []string
+	for i := 0; i < 9; i++ {
+		select {
+		case line := <-self.received:
+			lines = append(lines, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for metrics, got %v", lines)
+		}
+	}
+	return lines
+}
+
+func TestAddStatsReconnects(t *testing.T) {
+	carbon := &fakeCarbon{received: make(chan string, 100)}
+	driver := newStorage("cadvisor", nil, "carbon:2003", carbon.dial)
+	ref := info.ContainerReference{Name: "/a"}
+	stats := &info.ContainerStats{Timestamp: time.Unix(1434000000, 0)}
+
+	// Writing returns once the pipe is read, so the writes run concurrently
+	// with the reads.
+	done := make(chan error)
+	go func() { done <- driver.AddStats(ref, stats) }()
+	assert.Equal(t, "cadvisor.a.cpu.usage_total 0 1434000000", carbon.sample(t)[0])
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, len(carbon.servers))
+
+	// The idle connection is reused.
+	go func() { done <- driver.AddStats(ref, stats) }()
+	carbon.sample(t)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, len(carbon.servers))
+
+	// Carbon closed the connection.
+	carbon.servers[0].Close()
+	go func() { done <- driver.AddStats(ref, stats) }()
+	carbon.sample(t)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 2, len(carbon.servers))
+
+	// Carbon is down.
+	carbon.servers[1].Close()
+	carbon.dialErr = errors.New("connection refused")
+	assert.Error(t, driver.AddStats(ref, stats))
+	assert.Error(t, driver.Validate())
+	assert.NoError(t, driver.AddStats(ref, nil))
+	assert.NoError(t, driver.Close())
+}
+
+func TestValidate(t *testing.T) {
+	carbon := &fakeCarbon{received: make(chan string, 100)}
+	driver := newStorage("cadvisor", nil, "carbon:2003", carbon.dial)
+	assert.NoError(t, driver.Validate())
+
+	// The connection opened to validate is used for the stats.
+	done := make(chan error)
+	go func() { done <- driver.AddStats(info.ContainerReference{Name: "/a"}, &info.ContainerStats{}) }()
+	carbon.sample(t)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, len(carbon.servers))
+	assert.NoError(t, driver.Close())
+}
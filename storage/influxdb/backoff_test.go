@@ -0,0 +1,101 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropsStatsWhileInfluxdbIsDown(t *testing.T) {
+	var down int32 = 1
+	var writes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&writes, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	driver, err := New("machine", nil, "stats", "cadvisor", "root", "root", strings.TrimPrefix(server.URL, "http://"), false, 0)
+	assert.Nil(t, err)
+	driver.initialBackoff = time.Hour
+	driver.maxBackoff = 4 * time.Hour
+	ref := info.ContainerReference{Name: "/docker/abc"}
+	stats := &info.ContainerStats{Timestamp: time.Now()}
+
+	// The failed write opens the circuit, the next stats are dropped without
+	// trying to write them.
+	assert.NotNil(t, driver.AddStats(ref, stats))
+	assert.Nil(t, driver.AddStats(ref, stats))
+	assert.Nil(t, driver.AddStats(ref, stats))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&writes))
+	health := driver.Health()["influxdb"]
+	assert.Equal(t, uint64(3), health.DroppedCount)
+	assert.Equal(t, uint64(1), health.ErrorCount)
+
+	// Failing again after the backoff doubles it.
+	driver.openUntil = time.Time{}
+	assert.NotNil(t, driver.AddStats(ref, stats))
+	assert.Equal(t, 2*time.Hour, driver.backoff)
+
+	// Writes resume once InfluxDB recovered.
+	atomic.StoreInt32(&down, 0)
+	driver.openUntil = time.Time{}
+	assert.Nil(t, driver.AddStats(ref, stats))
+	assert.Nil(t, driver.AddStats(ref, stats))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&writes))
+	health = driver.Health()["influxdb"]
+	assert.Equal(t, uint64(4), health.DroppedCount)
+	assert.False(t, health.LastSuccessTime.IsZero())
+	assert.Equal(t, time.Duration(0), driver.backoff)
+}
+
+func TestHungInfluxdbOpensCircuit(t *testing.T) {
+	hung := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hung
+	}))
+	defer server.Close()
+	defer close(hung)
+
+	defer func(timeout time.Duration) { requestTimeout = timeout }(requestTimeout)
+	requestTimeout = 100 * time.Millisecond
+	driver, err := New("machine", nil, "stats", "cadvisor", "root", "root", strings.TrimPrefix(server.URL, "http://"), false, 0)
+	assert.Nil(t, err)
+	ref := info.ContainerReference{Name: "/docker/abc"}
+	stats := &info.ContainerStats{Timestamp: time.Now()}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- driver.AddStats(ref, stats)
+	}()
+	select {
+	case err := <-done:
+		assert.NotNil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the write to a hung InfluxDB did not time out")
+	}
+	assert.Equal(t, uint64(1), driver.Health()["influxdb"].ErrorCount)
+	assert.Nil(t, driver.AddStats(ref, stats))
+}
@@ -16,24 +16,54 @@ package influxdb
 
 import (
 	"fmt"
+	"net/http"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/golang/glog"
 	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
 	influxdb "github.com/influxdb/influxdb/client"
 )
 
 type influxdbStorage struct {
 	client         *influxdb.Client
 	machineName    string
+	labels         map[string]string
+	labelNames     []string
 	tableName      string
+	database       string
+	username       string
+	password       string
 	bufferDuration time.Duration
 	lastWrite      time.Time
 	series         []*influxdb.Series
 	lock           sync.Mutex
 	readyToFlush   func() bool
+
+	// Number of stats samples in series.
+	samples int
+	// Stats are dropped rather than written until openUntil after a failed
+	// write, so that a sick InfluxDB is not hammered and does not hold back
+	// housekeeping. The backoff starts at initialBackoff and is doubled
+	// after each consecutive failure up to maxBackoff. All guarded by lock.
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	backoff        time.Duration
+	openUntil      time.Time
+	health         v2.SubsystemHealth
 }
 
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+)
+
+// Timeout of the requests to InfluxDB, so that a hung InfluxDB fails the
+// writes and opens the circuit rather than blocking housekeeping.
+var requestTimeout = 10 * time.Second
+
 const (
 	colTimestamp          string = "time"
 	colMachineName        string = "machine"
@@ -66,7 +96,7 @@ func (self *influxdbStorage) getSeriesDefaultValues(
 	values *[]interface{}) {
 	// Timestamp
 	*columns = append(*columns, colTimestamp)
-	*values = append(*values, stats.Timestamp.UnixNano()/1E3)
+	*values = append(*values, stats.Timestamp.UnixNano()/1e3)
 
 	// Machine name
 	*columns = append(*columns, colMachineName)
@@ -81,6 +111,27 @@ func (self *influxdbStorage) getSeriesDefaultValues(
 	}
 }
 
+// Adds a column for each static label, unless there is already a column with
+// its name.
+func (self *influxdbStorage) addLabels(columns *[]string, values *[]interface{}) {
+	for _, name := range self.labelNames {
+		if hasColumn(*columns, name) {
+			continue
+		}
+		*columns = append(*columns, name)
+		*values = append(*values, self.labels[name])
+	}
+}
+
+func hasColumn(columns []string, name string) bool {
+	for _, column := range columns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
 // In order to maintain a fixed column format, we add a new series for each filesystem partition.
 func (self *influxdbStorage) containerFilesystemStatsToSeries(
 	ref info.ContainerReference,
@@ -101,6 +152,7 @@ func (self *influxdbStorage) containerFilesystemStatsToSeries(
 
 		columns = append(columns, colFsUsage)
 		values = append(values, fsStat.Usage)
+		self.addLabels(&columns, &values)
 		series = append(series, self.newSeries(columns, values))
 	}
 	return series
@@ -136,6 +188,7 @@ func (self *influxdbStorage) containerStatsToValues(
 	columns = append(columns, colTxErrors)
 	values = append(values, stats.Network.TxErrors)
 
+	self.addLabels(&columns, &values)
 	return columns, values
 }
 
@@ -182,7 +235,7 @@ func (self *influxdbStorage) valuesToContainerStats(columns []string, values []i
 		switch {
 		case col == colTimestamp:
 			if f64sec, ok := v.(float64); ok && stats.Timestamp.IsZero() {
-				stats.Timestamp = time.Unix(int64(f64sec)/1E3, (int64(f64sec)%1E3)*1E6)
+				stats.Timestamp = time.Unix(int64(f64sec)/1e3, (int64(f64sec)%1e3)*1e6)
 			}
 		case col == colMachineName:
 			if m, ok := v.(string); ok {
@@ -260,21 +313,30 @@ func (self *influxdbStorage) AddStats(ref info.ContainerReference, stats *info.C
 		return nil
 	}
 	var seriesToFlush []*influxdb.Series
+	var samplesToFlush int
 	func() {
 		// AddStats will be invoked simultaneously from multiple threads and only one of them will perform a write.
 		self.lock.Lock()
 		defer self.lock.Unlock()
 
+		if time.Now().Before(self.openUntil) {
+			self.health.DroppedCount++
+			return
+		}
 		self.series = append(self.series, self.newSeries(self.containerStatsToValues(ref, stats)))
 		self.series = append(self.series, self.containerFilesystemStatsToSeries(ref, stats)...)
+		self.samples++
 		if self.readyToFlush() {
 			seriesToFlush = self.series
+			samplesToFlush = self.samples
 			self.series = make([]*influxdb.Series, 0)
+			self.samples = 0
 			self.lastWrite = time.Now()
 		}
 	}()
 	if len(seriesToFlush) > 0 {
 		err := self.client.WriteSeriesWithTimePrecision(seriesToFlush, influxdb.Microsecond)
+		self.recordWrite(samplesToFlush, err)
 		if err != nil {
 			return fmt.Errorf("failed to write stats to influxDb - %s", err)
 		}
@@ -283,6 +345,44 @@ func (self *influxdbStorage) AddStats(ref info.ContainerReference, stats *info.C
 	return nil
 }
 
+// Records the outcome of writing the given number of stats samples. After a
+// failure, the samples are dropped and so are the next ones until the
+// backoff expires, the write after that probes whether InfluxDB recovered.
+func (self *influxdbStorage) recordWrite(samples int, err error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	now := time.Now()
+	if err == nil {
+		if self.backoff != 0 {
+			glog.Infof("InfluxDB recovered, resuming writes")
+		}
+		self.backoff = 0
+		self.health.LastSuccessTime = now
+		return
+	}
+	if self.backoff == 0 {
+		self.backoff = self.initialBackoff
+	} else {
+		self.backoff *= 2
+	}
+	if self.backoff > self.maxBackoff {
+		self.backoff = self.maxBackoff
+	}
+	self.openUntil = now.Add(self.backoff)
+	self.health.ErrorCount++
+	self.health.LastErrorTime = now
+	self.health.LastError = err.Error()
+	self.health.DroppedCount += uint64(samples)
+	glog.Warningf("Failed to write stats to InfluxDB, dropping stats for %v: %v", self.backoff, err)
+}
+
+// Returns the status of the writes to InfluxDB.
+func (self *influxdbStorage) Health() map[string]v2.SubsystemHealth {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return map[string]v2.SubsystemHealth{"influxdb": self.health}
+}
+
 func (self *influxdbStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
 	if numStats == 0 {
 		return nil, nil
@@ -319,9 +419,30 @@ func (self *influxdbStorage) RecentStats(containerName string, numStats int) ([]
 	return statsList, nil
 }
 
+// Checks that InfluxDB is reachable and that the user can access the database.
+func (self *influxdbStorage) Validate() error {
+	err := self.client.AuthenticateDatabaseUser(self.database, self.username, self.password)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate to influxdb database %q as %q - %s", self.database, self.username, err)
+	}
+	return nil
+}
+
+// Writes the buffered stats.
 func (self *influxdbStorage) Close() error {
+	self.lock.Lock()
+	series := self.series
+	self.series = make([]*influxdb.Series, 0)
+	self.samples = 0
+	self.lock.Unlock()
+	var err error
+	if len(series) > 0 {
+		if err = self.client.WriteSeriesWithTimePrecision(series, influxdb.Microsecond); err != nil {
+			err = fmt.Errorf("failed to write stats to influxDb - %s", err)
+		}
+	}
 	self.client = nil
-	return nil
+	return err
 }
 
 // Returns a new influxdb series.
@@ -338,8 +459,10 @@ func (self *influxdbStorage) newSeries(columns []string, points []interface{}) *
 
 // machineName: A unique identifier to identify the host that current cAdvisor
 // instance is running on.
+// labels: Written as additional columns of all the series.
 // influxdbHost: The host which runs influxdb.
-func New(machineName,
+func New(machineName string,
+	labels map[string]string,
 	tablename,
 	database,
 	username,
@@ -354,6 +477,8 @@ func New(machineName,
 		Password: password,
 		Database: database,
 		IsSecure: isSecure,
+		// The default client has no timeout.
+		HttpClient: &http.Client{Timeout: requestTimeout},
 	}
 	client, err := influxdb.NewClient(config)
 	if err != nil {
@@ -365,11 +490,21 @@ func New(machineName,
 	ret := &influxdbStorage{
 		client:         client,
 		machineName:    machineName,
+		labels:         labels,
 		tableName:      tablename,
+		database:       database,
+		username:       username,
+		password:       password,
 		bufferDuration: bufferDuration,
 		lastWrite:      time.Now(),
 		series:         make([]*influxdb.Series, 0),
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+	for name := range labels {
+		ret.labelNames = append(ret.labelNames, name)
 	}
+	sort.Strings(ret.labelNames)
 	ret.readyToFlush = ret.defaultReadyToFlush
 	return ret, nil
 }
@@ -134,6 +134,7 @@ func runStorageTest(f func(test.TestStorageDriver, *testing.T), t *testing.T, bu
 	defer client.Query(deleteAll)
 
 	driver, err := New(machineName,
+		nil,
 		tablename,
 		database,
 		username,
@@ -154,6 +155,7 @@ func runStorageTest(f func(test.TestStorageDriver, *testing.T), t *testing.T, bu
 
 	// generate another container's data on another machine.
 	driverForAnotherMachine, err := New("machineB",
+		nil,
 		tablename,
 		database,
 		username,
@@ -0,0 +1,93 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Downsamples the stats that no longer fit in the full resolution buffer of a
+// container into buckets of a fixed duration.
+//
+// The counters of a bucket (CPU time, bytes transferred, ...) are the ones of
+// its last sample so that rates computed across buckets stay accurate. The
+// memory usage, working set, cache, RSS and swap and the load average are
+// averaged over the bucket.
+type downsampler struct {
+	resolution time.Duration
+	// Completed buckets.
+	samples *StatsBuffer
+
+	// Bucket being filled, nil if there is none.
+	bucket      *info.ContainerStats
+	bucketStart time.Time
+	numSamples  uint64
+	usage       uint64
+	workingSet  uint64
+	cache       uint64
+	rss         uint64
+	swap        uint64
+	loadAverage int64
+}
+
+func newDownsampler(resolution time.Duration, maxSamples int) *downsampler {
+	return &downsampler{
+		resolution: resolution,
+		samples:    NewStatsBuffer(maxSamples),
+	}
+}
+
+// Adds a sample, newer than the ones added before, to the current bucket.
+func (self *downsampler) add(stats *info.ContainerStats) {
+	if self.bucket != nil && !stats.Timestamp.Before(self.bucketStart.Add(self.resolution)) {
+		self.flush()
+	}
+	if self.bucket == nil {
+		self.bucketStart = stats.Timestamp
+	}
+	last := *stats
+	self.bucket = &last
+	self.numSamples++
+	self.usage += stats.Memory.Usage
+	self.workingSet += stats.Memory.WorkingSet
+	self.cache += stats.Memory.Cache
+	self.rss += stats.Memory.RSS
+	self.swap += stats.Memory.Swap
+	self.loadAverage += int64(stats.Cpu.LoadAverage)
+}
+
+// Completes the current bucket.
+func (self *downsampler) flush() {
+	bucket := self.bucket
+	bucket.Resolution = self.resolution
+	bucket.Memory.Usage = self.usage / self.numSamples
+	bucket.Memory.WorkingSet = self.workingSet / self.numSamples
+	bucket.Memory.Cache = self.cache / self.numSamples
+	bucket.Memory.RSS = self.rss / self.numSamples
+	bucket.Memory.Swap = self.swap / self.numSamples
+	bucket.Cpu.LoadAverage = int32(self.loadAverage / int64(self.numSamples))
+	self.samples.Add(bucket)
+
+	self.bucket = nil
+	self.numSamples = 0
+	self.usage = 0
+	self.workingSet = 0
+	self.cache = 0
+	self.rss = 0
+	self.swap = 0
+	self.loadAverage = 0
+}
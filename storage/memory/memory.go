@@ -15,12 +15,15 @@
 package memory
 
 import (
+	"container/heap"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
 	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
 	"github.com/google/cadvisor/storage"
 )
 
@@ -30,12 +33,41 @@ type containerStorage struct {
 	ref         info.ContainerReference
 	recentStats *StatsBuffer
 	maxNumStats int
-	lock        sync.RWMutex
+	// Older stats, nil if they are dropped.
+	downsampled *downsampler
+	// Approximate number of bytes used by the stats of all containers,
+	// updated as the stats of this one change.
+	usedBytes *int64
+	lock      sync.RWMutex
+}
+
+// Minimum number of recent stats of a container kept when stats are evicted
+// to stay within the memory budget, so that its latest stats and rates
+// remain available.
+const minStatsKept = 2
+
+// Returns the approximate number of bytes used by the stats of the container.
+// The downsampling bucket being filled, a single sample, is not counted.
+func (self *containerStorage) bytes() int64 {
+	bytes := self.recentStats.Bytes()
+	if self.downsampled != nil {
+		bytes += self.downsampled.samples.Bytes()
+	}
+	return bytes
 }
 
 func (self *containerStorage) AddStats(stats *info.ContainerStats) error {
 	self.lock.Lock()
 	defer self.lock.Unlock()
+	before := self.bytes()
+	defer func() {
+		atomic.AddInt64(self.usedBytes, self.bytes()-before)
+	}()
+
+	// Keep the oldest stat downsampled before it is overwritten.
+	if self.downsampled != nil && self.recentStats.Size() == self.maxNumStats {
+		self.downsampled.add(self.recentStats.Get(self.maxNumStats - 1))
+	}
 
 	// Add the stat to storage.
 	self.recentStats.Add(stats)
@@ -45,15 +77,74 @@ func (self *containerStorage) AddStats(stats *info.ContainerStats) error {
 func (self *containerStorage) RecentStats(start, end time.Time, maxStats int) ([]*info.ContainerStats, error) {
 	self.lock.RLock()
 	defer self.lock.RUnlock()
-	return self.recentStats.InTimeRange(start, end, maxStats), nil
+	stats := self.recentStats.InTimeRange(start, end, maxStats)
+	if self.downsampled == nil || (maxStats >= 0 && len(stats) >= maxStats) {
+		return stats, nil
+	}
+
+	// The downsampled stats are all older than the full resolution ones.
+	remaining := -1
+	if maxStats >= 0 {
+		remaining = maxStats - len(stats)
+	}
+	older := self.downsampled.samples.InTimeRange(start, end, remaining)
+	return append(older, stats...), nil
+}
+
+func (self *containerStorage) LatestStats() *info.ContainerStats {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.recentStats.Latest()
+}
+
+// Returns the stats evictOldest would remove, nil if there are none.
+func (self *containerStorage) oldestEvictable() *info.ContainerStats {
+	if self.downsampled != nil && self.downsampled.samples.Size() > 0 {
+		return self.downsampled.samples.Oldest()
+	}
+	if self.recentStats.Size() > minStatsKept {
+		return self.recentStats.Oldest()
+	}
+	return nil
+}
+
+// Returns the timestamp of the oldest stats of the container which can be
+// evicted, false if there are none.
+func (self *containerStorage) oldestEvictableTime() (time.Time, bool) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	oldest := self.oldestEvictable()
+	if oldest == nil {
+		return time.Time{}, false
+	}
+	return oldest.Timestamp, true
+}
+
+// Removes the oldest stats of the container, downsampled ones first, keeping
+// at least minStatsKept recent ones.
+func (self *containerStorage) evictOldest() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	before := self.bytes()
+	if self.downsampled != nil && self.downsampled.samples.Size() > 0 {
+		self.downsampled.samples.RemoveOldest()
+	} else if self.recentStats.Size() > minStatsKept {
+		self.recentStats.RemoveOldest()
+	}
+	atomic.AddInt64(self.usedBytes, self.bytes()-before)
 }
 
-func newContainerStore(ref info.ContainerReference, maxNumStats int) *containerStorage {
-	return &containerStorage{
+func newContainerStore(ref info.ContainerReference, maxNumStats int, resolution time.Duration, maxDownsampled int, usedBytes *int64) *containerStorage {
+	cstore := &containerStorage{
 		ref:         ref,
 		recentStats: NewStatsBuffer(maxNumStats),
 		maxNumStats: maxNumStats,
+		usedBytes:   usedBytes,
+	}
+	if maxDownsampled > 0 {
+		cstore.downsampled = newDownsampler(resolution, maxDownsampled)
 	}
+	return cstore
 }
 
 type InMemoryStorage struct {
@@ -61,6 +152,21 @@ type InMemoryStorage struct {
 	containerStorageMap map[string]*containerStorage
 	maxNumStats         int
 	backend             storage.StorageDriver
+
+	// Duration of the downsampled stats and how many of them are kept once
+	// stats no longer fit in the maxNumStats most recent ones.
+	resolution     time.Duration
+	maxDownsampled int
+
+	// Approximate number of bytes used by the stats of all containers and
+	// the budget beyond which the oldest of them are evicted, 0 if unlimited.
+	// usedBytes is accessed atomically.
+	usedBytes int64
+	maxBytes  int64
+	// Serializes evictions.
+	evictLock sync.Mutex
+	// Whether the stats could not be evicted down to the budget last time.
+	overBudget bool
 }
 
 func (self *InMemoryStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
@@ -71,7 +177,7 @@ func (self *InMemoryStorage) AddStats(ref info.ContainerReference, stats *info.C
 		self.lock.Lock()
 		defer self.lock.Unlock()
 		if cstore, ok = self.containerStorageMap[ref.Name]; !ok {
-			cstore = newContainerStore(ref, self.maxNumStats)
+			cstore = newContainerStore(ref, self.maxNumStats, self.resolution, self.maxDownsampled, &self.usedBytes)
 			self.containerStorageMap[ref.Name] = cstore
 		}
 	}()
@@ -84,7 +190,112 @@ func (self *InMemoryStorage) AddStats(ref info.ContainerReference, stats *info.C
 			glog.Error(err)
 		}
 	}
-	return cstore.AddStats(stats)
+	err := cstore.AddStats(stats)
+	if self.maxBytes > 0 && atomic.LoadInt64(&self.usedBytes) > self.maxBytes {
+		self.evict()
+	}
+	return err
+}
+
+// A container whose oldest evictable stats have the given timestamp.
+type evictionCandidate struct {
+	cstore    *containerStorage
+	timestamp time.Time
+}
+
+// Min-heap of eviction candidates by timestamp.
+type evictionCandidates []evictionCandidate
+
+func (self evictionCandidates) Len() int { return len(self) }
+func (self evictionCandidates) Less(i, j int) bool {
+	return self[i].timestamp.Before(self[j].timestamp)
+}
+func (self evictionCandidates) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+
+func (self *evictionCandidates) Push(x interface{}) {
+	*self = append(*self, x.(evictionCandidate))
+}
+
+func (self *evictionCandidates) Pop() interface{} {
+	old := *self
+	n := len(old)
+	item := old[n-1]
+	*self = old[:n-1]
+	return item
+}
+
+// Evicts the oldest stats across all containers until they use less than 90%
+// of the memory budget, so that evictions do not happen on every new sample.
+func (self *InMemoryStorage) evict() {
+	self.evictLock.Lock()
+	defer self.evictLock.Unlock()
+	if atomic.LoadInt64(&self.usedBytes) <= self.maxBytes {
+		// Another eviction just made room.
+		return
+	}
+
+	self.lock.RLock()
+	candidates := make(evictionCandidates, 0, len(self.containerStorageMap))
+	for _, cstore := range self.containerStorageMap {
+		if timestamp, ok := cstore.oldestEvictableTime(); ok {
+			candidates = append(candidates, evictionCandidate{cstore, timestamp})
+		}
+	}
+	self.lock.RUnlock()
+	heap.Init(&candidates)
+
+	target := self.maxBytes - self.maxBytes/10
+	evicted := 0
+	for candidates.Len() > 0 && atomic.LoadInt64(&self.usedBytes) > target {
+		candidate := heap.Pop(&candidates).(evictionCandidate)
+		candidate.cstore.evictOldest()
+		evicted++
+		if timestamp, ok := candidate.cstore.oldestEvictableTime(); ok {
+			heap.Push(&candidates, evictionCandidate{candidate.cstore, timestamp})
+		}
+	}
+
+	used := atomic.LoadInt64(&self.usedBytes)
+	glog.V(3).Infof("Evicted %d stats, using about %d bytes of the %d bytes budget", evicted, used, self.maxBytes)
+	if used > self.maxBytes {
+		if !self.overBudget {
+			glog.Warningf("Stats use about %d bytes, over the budget of %d bytes, with only the %d most recent stats of each container left", used, self.maxBytes, minStatsKept)
+		}
+		self.overBudget = true
+	} else {
+		self.overBudget = false
+	}
+}
+
+// Drops the stats of a container, e.g. once it is destroyed.
+func (self *InMemoryStorage) RemoveContainer(name string) {
+	self.lock.Lock()
+	cstore, ok := self.containerStorageMap[name]
+	delete(self.containerStorageMap, name)
+	self.lock.Unlock()
+	if !ok {
+		return
+	}
+	cstore.lock.Lock()
+	defer cstore.lock.Unlock()
+	atomic.AddInt64(&self.usedBytes, -cstore.bytes())
+	// Stats still being added by the housekeeping of the container are not
+	// counted against the other containers.
+	cstore.usedBytes = new(int64)
+}
+
+// Returns the approximate number of bytes used by the stats of all containers.
+func (self *InMemoryStorage) UsedBytes() int64 {
+	return atomic.LoadInt64(&self.usedBytes)
+}
+
+// Limits the approximate number of bytes used by the stats of all containers
+// to maxBytes, evicting the oldest stats across all containers when they use
+// more. At least the most recent stats of each container are kept, so the
+// budget can be exceeded when there are very many containers. 0 means no
+// limit.
+func (self *InMemoryStorage) SetMaxBytes(maxBytes int64) {
+	self.maxBytes = maxBytes
 }
 
 func (self *InMemoryStorage) RecentStats(name string, start, end time.Time, maxStats int) ([]*info.ContainerStats, error) {
@@ -105,10 +316,36 @@ func (self *InMemoryStorage) RecentStats(name string, start, end time.Time, maxS
 	return cstore.RecentStats(start, end, maxStats)
 }
 
+// Returns the most recent stats of the container, or nil if there are none yet.
+func (self *InMemoryStorage) LatestStats(name string) (*info.ContainerStats, error) {
+	self.lock.RLock()
+	cstore, ok := self.containerStorageMap[name]
+	self.lock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unable to find data for container %v", name)
+	}
+	return cstore.LatestStats(), nil
+}
+
+// Returns the status of the writes to the backends of the backend storage
+// driver, nil if it does not report it.
+func (self *InMemoryStorage) BackendHealth() map[string]v2.SubsystemHealth {
+	if reporter, ok := self.backend.(storage.HealthReporter); ok {
+		return reporter.Health()
+	}
+	return nil
+}
+
+// Drops the stored stats and closes the backend, which writes the stats it
+// buffered.
 func (self *InMemoryStorage) Close() error {
 	self.lock.Lock()
 	self.containerStorageMap = make(map[string]*containerStorage, 32)
+	atomic.StoreInt64(&self.usedBytes, 0)
 	self.lock.Unlock()
+	if self.backend != nil {
+		return self.backend.Close()
+	}
 	return nil
 }
 
@@ -123,3 +360,18 @@ func New(
 	}
 	return ret
 }
+
+// Returns an InMemoryStorage which, once the maxNumStats most recent stats of
+// a container are stored, keeps up to maxDownsampled older stats averaged over
+// periods of the given resolution.
+func NewWithDownsampling(
+	maxNumStats int,
+	resolution time.Duration,
+	maxDownsampled int,
+	backend storage.StorageDriver,
+) *InMemoryStorage {
+	ret := New(maxNumStats, backend)
+	ret.resolution = resolution
+	ret.maxDownsampled = maxDownsampled
+	return ret
+}
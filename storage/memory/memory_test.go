@@ -15,10 +15,12 @@
 package memory
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/storage/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -95,3 +97,152 @@ func TestRecentStatsGetAllStats(t *testing.T) {
 
 	assert.Len(t, getRecentStats(t, memoryStorage, -1), 10)
 }
+
+func TestLatestStats(t *testing.T) {
+	memoryStorage := makeWithStats(10)
+
+	stats, err := memoryStorage.LatestStats(containerName)
+	assert.Nil(t, err)
+	assert.Equal(t, makeStat(9), stats)
+
+	_, err = memoryStorage.LatestStats("/unknown")
+	assert.NotNil(t, err)
+}
+
+func TestRecentStatsDownsampled(t *testing.T) {
+	memoryStorage := NewWithDownsampling(3, 2*time.Second, 10, nil)
+	for i := 0; i < 10; i++ {
+		stat := makeStat(i)
+		stat.Memory.Usage = uint64(i * 10)
+		stat.Memory.Cache = uint64(i * 2)
+		stat.Cpu.Usage.Total = uint64(i * 100)
+		require.Nil(t, memoryStorage.AddStats(containerRef, stat))
+	}
+
+	// Stats 0 to 6 fell out of the full resolution stats and are averaged in
+	// buckets of 2 seconds, the bucket of stat 6 is not complete yet.
+	stats := getRecentStats(t, memoryStorage, -1)
+	require.Equal(t, 6, len(stats))
+	for i, expected := range []struct {
+		second     int
+		resolution time.Duration
+		usage      uint64
+		cpu        uint64
+	}{
+		{1, 2 * time.Second, 5, 100},
+		{3, 2 * time.Second, 25, 300},
+		{5, 2 * time.Second, 45, 500},
+		{7, 0, 70, 700},
+		{8, 0, 80, 800},
+		{9, 0, 90, 900},
+	} {
+		assert.Equal(t, zero.Add(time.Duration(expected.second)*time.Second), stats[i].Timestamp)
+		assert.Equal(t, expected.resolution, stats[i].Resolution)
+		assert.Equal(t, expected.usage, stats[i].Memory.Usage)
+		assert.Equal(t, expected.usage/5, stats[i].Memory.Cache)
+		assert.Equal(t, expected.cpu, stats[i].Cpu.Usage.Total)
+	}
+
+	// The most recent stats are kept when they are limited.
+	stats = getRecentStats(t, memoryStorage, 4)
+	require.Equal(t, 4, len(stats))
+	assert.Equal(t, 2*time.Second, stats[0].Resolution)
+	assert.Equal(t, uint64(45), stats[0].Memory.Usage)
+	assert.Equal(t, time.Duration(0), stats[1].Resolution)
+
+	stats = getRecentStats(t, memoryStorage, 2)
+	require.Equal(t, 2, len(stats))
+	assert.Equal(t, uint64(80), stats[0].Memory.Usage)
+}
+
+func TestMaxBytes(t *testing.T) {
+	memoryStorage := New(10, nil)
+	// The stats reference nothing, they only use a slot of their buffer.
+	sampleBytes := statsSize(makeStat(0))
+	// Room for 10 stats, evictions go down to 9.
+	memoryStorage.SetMaxBytes(10 * sampleBytes)
+
+	containerRef2 := info.ContainerReference{Name: "/container2"}
+	for i := 0; i < 4; i++ {
+		require.Nil(t, memoryStorage.AddStats(containerRef, makeStat(2*i)))
+		require.Nil(t, memoryStorage.AddStats(containerRef2, makeStat(2*i+1)))
+	}
+	assert.Equal(t, 8*sampleBytes, memoryStorage.UsedBytes())
+	// The buffer of container2 grows to 8 slots, the oldest stats across
+	// containers are evicted until it shrinks back to 4.
+	require.Nil(t, memoryStorage.AddStats(containerRef2, makeStat(9)))
+	assert.Equal(t, 8*sampleBytes, memoryStorage.UsedBytes())
+	stats := getRecentStats(t, memoryStorage, -1)
+	require.Equal(t, 2, len(stats))
+	assert.Equal(t, makeStat(4), stats[0])
+	stats2, err := memoryStorage.RecentStats("/container2", zero, zero, -1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(stats2))
+	assert.Equal(t, makeStat(7), stats2[0])
+
+	// The most recent stats of each container are kept over the budget.
+	for i := 0; i < 10; i++ {
+		ref := info.ContainerReference{Name: fmt.Sprintf("/new%d", i)}
+		require.Nil(t, memoryStorage.AddStats(ref, makeStat(10+i)))
+	}
+	assert.Equal(t, 2, len(getRecentStats(t, memoryStorage, -1)))
+	latest, err := memoryStorage.LatestStats("/new0")
+	require.Nil(t, err)
+	assert.Equal(t, makeStat(10), latest)
+	assert.Equal(t, 18*sampleBytes, memoryStorage.UsedBytes())
+}
+
+func TestRemoveContainer(t *testing.T) {
+	memoryStorage := New(10, nil)
+	sampleBytes := statsSize(makeStat(0))
+	containerRef2 := info.ContainerReference{Name: "/container2"}
+	for i := 0; i < 4; i++ {
+		require.Nil(t, memoryStorage.AddStats(containerRef, makeStat(i)))
+		require.Nil(t, memoryStorage.AddStats(containerRef2, makeStat(i)))
+	}
+	assert.Equal(t, 8*sampleBytes, memoryStorage.UsedBytes())
+
+	memoryStorage.RemoveContainer(containerRef2.Name)
+	assert.Equal(t, 4*sampleBytes, memoryStorage.UsedBytes())
+	_, err := memoryStorage.LatestStats(containerRef2.Name)
+	assert.Error(t, err)
+	assert.Equal(t, 4, len(getRecentStats(t, memoryStorage, -1)))
+
+	// Unknown containers are ignored.
+	memoryStorage.RemoveContainer(containerRef2.Name)
+	assert.Equal(t, 4*sampleBytes, memoryStorage.UsedBytes())
+}
+
+func TestMaxBytesDownsampled(t *testing.T) {
+	memoryStorage := NewWithDownsampling(3, time.Second, 10, nil)
+	sampleBytes := statsSize(makeStat(0))
+	for i := 0; i < 6; i++ {
+		require.Nil(t, memoryStorage.AddStats(containerRef, makeStat(i)))
+	}
+	// Stats 0 and 1 are downsampled, stat 2 is in the bucket being filled.
+	assert.Equal(t, 5*sampleBytes, memoryStorage.UsedBytes())
+
+	// Downsampled stats are evicted first.
+	memoryStorage.SetMaxBytes(4 * sampleBytes)
+	require.Nil(t, memoryStorage.AddStats(containerRef, makeStat(6)))
+	stats := getRecentStats(t, memoryStorage, -1)
+	require.Equal(t, 3, len(stats))
+	assert.Equal(t, zero.Add(4*time.Second), stats[0].Timestamp)
+	assert.Equal(t, 3*sampleBytes, memoryStorage.UsedBytes())
+}
+
+func TestStatsSize(t *testing.T) {
+	stats := makeStat(0)
+	size := statsSize(stats)
+	stats.Filesystem = []info.FsStats{{Device: "sda1"}}
+	stats.Cpu.Usage.PerCpu = []uint64{1, 2}
+	assert.True(t, statsSize(stats) > size)
+}
+
+func TestCloseClosesBackend(t *testing.T) {
+	backend := &test.MockStorageDriver{MockCloseMethod: true}
+	backend.On("Close").Return(nil)
+	storage := New(10, backend)
+	assert.NoError(t, storage.Close())
+	backend.AssertExpectations(t)
+}
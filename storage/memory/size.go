@@ -0,0 +1,82 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"unsafe"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Rough overhead of a map entry on top of its key and value.
+const mapEntryOverhead = 16
+
+// Returns the approximate number of bytes used by stats, including what its
+// slices, maps and pointers reference. Strings shared with other samples
+// (device and interface names, ...) are counted each time.
+func statsSize(stats *info.ContainerStats) int64 {
+	size := int64(unsafe.Sizeof(*stats))
+	size += int64(len(stats.Cpu.Usage.PerCpu)) * int64(unsafe.Sizeof(uint64(0)))
+
+	for _, disks := range [][]info.PerDiskStats{
+		stats.DiskIo.IoServiceBytes,
+		stats.DiskIo.IoServiced,
+		stats.DiskIo.IoQueued,
+		stats.DiskIo.Sectors,
+		stats.DiskIo.IoServiceTime,
+		stats.DiskIo.IoWaitTime,
+		stats.DiskIo.IoMerged,
+		stats.DiskIo.IoTime,
+	} {
+		for _, disk := range disks {
+			size += int64(unsafe.Sizeof(disk)) + int64(len(disk.Device))
+			for key := range disk.Stats {
+				size += int64(unsafe.Sizeof(key)+unsafe.Sizeof(uint64(0))+mapEntryOverhead) + int64(len(key))
+			}
+		}
+	}
+	for _, disk := range stats.DiskIo.IoServiceTimeHistogram {
+		size += int64(unsafe.Sizeof(disk))
+		size += int64(len(disk.Read)+len(disk.Write)) * int64(unsafe.Sizeof(info.LatencyBucket{}))
+	}
+
+	for pageSize, hugetlb := range stats.Memory.HugeTlb {
+		size += int64(unsafe.Sizeof(pageSize)+unsafe.Sizeof(hugetlb)+mapEntryOverhead) + int64(len(pageSize))
+	}
+	for _, fs := range stats.Filesystem {
+		size += int64(unsafe.Sizeof(fs)) + int64(len(fs.Device))
+	}
+	for _, iface := range stats.Network.Interfaces {
+		size += int64(unsafe.Sizeof(iface)) + int64(len(iface.Name))
+	}
+	for _, accelerator := range stats.Accelerators {
+		size += int64(unsafe.Sizeof(accelerator)) + int64(len(accelerator.Make)+len(accelerator.Model)+len(accelerator.ID))
+	}
+	for name, values := range stats.CustomMetrics {
+		size += int64(unsafe.Sizeof(name)+unsafe.Sizeof(values)+mapEntryOverhead) + int64(len(name))
+		size += int64(len(values)) * int64(unsafe.Sizeof(info.MetricVal{}))
+	}
+
+	if stats.PSI != nil {
+		size += int64(unsafe.Sizeof(*stats.PSI))
+	}
+	if stats.Processes != nil {
+		size += int64(unsafe.Sizeof(*stats.Processes))
+	}
+	if stats.Pids != nil {
+		size += int64(unsafe.Sizeof(*stats.Pids))
+	}
+	return size
+}
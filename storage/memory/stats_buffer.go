@@ -17,49 +17,122 @@ package memory
 import (
 	"sort"
 	"time"
+	"unsafe"
 
 	info "github.com/google/cadvisor/info/v1"
 )
 
-// A circular buffer for ContainerStats.
+// A circular buffer for ContainerStats. The buffer only grows to its capacity
+// as elements are added, so that containers which were just created do not
+// hold the memory of a full buffer.
 type StatsBuffer struct {
-	buffer []info.ContainerStats
-	size   int
-	index  int
+	buffer   []info.ContainerStats
+	capacity int
+	size     int
+	index    int
+	// Approximate number of bytes referenced by the elements, on top of the
+	// slots of the buffer.
+	referencedBytes int64
 }
 
+// Bytes used by a slot of the buffer, whether it holds an element or not.
+const slotSize = int64(unsafe.Sizeof(info.ContainerStats{}))
+
 // Returns a new thread-compatible StatsBuffer.
 func NewStatsBuffer(size int) *StatsBuffer {
 	return &StatsBuffer{
-		buffer: make([]info.ContainerStats, size),
-		size:   0,
-		index:  size - 1,
+		capacity: size,
+		size:     0,
+		index:    -1,
 	}
 }
 
 // Adds an element to the start of the buffer (removing one from the end if necessary).
 func (self *StatsBuffer) Add(item *info.ContainerStats) {
-	if self.size < len(self.buffer) {
+	if len(self.buffer) < self.capacity {
+		// Still growing, elements are all at the start of the buffer.
+		if len(self.buffer) == cap(self.buffer) {
+			newCap := 2 * cap(self.buffer)
+			if newCap == 0 {
+				newCap = 1
+			}
+			if newCap > self.capacity {
+				newCap = self.capacity
+			}
+			buffer := make([]info.ContainerStats, len(self.buffer), newCap)
+			copy(buffer, self.buffer)
+			self.buffer = buffer
+		}
+		self.buffer = append(self.buffer, *item)
+		self.index = len(self.buffer) - 1
 		self.size++
+	} else {
+		self.index = (self.index + 1) % len(self.buffer)
+		if self.size < len(self.buffer) {
+			self.size++
+		} else {
+			self.referencedBytes -= statsSize(&self.buffer[self.index]) - slotSize
+		}
+		self.buffer[self.index] = *item
+	}
+	self.referencedBytes += statsSize(item) - slotSize
+}
+
+// Returns the oldest element, or nil if the buffer is empty.
+func (self *StatsBuffer) Oldest() *info.ContainerStats {
+	if self.size == 0 {
+		return nil
+	}
+	return self.Get(self.size - 1)
+}
+
+// Removes the oldest element, if any.
+func (self *StatsBuffer) RemoveOldest() {
+	if self.size == 0 {
+		return
+	}
+	oldest := self.Get(self.size - 1)
+	self.referencedBytes -= statsSize(oldest) - slotSize
+	// Release what the element references, its slot is only released when
+	// the buffer shrinks.
+	*oldest = info.ContainerStats{}
+	self.size--
+	if self.size <= cap(self.buffer)/4 {
+		self.shrink()
 	}
-	self.index = (self.index + 1) % len(self.buffer)
-	self.buffer[self.index] = *item
+}
+
+// Moves the elements to a buffer of twice their number, released entirely
+// when there are none, as if the buffer was growing again.
+func (self *StatsBuffer) shrink() {
+	if self.size == 0 {
+		self.buffer = nil
+		self.index = -1
+		return
+	}
+	buffer := make([]info.ContainerStats, 0, 2*self.size)
+	for i := self.size - 1; i >= 0; i-- {
+		buffer = append(buffer, *self.Get(i))
+	}
+	self.buffer = buffer
+	self.index = self.size - 1
+}
+
+// Returns the approximate number of bytes used by the buffer: its slots,
+// including the empty ones, and what its elements reference.
+func (self *StatsBuffer) Bytes() int64 {
+	return int64(cap(self.buffer))*slotSize + self.referencedBytes
 }
 
 // Returns up to maxResult elements in the specified time period (inclusive).
-// Results are from first to last. maxResults of -1 means no limit. When first
-// and last are specified, maxResults is ignored.
+// Results are from first to last. maxResults of -1 means no limit, otherwise
+// the most recent elements of the period are kept.
 func (self *StatsBuffer) InTimeRange(start, end time.Time, maxResults int) []*info.ContainerStats {
 	// No stats, return empty.
 	if self.size == 0 {
 		return []*info.ContainerStats{}
 	}
 
-	// Return all results in a time range if specified.
-	if !start.IsZero() && !end.IsZero() {
-		maxResults = -1
-	}
-
 	// NOTE: Since we store the elments in descending timestamp order "start" will
 	// be a higher index than "end".
 
@@ -145,6 +218,15 @@ func (self *StatsBuffer) Get(index int) *info.ContainerStats {
 	return &self.buffer[calculatedIndex]
 }
 
+// Returns a copy of the latest element, or nil if the buffer is empty.
+func (self *StatsBuffer) Latest() *info.ContainerStats {
+	if self.size == 0 {
+		return nil
+	}
+	latest := self.buffer[self.index]
+	return &latest
+}
+
 func (self *StatsBuffer) Size() int {
 	return self.size
 }
@@ -113,6 +113,21 @@ func TestGet(t *testing.T) {
 	expectElement(t, sb.Get(2), 1)
 }
 
+func TestLatest(t *testing.T) {
+	sb := NewStatsBuffer(2)
+	if sb.Latest() != nil {
+		t.Errorf("Expected no latest element in an empty buffer")
+	}
+	for i := 1; i <= 3; i++ {
+		sb.Add(createStats(int32(i)))
+		expectElement(t, sb.Latest(), int32(i))
+	}
+
+	// The latest element is a copy.
+	sb.Latest().Cpu.LoadAverage = 42
+	expectElement(t, sb.Get(0), 3)
+}
+
 func TestInTimeRange(t *testing.T) {
 	sb := NewStatsBuffer(5)
 	assert := assert.New(t)
@@ -161,8 +176,10 @@ func TestInTimeRange(t *testing.T) {
 	expectElements(t, sb.InTimeRange(createTime(3), createTime(5), 10), []int32{3, 4})
 	assert.Empty(sb.InTimeRange(createTime(5), createTime(5), 10))
 
-	// Start and end time ignores maxResults.
-	expectElements(t, sb.InTimeRange(createTime(1), createTime(5), 1), []int32{1, 2, 3, 4})
+	// maxResults bounds a time range, keeping the most recent elements.
+	expectElements(t, sb.InTimeRange(createTime(1), createTime(5), 1), []int32{4})
+	expectElements(t, sb.InTimeRange(createTime(1), createTime(3), 2), []int32{2, 3})
+	expectElements(t, sb.InTimeRange(createTime(1), createTime(5), -1), []int32{1, 2, 3, 4})
 
 	// No start time.
 	expectElements(t, sb.InTimeRange(empty, createTime(5), 10), []int32{1, 2, 3, 4})
@@ -207,3 +224,67 @@ func TestInTimeRangeWithLimit(t *testing.T) {
 	expectElements(t, sb.InTimeRange(empty, empty, 1), []int32{4})
 	assert.Empty(t, sb.InTimeRange(empty, empty, 0))
 }
+
+func TestRemoveOldest(t *testing.T) {
+	sb := NewStatsBuffer(3)
+	sb.RemoveOldest()
+	expectSize(t, sb, 0)
+	assert.Nil(t, sb.Oldest())
+
+	// While the buffer grows.
+	sb.Add(createStats(1))
+	sb.Add(createStats(2))
+	sb.RemoveOldest()
+	expectFirstN(t, sb, []int32{2})
+	sb.Add(createStats(3))
+	expectFirstN(t, sb, []int32{2, 3})
+	sb.Add(createStats(4))
+	expectFirstN(t, sb, []int32{2, 3, 4})
+	expectElement(t, sb.Oldest(), 2)
+
+	// Once it wrapped around.
+	sb.Add(createStats(5))
+	sb.RemoveOldest()
+	sb.RemoveOldest()
+	expectFirstN(t, sb, []int32{5})
+	sb.Add(createStats(6))
+	sb.Add(createStats(7))
+	sb.Add(createStats(8))
+	expectFirstN(t, sb, []int32{6, 7, 8})
+	assert.Equal(t, 3*statsSize(createStats(0)), sb.Bytes())
+
+	for i := 0; i < 3; i++ {
+		sb.RemoveOldest()
+	}
+	expectSize(t, sb, 0)
+	assert.Equal(t, int64(0), sb.Bytes())
+	assert.Nil(t, sb.Latest())
+}
+
+func TestRemoveOldestShrinks(t *testing.T) {
+	sb := NewStatsBuffer(8)
+	for i := 0; i < 10; i++ {
+		sb.Add(createStats(int32(i)))
+	}
+	slotBytes := statsSize(createStats(0))
+	assert.Equal(t, 8*slotBytes, sb.Bytes())
+
+	// The slots of the removed elements are kept until the buffer is a
+	// quarter full.
+	for i := 0; i < 5; i++ {
+		sb.RemoveOldest()
+	}
+	assert.Equal(t, 8*slotBytes, sb.Bytes())
+	sb.RemoveOldest()
+	expectFirstN(t, sb, []int32{8, 9})
+	assert.Equal(t, 4*slotBytes, sb.Bytes())
+
+	// It then grows again up to its capacity.
+	for i := 10; i < 16; i++ {
+		sb.Add(createStats(int32(i)))
+	}
+	expectFirstN(t, sb, []int32{8, 9, 10, 11, 12, 13, 14, 15})
+	sb.Add(createStats(16))
+	expectFirstN(t, sb, []int32{9, 10, 11, 12, 13, 14, 15, 16})
+	assert.Equal(t, 8*slotBytes, sb.Bytes())
+}
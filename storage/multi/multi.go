@@ -0,0 +1,143 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Storage driver writing to several storage drivers at once.
+package multi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+	"github.com/google/cadvisor/storage"
+)
+
+// Number of stats queued for a driver which is still writing the previous ones,
+// beyond which the stats are dropped for that driver.
+var queueSize = 1000
+
+type sample struct {
+	ref   info.ContainerReference
+	stats *info.ContainerStats
+}
+
+type multiStorage struct {
+	names   []string
+	drivers []storage.StorageDriver
+	// Stats waiting to be written by each driver.
+	queues []chan sample
+	// Done when the queues are drained.
+	wg sync.WaitGroup
+}
+
+// Returns a storage driver fanning out the stats to all the given drivers.
+// names are used to identify the drivers in errors.
+func New(names []string, drivers []storage.StorageDriver) (storage.StorageDriver, error) {
+	if len(names) != len(drivers) {
+		return nil, fmt.Errorf("got %d names for %d storage drivers", len(names), len(drivers))
+	}
+	self := &multiStorage{
+		names:   names,
+		drivers: drivers,
+		queues:  make([]chan sample, len(drivers)),
+	}
+	for i := range drivers {
+		self.queues[i] = make(chan sample, queueSize)
+		self.wg.Add(1)
+		go self.write(i)
+	}
+	return self, nil
+}
+
+// Writes the stats queued for the i-th driver until the queue is closed.
+func (self *multiStorage) write(i int) {
+	defer self.wg.Done()
+	for s := range self.queues[i] {
+		if err := self.drivers[i].AddStats(s.ref, s.stats); err != nil {
+			glog.Errorf("Failed to add stats for %q to storage driver %s: %v", s.ref.Name, self.names[i], err)
+		}
+	}
+}
+
+// Combines the errors of the drivers, nil if there are none.
+func (self *multiStorage) combineErrors(errs []error) error {
+	var msgs []string
+	for i, err := range errs {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s: %v", self.names[i], err))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("storage drivers failed: %s", strings.Join(msgs, "; "))
+}
+
+// Queues the stats for each driver, which writes them in the background so
+// that a slow or failing driver does not hold back the others nor the caller.
+// The errors of the drivers are logged. Only the drivers whose queue is full,
+// and which therefore drop the stats, are reported.
+func (self *multiStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	errs := make([]error, len(self.drivers))
+	for i, queue := range self.queues {
+		select {
+		case queue <- sample{ref, stats}:
+		default:
+			errs[i] = fmt.Errorf("%d stats waiting to be written, dropping the stats of %q", queueSize, ref.Name)
+		}
+	}
+	return self.combineErrors(errs)
+}
+
+// Reads the stats from the first driver able to return them.
+func (self *multiStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
+	errs := make([]error, len(self.drivers))
+	for i, driver := range self.drivers {
+		stats, err := driver.RecentStats(containerName, numStats)
+		if err == nil {
+			return stats, nil
+		}
+		errs[i] = err
+	}
+	return nil, self.combineErrors(errs)
+}
+
+// Combines the status of the backends of the drivers reporting one.
+func (self *multiStorage) Health() map[string]v2.SubsystemHealth {
+	health := map[string]v2.SubsystemHealth{}
+	for _, driver := range self.drivers {
+		if reporter, ok := driver.(storage.HealthReporter); ok {
+			for name, h := range reporter.Health() {
+				health[name] = h
+			}
+		}
+	}
+	return health
+}
+
+// Writes the queued stats and closes the drivers.
+func (self *multiStorage) Close() error {
+	for _, queue := range self.queues {
+		close(queue)
+	}
+	self.wg.Wait()
+	errs := make([]error, len(self.drivers))
+	for i, driver := range self.drivers {
+		errs[i] = driver.Close()
+	}
+	return self.combineErrors(errs)
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/storage"
+	"github.com/google/cadvisor/storage/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddStats(t *testing.T) {
+	ref := info.ContainerReference{Name: "/a"}
+	stats := &info.ContainerStats{}
+	failing := &test.MockStorageDriver{}
+	failing.On("AddStats", ref, stats).Return(errors.New("connection refused"))
+	working := &test.MockStorageDriver{}
+	working.On("AddStats", ref, stats).Return(nil)
+
+	driver, err := New([]string{"influxdb", "cassandra"}, []storage.StorageDriver{failing, working})
+	assert.NoError(t, err)
+	// The errors of the drivers are logged rather than returned.
+	assert.NoError(t, driver.AddStats(ref, stats))
+	assert.NoError(t, driver.Close())
+
+	// The working driver got the stats despite the failure of the other one.
+	failing.AssertExpectations(t)
+	working.AssertExpectations(t)
+}
+
+// Signals the stats it is given, then blocks until released.
+type blockingDriver struct {
+	test.MockStorageDriver
+	added   chan struct{}
+	release chan struct{}
+}
+
+func newBlockingDriver() *blockingDriver {
+	return &blockingDriver{
+		added:   make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (self *blockingDriver) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	self.added <- struct{}{}
+	<-self.release
+	return nil
+}
+
+func TestAddStatsSlowDriver(t *testing.T) {
+	defer func(size int) { queueSize = size }(queueSize)
+	queueSize = 1
+
+	ref := info.ContainerReference{Name: "/a"}
+	stats := &info.ContainerStats{}
+	slow := newBlockingDriver()
+	working := newBlockingDriver()
+	close(working.release)
+
+	driver, err := New([]string{"influxdb", "cassandra"}, []storage.StorageDriver{slow, working})
+	assert.NoError(t, err)
+	// The slow driver writes the first stats and queues the second ones.
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, driver.AddStats(ref, stats))
+		<-working.added
+		if i == 0 {
+			<-slow.added
+		}
+	}
+	// Its queue is full, so it drops the third ones while the other driver
+	// still gets them.
+	err = driver.AddStats(ref, stats)
+	if assert.Error(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "influxdb: 1 stats waiting"), err.Error())
+		assert.False(t, strings.Contains(err.Error(), "cassandra"), err.Error())
+	}
+	<-working.added
+
+	close(slow.release)
+	assert.NoError(t, driver.Close())
+	assert.Len(t, slow.added, 1)
+}
+
+func TestRecentStats(t *testing.T) {
+	expected := []*info.ContainerStats{{}}
+	failing := &test.MockStorageDriver{}
+	failing.On("RecentStats", "/a", 10).Return([]*info.ContainerStats(nil), errors.New("unsupported"))
+	working := &test.MockStorageDriver{}
+	working.On("RecentStats", "/a", 10).Return(expected, nil)
+
+	driver, err := New([]string{"bigquery", "influxdb"}, []storage.StorageDriver{failing, working})
+	assert.NoError(t, err)
+	stats, err := driver.RecentStats("/a", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stats)
+
+	driver, err = New([]string{"bigquery"}, []storage.StorageDriver{failing})
+	assert.NoError(t, err)
+	_, err = driver.RecentStats("/a", 10)
+	assert.Error(t, err)
+}
+
+func TestNewMismatchedNames(t *testing.T) {
+	_, err := New([]string{"influxdb"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Minimal client for the Redis serialization protocol (RESP). It only
+// supports what the storage driver needs: sending pipelined commands and
+// checking their replies for errors.
+
+// A single connection to a Redis server. Not safe for concurrent use.
+type conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+}
+
+// An error reply of the server. The connection can still be used after it.
+type replyError string
+
+func (self replyError) Error() string {
+	return string(self)
+}
+
+// Opens a connection to addr, over TLS if isSecure is set, authenticates with
+// password unless it is empty and selects the database db.
+func dial(addr string, isSecure bool, password string, db int, timeout time.Duration) (*conn, error) {
+	var c net.Conn
+	var err error
+	if isSecure {
+		c, err = tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, nil)
+	} else {
+		c, err = net.DialTimeout("tcp", addr, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := newConn(c, timeout)
+	var cmds [][]string
+	if password != "" {
+		cmds = append(cmds, []string{"AUTH", password})
+	}
+	if db != 0 {
+		cmds = append(cmds, []string{"SELECT", strconv.Itoa(db)})
+	}
+	if len(cmds) > 0 {
+		if err := ret.pipeline(cmds); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func newConn(c net.Conn, timeout time.Duration) *conn {
+	return &conn{
+		conn:    c,
+		reader:  bufio.NewReader(c),
+		timeout: timeout,
+	}
+}
+
+func (self *conn) close() error {
+	return self.conn.Close()
+}
+
+// Sends the commands at once, then reads all their replies. Returns the first
+// error reply, as a replyError, or the error which broke the connection.
+func (self *conn) pipeline(cmds [][]string) error {
+	if self.timeout > 0 {
+		self.conn.SetDeadline(time.Now().Add(self.timeout))
+	}
+	var buf bytes.Buffer
+	for _, cmd := range cmds {
+		writeCommand(&buf, cmd)
+	}
+	if _, err := self.conn.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	var firstErr error
+	for range cmds {
+		err := self.readReply()
+		if _, ok := err.(replyError); err != nil && !ok {
+			return err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Writes a command as an array of bulk strings.
+func writeCommand(buf *bytes.Buffer, args []string) {
+	fmt.Fprintf(buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// Reads a reply, discarding its content. Error replies, including the ones
+// nested in arrays, are returned as a replyError once the whole reply is read.
+func (self *conn) readReply() error {
+	line, err := self.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) == 0 {
+		return fmt.Errorf("empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return nil
+	case '-':
+		return replyError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("invalid bulk string length %q", line)
+		}
+		if n < 0 {
+			// Null bulk string.
+			return nil
+		}
+		_, err = io.CopyN(ioutil.Discard, self.reader, int64(n)+2)
+		return err
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("invalid array length %q", line)
+		}
+		var firstErr error
+		for i := 0; i < n; i++ {
+			err := self.readReply()
+			if _, ok := err.(replyError); err != nil && !ok {
+				return err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+	return fmt.Errorf("unexpected reply %q", line)
+}
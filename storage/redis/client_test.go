@@ -0,0 +1,154 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fake Redis server recording the commands it receives. Commands whose key
+// contains "wrong" fail, the others succeed.
+type fakeRedis struct {
+	commands chan []string
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{commands: make(chan []string, 1000)}
+}
+
+// Returns a connection to the fake server.
+func (self *fakeRedis) dial() (*conn, error) {
+	client, server := net.Pipe()
+	// Replies are written by another goroutine, so that the client can write
+	// whole pipelines before reading the replies.
+	replies := make(chan string, 1000)
+	go func() {
+		defer close(replies)
+		reader := bufio.NewReader(server)
+		for {
+			cmd, err := readCommand(reader)
+			if err != nil {
+				return
+			}
+			self.commands <- cmd
+			switch {
+			case len(cmd) > 1 && strings.Contains(cmd[1], "wrong"):
+				replies <- "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"
+			case cmd[0] == "PING":
+				replies <- "+PONG\r\n"
+			case cmd[0] == "RPUSH" || cmd[0] == "EXPIRE":
+				replies <- ":1\r\n"
+			case cmd[0] == "XADD":
+				replies <- "$15\r\n1526919030474-0\r\n"
+			default:
+				replies <- "+OK\r\n"
+			}
+		}
+	}()
+	go func() {
+		for reply := range replies {
+			server.Write([]byte(reply))
+		}
+		server.Close()
+	}()
+	return newConn(client, time.Second), nil
+}
+
+// Returns the commands received so far.
+func (self *fakeRedis) received() [][]string {
+	var cmds [][]string
+	for {
+		select {
+		case cmd := <-self.commands:
+			cmds = append(cmds, cmd)
+		default:
+			return cmds
+		}
+	}
+}
+
+// Reads a command sent as an array of bulk strings.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		return strings.TrimSuffix(line, "\r\n"), err
+	}
+	line, err := readLine()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(line, "*"))
+	if err != nil {
+		return nil, err
+	}
+	cmd := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if _, err := readLine(); err != nil {
+			return nil, err
+		}
+		arg, err := readLine()
+		if err != nil {
+			return nil, err
+		}
+		cmd = append(cmd, arg)
+	}
+	return cmd, nil
+}
+
+func TestWriteCommand(t *testing.T) {
+	var buf bytes.Buffer
+	writeCommand(&buf, []string{"RPUSH", "key", "a b"})
+	assert.Equal(t, "*3\r\n$5\r\nRPUSH\r\n$3\r\nkey\r\n$3\r\na b\r\n", buf.String())
+}
+
+func TestReadReply(t *testing.T) {
+	replies := "+OK\r\n:42\r\n$5\r\nhello\r\n$-1\r\n*2\r\n:1\r\n-ERR nested\r\n-ERR unknown command\r\n"
+	c := &conn{reader: bufio.NewReader(strings.NewReader(replies))}
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, c.readReply())
+	}
+	assert.Equal(t, replyError("ERR nested"), c.readReply())
+	assert.Equal(t, replyError("ERR unknown command"), c.readReply())
+	// The connection is closed.
+	_, ok := c.readReply().(replyError)
+	assert.False(t, ok)
+
+	c = &conn{reader: bufio.NewReader(strings.NewReader("?\r\n"))}
+	assert.Error(t, c.readReply())
+}
+
+func TestPipeline(t *testing.T) {
+	redis := newFakeRedis()
+	c, err := redis.dial()
+	require.NoError(t, err)
+	defer c.close()
+
+	assert.NoError(t, c.pipeline([][]string{{"SELECT", "2"}, {"RPUSH", "key", "value"}}))
+	assert.Equal(t, [][]string{{"SELECT", "2"}, {"RPUSH", "key", "value"}}, redis.received())
+
+	// The first error is returned once all the replies are read.
+	err = c.pipeline([][]string{{"RPUSH", "wrong", "value"}, {"RPUSH", "key", "value"}})
+	assert.Equal(t, replyError("WRONGTYPE Operation against a key holding the wrong kind of value"), err)
+	assert.NoError(t, c.pipeline([][]string{{"PING"}}))
+}
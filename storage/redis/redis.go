@@ -0,0 +1,309 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Types of the keys the stats are pushed to.
+const (
+	// Lists, the stats are appended with RPUSH.
+	KeyTypeList = "list"
+	// Streams, the stats are added with XADD.
+	KeyTypeStream = "stream"
+)
+
+const (
+	// Number of idle connections to Redis kept open.
+	maxIdleConns = 4
+	// Timeout for connecting and for each pipeline.
+	requestTimeout = 10 * time.Second
+	// Largest number of stats written in a single pipeline.
+	maxPipelineSize = 100
+	// Field of the stream entries holding the stats sample.
+	streamField = "sample"
+)
+
+// A stats sample, serialized as JSON.
+type sample struct {
+	MachineName   string               `json:"machine"`
+	ContainerName string               `json:"container_name"`
+	Aliases       []string             `json:"aliases,omitempty"`
+	Labels        map[string]string    `json:"labels,omitempty"`
+	Stats         *info.ContainerStats `json:"stats"`
+}
+
+// A serialized sample and the key it is pushed to.
+type entry struct {
+	key   string
+	value string
+}
+
+type redisStorage struct {
+	machineName    string
+	labels         map[string]string
+	keyPattern     string
+	keyType        string
+	maxLen         int64
+	ttl            time.Duration
+	bufferDuration time.Duration
+	lastWrite      time.Time
+	entries        []entry
+	lock           sync.Mutex
+	readyToFlush   func() bool
+	// Idle connections to Redis, shared by the concurrent writers.
+	conns chan *conn
+	dial  func() (*conn, error)
+}
+
+// Returns the key the stats of a container are pushed to, from the key
+// pattern.
+func (self *redisStorage) key(containerName string) string {
+	return strings.NewReplacer("{machine}", self.machineName, "{container}", containerName).Replace(self.keyPattern)
+}
+
+func (self *redisStorage) containerStatsToEntry(ref info.ContainerReference, stats *info.ContainerStats) (entry, error) {
+	containerName := ref.Name
+	if len(ref.Aliases) > 0 {
+		containerName = ref.Aliases[0]
+	}
+	value, err := json.Marshal(&sample{
+		MachineName:   self.machineName,
+		ContainerName: ref.Name,
+		Aliases:       ref.Aliases,
+		Labels:        self.labels,
+		Stats:         stats,
+	})
+	if err != nil {
+		return entry{}, err
+	}
+	return entry{key: self.key(containerName), value: string(value)}, nil
+}
+
+// Returns the commands pushing the entries, followed by the ones trimming and
+// setting the expiry of each key they are pushed to.
+func (self *redisStorage) commands(entries []entry) [][]string {
+	var cmds [][]string
+	var keys []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if self.keyType == KeyTypeStream {
+			cmd := []string{"XADD", e.key}
+			if self.maxLen > 0 {
+				cmd = append(cmd, "MAXLEN", "~", strconv.FormatInt(self.maxLen, 10))
+			}
+			cmds = append(cmds, append(cmd, "*", streamField, e.value))
+		} else {
+			cmds = append(cmds, []string{"RPUSH", e.key, e.value})
+		}
+		if !seen[e.key] {
+			seen[e.key] = true
+			keys = append(keys, e.key)
+		}
+	}
+	for _, key := range keys {
+		if self.keyType == KeyTypeList && self.maxLen > 0 {
+			cmds = append(cmds, []string{"LTRIM", key, strconv.FormatInt(-self.maxLen, 10), "-1"})
+		}
+		if ttl := int64(self.ttl / time.Second); ttl > 0 {
+			cmds = append(cmds, []string{"EXPIRE", key, strconv.FormatInt(ttl, 10)})
+		}
+	}
+	return cmds
+}
+
+func (self *redisStorage) OverrideReadyToFlush(readyToFlush func() bool) {
+	self.readyToFlush = readyToFlush
+}
+
+func (self *redisStorage) defaultReadyToFlush() bool {
+	return time.Since(self.lastWrite) >= self.bufferDuration
+}
+
+func (self *redisStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	if stats == nil {
+		return nil
+	}
+	e, err := self.containerStatsToEntry(ref, stats)
+	if err != nil {
+		return fmt.Errorf("failed to serialize stats - %s", err)
+	}
+	var entriesToFlush []entry
+	func() {
+		// AddStats will be invoked simultaneously from multiple threads and only one of them will perform a write.
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		self.entries = append(self.entries, e)
+		if self.readyToFlush() {
+			entriesToFlush = self.entries
+			self.entries = make([]entry, 0)
+			self.lastWrite = time.Now()
+		}
+	}()
+	return self.write(entriesToFlush)
+}
+
+// Pushes the entries to Redis, in pipelines of at most maxPipelineSize entries.
+func (self *redisStorage) write(entries []entry) error {
+	for len(entries) > 0 {
+		n := len(entries)
+		if n > maxPipelineSize {
+			n = maxPipelineSize
+		}
+		cmds := self.commands(entries[:n])
+		entries = entries[n:]
+		err := self.withConn(func(c *conn) error {
+			return c.pipeline(cmds)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write stats to redis - %s", err)
+		}
+	}
+	return nil
+}
+
+// Runs f with an idle connection, or a new one if there is none. f is retried
+// once on a new connection if the connection breaks, e.g. because Redis
+// closed it while idle.
+func (self *redisStorage) withConn(f func(c *conn) error) error {
+	var c *conn
+	select {
+	case c = <-self.conns:
+	default:
+	}
+	for attempt := 0; ; attempt++ {
+		if c == nil {
+			var err error
+			c, err = self.dial()
+			if err != nil {
+				return err
+			}
+		}
+		err := f(c)
+		if _, ok := err.(replyError); err == nil || ok {
+			self.putConn(c)
+			return err
+		}
+		c.close()
+		c = nil
+		if attempt > 0 {
+			return err
+		}
+	}
+}
+
+// Keeps a connection for later writes, or closes it if enough are kept.
+func (self *redisStorage) putConn(c *conn) {
+	select {
+	case self.conns <- c:
+	default:
+		c.close()
+	}
+}
+
+// Checks that Redis accepts connections and commands.
+func (self *redisStorage) Validate() error {
+	return self.withConn(func(c *conn) error {
+		return c.pipeline([][]string{{"PING"}})
+	})
+}
+
+// Stats pushed to Redis are consumed downstream, not read back.
+func (self *redisStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
+	return nil, fmt.Errorf("the redis storage driver does not support reading stats")
+}
+
+// Writes the buffered stats and closes the idle connections.
+func (self *redisStorage) Close() error {
+	self.lock.Lock()
+	entries := self.entries
+	self.entries = make([]entry, 0)
+	self.lock.Unlock()
+	err := self.write(entries)
+	for {
+		select {
+		case c := <-self.conns:
+			c.close()
+		default:
+			return err
+		}
+	}
+}
+
+func newStorage(machineName string, labels map[string]string, keyPattern, keyType string, maxLen int64, ttl, bufferDuration time.Duration, dial func() (*conn, error)) (*redisStorage, error) {
+	if keyType != KeyTypeList && keyType != KeyTypeStream {
+		return nil, fmt.Errorf("unknown redis key type %q, expected %q or %q", keyType, KeyTypeList, KeyTypeStream)
+	}
+	if keyPattern == "" {
+		return nil, fmt.Errorf("no redis key specified")
+	}
+	ret := &redisStorage{
+		machineName:    machineName,
+		labels:         labels,
+		keyPattern:     keyPattern,
+		keyType:        keyType,
+		maxLen:         maxLen,
+		ttl:            ttl,
+		bufferDuration: bufferDuration,
+		lastWrite:      time.Now(),
+		entries:        make([]entry, 0),
+		conns:          make(chan *conn, maxIdleConns),
+		dial:           dial,
+	}
+	ret.readyToFlush = ret.defaultReadyToFlush
+	return ret, nil
+}
+
+// machineName: A unique identifier to identify the host that current cAdvisor
+// instance is running on.
+// labels: Written in the "labels" field of all the samples.
+// addr: host:port of the Redis server.
+// password: Password to authenticate with, no authentication if empty.
+// db: Index of the database the stats are written to.
+// keyPattern: Key the stats are pushed to, in which "{machine}" and
+// "{container}" are replaced by machineName and the name of the container.
+// keyType: KeyTypeList or KeyTypeStream.
+// maxLen: Number of stats each key is trimmed to, keys are not trimmed if 0.
+// ttl: Expiry of the keys, renewed on every write. Keys never expire if 0.
+func New(machineName string,
+	labels map[string]string,
+	addr string,
+	isSecure bool,
+	password string,
+	db int,
+	keyPattern,
+	keyType string,
+	maxLen int64,
+	ttl time.Duration,
+	bufferDuration time.Duration,
+) (*redisStorage, error) {
+	connect := func() (*conn, error) {
+		c, err := dial(addr, isSecure, password, db, requestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to redis at %q - %s", addr, err)
+		}
+		return c, nil
+	}
+	return newStorage(machineName, labels, keyPattern, keyType, maxLen, ttl, bufferDuration, connect)
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(t *testing.T, redis *fakeRedis, keyType string, maxLen int64, ttl time.Duration) *redisStorage {
+	driver, err := newStorage("machine", map[string]string{"zone": "us-1"}, "cadvisor:{machine}:{container}", keyType, maxLen, ttl, 0, redis.dial)
+	require.NoError(t, err)
+	driver.OverrideReadyToFlush(func() bool { return true })
+	return driver
+}
+
+func TestAddStatsList(t *testing.T) {
+	redis := newFakeRedis()
+	driver := newTestStorage(t, redis, KeyTypeList, 100, 90*time.Second)
+	defer driver.Close()
+
+	stats := &info.ContainerStats{Timestamp: time.Unix(1434000000, 0).UTC()}
+	stats.Memory.Usage = 2048
+	require.NoError(t, driver.AddStats(info.ContainerReference{Name: "/docker/abc", Aliases: []string{"web", "abc"}}, stats))
+	assert.NoError(t, driver.AddStats(info.ContainerReference{Name: "/"}, nil))
+
+	cmds := redis.received()
+	require.Equal(t, 3, len(cmds))
+	assert.Equal(t, []string{"RPUSH", "cadvisor:machine:web"}, cmds[0][:2])
+	assert.Equal(t, []string{"LTRIM", "cadvisor:machine:web", "-100", "-1"}, cmds[1])
+	assert.Equal(t, []string{"EXPIRE", "cadvisor:machine:web", "90"}, cmds[2])
+
+	var s sample
+	require.NoError(t, json.Unmarshal([]byte(cmds[0][2]), &s))
+	assert.Equal(t, "machine", s.MachineName)
+	assert.Equal(t, "/docker/abc", s.ContainerName)
+	assert.Equal(t, map[string]string{"zone": "us-1"}, s.Labels)
+	assert.Equal(t, uint64(2048), s.Stats.Memory.Usage)
+	assert.True(t, s.Stats.Timestamp.Equal(stats.Timestamp))
+}
+
+func TestAddStatsStream(t *testing.T) {
+	redis := newFakeRedis()
+	driver := newTestStorage(t, redis, KeyTypeStream, 10, 0)
+	defer driver.Close()
+
+	require.NoError(t, driver.AddStats(info.ContainerReference{Name: "/"}, &info.ContainerStats{}))
+	cmds := redis.received()
+	require.Equal(t, 1, len(cmds))
+	assert.Equal(t, []string{"XADD", "cadvisor:machine:/", "MAXLEN", "~", "10", "*", streamField}, cmds[0][:7])
+}
+
+func TestAddStatsBuffered(t *testing.T) {
+	redis := newFakeRedis()
+	driver := newTestStorage(t, redis, KeyTypeList, 0, time.Minute)
+	defer driver.Close()
+	flush := false
+	driver.OverrideReadyToFlush(func() bool { return flush })
+
+	for i := 0; i < maxPipelineSize+1; i++ {
+		flush = i == maxPipelineSize
+		ref := info.ContainerReference{Name: fmt.Sprintf("/%d", i%2)}
+		require.NoError(t, driver.AddStats(ref, &info.ContainerStats{}))
+	}
+	cmds := redis.received()
+	// Each pipeline expires the keys it pushed to once.
+	require.Equal(t, maxPipelineSize+1+3, len(cmds))
+	assert.Equal(t, []string{"EXPIRE", "cadvisor:machine:/0", "60"}, cmds[maxPipelineSize])
+	assert.Equal(t, []string{"EXPIRE", "cadvisor:machine:/1", "60"}, cmds[maxPipelineSize+1])
+	assert.Equal(t, []string{"EXPIRE", "cadvisor:machine:/0", "60"}, cmds[maxPipelineSize+3])
+}
+
+func TestCloseWritesBufferedStats(t *testing.T) {
+	redis := newFakeRedis()
+	driver := newTestStorage(t, redis, KeyTypeList, 0, 0)
+	driver.OverrideReadyToFlush(func() bool { return false })
+
+	require.NoError(t, driver.AddStats(info.ContainerReference{Name: "/"}, &info.ContainerStats{}))
+	assert.Equal(t, 0, len(redis.received()))
+	require.NoError(t, driver.Close())
+	cmds := redis.received()
+	require.Equal(t, 1, len(cmds))
+	assert.Equal(t, []string{"RPUSH", "cadvisor:machine:/"}, cmds[0][:2])
+	assert.Equal(t, 0, len(driver.conns))
+}
+
+func TestAddStatsErrorReply(t *testing.T) {
+	redis := newFakeRedis()
+	driver, err := newStorage("wrong", nil, "{machine}", KeyTypeList, 0, 0, 0, redis.dial)
+	require.NoError(t, err)
+	defer driver.Close()
+	driver.OverrideReadyToFlush(func() bool { return true })
+
+	assert.Error(t, driver.AddStats(info.ContainerReference{Name: "/"}, &info.ContainerStats{}))
+	// The connection is kept after an error reply.
+	assert.Equal(t, 1, len(driver.conns))
+}
+
+func TestValidate(t *testing.T) {
+	redis := newFakeRedis()
+	driver := newTestStorage(t, redis, KeyTypeList, 0, 0)
+	assert.NoError(t, driver.Validate())
+	driver.Close()
+
+	driver.dial = func() (*conn, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+	assert.Error(t, driver.Validate())
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, err := New("machine", nil, "localhost:6379", false, "", 0, "cadvisor", "set", 0, 0, 0)
+	assert.Error(t, err)
+	_, err = New("machine", nil, "localhost:6379", false, "", 0, "", KeyTypeList, 0, 0, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stdout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// A stats sample, written as a single JSON line.
+type sample struct {
+	MachineName   string               `json:"machine"`
+	ContainerName string               `json:"container_name"`
+	Aliases       []string             `json:"aliases,omitempty"`
+	Labels        map[string]string    `json:"labels,omitempty"`
+	Stats         *info.ContainerStats `json:"stats"`
+}
+
+type stdoutStorage struct {
+	machineName string
+	labels      map[string]string
+	// Guards writes, so that concurrent samples are not interleaved.
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+func (self *stdoutStorage) AddStats(ref info.ContainerReference, stats *info.ContainerStats) error {
+	if stats == nil {
+		return nil
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	// The encoder terminates each sample with a newline.
+	err := self.encoder.Encode(&sample{
+		MachineName:   self.machineName,
+		ContainerName: ref.Name,
+		Aliases:       ref.Aliases,
+		Labels:        self.labels,
+		Stats:         stats,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write stats to stdout - %s", err)
+	}
+	return nil
+}
+
+// Stats written to stdout cannot be read back.
+func (self *stdoutStorage) RecentStats(containerName string, numStats int) ([]*info.ContainerStats, error) {
+	return nil, fmt.Errorf("the stdout storage driver does not keep stats")
+}
+
+func (self *stdoutStorage) Close() error {
+	return nil
+}
+
+func newStorage(machineName string, labels map[string]string, out io.Writer) *stdoutStorage {
+	return &stdoutStorage{
+		machineName: machineName,
+		labels:      labels,
+		encoder:     json.NewEncoder(out),
+	}
+}
+
+// machineName: A unique identifier to identify the host that current cAdvisor
+// instance is running on.
+// labels: Written in the "labels" field of all the samples.
+func New(machineName string, labels map[string]string) (*stdoutStorage, error) {
+	return newStorage(machineName, labels, os.Stdout), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stdout
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddStats(t *testing.T) {
+	var out bytes.Buffer
+	driver := newStorage("machine", map[string]string{"zone": "us-1"}, &out)
+	ref := info.ContainerReference{Name: "/docker/abc", Aliases: []string{"web", "abc"}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stats := &info.ContainerStats{Timestamp: time.Unix(int64(i), 0)}
+			stats.Memory.Usage = uint64(i)
+			assert.NoError(t, driver.AddStats(ref, stats))
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, driver.AddStats(ref, nil))
+
+	// Every sample is a single line of JSON.
+	scanner := bufio.NewScanner(&out)
+	lines := 0
+	for scanner.Scan() {
+		var s sample
+		if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &s), scanner.Text()) {
+			assert.Equal(t, "machine", s.MachineName)
+			assert.Equal(t, "/docker/abc", s.ContainerName)
+			assert.Equal(t, []string{"web", "abc"}, s.Aliases)
+			assert.Equal(t, map[string]string{"zone": "us-1"}, s.Labels)
+			assert.Equal(t, uint64(s.Stats.Timestamp.Unix()), s.Stats.Memory.Usage)
+		}
+		lines++
+	}
+	assert.Equal(t, 10, lines)
+
+	_, err := driver.RecentStats("/docker/abc", 1)
+	assert.Error(t, err)
+}
@@ -14,7 +14,10 @@
 
 package storage
 
-import info "github.com/google/cadvisor/info/v1"
+import (
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/info/v2"
+)
 
 type StorageDriver interface {
 	AddStats(ref info.ContainerReference, stats *info.ContainerStats) error
@@ -31,3 +34,19 @@ type StorageDriver interface {
 	// on the implementation of the storage driver.
 	Close() error
 }
+
+// Optionally implemented by storage drivers which can check, before any stats
+// are written, that their backend is reachable and accepts their
+// configuration (e.g. credentials).
+type Validator interface {
+	// Returns an error describing why stats could not be written.
+	Validate() error
+}
+
+// Optionally implemented by storage drivers which keep track of the writes to
+// their backend, e.g. to report the stats they dropped while it was down.
+type HealthReporter interface {
+	// Returns the status of the writes to each backend, by backend name
+	// (e.g. "influxdb").
+	Health() map[string]v2.SubsystemHealth
+}
@@ -18,80 +18,287 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/google/cadvisor/manager"
+	"github.com/google/cadvisor/metrics"
 	"github.com/google/cadvisor/storage"
 	"github.com/google/cadvisor/storage/bigquery"
+	"github.com/google/cadvisor/storage/cassandra"
+	"github.com/google/cadvisor/storage/elasticsearch"
+	"github.com/google/cadvisor/storage/graphite"
 	"github.com/google/cadvisor/storage/influxdb"
 	"github.com/google/cadvisor/storage/memory"
+	"github.com/google/cadvisor/storage/multi"
+	"github.com/google/cadvisor/storage/redis"
+	"github.com/google/cadvisor/storage/stdout"
 )
 
-var argDbUsername = flag.String("storage_driver_user", "root", "database username")
-var argDbPassword = flag.String("storage_driver_password", "root", "database password")
-var argDbHost = flag.String("storage_driver_host", "localhost:8086", "database host:port")
+var argDbUsername = flag.String("storage_driver_user", "", "database username. influxdb uses root if empty, the other drivers do not authenticate")
+var argDbPassword = flag.String("storage_driver_password", "", "database password. influxdb uses root if empty")
+var argDbHost = flag.String("storage_driver_host", "", "database host:port. Defaults to the usual port of the storage driver on localhost, e.g. localhost:8086 for influxdb")
 var argDbName = flag.String("storage_driver_db", "cadvisor", "database name")
 var argDbTable = flag.String("storage_driver_table", "stats", "table name")
 var argDbIsSecure = flag.Bool("storage_driver_secure", false, "use secure connection with database")
+var argDbTtl = flag.Duration("storage_driver_ttl", 0, "Time to live of the stats written by storage drivers that support expiration (cassandra and redis). 0 keeps them forever")
+var argEsRollover = flag.String("storage_driver_es_rollover", elasticsearch.RolloverDaily, "How the elasticsearch index is rolled over: none or daily, which appends the UTC date to the index name")
+var argEsMaxDocs = flag.Uint64("storage_driver_es_max_docs", 0, "Number of documents after which the elasticsearch index is rolled over to a new one. 0 does not limit the size of indices")
+var argRedisKey = flag.String("storage_driver_redis_key", "cadvisor:{machine}:{container}", "Key the stats are pushed to in redis. {machine} and {container} are replaced by the hostname and the name of the container")
+var argRedisKeyType = flag.String("storage_driver_redis_type", redis.KeyTypeList, "Type of the redis keys the stats are pushed to: list or stream")
+var argRedisMaxLen = flag.Int64("storage_driver_redis_max_len", 0, "Number of stats each redis key is trimmed to. 0 does not trim them")
+var argRedisDb = flag.Int("storage_driver_redis_db", 0, "Index of the redis database the stats are written to")
+var argRedisPassword = flag.String("storage_driver_redis_password", "", "Password to authenticate to redis with. Empty (default) for no authentication")
+var argGraphitePrefix = flag.String("storage_driver_graphite_prefix", "", "Prefix of the metric paths written to graphite. Defaults to cadvisor.<hostname>")
+var argDbValidate = flag.Bool("storage_driver_validate", false, "Exit at startup if a storage driver cannot reach its backend or its configuration is rejected, rather than only logging the error")
 var argDbBufferDuration = flag.Duration("storage_driver_buffer_duration", 60*time.Second, "Writes in the storage driver will be buffered for this duration, and committed to the non memory backends as a single transaction")
+var argDownsampleDuration = flag.Duration("storage_downsample_duration", 0, "How long the stats that no longer fit in the in-memory cache are kept, downsampled to -storage_downsample_resolution. 0 drops them")
+var argDownsampleResolution = flag.Duration("storage_downsample_resolution", time.Minute, "Period the stats kept for -storage_downsample_duration are averaged over")
+var argMaxStatsMemory = flag.Int64("max_stats_memory", 0, "Approximate number of bytes the stats kept in memory can use, beyond which the oldest stats across all containers are evicted. 0 means no limit")
 
 const statsRequestedByUI = 60
 
-// Creates a memory storage with an optional backend storage option.
-func NewMemoryStorage(backendStorageName string) (*memory.InMemoryStorage, error) {
-	var storageDriver *memory.InMemoryStorage
-	var backendStorage storage.StorageDriver
-	var err error
+// Address of the backend of each storage driver when -storage_driver_host is
+// not set.
+var defaultDbHosts = map[string]string{
+	"influxdb":      "localhost:8086",
+	"elasticsearch": "localhost:9200",
+	"graphite":      "localhost:2003",
+	"redis":         "localhost:6379",
+	"cassandra":     "localhost:9042",
+}
+
+// Flags overriding -storage_driver_host, -storage_driver_user and
+// -storage_driver_password for a single storage driver, so that the drivers
+// used at once can write to different servers. Unset for the drivers which do
+// not take a user or password.
+type dbFlags struct {
+	host     *string
+	user     *string
+	password *string
+}
+
+var argDbOverrides = map[string]dbFlags{
+	"influxdb": {
+		host:     flag.String("storage_driver_influxdb_host", "", "influxdb host:port, overriding -storage_driver_host"),
+		user:     flag.String("storage_driver_influxdb_user", "", "influxdb username, overriding -storage_driver_user"),
+		password: flag.String("storage_driver_influxdb_password", "", "influxdb password, overriding -storage_driver_password"),
+	},
+	"elasticsearch": {
+		host:     flag.String("storage_driver_es_host", "", "elasticsearch host:port, overriding -storage_driver_host"),
+		user:     flag.String("storage_driver_es_user", "", "elasticsearch username, overriding -storage_driver_user"),
+		password: flag.String("storage_driver_es_password", "", "elasticsearch password, overriding -storage_driver_password"),
+	},
+	"cassandra": {
+		host:     flag.String("storage_driver_cassandra_host", "", "Comma separated cassandra contact points, overriding -storage_driver_host"),
+		user:     flag.String("storage_driver_cassandra_user", "", "cassandra username, overriding -storage_driver_user"),
+		password: flag.String("storage_driver_cassandra_password", "", "cassandra password, overriding -storage_driver_password"),
+	},
+	"graphite": {
+		host: flag.String("storage_driver_graphite_host", "", "graphite host:port, overriding -storage_driver_host"),
+	},
+	"redis": {
+		host: flag.String("storage_driver_redis_host", "", "redis host:port, overriding -storage_driver_host"),
+	},
+}
+
+// Returns the value of the flag overriding the shared one for the storage
+// driver, or the shared value if it is not set.
+func dbFlag(override *string, shared string) string {
+	if override != nil && *override != "" {
+		return *override
+	}
+	return shared
+}
+
+// Returns the address of the backend of the storage driver with the given name.
+func dbHost(name string) string {
+	if host := dbFlag(argDbOverrides[name].host, *argDbHost); host != "" {
+		return host
+	}
+	return defaultDbHosts[name]
+}
+
+func dbUser(name string) string {
+	return dbFlag(argDbOverrides[name].user, *argDbUsername)
+}
+
+func dbPassword(name string) string {
+	return dbFlag(argDbOverrides[name].password, *argDbPassword)
+}
+
+// Creates a memory storage with optional backend storages. backendStorageNames
+// is a comma separated list of storage drivers the stats are all written to.
+func NewMemoryStorage(backendStorageNames string) (*memory.InMemoryStorage, error) {
 	// TODO(vmarmol): We shouldn't need the housekeeping interval here and it shouldn't be public.
 	statsToCache := int(*argDbBufferDuration / *manager.HousekeepingInterval)
 	if statsToCache < statsRequestedByUI {
 		// The UI requests the most recent 60 stats by default.
 		statsToCache = statsRequestedByUI
 	}
-	switch backendStorageName {
-	case "":
-		backendStorage = nil
-	case "influxdb":
-		var hostname string
-		hostname, err = os.Hostname()
+
+	var names []string
+	var drivers []storage.StorageDriver
+	for _, name := range strings.Split(backendStorageNames, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		driver, err := newStorageDriver(name)
+		if err == nil {
+			err = validateStorageDriver(name, driver)
+			if err != nil && *argDbValidate {
+				driver.Close()
+			} else if err != nil {
+				glog.Errorf("%v, stats may not be written to it", err)
+				err = nil
+			}
+		}
 		if err != nil {
+			// Close the drivers already created.
+			for _, d := range drivers {
+				d.Close()
+			}
 			return nil, err
 		}
+		names = append(names, name)
+		drivers = append(drivers, driver)
+	}
 
-		backendStorage, err = influxdb.New(
+	var backendStorage storage.StorageDriver
+	switch len(drivers) {
+	case 0:
+		glog.Infof("No backend storage selected")
+	case 1:
+		glog.Infof("Using backend storage type %q", names[0])
+		backendStorage = drivers[0]
+	default:
+		glog.Infof("Using backend storage types %q", names)
+		var err error
+		backendStorage, err = multi.New(names, drivers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if *argMaxStatsMemory < 0 {
+		return nil, fmt.Errorf("invalid stats memory budget %d", *argMaxStatsMemory)
+	}
+	glog.Infof("Caching %d stats in memory", statsToCache)
+	var memoryStorage *memory.InMemoryStorage
+	if *argDownsampleDuration > 0 {
+		if *argDownsampleResolution <= 0 {
+			return nil, fmt.Errorf("invalid downsampling resolution %v", *argDownsampleResolution)
+		}
+		maxDownsampled := int(*argDownsampleDuration / *argDownsampleResolution)
+		glog.Infof("Keeping %d older stats in memory downsampled to %v", maxDownsampled, *argDownsampleResolution)
+		memoryStorage = memory.NewWithDownsampling(statsToCache, *argDownsampleResolution, maxDownsampled, backendStorage)
+	} else {
+		memoryStorage = memory.New(statsToCache, backendStorage)
+	}
+	if *argMaxStatsMemory > 0 {
+		glog.Infof("Limiting the stats in memory to about %d bytes", *argMaxStatsMemory)
+		memoryStorage.SetMaxBytes(*argMaxStatsMemory)
+	}
+	return memoryStorage, nil
+}
+
+// Checks that the driver with the given name can write stats, if it supports
+// checking it.
+func validateStorageDriver(name string, driver storage.StorageDriver) error {
+	validator, ok := driver.(storage.Validator)
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(); err != nil {
+		return fmt.Errorf("storage driver %q is misconfigured or unreachable: %v", name, err)
+	}
+	glog.Infof("Validated storage driver %q", name)
+	return nil
+}
+
+// Creates the backend storage driver with the given name.
+func newStorageDriver(name string) (storage.StorageDriver, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	// Labels from -metric_labels, not written by the drivers whose schema is
+	// fixed (bigquery and cassandra).
+	labels := metrics.StaticLabels()
+	switch name {
+	case "influxdb":
+		return influxdb.New(
 			hostname,
+			labels,
 			*argDbTable,
 			*argDbName,
-			*argDbUsername,
-			*argDbPassword,
-			*argDbHost,
+			dbUser(name),
+			dbPassword(name),
+			dbHost(name),
 			*argDbIsSecure,
 			*argDbBufferDuration,
 		)
 	case "bigquery":
-		var hostname string
-		hostname, err = os.Hostname()
-		if err != nil {
-			return nil, err
-		}
-		backendStorage, err = bigquery.New(
+		return bigquery.New(
+			hostname,
+			*argDbTable,
+			*argDbName,
+		)
+	case "cassandra":
+		// storage_driver_host is a comma separated list of contact points and
+		// storage_driver_db names the keyspace.
+		return cassandra.New(
 			hostname,
 			*argDbTable,
 			*argDbName,
+			dbUser(name),
+			dbPassword(name),
+			dbHost(name),
+			*argDbTtl,
+			*argDbBufferDuration,
+		)
+	case "elasticsearch":
+		// storage_driver_db names the index, or prefixes the names of the
+		// indices when they are rolled over.
+		return elasticsearch.New(
+			hostname,
+			labels,
+			*argDbName,
+			dbUser(name),
+			dbPassword(name),
+			dbHost(name),
+			*argDbIsSecure,
+			*argEsRollover,
+			*argEsMaxDocs,
+			*argDbBufferDuration,
 		)
+	case "graphite":
+		// storage_driver_host is the Carbon plaintext listener.
+		return graphite.New(
+			hostname,
+			labels,
+			*argGraphitePrefix,
+			dbHost(name),
+		)
+	case "redis":
+		// storage_driver_host is the Redis server.
+		return redis.New(
+			hostname,
+			labels,
+			dbHost(name),
+			*argDbIsSecure,
+			*argRedisPassword,
+			*argRedisDb,
+			*argRedisKey,
+			*argRedisKeyType,
+			*argRedisMaxLen,
+			*argDbTtl,
+			*argDbBufferDuration,
+		)
+	case "stdout":
+		return stdout.New(hostname, labels)
 	default:
-		err = fmt.Errorf("unknown backend storage driver: %v", *argDbDriver)
-	}
-	if err != nil {
-		return nil, err
-	}
-	if backendStorageName != "" {
-		glog.Infof("Using backend storage type %q", backendStorageName)
-	} else {
-		glog.Infof("No backend storage selected")
+		return nil, fmt.Errorf("unknown backend storage driver: %v", name)
 	}
-	glog.Info("Caching %d stats in memory", statsToCache)
-	storageDriver = memory.New(statsToCache, backendStorage)
-	return storageDriver, nil
 }
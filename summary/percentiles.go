@@ -37,14 +37,24 @@ func (a uint64Slice) Less(i, j int) bool { return a[i] < a[j] }
 
 // Get 90th percentile of the provided samples. Round to integer.
 func (self uint64Slice) Get90Percentile() uint64 {
+	return self.GetPercentile(0.9)
+}
+
+// Get the given percentile, in (0, 1), of the provided samples. Round to integer.
+func (self uint64Slice) GetPercentile(p float64) uint64 {
 	count := self.Len()
 	if count == 0 {
 		return 0
 	}
 	sort.Sort(self)
-	n := float64(0.9 * (float64(count) + 1))
+	n := float64(p * (float64(count) + 1))
 	idx, frac := math.Modf(n)
 	index := int(idx)
+	if index < 1 {
+		index = 1
+	} else if index > count {
+		index = count
+	}
 	percentile := float64(self[index-1])
 	if index > 1 && index < count {
 		percentile += frac * float64(self[index]-self[index-1])
@@ -71,8 +81,11 @@ func (self *mean) Add(value uint64) {
 }
 
 type resource struct {
-	// list of samples being tracked.
-	samples uint64Slice
+	// estimators of the 50th, 90th, 95th and 99th percentiles of the samples.
+	fifty      *quantileEstimator
+	ninety     *quantileEstimator
+	ninetyFive *quantileEstimator
+	ninetyNine *quantileEstimator
 	// average from existing samples.
 	mean mean
 	// maximum value seen so far in the added samples.
@@ -89,34 +102,48 @@ func (self *resource) Add(p info.Percentiles) {
 	}
 	self.mean.Add(p.Mean)
 	// Selecting 90p of 90p :(
-	self.samples = append(self.samples, p.Ninety)
+	self.fifty.Add(p.Fifty)
+	self.ninety.Add(p.Ninety)
+	self.ninetyFive.Add(p.NinetyFive)
+	self.ninetyNine.Add(p.NinetyNine)
 }
 
 // Add a single sample. Internally, we convert it to a fake percentile sample.
 func (self *resource) AddSample(val uint64) {
 	sample := info.Percentiles{
-		Present: true,
-		Mean:    val,
-		Max:     val,
-		Ninety:  val,
+		Present:    true,
+		Mean:       val,
+		Max:        val,
+		Fifty:      val,
+		Ninety:     val,
+		NinetyFive: val,
+		NinetyNine: val,
 	}
 	self.Add(sample)
 }
 
-// Get max, average, and 90p from existing samples.
+// Get max, average, 50p, 90p, 95p and 99p from existing samples.
 func (self *resource) GetPercentile() info.Percentiles {
 	p := info.Percentiles{}
 	p.Mean = uint64(self.mean.Mean)
 	p.Max = self.max
-	p.Ninety = self.samples.Get90Percentile()
+	p.Fifty = self.fifty.Value()
+	p.Ninety = self.ninety.Value()
+	p.NinetyFive = self.ninetyFive.Value()
+	p.NinetyNine = self.ninetyNine.Value()
 	p.Present = true
 	return p
 }
 
+// The percentiles are estimated as samples are added, size is only a hint of
+// how many samples will be.
 func NewResource(size int) *resource {
 	return &resource{
-		samples: make(uint64Slice, 0, size),
-		mean:    mean{count: 0, Mean: 0},
+		fifty:      newQuantileEstimator(0.5),
+		ninety:     newQuantileEstimator(0.9),
+		ninetyFive: newQuantileEstimator(0.95),
+		ninetyNine: newQuantileEstimator(0.99),
+		mean:       mean{count: 0, Mean: 0},
 	}
 }
 
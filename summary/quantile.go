@@ -0,0 +1,140 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package summary
+
+import (
+	"math"
+	"sort"
+)
+
+// Number of markers of the P-square algorithm.
+const numMarkers = 5
+
+// Number of samples the quantile is computed exactly from. Past it, the
+// samples are no longer stored and the quantile is estimated.
+const maxExactSamples = 100
+
+// Streaming estimator of a quantile. The quantile of the first
+// maxExactSamples samples is exact, afterwards it is estimated with the
+// P-square algorithm of Jain and Chlamtac which only keeps five markers
+// whatever the number of samples added.
+type quantileEstimator struct {
+	// Quantile being estimated, in (0, 1).
+	quantile float64
+	// Samples added so far, nil once the markers are used.
+	samples uint64Slice
+	// Heights, actual and desired positions of the markers.
+	heights          [numMarkers]float64
+	positions        [numMarkers]float64
+	desiredPositions [numMarkers]float64
+	// Increments of the desired positions for each sample.
+	increments [numMarkers]float64
+}
+
+func newQuantileEstimator(quantile float64) *quantileEstimator {
+	q := quantile
+	return &quantileEstimator{
+		quantile:   q,
+		samples:    make(uint64Slice, 0, maxExactSamples),
+		increments: [numMarkers]float64{0, q / 2, q, (1 + q) / 2, 1},
+	}
+}
+
+func (self *quantileEstimator) Add(value uint64) {
+	if self.samples != nil {
+		self.samples = append(self.samples, value)
+		if len(self.samples) == maxExactSamples {
+			self.placeMarkers()
+		}
+		return
+	}
+	v := float64(value)
+
+	// Find the cell the value falls in, extending the extreme markers if needed.
+	var k int
+	switch {
+	case v < self.heights[0]:
+		self.heights[0] = v
+		k = 0
+	case v >= self.heights[numMarkers-1]:
+		self.heights[numMarkers-1] = v
+		k = numMarkers - 2
+	default:
+		for k = 0; k < numMarkers-2; k++ {
+			if v < self.heights[k+1] {
+				break
+			}
+		}
+	}
+	for i := k + 1; i < numMarkers; i++ {
+		self.positions[i]++
+	}
+	for i := range self.desiredPositions {
+		self.desiredPositions[i] += self.increments[i]
+	}
+
+	// Move the middle markers towards their desired positions.
+	for i := 1; i < numMarkers-1; i++ {
+		d := self.desiredPositions[i] - self.positions[i]
+		if (d >= 1 && self.positions[i+1]-self.positions[i] > 1) || (d <= -1 && self.positions[i-1]-self.positions[i] < -1) {
+			sign := math.Copysign(1, d)
+			height := self.parabolic(i, sign)
+			if self.heights[i-1] >= height || height >= self.heights[i+1] {
+				height = self.linear(i, sign)
+			}
+			self.heights[i] = height
+			self.positions[i] += sign
+		}
+	}
+}
+
+// Places the markers on the stored samples, which are then dropped.
+func (self *quantileEstimator) placeMarkers() {
+	sort.Sort(self.samples)
+	count := float64(len(self.samples))
+	for i := range self.desiredPositions {
+		self.desiredPositions[i] = 1 + (count-1)*self.increments[i]
+	}
+	// Markers must be at distinct positions, from 1 to count.
+	for i := range self.positions {
+		self.positions[i] = math.Max(math.Floor(self.desiredPositions[i]+0.5), float64(i+1))
+	}
+	for i := numMarkers - 1; i >= 0; i-- {
+		self.positions[i] = math.Min(self.positions[i], count-float64(numMarkers-1-i))
+		self.heights[i] = float64(self.samples[int(self.positions[i])-1])
+	}
+	self.samples = nil
+}
+
+// Piecewise-parabolic prediction of the height of marker i moved by d.
+func (self *quantileEstimator) parabolic(i int, d float64) float64 {
+	n := self.positions
+	q := self.heights
+	return q[i] + d/(n[i+1]-n[i-1])*((n[i]-n[i-1]+d)*(q[i+1]-q[i])/(n[i+1]-n[i])+(n[i+1]-n[i]-d)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+// Linear prediction of the height of marker i moved by d.
+func (self *quantileEstimator) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return self.heights[i] + d*(self.heights[j]-self.heights[i])/(self.positions[j]-self.positions[i])
+}
+
+// Returns the quantile of the samples added, 0 if there is none.
+func (self *quantileEstimator) Value() uint64 {
+	if self.samples != nil {
+		return self.samples.GetPercentile(self.quantile)
+	}
+	return uint64(self.heights[2])
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/google/cadvisor/info/v1"
 	info "github.com/google/cadvisor/info/v2"
+	"github.com/google/cadvisor/utils"
 )
 
 // Usage fields we track for generating percentiles.
@@ -169,6 +170,43 @@ func (s *StatsSummary) DerivedStats() (info.DerivedStats, error) {
 	return s.derivedStats, nil
 }
 
+// Computes the capacity left by the latest usage of a container before it hits
+// the limits in its spec. Memory limits above memoryCapacity, including no
+// limit, are capped to it. The headroom of a resource is 0 when its usage is
+// above its limit, and nil when it is not tracked or has no limit.
+func GetHeadroom(usage info.InstantUsage, spec v1.ContainerSpec, memoryCapacity uint64) info.Headroom {
+	var headroom info.Headroom
+	if spec.HasCpu {
+		// In milli-cpus, as the cpu rate.
+		cpuLimit := spec.Cpu.MaxLimit
+		if cpuLimit == 0 {
+			if cores, err := utils.CountCpus(spec.Cpu.Mask); err == nil {
+				cpuLimit = uint64(cores) * 1000
+			}
+		}
+		headroom.Cpu = percentLeft(usage.Cpu, cpuLimit)
+	}
+	if spec.HasMemory {
+		memoryLimit := spec.Memory.Limit
+		if memoryLimit == 0 || memoryLimit > memoryCapacity {
+			memoryLimit = memoryCapacity
+		}
+		headroom.Memory = percentLeft(usage.Memory, memoryLimit)
+	}
+	return headroom
+}
+
+func percentLeft(usage, limit uint64) *float64 {
+	if limit == 0 {
+		return nil
+	}
+	var left float64
+	if usage < limit {
+		left = float64(limit-usage) * 100 / float64(limit)
+	}
+	return &left
+}
+
 func New(spec v1.ContainerSpec) (*StatsSummary, error) {
 	summary := StatsSummary{}
 	if spec.HasCpu {
@@ -0,0 +1,91 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package summary
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/google/cadvisor/info/v1"
+	info "github.com/google/cadvisor/info/v2"
+)
+
+func percent(value float64) *float64 {
+	return &value
+}
+
+func TestGetHeadroom(t *testing.T) {
+	const memoryCapacity = 8 << 30
+	cases := []struct {
+		name     string
+		usage    info.InstantUsage
+		spec     v1.ContainerSpec
+		expected info.Headroom
+	}{
+		{
+			name:  "limits",
+			usage: info.InstantUsage{Cpu: 500, Memory: 768 << 20},
+			spec: v1.ContainerSpec{
+				HasCpu:    true,
+				Cpu:       v1.CpuSpec{MaxLimit: 2000, Mask: "0-7"},
+				HasMemory: true,
+				Memory:    v1.MemorySpec{Limit: 1 << 30},
+			},
+			expected: info.Headroom{Cpu: percent(75), Memory: percent(25)},
+		},
+		{
+			// Limited by the cores the container can run on and the machine memory.
+			name:  "unlimited",
+			usage: info.InstantUsage{Cpu: 1000, Memory: 2 << 30},
+			spec: v1.ContainerSpec{
+				HasCpu:    true,
+				Cpu:       v1.CpuSpec{Mask: "0-1,4-5"},
+				HasMemory: true,
+				Memory:    v1.MemorySpec{Limit: math.MaxUint64},
+			},
+			expected: info.Headroom{Cpu: percent(75), Memory: percent(75)},
+		},
+		{
+			name:  "over limits",
+			usage: info.InstantUsage{Cpu: 1200, Memory: 2 << 30},
+			spec: v1.ContainerSpec{
+				HasCpu:    true,
+				Cpu:       v1.CpuSpec{MaxLimit: 1000, Mask: "0"},
+				HasMemory: true,
+				Memory:    v1.MemorySpec{Limit: 1 << 30},
+			},
+			expected: info.Headroom{Cpu: percent(0), Memory: percent(0)},
+		},
+		{
+			// The cores of the container are unknown.
+			name:     "unknown limit",
+			usage:    info.InstantUsage{Cpu: 100},
+			spec:     v1.ContainerSpec{HasCpu: true},
+			expected: info.Headroom{},
+		},
+		{
+			name:     "untracked",
+			usage:    info.InstantUsage{Cpu: 100, Memory: 1 << 20},
+			spec:     v1.ContainerSpec{},
+			expected: info.Headroom{},
+		},
+	}
+	for _, c := range cases {
+		if headroom := GetHeadroom(c.usage, c.spec, memoryCapacity); !reflect.DeepEqual(headroom, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, headroom)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Attribution of NVIDIA GPUs to containers.
+package accelerators
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/google/cadvisor/utils"
+)
+
+const (
+	// Control device present on all hosts with the NVIDIA driver loaded.
+	nvidiaCtlDevice = "/dev/nvidiactl"
+
+	// Major number of the /dev/nvidia<minor> GPU devices.
+	nvidiaMajor = 195
+	// Minor number of /dev/nvidiactl, which is not a GPU.
+	nvidiaCtlMinor = 255
+
+	// The devices are queried at most this often, the result is shared by
+	// all the containers.
+	minQueryInterval = time.Second
+
+	// After a query fails, NVML is not queried again for initialBackoff,
+	// doubled after each consecutive failure up to maxBackoff.
+	initialBackoff = 10 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+// Time after which a query is reported as failed, e.g. when NVML hangs on a
+// wedged GPU. NVML is not queried again until the query returns.
+var queryTimeout = 10 * time.Second
+
+// A GPU as reported by NVML.
+type nvidiaDevice struct {
+	minor       int
+	uuid        string
+	model       string
+	memoryTotal uint64
+	memoryUsed  uint64
+	utilization uint64
+}
+
+// A process running on a GPU.
+type nvidiaProcess struct {
+	pid        int
+	uuid       string
+	memoryUsed uint64
+}
+
+// Collects the stats of the NVIDIA GPUs of the host through NVML.
+type NvidiaManager struct {
+	// Lists the devices and their processes.
+	queryDevices func() ([]nvidiaDevice, []nvidiaProcess, error)
+
+	lock      sync.Mutex
+	lastQuery time.Time
+	devices   []nvidiaDevice
+	processes []nvidiaProcess
+	// Whether NVML is being queried, the other callers meanwhile use the
+	// last devices.
+	querying bool
+	// Error of the last query, and until when it is returned rather than
+	// querying NVML again.
+	lastErr error
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// Returns a manager for the NVIDIA GPUs of the host, or an error if the host
+// has no NVIDIA driver or NVML cannot be loaded.
+func New() (*NvidiaManager, error) {
+	if !utils.FileExists(nvidiaCtlDevice) {
+		return nil, fmt.Errorf("no NVIDIA driver found: %q does not exist", nvidiaCtlDevice)
+	}
+	if err := initNvml(); err != nil {
+		return nil, err
+	}
+	return &NvidiaManager{queryDevices: queryNvml}, nil
+}
+
+// Refreshes the devices and their processes if they are older than
+// minQueryInterval, unless they are being refreshed or a query failed
+// recently, in which case its error is returned. NVML is queried without the
+// lock held so that a slow query does not block the other containers.
+func (self *NvidiaManager) update() error {
+	self.lock.Lock()
+	now := time.Now()
+	if self.querying || now.Before(self.retryAt) || now.Sub(self.lastQuery) < minQueryInterval {
+		err := self.lastErr
+		self.lock.Unlock()
+		return err
+	}
+	self.querying = true
+	self.lock.Unlock()
+
+	// NVML calls cannot be interrupted, a query that hangs keeps running
+	// in the background and querying stays set until it returns.
+	done := make(chan error, 1)
+	go func() {
+		devices, processes, err := self.queryDevices()
+		self.setResult(devices, processes, err)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(queryTimeout):
+		err := fmt.Errorf("NVML did not respond within %v", queryTimeout)
+		self.lock.Lock()
+		self.lastErr = err
+		self.lock.Unlock()
+		return err
+	}
+}
+
+// Records the result of a query.
+func (self *NvidiaManager) setResult(devices []nvidiaDevice, processes []nvidiaProcess, err error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.querying = false
+	self.lastErr = err
+	if err != nil {
+		if self.backoff == 0 {
+			self.backoff = initialBackoff
+		} else {
+			self.backoff *= 2
+		}
+		if self.backoff > maxBackoff {
+			self.backoff = maxBackoff
+		}
+		self.retryAt = time.Now().Add(self.backoff)
+		return
+	}
+	self.backoff = 0
+	self.devices = devices
+	self.processes = processes
+	self.lastQuery = time.Now()
+}
+
+// Returns the stats of the GPUs used by a container. A GPU is used by the
+// container if its device cgroup explicitly allows access to it or if one of
+// its processes runs on it. devicesPath is the path of the container in the
+// devices cgroup hierarchy, pids the processes of the container.
+func (self *NvidiaManager) GetStats(devicesPath string, pids []int) ([]info.AcceleratorStats, error) {
+	err := self.update()
+	if err != nil {
+		return nil, err
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	allowed := map[int]bool{}
+	if devicesPath != "" {
+		out, err := ioutil.ReadFile(path.Join(devicesPath, "devices.list"))
+		if err == nil {
+			allowed = allowedDevices(string(out))
+		}
+	}
+	inContainer := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		inContainer[pid] = true
+	}
+	memoryUsed := map[string]uint64{}
+	for _, p := range self.processes {
+		if inContainer[p.pid] {
+			memoryUsed[p.uuid] += p.memoryUsed
+		}
+	}
+
+	var stats []info.AcceleratorStats
+	for _, d := range self.devices {
+		if _, ok := memoryUsed[d.uuid]; !ok && !allowed[d.minor] {
+			continue
+		}
+		stats = append(stats, info.AcceleratorStats{
+			Make:        "nvidia",
+			Model:       d.model,
+			ID:          d.uuid,
+			MemoryTotal: d.memoryTotal,
+			MemoryUsed:  memoryUsed[d.uuid],
+			DutyCycle:   d.utilization,
+		})
+	}
+	return stats, nil
+}
+
+// Returns the minor numbers of the GPUs a devices.list explicitly allows
+// access to. Wildcard entries, e.g. the "a *:* rwm" of unrestricted cgroups,
+// are not taken as GPU access.
+func allowedDevices(devicesList string) map[int]bool {
+	allowed := map[int]bool{}
+	for _, line := range strings.Split(devicesList, "\n") {
+		// Entries are "<type> <major>:<minor> <access>".
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != "c" {
+			continue
+		}
+		numbers := strings.SplitN(fields[1], ":", 2)
+		if len(numbers) != 2 || numbers[0] != strconv.Itoa(nvidiaMajor) {
+			continue
+		}
+		minor, err := strconv.Atoi(numbers[1])
+		if err != nil || minor == nvidiaCtlMinor {
+			continue
+		}
+		allowed[minor] = true
+	}
+	return allowed
+}
@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accelerators
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedDevices(t *testing.T) {
+	assert.Equal(t, map[int]bool{1: true}, allowedDevices("c 1:3 rwm\nc 195:255 rw\nc 195:1 rw\nb 195:0 r\n"))
+	assert.Empty(t, allowedDevices("a *:* rwm\n"))
+}
+
+func TestGetStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accelerators")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(path.Join(dir, "devices.list"), []byte("c 195:255 rw\nc 195:1 rw\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &NvidiaManager{
+		// Recent enough not to query NVML.
+		lastQuery: time.Now(),
+		devices: []nvidiaDevice{
+			{minor: 0, uuid: "GPU-aaa", model: "Tesla K80", memoryTotal: 100, utilization: 35},
+			{minor: 1, uuid: "GPU-bbb", model: "Tesla K80", memoryTotal: 100, utilization: 10},
+			{minor: 2, uuid: "GPU-ccc", model: "Tesla K80", memoryTotal: 100, utilization: 90},
+		},
+		processes: []nvidiaProcess{
+			{pid: 10, uuid: "GPU-aaa", memoryUsed: 20},
+			{pid: 11, uuid: "GPU-aaa", memoryUsed: 5},
+			{pid: 20, uuid: "GPU-ccc", memoryUsed: 50},
+		},
+	}
+	stats, err := m.GetStats(dir, []int{10, 11, 12})
+	assert.NoError(t, err)
+	assert.Equal(t, []info.AcceleratorStats{
+		{Make: "nvidia", Model: "Tesla K80", ID: "GPU-aaa", MemoryTotal: 100, MemoryUsed: 25, DutyCycle: 35},
+		{Make: "nvidia", Model: "Tesla K80", ID: "GPU-bbb", MemoryTotal: 100, MemoryUsed: 0, DutyCycle: 10},
+	}, stats)
+
+	stats, err = m.GetStats("", []int{30})
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestGetStatsTimesOut(t *testing.T) {
+	defer func(timeout time.Duration) { queryTimeout = timeout }(queryTimeout)
+	queryTimeout = 100 * time.Millisecond
+
+	// Hangs until released, and counts how many times it was called.
+	release := make(chan struct{})
+	queries := 0
+	m := &NvidiaManager{
+		queryDevices: func() ([]nvidiaDevice, []nvidiaProcess, error) {
+			queries++
+			<-release
+			return nil, nil, fmt.Errorf("wedged GPU")
+		},
+	}
+	start := time.Now()
+	_, err := m.GetStats("", []int{10})
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second, "query was waited for longer than %v", queryTimeout)
+
+	// The timeout is returned without querying again while the query hangs.
+	_, err = m.GetStats("", []int{10})
+	assert.Error(t, err)
+
+	// Once the query returns, it is not retried during the backoff.
+	close(release)
+	for {
+		m.lock.Lock()
+		querying := m.querying
+		m.lock.Unlock()
+		if !querying {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, err = m.GetStats("", []int{10})
+	assert.Error(t, err)
+	assert.Equal(t, 1, queries)
+	assert.Equal(t, initialBackoff, m.backoff)
+}
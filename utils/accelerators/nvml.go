@@ -0,0 +1,104 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+// +build cgo
+
+package accelerators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// Loads libnvidia-ml, which is installed with the NVIDIA driver. The library
+// is opened at runtime so that the binary also runs on hosts without it.
+func initNvml() error {
+	ret := nvml.Init()
+	if ret == nvml.ERROR_LIBRARY_NOT_FOUND {
+		return fmt.Errorf("NVIDIA driver found but libnvidia-ml.so.1 could not be loaded")
+	}
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %s", nvml.ErrorString(ret))
+	}
+	return nil
+}
+
+// Returns the error of a query that older GPUs may not support, nil if the
+// query succeeded or is not supported.
+func optional(ret nvml.Return) error {
+	if ret == nvml.SUCCESS || ret == nvml.ERROR_NOT_SUPPORTED {
+		return nil
+	}
+	return errors.New(nvml.ErrorString(ret))
+}
+
+// Lists the devices and the processes running on them through NVML.
+func queryNvml() ([]nvidiaDevice, []nvidiaProcess, error) {
+	count, ret := nvml.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, nil, fmt.Errorf("failed to count the NVIDIA devices: %s", nvml.ErrorString(ret))
+	}
+	devices := make([]nvidiaDevice, 0, count)
+	var processes []nvidiaProcess
+	for i := 0; i < count; i++ {
+		device, ret := nvml.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, nil, fmt.Errorf("failed to get NVIDIA device %d: %s", i, nvml.ErrorString(ret))
+		}
+		minor, ret := device.GetMinorNumber()
+		if ret != nvml.SUCCESS {
+			return nil, nil, fmt.Errorf("failed to get the minor number of NVIDIA device %d: %s", i, nvml.ErrorString(ret))
+		}
+		uuid, ret := device.GetUUID()
+		if ret != nvml.SUCCESS {
+			return nil, nil, fmt.Errorf("failed to get the UUID of NVIDIA device %d: %s", i, nvml.ErrorString(ret))
+		}
+		model, ret := device.GetName()
+		if ret != nvml.SUCCESS {
+			return nil, nil, fmt.Errorf("failed to get the name of GPU %q: %s", uuid, nvml.ErrorString(ret))
+		}
+		memory, ret := device.GetMemoryInfo()
+		if err := optional(ret); err != nil {
+			return nil, nil, fmt.Errorf("failed to get the memory of GPU %q: %v", uuid, err)
+		}
+		utilization, ret := device.GetUtilizationRates()
+		if err := optional(ret); err != nil {
+			return nil, nil, fmt.Errorf("failed to get the utilization of GPU %q: %v", uuid, err)
+		}
+		devices = append(devices, nvidiaDevice{
+			minor:       minor,
+			uuid:        uuid,
+			model:       model,
+			memoryTotal: memory.Total,
+			memoryUsed:  memory.Used,
+			utilization: uint64(utilization.Gpu),
+		})
+
+		running, ret := device.GetComputeRunningProcesses()
+		if err := optional(ret); err != nil {
+			return nil, nil, fmt.Errorf("failed to list the processes of GPU %q: %v", uuid, err)
+		}
+		for _, p := range running {
+			processes = append(processes, nvidiaProcess{
+				pid:        int(p.Pid),
+				uuid:       uuid,
+				memoryUsed: p.UsedGpuMemory,
+			})
+		}
+	}
+	return devices, processes, nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cgo
+// +build !cgo
+
+package accelerators
+
+import "fmt"
+
+// NVML is only available through cgo.
+func initNvml() error {
+	return fmt.Errorf("NVIDIA driver found but cAdvisor was built without cgo, which NVML requires")
+}
+
+func queryNvml() ([]nvidiaDevice, []nvidiaProcess, error) {
+	return nil, nil, fmt.Errorf("NVML is not available")
+}
@@ -0,0 +1,171 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Detection of the cloud provider the machine runs in, and of the type and
+// location of its instance, from the metadata service of the provider.
+package cloudinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Asset tag of the chassis of all Azure virtual machines.
+const azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
+
+var (
+	// Directory of the DMI attributes of the machine, overridden in tests.
+	dmiIdDir = "/sys/class/dmi/id"
+
+	// Address of the metadata services, the same for all providers.
+	metadataUrl = "http://169.254.169.254"
+
+	metadataClient = &http.Client{Timeout: 2 * time.Second}
+)
+
+type CloudInfo struct {
+	// One of the info.CloudProvider* constants, empty if none was detected.
+	Provider     string
+	InstanceType string
+	Region       string
+	Zone         string
+}
+
+// Detects the cloud provider from the DMI attributes of the machine, which
+// avoids waiting on a metadata service outside of a cloud, then reads the
+// type, region and zone of the instance from the metadata service of the
+// provider. Returns an empty CloudInfo if the machine is not in a known cloud.
+func GetCloudInfo() (CloudInfo, error) {
+	var cloudInfo CloudInfo
+	var err error
+	switch {
+	case strings.HasPrefix(readDmiId("product_name"), "Google"):
+		cloudInfo, err = getGceInfo()
+		cloudInfo.Provider = info.CloudProviderGCE
+	case strings.HasPrefix(readDmiId("sys_vendor"), "Amazon") || strings.HasPrefix(strings.ToLower(readDmiId("product_uuid")), "ec2"):
+		cloudInfo, err = getAwsInfo()
+		cloudInfo.Provider = info.CloudProviderAWS
+	case readDmiId("chassis_asset_tag") == azureAssetTag:
+		cloudInfo, err = getAzureInfo()
+		cloudInfo.Provider = info.CloudProviderAzure
+	}
+	return cloudInfo, err
+}
+
+// Returns the value of a DMI attribute, or an empty string if it can not be
+// read, e.g. product_uuid when not running as root.
+func readDmiId(name string) string {
+	out, err := ioutil.ReadFile(path.Join(dmiIdDir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Sends a request to the metadata service and returns the body of its
+// response.
+func getMetadata(method, urlPath string, header map[string]string) (string, error) {
+	req, err := http.NewRequest(method, metadataUrl+urlPath, nil)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata request %s %q failed with status %q", method, urlPath, resp.Status)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// The GCE metadata service returns the machine type and zone as resource
+// paths, e.g. "projects/123/zones/us-central1-a". The region is the zone
+// without its last dash separated part.
+func getGceInfo() (CloudInfo, error) {
+	header := map[string]string{"Metadata-Flavor": "Google"}
+	var cloudInfo CloudInfo
+	machineType, err := getMetadata("GET", "/computeMetadata/v1/instance/machine-type", header)
+	if err != nil {
+		return cloudInfo, err
+	}
+	cloudInfo.InstanceType = path.Base(machineType)
+	zone, err := getMetadata("GET", "/computeMetadata/v1/instance/zone", header)
+	if err != nil {
+		return cloudInfo, err
+	}
+	cloudInfo.Zone = path.Base(zone)
+	if i := strings.LastIndex(cloudInfo.Zone, "-"); i > 0 {
+		cloudInfo.Region = cloudInfo.Zone[:i]
+	}
+	return cloudInfo, nil
+}
+
+// Uses a session token (IMDSv2) when the metadata service hands one out, and
+// plain requests (IMDSv1) otherwise.
+func getAwsInfo() (CloudInfo, error) {
+	header := map[string]string{}
+	token, err := getMetadata("PUT", "/latest/api/token", map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err == nil {
+		header["X-aws-ec2-metadata-token"] = token
+	}
+	var cloudInfo CloudInfo
+	if cloudInfo.InstanceType, err = getMetadata("GET", "/latest/meta-data/instance-type", header); err != nil {
+		return cloudInfo, err
+	}
+	if cloudInfo.Region, err = getMetadata("GET", "/latest/meta-data/placement/region", header); err != nil {
+		return cloudInfo, err
+	}
+	if cloudInfo.Zone, err = getMetadata("GET", "/latest/meta-data/placement/availability-zone", header); err != nil {
+		return cloudInfo, err
+	}
+	return cloudInfo, nil
+}
+
+// The zone is only set for virtual machines deployed in an availability zone,
+// as a number (e.g. "1") which only makes sense within the region.
+func getAzureInfo() (CloudInfo, error) {
+	var cloudInfo CloudInfo
+	out, err := getMetadata("GET", "/metadata/instance/compute?api-version=2021-02-01", map[string]string{"Metadata": "true"})
+	if err != nil {
+		return cloudInfo, err
+	}
+	var compute struct {
+		VmSize   string `json:"vmSize"`
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+	}
+	if err := json.Unmarshal([]byte(out), &compute); err != nil {
+		return cloudInfo, fmt.Errorf("failed to parse the Azure instance metadata: %v", err)
+	}
+	cloudInfo.InstanceType = compute.VmSize
+	cloudInfo.Region = compute.Location
+	cloudInfo.Zone = compute.Zone
+	return cloudInfo, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudinfo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	info "github.com/google/cadvisor/info/v1"
+)
+
+// Points the detection at a fake DMI directory holding the given attributes
+// and at a fake metadata service answering the given paths. GET requests
+// must carry the given header.
+func setup(t *testing.T, dmi map[string]string, header [2]string, responses map[string]string) func() {
+	dir, err := ioutil.TempDir("", "dmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range dmi {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.Method+" "+r.URL.RequestURI()]
+		if !ok || (r.Method == "GET" && r.Header.Get(header[0]) != header[1]) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	oldDmiIdDir, oldMetadataUrl := dmiIdDir, metadataUrl
+	dmiIdDir, metadataUrl = dir, server.URL
+	return func() {
+		dmiIdDir, metadataUrl = oldDmiIdDir, oldMetadataUrl
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGetCloudInfo(t *testing.T) {
+	cases := []struct {
+		name      string
+		dmi       map[string]string
+		header    [2]string
+		responses map[string]string
+		expected  CloudInfo
+	}{
+		{
+			name:   "gce",
+			dmi:    map[string]string{"product_name": "Google Compute Engine", "sys_vendor": "Google"},
+			header: [2]string{"Metadata-Flavor", "Google"},
+			responses: map[string]string{
+				"GET /computeMetadata/v1/instance/machine-type": "projects/123/machineTypes/n1-standard-4",
+				"GET /computeMetadata/v1/instance/zone":         "projects/123/zones/us-central1-a",
+			},
+			expected: CloudInfo{Provider: info.CloudProviderGCE, InstanceType: "n1-standard-4", Region: "us-central1", Zone: "us-central1-a"},
+		},
+		{
+			name:   "aws",
+			dmi:    map[string]string{"sys_vendor": "Amazon EC2", "product_name": "m5.large"},
+			header: [2]string{"X-aws-ec2-metadata-token", "token"},
+			responses: map[string]string{
+				"PUT /latest/api/token":                             "token",
+				"GET /latest/meta-data/instance-type":               "m5.large",
+				"GET /latest/meta-data/placement/region":            "us-west-2",
+				"GET /latest/meta-data/placement/availability-zone": "us-west-2b",
+			},
+			expected: CloudInfo{Provider: info.CloudProviderAWS, InstanceType: "m5.large", Region: "us-west-2", Zone: "us-west-2b"},
+		},
+		{
+			name:   "azure",
+			dmi:    map[string]string{"sys_vendor": "Microsoft Corporation", "chassis_asset_tag": azureAssetTag},
+			header: [2]string{"Metadata", "true"},
+			responses: map[string]string{
+				"GET /metadata/instance/compute?api-version=2021-02-01": `{"location": "westeurope", "vmSize": "Standard_D2s_v3", "zone": "2"}`,
+			},
+			expected: CloudInfo{Provider: info.CloudProviderAzure, InstanceType: "Standard_D2s_v3", Region: "westeurope", Zone: "2"},
+		},
+		{
+			// Hyper-V virtual machines outside of Azure.
+			name:     "none",
+			dmi:      map[string]string{"sys_vendor": "Microsoft Corporation", "product_name": "Virtual Machine"},
+			expected: CloudInfo{},
+		},
+	}
+	for _, c := range cases {
+		cleanup := setup(t, c.dmi, c.header, c.responses)
+		cloudInfo, err := GetCloudInfo()
+		cleanup()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(cloudInfo, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, cloudInfo)
+		}
+	}
+}
+
+func TestGetCloudInfoAwsWithoutToken(t *testing.T) {
+	// Instances which do not hand out session tokens only answer plain requests.
+	defer setup(t, map[string]string{"product_uuid": "EC2E1916-9099-7CAF-FD21-012345ABCDEF"}, [2]string{}, map[string]string{
+		"GET /latest/meta-data/instance-type":               "t2.micro",
+		"GET /latest/meta-data/placement/region":            "eu-west-1",
+		"GET /latest/meta-data/placement/availability-zone": "eu-west-1a",
+	})()
+	cloudInfo, err := GetCloudInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := CloudInfo{Provider: info.CloudProviderAWS, InstanceType: "t2.micro", Region: "eu-west-1", Zone: "eu-west-1a"}
+	if !reflect.DeepEqual(cloudInfo, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cloudInfo)
+	}
+}
+
+func TestGetCloudInfoMetadataError(t *testing.T) {
+	defer setup(t, map[string]string{"product_name": "Google Compute Engine"}, [2]string{}, nil)()
+	cloudInfo, err := GetCloudInfo()
+	if err == nil {
+		t.Errorf("expected an error when the metadata service fails, got %+v", cloudInfo)
+	}
+	if cloudInfo.Provider != info.CloudProviderGCE {
+		t.Errorf("expected the provider to be detected despite the error, got %q", cloudInfo.Provider)
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgpack encodes values in the MessagePack format
+// (http://msgpack.org). Values are laid out the way encoding/json lays them
+// out: structs become maps keyed by the names of their json tags, fields
+// tagged "-" are skipped, omitempty is honored, embedded structs are inlined
+// and time.Time is encoded as an RFC 3339 string.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Returns the MessagePack encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	e := &encoder{}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+type encoder struct {
+	bytes.Buffer
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (self *encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		self.WriteByte(0xc0)
+		return nil
+	}
+	if v.Type() == timeType {
+		self.encodeString(v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			self.WriteByte(0xc3)
+		} else {
+			self.WriteByte(0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		self.encodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		self.encodeUint(v.Uint())
+	case reflect.Float32:
+		self.WriteByte(0xca)
+		self.writeBigEndian(math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		self.WriteByte(0xcb)
+		self.writeBigEndian(math.Float64bits(v.Float()))
+	case reflect.String:
+		self.encodeString(v.String())
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			self.WriteByte(0xc0)
+			return nil
+		}
+		return self.encode(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			self.WriteByte(0xc0)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			self.encodeBinary(v.Bytes())
+			return nil
+		}
+		return self.encodeArray(v)
+	case reflect.Array:
+		return self.encodeArray(v)
+	case reflect.Map:
+		if v.IsNil() {
+			self.WriteByte(0xc0)
+			return nil
+		}
+		return self.encodeMap(v)
+	case reflect.Struct:
+		return self.encodeStruct(v)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+func (self *encoder) writeBigEndian(v interface{}) {
+	// Writing to a bytes.Buffer never fails.
+	binary.Write(self, binary.BigEndian, v)
+}
+
+func (self *encoder) encodeInt(i int64) {
+	switch {
+	case i >= 0:
+		self.encodeUint(uint64(i))
+	case i >= -32:
+		self.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8:
+		self.WriteByte(0xd0)
+		self.writeBigEndian(int8(i))
+	case i >= math.MinInt16:
+		self.WriteByte(0xd1)
+		self.writeBigEndian(int16(i))
+	case i >= math.MinInt32:
+		self.WriteByte(0xd2)
+		self.writeBigEndian(int32(i))
+	default:
+		self.WriteByte(0xd3)
+		self.writeBigEndian(i)
+	}
+}
+
+func (self *encoder) encodeUint(u uint64) {
+	switch {
+	case u <= 0x7f:
+		self.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		self.WriteByte(0xcc)
+		self.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		self.WriteByte(0xcd)
+		self.writeBigEndian(uint16(u))
+	case u <= math.MaxUint32:
+		self.WriteByte(0xce)
+		self.writeBigEndian(uint32(u))
+	default:
+		self.WriteByte(0xcf)
+		self.writeBigEndian(u)
+	}
+}
+
+// Writes the header of a string, binary, array or map of length n. small is
+// the header of the fixed size variant, holding up to smallMax elements, and
+// sized the headers of the variants with 8 (if any), 16 and 32 bit lengths.
+func (self *encoder) writeHeader(n int, small byte, smallMax int, sized [3]byte) {
+	switch {
+	case n <= smallMax:
+		self.WriteByte(small | byte(n))
+	case sized[0] != 0 && n <= math.MaxUint8:
+		self.WriteByte(sized[0])
+		self.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		self.WriteByte(sized[1])
+		self.writeBigEndian(uint16(n))
+	default:
+		self.WriteByte(sized[2])
+		self.writeBigEndian(uint32(n))
+	}
+}
+
+func (self *encoder) encodeString(s string) {
+	self.writeHeader(len(s), 0xa0, 31, [3]byte{0xd9, 0xda, 0xdb})
+	self.WriteString(s)
+}
+
+func (self *encoder) encodeBinary(b []byte) {
+	self.writeHeader(len(b), 0xc4, -1, [3]byte{0xc4, 0xc5, 0xc6})
+	self.Write(b)
+}
+
+func (self *encoder) encodeArray(v reflect.Value) error {
+	self.writeHeader(v.Len(), 0x90, 15, [3]byte{0, 0xdc, 0xdd})
+	for i := 0; i < v.Len(); i++ {
+		if err := self.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Maps are encoded with their keys sorted, keys which are not strings are
+// converted to strings like encoding/json does.
+func (self *encoder) encodeMap(v reflect.Value) error {
+	keys := make(map[string]reflect.Value, v.Len())
+	names := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		var name string
+		switch key.Kind() {
+		case reflect.String:
+			name = key.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			name = strconv.FormatInt(key.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			name = strconv.FormatUint(key.Uint(), 10)
+		default:
+			return fmt.Errorf("unsupported map key type %v", key.Type())
+		}
+		keys[name] = key
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	self.writeHeader(len(names), 0x80, 15, [3]byte{0, 0xde, 0xdf})
+	for _, name := range names {
+		self.encodeString(name)
+		if err := self.encode(v.MapIndex(keys[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+func (self *encoder) encodeStruct(v reflect.Value) error {
+	fields := structFields(v)
+	self.writeHeader(len(fields), 0x80, 15, [3]byte{0, 0xde, 0xdf})
+	for _, f := range fields {
+		self.encodeString(f.name)
+		if err := self.encode(f.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the fields of a struct which are encoded, in declaration order.
+func structFields(v reflect.Value) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			// Unexported.
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name, options = tag[:idx], tag[idx+1:]
+		}
+		value := v.Field(i)
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, structFields(value)...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if hasOption(options, "omitempty") && isEmpty(value) {
+			continue
+		}
+		fields = append(fields, field{name, value})
+	}
+	return fields
+}
+
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether the value is empty as defined by the omitempty option of
+// encoding/json.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgpack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type outer struct {
+	inner
+	Count   int               `json:"count,omitempty"`
+	Skipped string            `json:"-"`
+	Values  []uint64          `json:"values,omitempty"`
+	Labels  map[string]string `json:"labels"`
+	Time    time.Time         `json:"time"`
+	Ratio   float64
+	Next    *outer `json:"next,omitempty"`
+	hidden  int
+}
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{5, []byte{0x05}},
+		{-3, []byte{0xfd}},
+		{-100, []byte{0xd0, 0x9c}},
+		{200, []byte{0xcc, 0xc8}},
+		{uint64(1) << 40, []byte{0xcf, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{int64(-1) << 40, []byte{0xd3, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{map[int]bool{2: false, 1: true}, []byte{0x82, 0xa1, '1', 0xc3, 0xa1, '2', 0xc2}},
+		{
+			outer{inner: inner{Name: "a"}, Skipped: "b", Time: time.Unix(0, 0).UTC(), hidden: 1},
+			append(append([]byte{0x84, 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'a', 0xa6, 'l', 'a', 'b', 'e', 'l', 's', 0xc0, 0xa4, 't', 'i', 'm', 'e', 0xb4},
+				"1970-01-01T00:00:00Z"...), 0xa5, 'R', 'a', 't', 'i', 'o', 0xcb, 0, 0, 0, 0, 0, 0, 0, 0),
+		},
+	}
+	for _, testCase := range testCases {
+		out, err := Marshal(testCase.value)
+		if err != nil {
+			t.Errorf("failed to marshal %#v: %v", testCase.value, err)
+			continue
+		}
+		if !bytes.Equal(out, testCase.expected) {
+			t.Errorf("marshaled %#v to % x, expected % x", testCase.value, out, testCase.expected)
+		}
+	}
+}
+
+func TestMarshalLengths(t *testing.T) {
+	out, err := Marshal(strings.Repeat("a", 300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out[:3], []byte{0xda, 0x01, 0x2c}) || len(out) != 303 {
+		t.Errorf("unexpected encoding of a 300 byte string: % x", out[:3])
+	}
+
+	out, err = Marshal(make([]bool, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out[:3], []byte{0xdc, 0x00, 0x14}) || len(out) != 23 {
+		t.Errorf("unexpected encoding of a 20 element array: % x", out[:3])
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	if _, err := Marshal(make(chan int)); err == nil {
+		t.Error("expected an error marshaling a channel")
+	}
+}
@@ -32,7 +32,9 @@ import (
 var containerRegexp *regexp.Regexp = regexp.MustCompile(
 	`Task in (.*) killed as a result of limit of `)
 var lastLineRegexp *regexp.Regexp = regexp.MustCompile(
-	`(^[A-Z]{1}[a-z]{2} .*[0-9]{1,2} [0-9]{1,2}:[0-9]{2}:[0-9]{2}) .* Killed process ([0-9]+) \(([0-9A-Za-z_]+)\)`)
+	`(^[A-Z]{1}[a-z]{2} .*[0-9]{1,2} [0-9]{1,2}:[0-9]{2}:[0-9]{2}) .* Killed process ([0-9]+) \(([^)]+)\)`)
+var memoryLimitRegexp *regexp.Regexp = regexp.MustCompile(
+	`memory: usage [0-9]+kB, limit ([0-9]+)kB`)
 var firstLineRegexp *regexp.Regexp = regexp.MustCompile(
 	`invoked oom-killer:`)
 
@@ -52,6 +54,9 @@ type OomInstance struct {
 	TimeOfDeath time.Time
 	// the absolute name of the container that OOMed
 	ContainerName string
+	// the memory limit of the container at the time of the kill in bytes,
+	// 0 if it was not reported by the kernel
+	MemoryLimit uint64
 }
 
 // gets the container name from a line and adds it to the oomInstance.
@@ -64,6 +69,20 @@ func getContainerName(line string, currentOomInstance *OomInstance) error {
 	return nil
 }
 
+// gets the memory limit of the container from a line and adds it to the oomInstance.
+func getMemoryLimit(line string, currentOomInstance *OomInstance) error {
+	parsedLine := memoryLimitRegexp.FindStringSubmatch(line)
+	if parsedLine == nil {
+		return nil
+	}
+	limitKb, err := strconv.ParseUint(parsedLine[1], 10, 64)
+	if err != nil {
+		return err
+	}
+	currentOomInstance.MemoryLimit = limitKb * 1024
+	return nil
+}
+
 // gets the pid, name, and date from a line and adds it to oomInstance
 func getProcessNamePid(line string, currentOomInstance *OomInstance) (bool, error) {
 	reList := lastLineRegexp.FindStringSubmatch(line)
@@ -146,6 +165,10 @@ func (self *OomParser) StreamOoms(outStream chan *OomInstance) {
 				if err != nil {
 					glog.Errorf("%v", err)
 				}
+				err = getMemoryLimit(line, oomCurrentInstance)
+				if err != nil {
+					glog.Errorf("%v", err)
+				}
 				finished, err = getProcessNamePid(line, oomCurrentInstance)
 				if err != nil {
 					glog.Errorf("%v", err)
@@ -24,6 +24,8 @@ import (
 const startLine = "Jan 21 22:01:49 localhost kernel: [62278.816267] ruby invoked oom-killer: gfp_mask=0x201da, order=0, oom_score_adj=0"
 const endLine = "Jan 21 22:01:49 localhost kernel: [62279.421192] Killed process 19667 (evilprogram2) total-vm:1460016kB, anon-rss:1414008kB, file-rss:4kB"
 const containerLine = "Jan 26 14:10:07 kateknister0.mtv.corp.google.com kernel: [1814368.465205] Task in /mem2 killed as a result of limit of /mem2"
+const memoryLine = "Jan  5 15:19:27 kernel: [ 5864.708495] memory: usage 980kB, limit 980kB, failcnt 4152239"
+const dashedNameLine = "Jan 21 22:01:49 localhost kernel: [62279.421192] Killed process 19668 (kube-proxy.bin) total-vm:1460016kB, anon-rss:1414008kB, file-rss:4kB"
 const containerLogFile = "containerOomExampleLog.txt"
 const systemLogFile = "systemOomExampleLog.txt"
 
@@ -39,6 +41,7 @@ func createExpectedContainerOomInstance(t *testing.T) *OomInstance {
 		ProcessName:   "memorymonster",
 		TimeOfDeath:   deathTime,
 		ContainerName: "/mem2",
+		MemoryLimit:   980 * 1024,
 	}
 }
 
@@ -105,6 +108,35 @@ func TestGetProcessNamePid(t *testing.T) {
 	}
 }
 
+func TestGetMemoryLimit(t *testing.T) {
+	currentOomInstance := new(OomInstance)
+	err := getMemoryLimit(containerLine, currentOomInstance)
+	if err != nil {
+		t.Errorf("bad line fed to getMemoryLimit should yield no error, but had error %v", err)
+	}
+	if currentOomInstance.MemoryLimit != 0 {
+		t.Errorf("bad line fed to getMemoryLimit should not set a limit but set it to %d", currentOomInstance.MemoryLimit)
+	}
+	err = getMemoryLimit(memoryLine, currentOomInstance)
+	if err != nil {
+		t.Errorf("memory line fed to getMemoryLimit should yield no error, but had error %v", err)
+	}
+	if currentOomInstance.MemoryLimit != 980*1024 {
+		t.Errorf("getMemoryLimit should have set the limit to %d, not %d", 980*1024, currentOomInstance.MemoryLimit)
+	}
+}
+
+func TestGetProcessNameWithPunctuation(t *testing.T) {
+	currentOomInstance := new(OomInstance)
+	couldParseLine, err := getProcessNamePid(dashedNameLine, currentOomInstance)
+	if err != nil || !couldParseLine {
+		t.Fatalf("good line fed to getProcessNamePid should be parsed, got %v with error %v", couldParseLine, err)
+	}
+	if currentOomInstance.ProcessName != "kube-proxy.bin" {
+		t.Errorf("getProcessNamePid should have set processName to kube-proxy.bin, not %s", currentOomInstance.ProcessName)
+	}
+}
+
 func TestCheckIfStartOfMessages(t *testing.T) {
 	couldParseLine := checkIfStartOfOomMessages(endLine)
 	if couldParseLine {
@@ -16,7 +16,12 @@ package sysinfo
 
 import (
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -208,3 +213,393 @@ func getNetworkStats(name string, sysFs sysfs.SysFs) (info.NetworkStats, error)
 func GetSystemUUID(sysFs sysfs.SysFs) (string, error) {
 	return sysFs.GetSystemUUID()
 }
+
+// Get the TCP connection and UDP socket counts of the network namespace
+// whose /proc/net directory is procNetDir (e.g. /proc/<pid>/net).
+func GetSocketStats(procNetDir string) (info.TcpStats, info.UdpStats, error) {
+	tcp := info.TcpStats{}
+	udp := info.UdpStats{}
+	for _, file := range []string{"tcp", "tcp6"} {
+		lines, err := readProcNetFile(path.Join(procNetDir, file))
+		if err != nil {
+			return tcp, udp, err
+		}
+		err = addTcpStats(&tcp, lines)
+		if err != nil {
+			return tcp, udp, err
+		}
+	}
+	for _, file := range []string{"udp", "udp6"} {
+		lines, err := readProcNetFile(path.Join(procNetDir, file))
+		if err != nil {
+			return tcp, udp, err
+		}
+		err = addUdpStats(&udp, lines)
+		if err != nil {
+			return tcp, udp, err
+		}
+	}
+	return tcp, udp, nil
+}
+
+// Get the counters of the interfaces of the network namespace whose /proc/net
+// directory is procNetDir (e.g. /proc/<pid>/net), in the order the kernel
+// lists them. The loopback interface is skipped unless includeLoopback is set.
+func GetInterfaceStats(procNetDir string, includeLoopback bool) ([]info.InterfaceStats, error) {
+	out, err := ioutil.ReadFile(path.Join(procNetDir, "dev"))
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	// Skip the two header lines.
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("malformed %s", path.Join(procNetDir, "dev"))
+	}
+	var interfaces []info.InterfaceStats
+	for _, line := range lines[2:] {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed network device line %q", line)
+		}
+		name := strings.TrimSpace(parts[0])
+		if name == "lo" && !includeLoopback {
+			continue
+		}
+		// 8 receive counters followed by 8 transmit ones.
+		fields := strings.Fields(parts[1])
+		if len(fields) != 16 {
+			return nil, fmt.Errorf("malformed network device line %q", line)
+		}
+		values := make([]uint64, len(fields))
+		for i, field := range fields {
+			values[i], err = strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed network device counter in %q: %v", line, err)
+			}
+		}
+		interfaces = append(interfaces, info.InterfaceStats{
+			Name:      name,
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			RxDropped: values[3],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+			TxDropped: values[11],
+		})
+	}
+	return interfaces, nil
+}
+
+var numaNodeRegExp = regexp.MustCompile(`^node(\d+)$`)
+var hugePagesRegExp = regexp.MustCompile(`^hugepages-(\d+)kB$`)
+
+// Get the NUMA nodes listed in nodeDir (e.g. /sys/devices/system/node),
+// sorted by id. Returns no nodes if nodeDir does not exist, as on kernels
+// built without NUMA support.
+func GetNumaNodes(nodeDir string) ([]info.NumaNode, error) {
+	entries, err := ioutil.ReadDir(nodeDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var nodes []info.NumaNode
+	// ReadDir sorts by name, node10 comes before node2.
+	for _, entry := range entries {
+		match := numaNodeRegExp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		id, _ := strconv.Atoi(match[1])
+		node, err := getNumaNode(path.Join(nodeDir, entry.Name()), id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Sort(numaNodesById(nodes))
+	return nodes, nil
+}
+
+type numaNodesById []info.NumaNode
+
+func (s numaNodesById) Len() int           { return len(s) }
+func (s numaNodesById) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s numaNodesById) Less(i, j int) bool { return s[i].Id < s[j].Id }
+
+func getNumaNode(dir string, id int) (info.NumaNode, error) {
+	node := info.NumaNode{Id: id}
+	cpuList, err := ioutil.ReadFile(path.Join(dir, "cpulist"))
+	if err != nil {
+		return node, err
+	}
+	node.Cpus, err = parseCpuList(strings.TrimSpace(string(cpuList)))
+	if err != nil {
+		return node, fmt.Errorf("malformed cpulist of NUMA node %d: %v", id, err)
+	}
+
+	// Lines are of the form "Node 0 MemTotal:       16333408 kB".
+	meminfo, err := ioutil.ReadFile(path.Join(dir, "meminfo"))
+	if err != nil {
+		return node, err
+	}
+	for _, line := range strings.Split(string(meminfo), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[4] != "kB" {
+			continue
+		}
+		var value *uint64
+		switch fields[2] {
+		case "MemTotal:":
+			value = &node.MemoryTotal
+		case "MemFree:":
+			value = &node.MemoryFree
+		default:
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return node, fmt.Errorf("malformed meminfo line %q of NUMA node %d: %v", line, id, err)
+		}
+		*value = kb * 1024
+	}
+
+	hugePages, err := ioutil.ReadDir(path.Join(dir, "hugepages"))
+	if os.IsNotExist(err) {
+		return node, nil
+	}
+	if err != nil {
+		return node, err
+	}
+	for _, entry := range hugePages {
+		match := hugePagesRegExp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		pages := info.HugePagesInfo{}
+		pages.PageSize, _ = strconv.ParseUint(match[1], 10, 64)
+		for file, value := range map[string]*uint64{"nr_hugepages": &pages.NumPages, "free_hugepages": &pages.FreePages} {
+			out, err := ioutil.ReadFile(path.Join(dir, "hugepages", entry.Name(), file))
+			if err != nil {
+				return node, err
+			}
+			*value, err = strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+			if err != nil {
+				return node, fmt.Errorf("malformed %s of NUMA node %d: %v", file, id, err)
+			}
+		}
+		node.HugePages = append(node.HugePages, pages)
+	}
+	return node, nil
+}
+
+// Parses a list of CPUs, e.g. "0-3,8-11", as in cpulist files.
+func parseCpuList(list string) ([]int, error) {
+	cpus := []int{}
+	if list == "" {
+		return cpus, nil
+	}
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// Get the number of processes among pids, the file descriptors opened by the
+// one closest to its limit on open files and that limit, reading the /proc
+// filesystem mounted at procRoot. Processes which exit meanwhile are skipped.
+func GetProcessStats(procRoot string, pids []int) (info.ProcessStats, error) {
+	stats := info.ProcessStats{}
+	for _, pid := range pids {
+		procDir := path.Join(procRoot, strconv.Itoa(pid))
+		fds, err := ioutil.ReadDir(path.Join(procDir, "fd"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		limit, err := readMaxOpenFiles(path.Join(procDir, "limits"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		voluntary, involuntary, err := readCtxSwitches(procDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		stats.ProcessCount++
+		open := uint64(len(fds))
+		if stats.MaxFDs == 0 || float64(open)/float64(limit) > float64(stats.OpenFDs)/float64(stats.MaxFDs) {
+			stats.OpenFDs = open
+			stats.MaxFDs = limit
+		}
+		stats.VoluntaryCtxSwitches += voluntary
+		stats.InvoluntaryCtxSwitches += involuntary
+	}
+	return stats, nil
+}
+
+// Returns the voluntary and involuntary context switches of the threads of
+// the process at procDir, summed from their task/<tid>/status files. Threads
+// which exited while they were listed are skipped.
+func readCtxSwitches(procDir string) (uint64, uint64, error) {
+	tasks, err := ioutil.ReadDir(path.Join(procDir, "task"))
+	if err != nil {
+		return 0, 0, err
+	}
+	var voluntary, involuntary uint64
+	for _, task := range tasks {
+		out, err := ioutil.ReadFile(path.Join(procDir, "task", task.Name(), "status"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			var count *uint64
+			switch fields[0] {
+			case "voluntary_ctxt_switches:":
+				count = &voluntary
+			case "nonvoluntary_ctxt_switches:":
+				count = &involuntary
+			default:
+				continue
+			}
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("malformed %s in %q: %v", fields[0], task.Name(), err)
+			}
+			*count += value
+		}
+	}
+	return voluntary, involuntary, nil
+}
+
+// Returns the soft limit on open files of a /proc/<pid>/limits file, the
+// maximum value if there is none.
+func readMaxOpenFiles(file string) (uint64, error) {
+	out, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) < 1 {
+			break
+		}
+		if fields[0] == "unlimited" {
+			return math.MaxUint64, nil
+		}
+		limit, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed open files limit in %q: %v", line, err)
+		}
+		return limit, nil
+	}
+	return 0, fmt.Errorf("no open files limit in %q", file)
+}
+
+// Returns the socket lines of a /proc/net socket table. The IPv6 tables are
+// missing when IPv6 is disabled, they are treated as empty.
+func readProcNetFile(file string) ([]string, error) {
+	out, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) && strings.HasSuffix(file, "6") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	// Skip the header.
+	return lines[1:], nil
+}
+
+func addTcpStats(stats *info.TcpStats, lines []string) error {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return fmt.Errorf("malformed tcp socket line %q", line)
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return fmt.Errorf("malformed tcp socket state in %q: %v", line, err)
+		}
+		// States are from include/net/tcp_states.h.
+		switch state {
+		case 1:
+			stats.Established++
+		case 2:
+			stats.SynSent++
+		case 3:
+			stats.SynRecv++
+		case 4:
+			stats.FinWait1++
+		case 5:
+			stats.FinWait2++
+		case 6:
+			stats.TimeWait++
+		case 7:
+			stats.Close++
+		case 8:
+			stats.CloseWait++
+		case 9:
+			stats.LastAck++
+		case 10:
+			stats.Listen++
+		case 11:
+			stats.Closing++
+		}
+	}
+	return nil
+}
+
+func addUdpStats(stats *info.UdpStats, lines []string) error {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		// The drops are the last field, it is missing on kernels older than 2.6.27.
+		if len(fields) < 10 {
+			return fmt.Errorf("malformed udp socket line %q", line)
+		}
+		stats.Sockets++
+		if len(fields) < 13 {
+			continue
+		}
+		drops, err := strconv.ParseUint(fields[12], 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed udp socket drops in %q: %v", line, err)
+		}
+		stats.Dropped += drops
+	}
+	return nil
+}
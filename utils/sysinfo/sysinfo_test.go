@@ -15,6 +15,12 @@
 package sysinfo
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
 	"testing"
 
 	info "github.com/google/cadvisor/info/v1"
@@ -125,7 +131,191 @@ func TestGetNetworkStats(t *testing.T) {
 	if err != nil {
 		t.Errorf("call to getNetworkStats() failed with %s", err)
 	}
-	if expected_stats != netStats {
+	if !reflect.DeepEqual(expected_stats, netStats) {
 		t.Errorf("expected to get stats %+v, got %+v", expected_stats, netStats)
 	}
 }
+
+const (
+	procNetTcp = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12345 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 12346 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:C350 0100007F:1F90 06 00000000:00000000 03:00000F9A 00000000     0        0 0 3 0000000000000000
+`
+	procNetTcp6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:0016 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12347 1 0000000000000000 100 0 0 10 0
+`
+	procNetUdp = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  10: 00000000:0044 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 12348 2 0000000000000000 3
+  11: 0100007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 12349 2 0000000000000000 0
+`
+	procNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0: 2000 20 1 2    0     0          0         0 3000 30 3 4    0     0       0          0
+  net1:    5000      50    0    0    0     0          0         0     6000      60    0    1    0     0       0          0
+`
+)
+
+func TestGetSocketStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc-net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// There is no udp6 file, as when IPv6 is disabled.
+	files := map[string]string{"tcp": procNetTcp, "tcp6": procNetTcp6, "udp": procNetUdp}
+	for name, content := range files {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tcp, udp, err := GetSocketStats(dir)
+	if err != nil {
+		t.Fatalf("call to GetSocketStats() failed with %s", err)
+	}
+	expectedTcp := info.TcpStats{Listen: 2, Established: 1, TimeWait: 1}
+	if tcp != expectedTcp {
+		t.Errorf("expected tcp stats %+v, got %+v", expectedTcp, tcp)
+	}
+	expectedUdp := info.UdpStats{Sockets: 2, Dropped: 3}
+	if udp != expectedUdp {
+		t.Errorf("expected udp stats %+v, got %+v", expectedUdp, udp)
+	}
+
+	os.Remove(path.Join(dir, "udp"))
+	if _, _, err = GetSocketStats(dir); err == nil {
+		t.Errorf("expected an error without an udp socket table")
+	}
+}
+
+func TestGetInterfaceStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc-net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(path.Join(dir, "dev"), []byte(procNetDev), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	interfaces, err := GetInterfaceStats(dir, false)
+	if err != nil {
+		t.Fatalf("call to GetInterfaceStats() failed with %s", err)
+	}
+	expected := []info.InterfaceStats{
+		{Name: "eth0", RxBytes: 2000, RxPackets: 20, RxErrors: 1, RxDropped: 2, TxBytes: 3000, TxPackets: 30, TxErrors: 3, TxDropped: 4},
+		{Name: "net1", RxBytes: 5000, RxPackets: 50, TxBytes: 6000, TxPackets: 60, TxDropped: 1},
+	}
+	if !reflect.DeepEqual(interfaces, expected) {
+		t.Errorf("expected interface stats %+v, got %+v", expected, interfaces)
+	}
+
+	interfaces, err = GetInterfaceStats(dir, true)
+	if err != nil {
+		t.Fatalf("call to GetInterfaceStats() failed with %s", err)
+	}
+	if len(interfaces) != 3 || interfaces[0].Name != "lo" {
+		t.Errorf("expected the loopback interface first, got %+v", interfaces)
+	}
+}
+
+func TestGetProcessStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	processes := map[string]struct {
+		fds   int
+		limit string
+		// Voluntary and involuntary context switches of each thread.
+		ctxSwitches map[string][2]int
+	}{
+		"10": {3, "1024                 4096                 files", map[string][2]int{"10": {5, 1}, "15": {10, 2}}},
+		"11": {1, "512                  512                  files", map[string][2]int{"11": {1, 0}}},
+		"12": {2, "unlimited            unlimited            files", map[string][2]int{"12": {0, 3}}},
+	}
+	for pid, process := range processes {
+		if err := os.MkdirAll(path.Join(dir, pid, "fd"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for fd := 0; fd < process.fds; fd++ {
+			if err := ioutil.WriteFile(path.Join(dir, pid, "fd", strconv.Itoa(fd)), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		limits := "Limit                     Soft Limit           Hard Limit           Units\nMax processes             63704                63704                processes\nMax open files            " + process.limit + "\n"
+		if err := ioutil.WriteFile(path.Join(dir, pid, "limits"), []byte(limits), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for tid, switches := range process.ctxSwitches {
+			if err := os.MkdirAll(path.Join(dir, pid, "task", tid), 0755); err != nil {
+				t.Fatal(err)
+			}
+			status := fmt.Sprintf("Name:\tapp\nPid:\t%s\nvoluntary_ctxt_switches:\t%d\nnonvoluntary_ctxt_switches:\t%d\n", tid, switches[0], switches[1])
+			if err := ioutil.WriteFile(path.Join(dir, pid, "task", tid, "status"), []byte(status), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Process 13 exited since the pids were listed.
+	stats, err := GetProcessStats(dir, []int{10, 11, 12, 13})
+	if err != nil {
+		t.Fatalf("call to GetProcessStats() failed with %s", err)
+	}
+	expected := info.ProcessStats{ProcessCount: 3, OpenFDs: 3, MaxFDs: 1024, VoluntaryCtxSwitches: 16, InvoluntaryCtxSwitches: 6}
+	if stats != expected {
+		t.Errorf("expected process stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestGetNumaNodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"node0/cpulist": "0-1,4\n",
+		"node0/meminfo": "Node 0 MemTotal:       1024 kB\nNode 0 MemFree:         512 kB\nNode 0 HugePages_Total:     2\n",
+		"node0/hugepages/hugepages-2048kB/nr_hugepages":   "2\n",
+		"node0/hugepages/hugepages-2048kB/free_hugepages": "1\n",
+		"node10/cpulist": "2-3\n",
+		"node10/meminfo": "Node 10 MemTotal:       2048 kB\nNode 10 MemFree:        2048 kB\n",
+		"possible":       "0,10\n",
+	}
+	for file, content := range files {
+		if err := os.MkdirAll(path.Dir(path.Join(dir, file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nodes, err := GetNumaNodes(dir)
+	if err != nil {
+		t.Fatalf("call to GetNumaNodes() failed with %s", err)
+	}
+	expected := []info.NumaNode{
+		{
+			Id:          0,
+			Cpus:        []int{0, 1, 4},
+			MemoryTotal: 1024 * 1024,
+			MemoryFree:  512 * 1024,
+			HugePages:   []info.HugePagesInfo{{PageSize: 2048, NumPages: 2, FreePages: 1}},
+		},
+		{Id: 10, Cpus: []int{2, 3}, MemoryTotal: 2048 * 1024, MemoryFree: 2048 * 1024},
+	}
+	if !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("expected NUMA nodes %+v, got %+v", expected, nodes)
+	}
+
+	nodes, err = GetNumaNodes(path.Join(dir, "missing"))
+	if err != nil || len(nodes) != 0 {
+		t.Errorf("expected no NUMA nodes without a node directory, got %+v, %v", nodes, err)
+	}
+}
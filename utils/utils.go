@@ -14,7 +14,11 @@
 
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Returns a mask of all cores on the machine if the passed-in mask is empty.
 func FixCpuMask(mask string, cores int) string {
@@ -27,3 +31,29 @@ func FixCpuMask(mask string, cores int) string {
 	}
 	return mask
 }
+
+// Returns the number of cores in a mask of the cpuset format, e.g. "0-3,8".
+func CountCpus(mask string) (int, error) {
+	count := 0
+	for _, part := range strings.Split(strings.TrimSpace(mask), ",") {
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu mask %q: %v", mask, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid cpu mask %q: %v", mask, err)
+			}
+		}
+		if last < first {
+			return 0, fmt.Errorf("invalid cpu mask %q: range %q is reversed", mask, part)
+		}
+		count += last - first + 1
+	}
+	return count, nil
+}
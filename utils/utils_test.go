@@ -0,0 +1,37 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "testing"
+
+func TestCountCpus(t *testing.T) {
+	for mask, expected := range map[string]int{
+		"0":         1,
+		"0-3":       4,
+		"0-3,8\n":   5,
+		"0,2,4-5,7": 5,
+		"":          0,
+	} {
+		count, err := CountCpus(mask)
+		if err != nil || count != expected {
+			t.Errorf("expected %d cpus in %q, got %d (error: %v)", expected, mask, count, err)
+		}
+	}
+	for _, mask := range []string{"a", "0-b", "3-1"} {
+		if _, err := CountCpus(mask); err == nil {
+			t.Errorf("expected an error for mask %q", mask)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Minimal server side implementation of the WebSocket protocol (RFC 6455).
+// It supports what cAdvisor needs to push messages to clients: the opening
+// handshake, unfragmented text frames, and the ping/pong and close control frames.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Frame opcodes as defined in RFC 6455 section 5.2.
+const (
+	TextMessage  = 0x1
+	CloseMessage = 0x8
+	PingMessage  = 0x9
+	PongMessage  = 0xA
+)
+
+// GUID appended to the client key when computing the accept key.
+const acceptGuid = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Largest payload accepted from a client. Clients are not expected to send
+// anything but control frames.
+const maxReadPayload = 64 * 1024
+
+type Conn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+
+	// Serializes frame writes.
+	writeLock sync.Mutex
+}
+
+// Returns the Sec-WebSocket-Accept value for the specified client key.
+func computeAcceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key+acceptGuid)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Returns whether the comma separated header contains the specified token (case-insensitive).
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header[name] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns whether the request asks for a WebSocket upgrade.
+func IsWebSocketRequest(r *http.Request) bool {
+	return headerContainsToken(r.Header, "Connection", "upgrade") && headerContainsToken(r.Header, "Upgrade", "websocket")
+}
+
+// Upgrade performs the opening handshake and takes over the underlying connection.
+// No further writes to w are possible once the upgrade succeeds.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != "GET" {
+		return nil, fmt.Errorf("websocket: method must be GET, not %q", r.Method)
+	}
+	if !IsWebSocketRequest(r) {
+		return nil, fmt.Errorf("websocket: request is not a WebSocket upgrade")
+	}
+	if r.Header.Get("Sec-Websocket-Version") != "13" {
+		return nil, fmt.Errorf("websocket: unsupported version %q", r.Header.Get("Sec-Websocket-Version"))
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("websocket: missing Sec-WebSocket-Key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("websocket: could not access http.Hijacker")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("websocket: failed to hijack connection: %v", err)
+	}
+
+	_, err = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", computeAcceptKey(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: failed to complete handshake: %v", err)
+	}
+	return newConn(conn, rw), nil
+}
+
+func newConn(conn net.Conn, rw *bufio.ReadWriter) *Conn {
+	return &Conn{
+		conn: conn,
+		rw:   rw,
+	}
+}
+
+// Writes a single unmasked frame with the FIN bit set.
+func (self *Conn) writeFrame(opcode byte, payload []byte) error {
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	n := len(payload)
+	switch {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = header[:4]
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header[1] = 127
+		header = header[:10]
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	if _, err := self.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := self.rw.Write(payload); err != nil {
+		return err
+	}
+	return self.rw.Flush()
+}
+
+// Sends data as a single text message.
+func (self *Conn) WriteText(data []byte) error {
+	return self.writeFrame(TextMessage, data)
+}
+
+// Sends a ping control frame.
+func (self *Conn) WritePing() error {
+	return self.writeFrame(PingMessage, nil)
+}
+
+// Reads the next message from the client. Pings are answered automatically
+// and returned to the caller like any other message.
+func (self *Conn) ReadMessage() (opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(self.rw, header[:]); err != nil {
+		return
+	}
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(self.rw, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(self.rw, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxReadPayload {
+		err = fmt.Errorf("websocket: frame of %d bytes exceeds limit of %d bytes", length, maxReadPayload)
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(self.rw, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(self.rw, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	if opcode == PingMessage {
+		err = self.writeFrame(PongMessage, payload)
+	}
+	return
+}
+
+// Sends a close frame and closes the underlying connection.
+func (self *Conn) Close() error {
+	// The peer may already be gone, the close frame is best effort.
+	self.writeFrame(CloseMessage, nil)
+	return self.conn.Close()
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestComputeAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	got := computeAcceptKey("dGhlIHNhbXBsZSBub25jZQ==")
+	expected := "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	if got != expected {
+		t.Errorf("expected accept key %q, got %q", expected, got)
+	}
+}
+
+func TestIsWebSocketRequest(t *testing.T) {
+	r, err := http.NewRequest("GET", "http://localhost:8080/api/v2.1/eventsws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsWebSocketRequest(r) {
+		t.Errorf("plain request detected as a WebSocket request")
+	}
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	r.Header.Set("Upgrade", "WebSocket")
+	if !IsWebSocketRequest(r) {
+		t.Errorf("upgrade request not detected as a WebSocket request")
+	}
+}
+
+func newPipeConn() (*Conn, net.Conn) {
+	server, client := net.Pipe()
+	return newConn(server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server))), client
+}
+
+func TestWriteText(t *testing.T) {
+	conn, client := newPipeConn()
+	defer client.Close()
+
+	payload := bytes.Repeat([]byte("a"), 300)
+	go conn.WriteText(payload)
+
+	frame := make([]byte, 4+len(payload))
+	if _, err := io.ReadFull(client, frame); err != nil {
+		t.Fatal(err)
+	}
+	if frame[0] != 0x80|TextMessage {
+		t.Errorf("expected first byte %#x, got %#x", 0x80|TextMessage, frame[0])
+	}
+	if frame[1] != 126 || frame[2] != 0x01 || frame[3] != 0x2C {
+		t.Errorf("unexpected extended length header % x", frame[1:4])
+	}
+	if !bytes.Equal(frame[4:], payload) {
+		t.Errorf("payload mismatch")
+	}
+}
+
+func TestReadMessageAnswersPing(t *testing.T) {
+	conn, client := newPipeConn()
+	defer client.Close()
+
+	// Masked ping from the client with payload "hi".
+	mask := []byte{1, 2, 3, 4}
+	ping := []byte{0x80 | PingMessage, 0x80 | 2}
+	ping = append(ping, mask...)
+	ping = append(ping, 'h'^mask[0], 'i'^mask[1])
+	go client.Write(ping)
+
+	done := make(chan error)
+	go func() {
+		opcode, payload, err := conn.ReadMessage()
+		if err == nil && (opcode != PingMessage || string(payload) != "hi") {
+			t.Errorf("expected ping with payload %q, got opcode %#x with payload %q", "hi", opcode, payload)
+		}
+		done <- err
+	}()
+
+	pong := make([]byte, 4)
+	if _, err := io.ReadFull(client, pong); err != nil {
+		t.Fatal(err)
+	}
+	if pong[0] != 0x80|PongMessage || pong[1] != 2 || string(pong[2:]) != "hi" {
+		t.Errorf("unexpected pong frame % x", pong)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}